@@ -0,0 +1,437 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes/empty"
+	"google.golang.org/protobuf/encoding/protojson"
+
+	"github.com/vulturedb/vulture/service/rpc"
+)
+
+// archiveChunkSize is the most archive data sent in a single message
+const archiveChunkSize = 64 * 1024
+
+// snapshotToFile writes an archive of a tree on the server to a file
+func snapshotToFile(
+	client rpc.MSTServiceClient,
+	req *rpc.MSTSnapshotRequest,
+	path string,
+) (int64, error) {
+	stream, err := client.Snapshot(context.Background(), req)
+	if err != nil {
+		return 0, err
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+	written := int64(0)
+	for {
+		chunk, err := stream.Recv()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return 0, err
+		}
+		n, err := f.Write(chunk.GetData())
+		if err != nil {
+			return 0, err
+		}
+		written += int64(n)
+	}
+	return written, f.Sync()
+}
+
+// restoreFromFile sends an archive in a file to the server to merge into the tree of a keyspace
+func restoreFromFile(
+	client rpc.MSTServiceClient,
+	keyspace string,
+	path string,
+) (*rpc.MSTRestoreResponse, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	stream, err := client.Restore(context.Background())
+	if err != nil {
+		return nil, err
+	}
+	buf := make([]byte, archiveChunkSize)
+	for first := true; ; first = false {
+		n, err := f.Read(buf)
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, err
+		}
+		data := make([]byte, n)
+		copy(data, buf[:n])
+		chunk := &rpc.MSTArchiveChunk{Data: data}
+		if first {
+			// The server takes the keyspace from the first chunk
+			chunk.Keyspace = keyspace
+		}
+		err = stream.Send(chunk)
+		if err != nil {
+			// The server's error is returned by CloseAndRecv
+			break
+		}
+	}
+	return stream.CloseAndRecv()
+}
+
+// backupExt is the extension of the archive of each keyspace in a backup
+const backupExt = ".mst"
+
+// manifestFileName names the file of a backup that describes what's in it
+const manifestFileName = "MANIFEST.json"
+
+// backupManifest describes a backup: the catalog its archives were taken at, the keyspaces of the
+// catalog and the tables among them, so that it can be restored into a server that has none of
+// them. Each one is the JSON of its message.
+type backupManifest struct {
+	Catalog   json.RawMessage   `json:"catalog"`
+	Keyspaces []json.RawMessage `json:"keyspaces"`
+	Tables    []json.RawMessage `json:"tables"`
+}
+
+// backupContents is what a backup holds
+type backupContents struct {
+	catalog   *rpc.MSTCatalog
+	keyspaces []*rpc.MSTKeyspace
+	tables    []*rpc.TableInfo
+}
+
+func writeManifest(path string, contents backupContents) error {
+	manifest := backupManifest{
+		Keyspaces: make([]json.RawMessage, len(contents.keyspaces)),
+		Tables:    make([]json.RawMessage, len(contents.tables)),
+	}
+	var err error
+	manifest.Catalog, err = protojson.Marshal(contents.catalog)
+	for i := 0; err == nil && i < len(contents.keyspaces); i++ {
+		manifest.Keyspaces[i], err = protojson.Marshal(contents.keyspaces[i])
+	}
+	for i := 0; err == nil && i < len(contents.tables); i++ {
+		manifest.Tables[i], err = protojson.Marshal(contents.tables[i])
+	}
+	if err != nil {
+		return err
+	}
+	raw, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, append(raw, '\n'), 0644)
+}
+
+func readManifest(path string) (backupContents, error) {
+	raw, err := ioutil.ReadFile(path)
+	if err != nil {
+		return backupContents{}, err
+	}
+	manifest := backupManifest{}
+	err = json.Unmarshal(raw, &manifest)
+	if err != nil {
+		return backupContents{}, err
+	}
+	contents := backupContents{
+		catalog:   &rpc.MSTCatalog{},
+		keyspaces: make([]*rpc.MSTKeyspace, len(manifest.Keyspaces)),
+		tables:    make([]*rpc.TableInfo, len(manifest.Tables)),
+	}
+	err = protojson.Unmarshal(manifest.Catalog, contents.catalog)
+	for i := 0; err == nil && i < len(manifest.Keyspaces); i++ {
+		contents.keyspaces[i] = &rpc.MSTKeyspace{}
+		err = protojson.Unmarshal(manifest.Keyspaces[i], contents.keyspaces[i])
+	}
+	for i := 0; err == nil && i < len(manifest.Tables); i++ {
+		contents.tables[i] = &rpc.TableInfo{}
+		err = protojson.Unmarshal(manifest.Tables[i], contents.tables[i])
+	}
+	if err != nil {
+		return backupContents{}, err
+	}
+	return contents, nil
+}
+
+// backupDatabase writes an archive of every keyspace to a directory, named after the keyspace,
+// along with a manifest of the catalog, the keyspaces and the tables. All of them are taken at the
+// roots of a single catalog, which is returned.
+func backupDatabase(
+	client rpc.MSTServiceClient,
+	admin rpc.MSTAdminServiceClient,
+	tables rpc.TableServiceClient,
+	dir string,
+) (*rpc.MSTCatalog, error) {
+	err := os.MkdirAll(dir, 0755)
+	if err != nil {
+		return nil, err
+	}
+	// The snapshot keeps the trees around while they're archived
+	name := fmt.Sprintf("backup-%d", time.Now().UnixNano())
+	catalog, err := admin.CreateDatabaseSnapshot(
+		context.Background(),
+		&rpc.MSTDatabaseSnapshotRequest{Name: name},
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_, err := admin.DeleteDatabaseSnapshot(
+			context.Background(),
+			&rpc.MSTDatabaseSnapshotRequest{Name: name},
+		)
+		if err != nil {
+			log.Printf("Couldn't delete snapshot %s: %v", name, err)
+		}
+	}()
+	contents, err := catalogContents(admin, tables, catalog)
+	if err != nil {
+		return nil, err
+	}
+	for _, entry := range catalog.GetEntries() {
+		_, err = snapshotToFile(
+			client,
+			&rpc.MSTSnapshotRequest{Keyspace: entry.GetKeyspace(), Snapshot: name},
+			filepath.Join(dir, entry.GetKeyspace()+backupExt),
+		)
+		if err != nil {
+			return nil, fmt.Errorf("Couldn't back up keyspace %s: %w", entry.GetKeyspace(), err)
+		}
+	}
+	// The manifest is written last, so backups that weren't finished can't be restored
+	err = writeManifest(filepath.Join(dir, manifestFileName), contents)
+	if err != nil {
+		return nil, fmt.Errorf("Couldn't write manifest: %w", err)
+	}
+	return catalog, nil
+}
+
+// catalogContents returns the keyspaces of a catalog and the tables they belong to, as they are
+// on the server. Keyspaces that were dropped and tables that were altered since the catalog was
+// taken can't be described, so that's an error.
+func catalogContents(
+	admin rpc.MSTAdminServiceClient,
+	tables rpc.TableServiceClient,
+	catalog *rpc.MSTCatalog,
+) (backupContents, error) {
+	keyspaces, err := admin.ListKeyspaces(context.Background(), &empty.Empty{})
+	if err != nil {
+		return backupContents{}, err
+	}
+	listed, err := tables.ListTables(context.Background(), &empty.Empty{})
+	if err != nil {
+		return backupContents{}, err
+	}
+	specs := make(map[string]*rpc.MSTKeyspace, len(keyspaces.GetKeyspaces()))
+	for _, ks := range keyspaces.GetKeyspaces() {
+		specs[ks.GetName()] = ks
+	}
+	entries := make(map[string]*rpc.MSTCatalogEntry, len(catalog.GetEntries()))
+	contents := backupContents{catalog: catalog}
+	for _, entry := range catalog.GetEntries() {
+		spec, exists := specs[entry.GetKeyspace()]
+		if !exists {
+			return backupContents{}, fmt.Errorf(
+				"Keyspace %s was dropped during the backup",
+				entry.GetKeyspace(),
+			)
+		}
+		entries[entry.GetKeyspace()] = entry
+		contents.keyspaces = append(contents.keyspaces, spec)
+	}
+	for _, table := range listed.GetTables() {
+		entry, exists := entries[table.GetKeyspace()]
+		if !exists {
+			// The table was created during the backup
+			continue
+		} else if !bytes.Equal(entry.GetSchemaHash(), table.GetSchemaId()) {
+			return backupContents{}, fmt.Errorf(
+				"Table %s was altered during the backup",
+				table.GetName(),
+			)
+		}
+		contents.tables = append(contents.tables, table)
+	}
+	return contents, nil
+}
+
+// createTables creates the tables of a backup that a server doesn't have, with every version of
+// their schema so that they have the same schema IDs. Tables it does have need to have the same
+// schema.
+func createTables(tables rpc.TableServiceClient, backedUp []*rpc.TableInfo) error {
+	listed, err := tables.ListTables(context.Background(), &empty.Empty{})
+	if err != nil {
+		return err
+	}
+	existing := make(map[string]*rpc.TableInfo, len(listed.GetTables()))
+	for _, table := range listed.GetTables() {
+		existing[table.GetName()] = table
+	}
+	for _, table := range backedUp {
+		if current, exists := existing[table.GetName()]; exists {
+			if !bytes.Equal(current.GetSchemaId(), table.GetSchemaId()) {
+				return fmt.Errorf(
+					"Table %s has a different schema than in the backup",
+					table.GetName(),
+				)
+			}
+			continue
+		}
+		versions := table.GetVersions()
+		if len(versions) == 0 {
+			return fmt.Errorf("Table %s has no schema in the backup", table.GetName())
+		}
+		_, err = tables.CreateTable(
+			context.Background(),
+			&rpc.CreateTableRequest{Name: table.GetName(), Schema: versions[0]},
+		)
+		for _, version := range versions[1:] {
+			if err != nil {
+				break
+			}
+			_, err = tables.AlterTable(
+				context.Background(),
+				&rpc.AlterTableRequest{Table: table.GetName(), Schema: version},
+			)
+		}
+		if err != nil {
+			return fmt.Errorf("Couldn't create table %s: %w", table.GetName(), err)
+		}
+	}
+	return nil
+}
+
+// createKeyspaces creates the keyspaces of a backup that a server doesn't have. Keyspaces it does
+// have, including those of tables, need to be the same as in the backup.
+func createKeyspaces(admin rpc.MSTAdminServiceClient, backedUp []*rpc.MSTKeyspace) error {
+	listed, err := admin.ListKeyspaces(context.Background(), &empty.Empty{})
+	if err != nil {
+		return err
+	}
+	existing := make(map[string]*rpc.MSTKeyspace, len(listed.GetKeyspaces()))
+	for _, ks := range listed.GetKeyspaces() {
+		existing[ks.GetName()] = ks
+	}
+	for _, ks := range backedUp {
+		if current, exists := existing[ks.GetName()]; exists {
+			if !proto.Equal(current, ks) {
+				return fmt.Errorf("Keyspace %s is different than in the backup", ks.GetName())
+			}
+			continue
+		}
+		_, err = admin.CreateKeyspace(context.Background(), ks)
+		if err != nil {
+			return fmt.Errorf("Couldn't create keyspace %s: %w", ks.GetName(), err)
+		}
+	}
+	return nil
+}
+
+// restoreDatabase restores a backup, creating the tables and keyspaces of its manifest that are
+// missing first, and restoring every archive into the keyspace it's named after. Keyspaces that
+// were empty have to end up at the root they have in the backup, and if every keyspace was, the
+// server has to end up at the root of the backup's catalog. It returns the server's catalog after
+// the restore and the keyspaces that had data, which the backup was merged into.
+func restoreDatabase(
+	client rpc.MSTServiceClient,
+	admin rpc.MSTAdminServiceClient,
+	tables rpc.TableServiceClient,
+	dir string,
+) (*rpc.MSTCatalog, []string, error) {
+	contents, err := readManifest(filepath.Join(dir, manifestFileName))
+	if err != nil {
+		return nil, nil, fmt.Errorf("Couldn't read manifest: %w", err)
+	}
+	err = createTables(tables, contents.tables)
+	if err == nil {
+		err = createKeyspaces(admin, contents.keyspaces)
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	before, err := admin.Catalog(context.Background(), &rpc.MSTCatalogRequest{})
+	if err != nil {
+		return nil, nil, err
+	}
+	hadData := make(map[string]bool, len(before.GetEntries()))
+	for _, entry := range before.GetEntries() {
+		hadData[entry.GetKeyspace()] = len(entry.GetRootHash()) > 0
+	}
+	merged := []string{}
+	for _, entry := range contents.catalog.GetEntries() {
+		name := entry.GetKeyspace()
+		if len(entry.GetRootHash()) > 0 {
+			_, err = restoreFromFile(client, name, filepath.Join(dir, name+backupExt))
+			if err != nil {
+				return nil, nil, fmt.Errorf("Couldn't restore keyspace %s: %w", name, err)
+			}
+		}
+		if hadData[name] {
+			merged = append(merged, name)
+		}
+	}
+	after, err := admin.Catalog(context.Background(), &rpc.MSTCatalogRequest{})
+	if err != nil {
+		return nil, nil, err
+	}
+	err = verifyRestore(contents.catalog, after, hadData)
+	if err != nil {
+		return nil, nil, err
+	}
+	return after, merged, nil
+}
+
+// verifyRestore checks the catalog of a server that a backup was restored into against the
+// catalog of the backup
+func verifyRestore(
+	backedUp *rpc.MSTCatalog,
+	restored *rpc.MSTCatalog,
+	hadData map[string]bool,
+) error {
+	entries := make(map[string]*rpc.MSTCatalogEntry, len(restored.GetEntries()))
+	for _, entry := range restored.GetEntries() {
+		entries[entry.GetKeyspace()] = entry
+	}
+	exact := len(restored.GetEntries()) == len(backedUp.GetEntries())
+	for _, entry := range backedUp.GetEntries() {
+		name := entry.GetKeyspace()
+		current, exists := entries[name]
+		if !exists {
+			return fmt.Errorf("Keyspace %s is missing after the restore", name)
+		} else if !bytes.Equal(current.GetSchemaHash(), entry.GetSchemaHash()) {
+			return fmt.Errorf("Keyspace %s has a different schema than in the backup", name)
+		} else if hadData[name] {
+			exact = false
+		} else if !bytes.Equal(current.GetRootHash(), entry.GetRootHash()) {
+			return fmt.Errorf(
+				"Keyspace %s is at root %x instead of %x of the backup",
+				name,
+				current.GetRootHash(),
+				entry.GetRootHash(),
+			)
+		}
+	}
+	if exact && !bytes.Equal(restored.GetRootHash(), backedUp.GetRootHash()) {
+		return fmt.Errorf(
+			"Catalog is at root %x instead of %x of the backup",
+			restored.GetRootHash(),
+			backedUp.GetRootHash(),
+		)
+	}
+	return nil
+}
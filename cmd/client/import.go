@@ -0,0 +1,89 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/vulturedb/vulture/mst"
+	"github.com/vulturedb/vulture/service/rpc"
+)
+
+type importPair struct {
+	key    mst.Key
+	rawKey []byte
+	rawVal []byte
+}
+
+// readImportFile reads one "<key> <value>" pair per line of a file, sorted by key the way the
+// server sorts them
+func readImportFile(path string) ([]importPair, error) {
+	kr, err := mst.KeyReaderFor(*keyType)
+	if err != nil {
+		return nil, err
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	pairs := []importPair{}
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" {
+			continue
+		}
+		tokens := strings.SplitN(text, " ", 2)
+		if len(tokens) != 2 {
+			return nil, fmt.Errorf("Line %d doesn't have a key and a value", line)
+		}
+		rawKey, err := encodeKey(tokens[0])
+		if err != nil {
+			return nil, fmt.Errorf("Invalid key on line %d: %w", line, err)
+		}
+		key, err := kr.FromBytes(rawKey)
+		if err != nil {
+			return nil, fmt.Errorf("Invalid key on line %d: %w", line, err)
+		}
+		rawVal, err := encodeValue(tokens[1])
+		if err != nil {
+			return nil, fmt.Errorf("Invalid value on line %d: %w", line, err)
+		}
+		pairs = append(pairs, importPair{key, rawKey, rawVal})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	sort.SliceStable(pairs, func(i, j int) bool { return pairs[i].key.Less(pairs[j].key) })
+	return pairs, nil
+}
+
+// importFile bulk loads the pairs in a file into the server
+func importFile(client rpc.MSTServiceClient, path string) (*rpc.MSTImportResponse, error) {
+	pairs, err := readImportFile(path)
+	if err != nil {
+		return nil, err
+	}
+	stream, err := client.Import(context.Background())
+	if err != nil {
+		return nil, err
+	}
+	for i, pair := range pairs {
+		req := &rpc.MSTImportRequest{Key: pair.rawKey, Value: pair.rawVal}
+		if i == 0 {
+			// The server takes the keyspace from the first pair
+			req.Keyspace = *keyspace
+		}
+		err = stream.Send(req)
+		if err != nil {
+			// The server's error is returned by CloseAndRecv
+			break
+		}
+	}
+	return stream.CloseAndRecv()
+}
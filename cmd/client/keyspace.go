@@ -0,0 +1,76 @@
+package main
+
+import (
+	"context"
+	"crypto"
+	"encoding/hex"
+	"fmt"
+
+	"github.com/golang/protobuf/ptypes/empty"
+
+	"github.com/vulturedb/vulture/mst"
+	"github.com/vulturedb/vulture/service/rpc"
+)
+
+// useKeyspace makes every command use a keyspace, and reads and writes keys and values with its
+// types
+func useKeyspace(admin rpc.MSTAdminServiceClient, name string) error {
+	if name == "" {
+		name = mst.DefaultKeyspace
+	}
+	resp, err := admin.ListKeyspaces(context.Background(), &empty.Empty{})
+	if err != nil {
+		return err
+	}
+	for _, ks := range resp.GetKeyspaces() {
+		if ks.GetName() == name {
+			*keyspace = name
+			*keyType = ks.GetKeyType()
+			*valueType = ks.GetValueType()
+			treeBase = mst.Base(ks.GetBase())
+			treeHash = crypto.Hash(ks.GetHash())
+			return nil
+		}
+	}
+	return fmt.Errorf("No keyspace named %s", name)
+}
+
+func printKeyspaces(admin rpc.MSTAdminServiceClient) error {
+	resp, err := admin.ListKeyspaces(context.Background(), &empty.Empty{})
+	if err != nil {
+		return err
+	}
+	for _, ks := range resp.GetKeyspaces() {
+		current := " "
+		if ks.GetName() == *keyspace || (*keyspace == "" && ks.GetName() == mst.DefaultKeyspace) {
+			current = "*"
+		}
+		aggregator := ks.GetAggregator()
+		if aggregator == "" {
+			aggregator = "none"
+		}
+		fmt.Printf(
+			"%s %s: %s keys, %s values, base %d, %s, aggregator %s\n",
+			current,
+			ks.GetName(),
+			ks.GetKeyType(),
+			ks.GetValueType(),
+			1<<ks.GetBase(),
+			crypto.Hash(ks.GetHash()),
+			aggregator,
+		)
+	}
+	return nil
+}
+
+func printCatalog(catalog *rpc.MSTCatalog) {
+	fmt.Printf("catalog %s\n", hex.EncodeToString(catalog.GetRootHash()))
+	for _, entry := range catalog.GetEntries() {
+		fmt.Printf(
+			"%s: %s (schema %s)\n",
+			entry.GetKeyspace(),
+			hex.EncodeToString(entry.GetRootHash()),
+			hex.EncodeToString(entry.GetSchemaHash()),
+		)
+	}
+}
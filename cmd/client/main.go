@@ -2,7 +2,10 @@ package main
 
 import (
 	"bufio"
+	"bytes"
 	"context"
+	"crypto"
+	"encoding/hex"
 	"flag"
 	"fmt"
 	"io"
@@ -11,14 +14,60 @@ import (
 	"strconv"
 	"strings"
 
-	"github.com/vulturedb/vulture/service/rpc"
 	"google.golang.org/grpc"
+
+	"github.com/vulturedb/vulture/mst"
+	"github.com/vulturedb/vulture/service/rpc"
+	"github.com/vulturedb/vulture/service/server"
 )
 
 const usage string = `
 Available commands:
 put <key> <value>
 get <key>
+vget <key> [<root hash>]
+del <key>
+scan [<start> [<end>]]
+rscan [<start> [<end>]]
+pscan <prefix>
+count [<start> [<end>]]
+aggregate [<start> [<end>]]
+import <file>
+snapshot <file>
+restore <file>
+begin
+commit
+abort
+mksnap <name> [<root hash>]
+rmsnap <name>
+snaps
+history [<limit>]
+at [root <root hash> | snapshot <name>]
+query <query>
+keyspaces
+use [<keyspace>]
+mkks <keyspace> <key type> <value type> [<aggregator>]
+rmks <keyspace>
+catalog
+mkdbsnap <name>
+rmdbsnap <name>
+backup <dir>
+restoredb <dir>
+
+Puts and deletes between begin and commit are written as one batch. Files to import have one
+"<key> <value>" pair per line. Snapshots are archives of the whole tree, which restore merges back
+into a server. Aggregates sum up the values in a range if the server keeps aggregates. After at,
+get, scans and aggregates read the tree at a past root or a named snapshot until at is
+used without arguments. Queries select rows of tables, like
+SELECT name, age FROM users WHERE age >= 18 ORDER BY id DESC LIMIT 10
+Every command but query uses the default keyspace until use switches to another one. New
+keyspaces have the base and hash function of the default keyspace. The catalog holds the root of
+every keyspace under one root hash, with the keyspace of the rows of each table holding the ID of
+the latest version of the table's schema as its schema. Database snapshots and backups cover
+every keyspace at the roots of one catalog, and backups hold a manifest of the catalog, keyspaces
+and tables. restoredb creates the tables and keyspaces of a backup that are missing, restores each
+keyspace into the keyspace of the same name and checks that the ones that were empty end up at
+the roots of the backup's catalog.
 `
 
 func printReplUsage() {
@@ -27,6 +76,59 @@ func printReplUsage() {
 
 var host = flag.String("host", "localhost", "host to connect to")
 var port = flag.Int("port", 6667, "port to connect to")
+var keyType = flag.String("key-type", "uint32", "key type of the default keyspace")
+var valueType = flag.String("value-type", "uint32", "value type of the default keyspace")
+var keyspace = flag.String("keyspace", "", "keyspace to use, the default one if empty")
+
+// These have to match the tree on the server for proofs to verify, and are replaced by the ones
+// of the keyspace in use
+var treeHash = crypto.SHA256
+var treeBase = mst.Base16
+
+// verifyGet checks the proof in resp against rootHash and returns the proven value
+func verifyGet(
+	key []byte,
+	rootHash []byte,
+	resp *rpc.MSTGetWithProofResponse,
+) (mst.Value, error) {
+	kr, err := mst.KeyReaderFor(*keyType)
+	if err != nil {
+		return nil, err
+	}
+	vr, err := mst.ValueReaderFor(*valueType)
+	if err != nil {
+		return nil, err
+	}
+	mstKey, err := kr.FromBytes(key)
+	if err != nil {
+		return nil, err
+	}
+	proof := make(mst.Proof, 0, len(resp.GetProof()))
+	for _, rpcNode := range resp.GetProof() {
+		node, err := server.NodeFromRPC(rpcNode, kr, vr)
+		if err != nil {
+			return nil, err
+		}
+		proof = append(proof, node)
+	}
+	val, err := mst.VerifyProof(rootHash, mstKey, proof, treeHash, treeBase)
+	if err != nil {
+		return nil, err
+	}
+	if (val != nil) != resp.GetFound() {
+		return nil, fmt.Errorf("Proof doesn't match the returned value")
+	}
+	if val != nil {
+		raw, err := toBytes(val)
+		if err != nil {
+			return nil, err
+		}
+		if !bytes.Equal(raw, resp.GetValue()) {
+			return nil, fmt.Errorf("Proof doesn't match the returned value")
+		}
+	}
+	return val, nil
+}
 
 func main() {
 	flag.Parse()
@@ -40,9 +142,22 @@ func main() {
 	defer conn.Close()
 	fmt.Printf("Connected to %s\n", address)
 	client := rpc.NewMSTServiceClient(conn)
+	tableClient := rpc.NewTableServiceClient(conn)
+	adminClient := rpc.NewMSTAdminServiceClient(conn)
+	if *keyspace != "" {
+		err = useKeyspace(adminClient, *keyspace)
+		if err != nil {
+			log.Fatalf("Failed to use keyspace: %v", err)
+		}
+	}
 
 	// Repl loop
 	reader := bufio.NewReader(os.Stdin)
+	// Writes of the current batch, or nil if no batch was begun
+	var batch []*rpc.MSTWrite
+	// The version of the tree that get and scans read, the current one if both are empty
+	var atRoot []byte
+	var atSnapshot string
 	for true {
 		fmt.Printf("> ")
 		text, err := reader.ReadString('\n')
@@ -61,24 +176,29 @@ func main() {
 		}
 		switch tokens[0] {
 		case "put":
-			if len(tokens) != 3 {
+			if len(tokens) < 3 {
 				printReplUsage()
 				continue
 			}
-			rawKey, err := strconv.ParseUint(tokens[1], 10, 32)
+			key, err := encodeKey(tokens[1])
 			if err != nil {
 				printReplUsage()
 				continue
 			}
-			rawVal, err := strconv.ParseUint(tokens[2], 10, 32)
+			val, err := encodeValue(strings.Join(tokens[2:], " "))
 			if err != nil {
 				printReplUsage()
 				continue
 			}
+			if batch != nil {
+				batch = append(batch, &rpc.MSTWrite{Key: key, Value: val})
+				continue
+			}
 
 			_, err = client.Put(context.Background(), &rpc.MSTPutRequest{
-				Key:   uint32(rawKey),
-				Value: uint32(rawVal),
+				Key:      key,
+				Value:    val,
+				Keyspace: *keyspace,
 			})
 			if err != nil {
 				log.Fatalf("Error when putting: %v", err)
@@ -88,18 +208,478 @@ func main() {
 				printReplUsage()
 				continue
 			}
-			rawKey, err := strconv.ParseUint(tokens[1], 10, 32)
+			key, err := encodeKey(tokens[1])
 			if err != nil {
 				printReplUsage()
 				continue
 			}
-			resp, err := client.Get(context.Background(), &rpc.MSTGetRequest{Key: uint32(rawKey)})
+			resp, err := client.Get(context.Background(), &rpc.MSTGetRequest{
+				Key:      key,
+				RootHash: atRoot,
+				Snapshot: atSnapshot,
+				Keyspace: *keyspace,
+			})
 			if err != nil {
 				log.Fatalf("Error when getting: %v", err)
 			}
-			fmt.Printf("%d\n", resp.GetValue())
+			if !resp.GetFound() {
+				fmt.Println("not found")
+				continue
+			}
+			val, err := decodeValue(resp.GetValue())
+			if err != nil {
+				log.Fatalf("Error when getting: %v", err)
+			}
+			fmt.Println(val)
+		case "vget":
+			if len(tokens) != 2 && len(tokens) != 3 {
+				printReplUsage()
+				continue
+			}
+			key, err := encodeKey(tokens[1])
+			if err != nil {
+				printReplUsage()
+				continue
+			}
+			var rootHash []byte
+			if len(tokens) == 3 {
+				rootHash, err = hex.DecodeString(tokens[2])
+				if err != nil {
+					printReplUsage()
+					continue
+				}
+			}
+			resp, err := client.GetWithProof(
+				context.Background(),
+				&rpc.MSTGetWithProofRequest{Key: key, Keyspace: *keyspace},
+			)
+			if err != nil {
+				log.Fatalf("Error when getting with proof: %v", err)
+			}
+			if rootHash == nil {
+				// Without a trusted root this only checks that the server is consistent
+				rootHash = resp.GetRootHash()
+			} else if !bytes.Equal(rootHash, resp.GetRootHash()) {
+				fmt.Printf("note: server is at root %s\n", hex.EncodeToString(resp.GetRootHash()))
+			}
+			val, err := verifyGet(key, rootHash, resp)
+			if err != nil {
+				fmt.Printf("invalid proof: %v\n", err)
+				continue
+			}
+			if val == nil {
+				fmt.Printf("not found (root %s)\n", hex.EncodeToString(rootHash))
+			} else {
+				fmt.Printf("%s (root %s)\n", formatValue(val), hex.EncodeToString(rootHash))
+			}
+		case "del":
+			if len(tokens) != 2 {
+				printReplUsage()
+				continue
+			}
+			key, err := encodeKey(tokens[1])
+			if err != nil {
+				printReplUsage()
+				continue
+			}
+			if batch != nil {
+				batch = append(batch, &rpc.MSTWrite{Key: key, Delete: true})
+				continue
+			}
+			_, err = client.Delete(
+				context.Background(),
+				&rpc.MSTDeleteRequest{Key: key, Keyspace: *keyspace},
+			)
+			if err != nil {
+				log.Fatalf("Error when deleting: %v", err)
+			}
+		case "import":
+			if len(tokens) != 2 {
+				printReplUsage()
+				continue
+			}
+			resp, err := importFile(client, tokens[1])
+			if err != nil {
+				fmt.Printf("couldn't import: %v\n", err)
+				continue
+			}
+			fmt.Printf(
+				"imported %d pairs (root %s)\n",
+				resp.GetCount(),
+				hex.EncodeToString(resp.GetRootHash()),
+			)
+		case "snapshot":
+			if len(tokens) != 2 {
+				printReplUsage()
+				continue
+			}
+			written, err := snapshotToFile(
+				client,
+				&rpc.MSTSnapshotRequest{Keyspace: *keyspace},
+				tokens[1],
+			)
+			if err != nil {
+				fmt.Printf("couldn't snapshot: %v\n", err)
+				continue
+			}
+			fmt.Printf("wrote %d bytes\n", written)
+		case "restore":
+			if len(tokens) != 2 {
+				printReplUsage()
+				continue
+			}
+			resp, err := restoreFromFile(client, *keyspace, tokens[1])
+			if err != nil {
+				fmt.Printf("couldn't restore: %v\n", err)
+				continue
+			}
+			fmt.Printf("restored (root %s)\n", hex.EncodeToString(resp.GetRootHash()))
+		case "begin":
+			if len(tokens) != 1 || batch != nil {
+				printReplUsage()
+				continue
+			}
+			batch = []*rpc.MSTWrite{}
+		case "commit":
+			if len(tokens) != 1 || batch == nil {
+				printReplUsage()
+				continue
+			}
+			_, err = client.WriteBatch(
+				context.Background(),
+				&rpc.MSTWriteBatchRequest{Writes: batch, Keyspace: *keyspace},
+			)
+			if err != nil {
+				log.Fatalf("Error when writing batch: %v", err)
+			}
+			fmt.Printf("wrote %d writes\n", len(batch))
+			batch = nil
+		case "abort":
+			if len(tokens) != 1 || batch == nil {
+				printReplUsage()
+				continue
+			}
+			batch = nil
+		case "mksnap":
+			if len(tokens) != 2 && len(tokens) != 3 {
+				printReplUsage()
+				continue
+			}
+			req := &rpc.MSTCreateSnapshotRequest{Name: tokens[1], Keyspace: *keyspace}
+			if len(tokens) == 3 {
+				req.RootHash, err = hex.DecodeString(tokens[2])
+				if err != nil {
+					printReplUsage()
+					continue
+				}
+			}
+			resp, err := client.CreateSnapshot(context.Background(), req)
+			if err != nil {
+				fmt.Printf("couldn't create snapshot: %v\n", err)
+				continue
+			}
+			fmt.Printf(
+				"created %s (root %s)\n",
+				resp.GetName(),
+				hex.EncodeToString(resp.GetRootHash()),
+			)
+		case "rmsnap":
+			if len(tokens) != 2 {
+				printReplUsage()
+				continue
+			}
+			_, err = client.DeleteSnapshot(
+				context.Background(),
+				&rpc.MSTDeleteSnapshotRequest{Name: tokens[1], Keyspace: *keyspace},
+			)
+			if err != nil {
+				fmt.Printf("couldn't delete snapshot: %v\n", err)
+			}
+		case "snaps":
+			if len(tokens) != 1 {
+				printReplUsage()
+				continue
+			}
+			resp, err := client.ListSnapshots(
+				context.Background(),
+				&rpc.MSTListSnapshotsRequest{Keyspace: *keyspace},
+			)
+			if err != nil {
+				log.Fatalf("Error when listing snapshots: %v", err)
+			}
+			for _, snapshot := range resp.GetSnapshots() {
+				fmt.Printf(
+					"%s: %s (%s)\n",
+					snapshot.GetName(),
+					hex.EncodeToString(snapshot.GetRootHash()),
+					formatTimestamp(snapshot.GetCreated()),
+				)
+			}
+		case "history":
+			if len(tokens) > 2 {
+				printReplUsage()
+				continue
+			}
+			req := &rpc.MSTRootHistoryRequest{Keyspace: *keyspace}
+			if len(tokens) == 2 {
+				limit, err := strconv.ParseUint(tokens[1], 10, 32)
+				if err != nil {
+					printReplUsage()
+					continue
+				}
+				req.Limit = uint32(limit)
+			}
+			resp, err := client.RootHistory(context.Background(), req)
+			if err != nil {
+				log.Fatalf("Error when getting root history: %v", err)
+			}
+			for _, entry := range resp.GetEntries() {
+				fmt.Printf(
+					"%s: %s\n",
+					formatTimestamp(entry.GetTime()),
+					hex.EncodeToString(entry.GetRootHash()),
+				)
+			}
+		case "at":
+			if len(tokens) == 1 {
+				atRoot, atSnapshot = nil, ""
+				continue
+			} else if len(tokens) != 3 {
+				printReplUsage()
+				continue
+			}
+			switch tokens[1] {
+			case "root":
+				root, err := hex.DecodeString(tokens[2])
+				if err != nil {
+					printReplUsage()
+					continue
+				}
+				atRoot, atSnapshot = root, ""
+			case "snapshot":
+				atRoot, atSnapshot = nil, tokens[2]
+			default:
+				printReplUsage()
+			}
+		case "scan", "rscan":
+			bounds, ok := parseBounds(tokens[1:])
+			if !ok {
+				printReplUsage()
+				continue
+			}
+			printScan(client, &rpc.MSTScanRequest{
+				Start:    bounds[0],
+				End:      bounds[1],
+				Reverse:  tokens[0] == "rscan",
+				RootHash: atRoot,
+				Snapshot: atSnapshot,
+				Keyspace: *keyspace,
+			})
+		case "pscan":
+			if len(tokens) != 2 {
+				printReplUsage()
+				continue
+			}
+			prefix, err := encodeKey(tokens[1])
+			if err != nil {
+				printReplUsage()
+				continue
+			}
+			printScan(client, &rpc.MSTScanRequest{
+				Prefix:   prefix,
+				RootHash: atRoot,
+				Snapshot: atSnapshot,
+				Keyspace: *keyspace,
+			})
+		case "count", "aggregate":
+			bounds, ok := parseBounds(tokens[1:])
+			if !ok {
+				printReplUsage()
+				continue
+			}
+			summary, err := client.Aggregate(context.Background(), &rpc.MSTAggregateRequest{
+				Start:    bounds[0],
+				End:      bounds[1],
+				RootHash: atRoot,
+				Snapshot: atSnapshot,
+				Keyspace: *keyspace,
+			})
+			if err != nil {
+				fmt.Printf("couldn't aggregate: %v\n", err)
+			} else if tokens[0] == "count" {
+				fmt.Println(summary.GetCount())
+			} else {
+				printSummary(summary)
+			}
+		case "query":
+			if len(tokens) < 2 {
+				printReplUsage()
+				continue
+			}
+			if err := runQuery(tableClient, strings.Join(tokens[1:], " ")); err != nil {
+				fmt.Printf("couldn't query: %v\n", err)
+			}
+		case "keyspaces":
+			if len(tokens) != 1 {
+				printReplUsage()
+				continue
+			}
+			if err := printKeyspaces(adminClient); err != nil {
+				log.Fatalf("Error when listing keyspaces: %v", err)
+			}
+		case "use":
+			if len(tokens) > 2 || batch != nil {
+				printReplUsage()
+				continue
+			}
+			name := ""
+			if len(tokens) == 2 {
+				name = tokens[1]
+			}
+			if err := useKeyspace(adminClient, name); err != nil {
+				fmt.Printf("couldn't use keyspace: %v\n", err)
+				continue
+			}
+			// Roots and snapshots belong to the previous keyspace
+			atRoot, atSnapshot = nil, ""
+		case "mkks":
+			if len(tokens) != 4 && len(tokens) != 5 {
+				printReplUsage()
+				continue
+			}
+			req := &rpc.MSTKeyspace{Name: tokens[1], KeyType: tokens[2], ValueType: tokens[3]}
+			if len(tokens) == 5 {
+				req.Aggregator = tokens[4]
+			}
+			_, err = adminClient.CreateKeyspace(context.Background(), req)
+			if err != nil {
+				fmt.Printf("couldn't create keyspace: %v\n", err)
+			}
+		case "rmks":
+			if len(tokens) != 2 {
+				printReplUsage()
+				continue
+			}
+			_, err = adminClient.DropKeyspace(
+				context.Background(),
+				&rpc.MSTDropKeyspaceRequest{Name: tokens[1]},
+			)
+			if err != nil {
+				fmt.Printf("couldn't drop keyspace: %v\n", err)
+			}
+		case "catalog":
+			if len(tokens) != 1 {
+				printReplUsage()
+				continue
+			}
+			resp, err := adminClient.Catalog(context.Background(), &rpc.MSTCatalogRequest{})
+			if err != nil {
+				log.Fatalf("Error when getting catalog: %v", err)
+			}
+			printCatalog(resp)
+		case "mkdbsnap":
+			if len(tokens) != 2 {
+				printReplUsage()
+				continue
+			}
+			resp, err := adminClient.CreateDatabaseSnapshot(
+				context.Background(),
+				&rpc.MSTDatabaseSnapshotRequest{Name: tokens[1]},
+			)
+			if err != nil {
+				fmt.Printf("couldn't create snapshot: %v\n", err)
+				continue
+			}
+			printCatalog(resp)
+		case "rmdbsnap":
+			if len(tokens) != 2 {
+				printReplUsage()
+				continue
+			}
+			_, err = adminClient.DeleteDatabaseSnapshot(
+				context.Background(),
+				&rpc.MSTDatabaseSnapshotRequest{Name: tokens[1]},
+			)
+			if err != nil {
+				fmt.Printf("couldn't delete snapshot: %v\n", err)
+			}
+		case "backup":
+			if len(tokens) != 2 {
+				printReplUsage()
+				continue
+			}
+			resp, err := backupDatabase(client, adminClient, tableClient, tokens[1])
+			if err != nil {
+				fmt.Printf("couldn't back up: %v\n", err)
+				continue
+			}
+			printCatalog(resp)
+		case "restoredb":
+			if len(tokens) != 2 {
+				printReplUsage()
+				continue
+			}
+			resp, merged, err := restoreDatabase(client, adminClient, tableClient, tokens[1])
+			if err != nil {
+				fmt.Printf("couldn't restore: %v\n", err)
+				continue
+			}
+			printCatalog(resp)
+			if len(merged) > 0 {
+				fmt.Printf("merged into %s, which already had data\n", strings.Join(merged, ", "))
+			}
 		default:
 			printReplUsage()
 		}
 	}
 }
+
+// parseBounds encodes the optional start and end keys of a range
+func parseBounds(tokens []string) ([2][]byte, bool) {
+	bounds := [2][]byte{}
+	if len(tokens) > 2 {
+		return bounds, false
+	}
+	for i, token := range tokens {
+		var err error
+		bounds[i], err = encodeKey(token)
+		if err != nil {
+			return bounds, false
+		}
+	}
+	return bounds, true
+}
+
+func printSummary(summary *rpc.MSTSummary) {
+	fmt.Printf("count: %d\n", summary.GetCount())
+	if summary.GetMeasured() == 0 {
+		return
+	}
+	fmt.Printf("sum: %g\n", summary.GetSum())
+	fmt.Printf("min: %g\n", summary.GetMin())
+	fmt.Printf("max: %g\n", summary.GetMax())
+}
+
+func printScan(client rpc.MSTServiceClient, req *rpc.MSTScanRequest) {
+	stream, err := client.Scan(context.Background(), req)
+	if err != nil {
+		log.Fatalf("Error when scanning: %v", err)
+	}
+	for {
+		resp, err := stream.Recv()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			log.Fatalf("Error when scanning: %v", err)
+		}
+		key, err := decodeKey(resp.GetKey())
+		if err != nil {
+			log.Fatalf("Error when scanning: %v", err)
+		}
+		val, err := decodeValue(resp.GetValue())
+		if err != nil {
+			log.Fatalf("Error when scanning: %v", err)
+		}
+		fmt.Printf("%s: %s\n", key, val)
+	}
+}
@@ -0,0 +1,141 @@
+package main
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/golang/protobuf/ptypes"
+	"github.com/google/uuid"
+
+	"github.com/vulturedb/vulture/service/rpc"
+)
+
+// nullText is shown for null fields
+const nullText = "NULL"
+
+// formatTableValue formats a field of a row for printing
+func formatTableValue(v *rpc.TableValue) string {
+	switch kind := v.GetKind().(type) {
+	case *rpc.TableValue_StringValue:
+		return kind.StringValue
+	case *rpc.TableValue_IntValue:
+		return strconv.FormatInt(int64(kind.IntValue), 10)
+	case *rpc.TableValue_LongValue:
+		return strconv.FormatInt(kind.LongValue, 10)
+	case *rpc.TableValue_FloatValue:
+		return strconv.FormatFloat(float64(kind.FloatValue), 'g', -1, 32)
+	case *rpc.TableValue_DoubleValue:
+		return strconv.FormatFloat(kind.DoubleValue, 'g', -1, 64)
+	case *rpc.TableValue_BooleanValue:
+		return strconv.FormatBool(kind.BooleanValue)
+	case *rpc.TableValue_BytesValue:
+		return hex.EncodeToString(kind.BytesValue)
+	case *rpc.TableValue_TimestampValue:
+		t, err := ptypes.Timestamp(kind.TimestampValue)
+		if err != nil {
+			return "invalid time"
+		}
+		return t.Format(time.RFC3339Nano)
+	case *rpc.TableValue_UuidValue:
+		id, err := uuid.FromBytes(kind.UuidValue)
+		if err != nil {
+			return "invalid uuid"
+		}
+		return id.String()
+	case *rpc.TableValue_DecimalValue:
+		return kind.DecimalValue
+	case *rpc.TableValue_RecordValue:
+		fields := kind.RecordValue.GetFields()
+		names := make([]string, 0, len(fields))
+		for name := range fields {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		formatted := make([]string, len(names))
+		for i, name := range names {
+			formatted[i] = fmt.Sprintf("%s: %s", name, formatTableValue(fields[name]))
+		}
+		return "{" + strings.Join(formatted, ", ") + "}"
+	case *rpc.TableValue_ArrayValue:
+		items := kind.ArrayValue.GetItems()
+		formatted := make([]string, len(items))
+		for i, item := range items {
+			formatted[i] = formatTableValue(item)
+		}
+		return "[" + strings.Join(formatted, ", ") + "]"
+	default:
+		return nullText
+	}
+}
+
+// printTable prints rows under a header of columns, with every column as wide as its widest value
+func printTable(columns []string, rows [][]string) {
+	widths := make([]int, len(columns))
+	for i, column := range columns {
+		widths[i] = utf8.RuneCountInString(column)
+	}
+	for _, row := range rows {
+		for i, text := range row {
+			if n := utf8.RuneCountInString(text); n > widths[i] {
+				widths[i] = n
+			}
+		}
+	}
+	printRow := func(row []string) {
+		padded := make([]string, len(row))
+		for i, text := range row {
+			padded[i] = text + strings.Repeat(" ", widths[i]-utf8.RuneCountInString(text))
+		}
+		fmt.Println(strings.TrimRight(strings.Join(padded, " | "), " "))
+	}
+	printRow(columns)
+	separators := make([]string, len(columns))
+	for i, width := range widths {
+		separators[i] = strings.Repeat("-", width)
+	}
+	fmt.Println(strings.Join(separators, "-+-"))
+	for _, row := range rows {
+		printRow(row)
+	}
+	fmt.Printf("(%d rows)\n", len(rows))
+}
+
+// runQuery runs a query on the server and prints its result as a table
+func runQuery(client rpc.TableServiceClient, text string) error {
+	stream, err := client.Query(context.Background(), &rpc.QueryRequest{Query: text})
+	if err != nil {
+		return err
+	}
+	var columns []string
+	rows := [][]string{}
+	for {
+		resp, err := stream.Recv()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return err
+		}
+		if resp.GetRow() == nil {
+			columns = resp.GetColumns()
+			continue
+		}
+		fields := resp.GetRow().GetFields()
+		row := make([]string, len(columns))
+		for i, column := range columns {
+			row[i] = nullText
+			if v, ok := fields[column]; ok {
+				row[i] = formatTableValue(v)
+			}
+		}
+		rows = append(rows, row)
+	}
+	printTable(columns, rows)
+	return nil
+}
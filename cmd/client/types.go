@@ -0,0 +1,124 @@
+package main
+
+import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/golang/protobuf/ptypes"
+	"github.com/golang/protobuf/ptypes/timestamp"
+
+	"github.com/vulturedb/vulture/mst"
+)
+
+func toBytes(w mst.Writable) ([]byte, error) {
+	buf := new(bytes.Buffer)
+	err := w.Write(buf)
+	if err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// encodeKey parses a key typed into the repl and encodes it the way the server reads keys
+func encodeKey(text string) ([]byte, error) {
+	var key mst.Key
+	switch *keyType {
+	case "uint32":
+		raw, err := strconv.ParseUint(text, 10, 32)
+		if err != nil {
+			return nil, err
+		}
+		key = mst.UInt32(raw)
+	case "int64":
+		raw, err := strconv.ParseInt(text, 10, 64)
+		if err != nil {
+			return nil, err
+		}
+		key = mst.Int64(raw)
+	case "string":
+		key = mst.String(text)
+	case "bytes":
+		raw, err := hex.DecodeString(text)
+		if err != nil {
+			return nil, err
+		}
+		key = mst.Bytes(raw)
+	default:
+		return nil, fmt.Errorf("Unknown key type %s", *keyType)
+	}
+	return toBytes(key)
+}
+
+// encodeValue parses a value typed into the repl and encodes it the way the server reads values.
+// Last-writer-wins values are timestamped with the local clock.
+func encodeValue(text string) ([]byte, error) {
+	var val mst.Value
+	switch *valueType {
+	case "uint32":
+		raw, err := strconv.ParseUint(text, 10, 32)
+		if err != nil {
+			return nil, err
+		}
+		val = mst.UInt32(raw)
+	case "lww":
+		val = mst.NewLWWBytes(uint64(time.Now().UnixNano()), []byte(text))
+	default:
+		return nil, fmt.Errorf("Unknown value type %s", *valueType)
+	}
+	return toBytes(val)
+}
+
+func formatTimestamp(ts *timestamp.Timestamp) string {
+	t, err := ptypes.Timestamp(ts)
+	if err != nil {
+		return "invalid time"
+	}
+	return t.Local().Format("2006-01-02 15:04:05.000")
+}
+
+func formatKey(key mst.Key) string {
+	switch k := key.(type) {
+	case mst.Bytes:
+		return hex.EncodeToString(k)
+	case mst.String:
+		return string(k)
+	default:
+		return fmt.Sprintf("%v", k)
+	}
+}
+
+func formatValue(val mst.Value) string {
+	switch v := val.(type) {
+	case mst.LWWBytes:
+		return string(v.Value())
+	default:
+		return fmt.Sprintf("%v", v)
+	}
+}
+
+func decodeKey(raw []byte) (string, error) {
+	kr, err := mst.KeyReaderFor(*keyType)
+	if err != nil {
+		return "", err
+	}
+	key, err := kr.FromBytes(raw)
+	if err != nil {
+		return "", err
+	}
+	return formatKey(key), nil
+}
+
+func decodeValue(raw []byte) (string, error) {
+	vr, err := mst.ValueReaderFor(*valueType)
+	if err != nil {
+		return "", err
+	}
+	val, err := vr.FromBytes(raw)
+	if err != nil {
+		return "", err
+	}
+	return formatValue(val), nil
+}
@@ -3,35 +3,42 @@ package main
 import (
 	// "context"
 	"crypto"
-	"encoding/binary"
 	"flag"
 	"fmt"
 	"log"
 	"net"
+	"path/filepath"
+	"time"
 
 	// mh "github.com/multiformats/go-multihash"
 	"google.golang.org/grpc"
 
-	// "github.com/vulturedb/vulture/ipfs"
+	"github.com/vulturedb/vulture/disk"
+	"github.com/vulturedb/vulture/ipfs"
 	"github.com/vulturedb/vulture/mst"
 	"github.com/vulturedb/vulture/service/rpc"
 	"github.com/vulturedb/vulture/service/server"
 )
 
-type UInt32KeyReader struct{}
-
-func (kr UInt32KeyReader) FromBytes(b []byte) (mst.Key, error) {
-	return mst.UInt32(binary.LittleEndian.Uint32(b)), nil
-}
-
-type UInt32ValueReader struct{}
-
-func (kr UInt32ValueReader) FromBytes(b []byte) (mst.Value, error) {
-	return mst.UInt32(binary.LittleEndian.Uint32(b)), nil
-}
-
 var host = flag.String("host", "0.0.0.0", "post to serve API for")
 var port = flag.Int("port", 6667, "port to serve API on")
+var keyType = flag.String("key-type", "uint32", "key type, one of uint32, int64, string or bytes")
+var valueType = flag.String("value-type", "uint32", "value type, either uint32 or lww")
+var dataDir = flag.String("data-dir", "", "directory to keep data in, in memory if empty")
+var gcInterval = flag.Duration("gc-interval", time.Minute, "how often to collect garbage, or 0")
+var antiEntropyInterval = flag.Duration(
+	"anti-entropy-interval",
+	time.Minute,
+	"how often to compare catalogs with the other servers, or 0",
+)
+var replica = flag.String("replica", "", "unique name of this server, its address if empty")
+var aggregator = flag.String(
+	"aggregator",
+	"",
+	"aggregator the default keyspace keeps range aggregates with, uint32 or none if empty. Has "+
+		"to match the data directory and the other servers",
+)
+var history = flag.Duration("history", 10*time.Minute, "how long past roots can still be read")
 
 // Temporary
 var otherHost = flag.String("other-host", "localhost", "host of other server")
@@ -49,20 +56,74 @@ func main() {
 	// 	log.Fatalf("Failed to create ipfs node: %v", err)
 	// }
 	// log.Printf("IPFS node is running")
-	// ipfs.RegisterTypes()
-	store := mst.NewLocalNodeStore(crypto.SHA256)
-	tree := mst.NewMST(mst.Base16, crypto.SHA256, store)
+	ipfs.RegisterTypes()
+	defaultKeyspace := mst.Keyspace{
+		Name:       mst.DefaultKeyspace,
+		Base:       mst.Base16,
+		Hash:       crypto.SHA256,
+		KeyType:    *keyType,
+		ValueType:  *valueType,
+		Aggregator: *aggregator,
+	}
+	err := defaultKeyspace.Validate()
+	if err != nil {
+		log.Fatalf("Invalid default keyspace: %v", err)
+	}
+
+	var storage server.KeyspaceStorage
+	var keyspaces mst.KeyspaceStore
+	var tables server.TableStore
+	schemaDir := ""
+	if *dataDir == "" {
+		storage = server.MemoryStorage{}
+		keyspaces = &server.MemoryKeyspaceStore{}
+		tables = &server.MemoryTableStore{}
+	} else {
+		schemaDir = filepath.Join(*dataDir, "schemas")
+		storage = disk.NewStorage(*dataDir)
+		keyspaces, err = disk.NewKeyspaceStore(*dataDir)
+		if err != nil {
+			log.Fatalf("Failed to open keyspace store: %v", err)
+		}
+		tables, err = disk.NewTableStore(*dataDir)
+		if err != nil {
+			log.Fatalf("Failed to open table store: %v", err)
+		}
+		log.Printf("Opened data directory %s", *dataDir)
+	}
 	peers := server.NewPeers(&server.SelectAll{})
 	peers.Add(*otherHost, *otherPort)
-	mstServer := server.NewMSTServer(tree, peers)
-	managerServer := server.NewMSTManagerServer(
+	mstServer, err := server.NewMSTServer(defaultKeyspace, storage, keyspaces, *history, peers)
+	if err != nil {
+		log.Fatalf("Failed to create server: %v", err)
+	}
+	managerServer := server.NewMSTManagerServer(mstServer)
+	address := fmt.Sprintf("%s:%d", *host, *port)
+	if *replica == "" {
+		*replica = address
+	}
+	schemaDAG, err := ipfs.NewLocalDAG(schemaDir)
+	if err != nil {
+		log.Fatalf("Failed to open schema store: %v", err)
+	}
+	tableServer, err := server.NewTableServer(
 		mstServer,
-		UInt32KeyReader{},
-		UInt32ValueReader{},
+		*replica,
+		ipfs.NewSchemaStore(schemaDAG),
+		tables,
 	)
+	if err != nil {
+		log.Fatalf("Failed to open tables: %v", err)
+	}
+
+	if *gcInterval > 0 {
+		go managerServer.RunGarbageCollection(*gcInterval)
+	}
+	if *antiEntropyInterval > 0 {
+		go mstServer.RunAntiEntropy(*antiEntropyInterval)
+	}
 
 	// Start the grpc server
-	address := fmt.Sprintf("%s:%d", *host, *port)
 	lis, err := net.Listen("tcp", address)
 	if err != nil {
 		log.Fatalf("Failed to listen: %v", err)
@@ -71,7 +132,9 @@ func main() {
 
 	grpcServer := grpc.NewServer()
 	rpc.RegisterMSTServiceServer(grpcServer, mstServer)
+	rpc.RegisterMSTAdminServiceServer(grpcServer, mstServer)
 	rpc.RegisterMSTManagerServiceServer(grpcServer, managerServer)
+	rpc.RegisterTableServiceServer(grpcServer, tableServer)
 	err = grpcServer.Serve(lis)
 	if err != nil {
 		log.Fatalf("Failed to serve: %v", err)
@@ -0,0 +1,62 @@
+package core
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/vulturedb/vulture/mst"
+)
+
+// FieldAggregator is an mst.Aggregator that measures one numeric field of RowValues. Rows where
+// the field is null, or isn't a number, aren't measured. Decimals are measured as the nearest
+// float64. Keyspaces name it "field:<field>", see mst.AggregatorFor.
+type FieldAggregator struct {
+	Field string
+}
+
+func init() {
+	mst.RegisterAggregator("field", func(field string) (mst.Aggregator, error) {
+		if field == "" {
+			return nil, fmt.Errorf("Field aggregator needs a field")
+		}
+		return FieldAggregator{field}, nil
+	})
+}
+
+func (a FieldAggregator) Name() string {
+	return "field:" + a.Field
+}
+
+func (a FieldAggregator) Measure(v mst.Value) (float64, bool) {
+	row, ok := v.(RowValue)
+	if !ok {
+		return 0, false
+	}
+	state, ok := row.fields[a.Field]
+	if !ok {
+		return 0, false
+	}
+	val, ok := state.value()
+	if !ok {
+		return 0, false
+	}
+	switch n := val.(type) {
+	case int32:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case float32:
+		return float64(n), true
+	case float64:
+		return n, true
+	case Decimal:
+		f, _ := new(big.Rat).SetFrac(n.Unscaled, new(big.Int).Exp(
+			big.NewInt(10),
+			big.NewInt(int64(n.Scale)),
+			nil,
+		)).Float64()
+		return f, true
+	default:
+		return 0, false
+	}
+}
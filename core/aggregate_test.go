@@ -0,0 +1,62 @@
+package core
+
+import (
+	"crypto"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/vulturedb/vulture/mst"
+)
+
+func TestFieldAggregator(t *testing.T) {
+	s := countersSchema()
+	tree := mst.NewLocalMST(mst.Base4, crypto.SHA256)
+	tree = tree.WithAggregator(FieldAggregator{"balance"})
+	for i, fields := range []map[string]interface{}{
+		{"id": "a", "balance": int64(10), "low": NewDecimal(big.NewInt(-15), 1)},
+		{"id": "b", "balance": int64(-4), "low": NewDecimal(big.NewInt(25), 1)},
+		{"id": "c", "balance": int64(7)},
+	} {
+		row := account(fields)
+		v, err := NewRowValue().Update(s, row, HLC{uint64(i + 1), 0, "a"})
+		require.NoError(t, err)
+		key, err := s.PrimaryKeyOf(row)
+		require.NoError(t, err)
+		tree, err = tree.Put(key, v)
+		require.NoError(t, err)
+	}
+	summary, err := tree.Aggregate(nil, nil)
+	require.NoError(t, err)
+	assert.Equal(t, mst.Summary{Count: 3, Measured: 3, Sum: 13, Min: -4, Max: 10}, summary)
+
+	low := FieldAggregator{"low"}
+	b, err := tree.Get(mustPrimaryKey(t, s, "b"))
+	require.NoError(t, err)
+	measured, ok := low.Measure(b)
+	assert.True(t, ok)
+	assert.Equal(t, 2.5, measured)
+	_, ok = FieldAggregator{"name"}.Measure(b)
+	assert.False(t, ok)
+	_, ok = FieldAggregator{"tags"}.Measure(b)
+	assert.False(t, ok)
+}
+
+func TestFieldAggregatorFor(t *testing.T) {
+	a, err := mst.AggregatorFor("field:balance")
+	require.NoError(t, err)
+	assert.Equal(t, FieldAggregator{"balance"}, a)
+	assert.Equal(t, "field:balance", a.Name())
+	_, err = mst.AggregatorFor("field:")
+	assert.Error(t, err)
+	_, err = mst.AggregatorFor("sum:balance")
+	assert.Error(t, err)
+}
+
+func mustPrimaryKey(t *testing.T, s Schema, id string) PrimaryKey {
+	key, err := s.PrimaryKeyOf(Row{Data: map[string]interface{}{"id": id}})
+	require.NoError(t, err)
+	return key
+}
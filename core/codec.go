@@ -0,0 +1,300 @@
+package core
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+	"math/big"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Every encoded field value starts with a tag for its type, so rows can be decoded without their
+// schema
+const (
+	tagString byte = iota + 1
+	tagInt
+	tagLong
+	tagFloat
+	tagDouble
+	tagBoolean
+	tagBytes
+	tagTimestamp
+	tagUUID
+	tagDecimal
+	tagArray
+	tagRecord
+)
+
+func putLength(w *bytes.Buffer, n int) {
+	buf := make([]byte, 4)
+	binary.LittleEndian.PutUint32(buf, uint32(n))
+	w.Write(buf)
+}
+
+func getLength(r *bytes.Reader) (int, error) {
+	buf := make([]byte, 4)
+	if _, err := io.ReadFull(r, buf); err != nil {
+		return 0, err
+	}
+	n := int(binary.LittleEndian.Uint32(buf))
+	if n > r.Len() {
+		return 0, fmt.Errorf("Encoded length %d is longer than the remaining %d bytes", n, r.Len())
+	}
+	return n, nil
+}
+
+func getBytes(r *bytes.Reader) ([]byte, error) {
+	n, err := getLength(r)
+	if err != nil {
+		return nil, err
+	}
+	buf := make([]byte, n)
+	if _, err := io.ReadFull(r, buf); err != nil {
+		return nil, err
+	}
+	return buf, nil
+}
+
+// Timestamps are stored as nanoseconds since the epoch, which covers the years 1678 to 2262
+var (
+	minTimestamp = time.Unix(0, math.MinInt64)
+	maxTimestamp = time.Unix(0, math.MaxInt64)
+)
+
+func timestampNanos(t time.Time) (int64, error) {
+	if t.Before(minTimestamp) || t.After(maxTimestamp) {
+		return 0, fmt.Errorf("Timestamp %s is out of range", t)
+	}
+	return t.UnixNano(), nil
+}
+
+func encodeField(w *bytes.Buffer, v interface{}) error {
+	buf := make([]byte, 8)
+	switch val := v.(type) {
+	case string:
+		w.WriteByte(tagString)
+		putLength(w, len(val))
+		w.WriteString(val)
+	case int32:
+		w.WriteByte(tagInt)
+		binary.LittleEndian.PutUint32(buf, uint32(val))
+		w.Write(buf[:4])
+	case int64:
+		w.WriteByte(tagLong)
+		binary.LittleEndian.PutUint64(buf, uint64(val))
+		w.Write(buf)
+	case float32:
+		w.WriteByte(tagFloat)
+		binary.LittleEndian.PutUint32(buf, math.Float32bits(val))
+		w.Write(buf[:4])
+	case float64:
+		w.WriteByte(tagDouble)
+		binary.LittleEndian.PutUint64(buf, math.Float64bits(val))
+		w.Write(buf)
+	case bool:
+		w.WriteByte(tagBoolean)
+		if val {
+			w.WriteByte(1)
+		} else {
+			w.WriteByte(0)
+		}
+	case []byte:
+		w.WriteByte(tagBytes)
+		putLength(w, len(val))
+		w.Write(val)
+	case time.Time:
+		nanos, err := timestampNanos(val)
+		if err != nil {
+			return err
+		}
+		w.WriteByte(tagTimestamp)
+		binary.LittleEndian.PutUint64(buf, uint64(nanos))
+		w.Write(buf)
+	case uuid.UUID:
+		w.WriteByte(tagUUID)
+		w.Write(val[:])
+	case Decimal:
+		if val.Unscaled == nil {
+			return fmt.Errorf("Decimal has no value")
+		}
+		w.WriteByte(tagDecimal)
+		binary.LittleEndian.PutUint32(buf, val.Scale)
+		w.Write(buf[:4])
+		// The sign is kept apart from the magnitude since big.Int only encodes the latter
+		w.WriteByte(byte(val.Unscaled.Sign() + 1))
+		magnitude := val.Unscaled.Bytes()
+		putLength(w, len(magnitude))
+		w.Write(magnitude)
+	case []interface{}:
+		w.WriteByte(tagArray)
+		putLength(w, len(val))
+		for i, item := range val {
+			if err := encodeField(w, item); err != nil {
+				return fmt.Errorf("Couldn't encode item %d: %w", i, err)
+			}
+		}
+	case Row:
+		w.WriteByte(tagRecord)
+		return encodeRow(w, val)
+	default:
+		return fmt.Errorf("Unsupported field value %v of type %T", v, v)
+	}
+	return nil
+}
+
+func decodeField(r *bytes.Reader) (interface{}, error) {
+	tag, err := r.ReadByte()
+	if err != nil {
+		return nil, err
+	}
+	switch tag {
+	case tagString:
+		buf, err := getBytes(r)
+		if err != nil {
+			return nil, err
+		}
+		return string(buf), nil
+	case tagBytes:
+		return getBytes(r)
+	case tagDecimal:
+		buf := make([]byte, 4)
+		if _, err := io.ReadFull(r, buf); err != nil {
+			return nil, err
+		}
+		scale := binary.LittleEndian.Uint32(buf)
+		sign, err := r.ReadByte()
+		if err != nil {
+			return nil, err
+		} else if sign > 2 {
+			return nil, fmt.Errorf("Invalid decimal sign %d", sign)
+		}
+		magnitude, err := getBytes(r)
+		if err != nil {
+			return nil, err
+		}
+		unscaled := new(big.Int).SetBytes(magnitude)
+		if sign == 0 {
+			unscaled.Neg(unscaled)
+		}
+		return Decimal{unscaled, scale}, nil
+	case tagArray:
+		numItems, err := getLength(r)
+		if err != nil {
+			return nil, err
+		}
+		items := make([]interface{}, numItems)
+		for i := range items {
+			items[i], err = decodeField(r)
+			if err != nil {
+				return nil, fmt.Errorf("Couldn't decode item %d: %w", i, err)
+			}
+		}
+		return items, nil
+	case tagRecord:
+		return decodeRow(r)
+	}
+
+	var size int
+	switch tag {
+	case tagInt, tagFloat:
+		size = 4
+	case tagLong, tagDouble, tagTimestamp:
+		size = 8
+	case tagBoolean:
+		size = 1
+	case tagUUID:
+		size = 16
+	default:
+		return nil, fmt.Errorf("Unknown field tag %d", tag)
+	}
+	buf := make([]byte, size)
+	if _, err := io.ReadFull(r, buf); err != nil {
+		return nil, err
+	}
+	switch tag {
+	case tagInt:
+		return int32(binary.LittleEndian.Uint32(buf)), nil
+	case tagLong:
+		return int64(binary.LittleEndian.Uint64(buf)), nil
+	case tagFloat:
+		return math.Float32frombits(binary.LittleEndian.Uint32(buf)), nil
+	case tagDouble:
+		return math.Float64frombits(binary.LittleEndian.Uint64(buf)), nil
+	case tagTimestamp:
+		return time.Unix(0, int64(binary.LittleEndian.Uint64(buf))).UTC(), nil
+	case tagUUID:
+		return uuid.FromBytes(buf)
+	default:
+		return buf[0] != 0, nil
+	}
+}
+
+// EncodeRow encodes a row with its fields in name order, so equal rows always encode to the same
+// bytes
+func EncodeRow(r Row) ([]byte, error) {
+	w := new(bytes.Buffer)
+	if err := encodeRow(w, r); err != nil {
+		return nil, err
+	}
+	return w.Bytes(), nil
+}
+
+func encodeRow(w *bytes.Buffer, r Row) error {
+	names := make([]string, 0, len(r.Data))
+	for name := range r.Data {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	putLength(w, len(names))
+	for _, name := range names {
+		putLength(w, len(name))
+		w.WriteString(name)
+		if err := encodeField(w, r.Data[name]); err != nil {
+			return fmt.Errorf("Couldn't encode field %s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// DecodeRow decodes a row written by EncodeRow
+func DecodeRow(b []byte) (Row, error) {
+	r := bytes.NewReader(b)
+	row, err := decodeRow(r)
+	if err != nil {
+		return Row{}, err
+	}
+	if r.Len() != 0 {
+		return Row{}, fmt.Errorf("Encoded row has %d trailing bytes", r.Len())
+	}
+	return row, nil
+}
+
+func decodeRow(r *bytes.Reader) (Row, error) {
+	numFields, err := getLength(r)
+	if err != nil {
+		return Row{}, err
+	}
+	data := make(map[string]interface{}, numFields)
+	for i := 0; i < numFields; i++ {
+		nameLen, err := getLength(r)
+		if err != nil {
+			return Row{}, err
+		}
+		name := make([]byte, nameLen)
+		if _, err := io.ReadFull(r, name); err != nil {
+			return Row{}, err
+		}
+		v, err := decodeField(r)
+		if err != nil {
+			return Row{}, fmt.Errorf("Couldn't decode field %s: %w", name, err)
+		}
+		data[string(name)] = v
+	}
+	return Row{Data: data}, nil
+}
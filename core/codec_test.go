@@ -0,0 +1,76 @@
+package core
+
+import (
+	"math"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEncodeDecodeRow(t *testing.T) {
+	row := Row{Data: map[string]interface{}{
+		"string":  "héllo",
+		"int":     int32(-5),
+		"long":    int64(math.MinInt64),
+		"float":   float32(1.5),
+		"double":  math.Inf(-1),
+		"boolean": true,
+	}}
+	encoded, err := EncodeRow(row)
+	require.NoError(t, err)
+	decoded, err := DecodeRow(encoded)
+	require.NoError(t, err)
+	assert.Equal(t, row, decoded)
+
+	// Encoding doesn't depend on map order
+	again, err := EncodeRow(decoded)
+	require.NoError(t, err)
+	assert.Equal(t, encoded, again)
+
+	for i := 0; i < len(encoded); i++ {
+		_, err := DecodeRow(encoded[:i])
+		assert.Error(t, err)
+	}
+}
+
+func TestEncodeDecodeRichRow(t *testing.T) {
+	row := Row{Data: map[string]interface{}{
+		"bytes":     []byte{0, 1, 2},
+		"timestamp": time.Unix(1600000000, 123).UTC(),
+		"uuid":      uuid.MustParse("6ba7b810-9dad-11d1-80b4-00c04fd430c8"),
+		"decimal":   NewDecimal(big.NewInt(-1250), 2),
+		"array":     []interface{}{int32(1), "two", []interface{}{false}},
+		"record": Row{Data: map[string]interface{}{
+			"nested": Row{Data: map[string]interface{}{"n": int64(7)}},
+		}},
+	}}
+	encoded, err := EncodeRow(row)
+	require.NoError(t, err)
+	decoded, err := DecodeRow(encoded)
+	require.NoError(t, err)
+	// big.Int isn't comparable with assert.Equal, so decimals are compared on their own
+	assert.Equal(t, "-12.50", decoded.Data["decimal"].(Decimal).String())
+	delete(row.Data, "decimal")
+	delete(decoded.Data, "decimal")
+	assert.Equal(t, row, decoded)
+
+	for i := 0; i < len(encoded); i++ {
+		_, err := DecodeRow(encoded[:i])
+		assert.Error(t, err)
+	}
+}
+
+func TestEncodeRowTimestampOutOfRange(t *testing.T) {
+	row := Row{Data: map[string]interface{}{"timestamp": time.Unix(1<<40, 0)}}
+	_, err := EncodeRow(row)
+	assert.Error(t, err)
+}
+
+func TestEncodeRowUnsupportedType(t *testing.T) {
+	_, err := EncodeRow(Row{Data: map[string]interface{}{"int": 5}})
+	assert.Error(t, err)
+}
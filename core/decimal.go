@@ -0,0 +1,60 @@
+package core
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+// Decimal is an exact decimal number equal to Unscaled * 10^-Scale. Decimals with different scales
+// are different values even if they're numerically equal, so 1.0 and 1.00 are kept apart.
+type Decimal struct {
+	Unscaled *big.Int
+	Scale    uint32
+}
+
+func NewDecimal(unscaled *big.Int, scale uint32) Decimal {
+	return Decimal{unscaled, scale}
+}
+
+// ParseDecimal parses a decimal written as an optionally signed number with an optional fractional
+// part, like -12.50. The scale is the number of digits after the decimal point.
+func ParseDecimal(s string) (Decimal, error) {
+	digits := s
+	scale := 0
+	if point := strings.IndexByte(s, '.'); point >= 0 {
+		digits = s[:point] + s[point+1:]
+		scale = len(s) - point - 1
+		if scale == 0 {
+			return Decimal{}, fmt.Errorf("Invalid decimal %s", s)
+		}
+	}
+	trimmed := strings.TrimLeft(digits, "+-")
+	if trimmed == "" || strings.Trim(trimmed, "0123456789") != "" || len(digits)-len(trimmed) > 1 {
+		return Decimal{}, fmt.Errorf("Invalid decimal %s", s)
+	}
+	unscaled, ok := new(big.Int).SetString(digits, 10)
+	if !ok {
+		return Decimal{}, fmt.Errorf("Invalid decimal %s", s)
+	}
+	return Decimal{unscaled, uint32(scale)}, nil
+}
+
+func (d Decimal) String() string {
+	if d.Unscaled == nil {
+		return "<nil>"
+	}
+	digits := new(big.Int).Abs(d.Unscaled).String()
+	sign := ""
+	if d.Unscaled.Sign() < 0 {
+		sign = "-"
+	}
+	if d.Scale == 0 {
+		return sign + digits
+	}
+	scale := int(d.Scale)
+	if len(digits) <= scale {
+		digits = strings.Repeat("0", scale-len(digits)+1) + digits
+	}
+	return sign + digits[:len(digits)-scale] + "." + digits[len(digits)-scale:]
+}
@@ -0,0 +1,36 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseDecimal(t *testing.T) {
+	tests := []struct {
+		in       string
+		unscaled int64
+		scale    uint32
+		out      string
+	}{
+		{"0", 0, 0, "0"},
+		{"12", 12, 0, "12"},
+		{"-12.50", -1250, 2, "-12.50"},
+		{"+0.05", 5, 2, "0.05"},
+		{"-.5", -5, 1, "-0.5"},
+		{"007.0", 70, 1, "7.0"},
+	}
+	for _, test := range tests {
+		d, err := ParseDecimal(test.in)
+		require.NoError(t, err, test.in)
+		assert.Equal(t, test.unscaled, d.Unscaled.Int64(), test.in)
+		assert.Equal(t, test.scale, d.Scale, test.in)
+		assert.Equal(t, test.out, d.String(), test.in)
+	}
+
+	for _, in := range []string{"", "-", ".", "1.", "1.2.3", "--1", "1-", "1e5", "0x10", " 1"} {
+		_, err := ParseDecimal(in)
+		assert.Error(t, err, in)
+	}
+}
@@ -0,0 +1,300 @@
+package core
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// isWidening returns whether every value of type from can be converted to type to without losing
+// anything
+func isWidening(from string, to string) bool {
+	switch from {
+	case "int":
+		return to == "long" || to == "double"
+	case "float":
+		return to == "double"
+	default:
+		return false
+	}
+}
+
+// upgradeValue converts a value that was valid for an earlier version of spec into one that's
+// valid for spec, widening numbers, including those in arrays and records
+func upgradeValue(v interface{}, spec FieldSpec) interface{} {
+	switch val := v.(type) {
+	case int32:
+		switch spec.Type {
+		case "long":
+			return int64(val)
+		case "double":
+			return float64(val)
+		}
+	case float32:
+		if spec.Type == "double" {
+			return float64(val)
+		}
+	case []interface{}:
+		if spec.Items == nil {
+			return v
+		}
+		items := make([]interface{}, len(val))
+		for i, item := range val {
+			items[i] = upgradeValue(item, *spec.Items)
+		}
+		return items
+	case Row:
+		return upgradeRecord(val, spec.Fields)
+	}
+	return v
+}
+
+func upgradeRecord(r Row, fields map[string]FieldSpec) Row {
+	data := make(map[string]interface{}, len(r.Data))
+	for fieldName, v := range r.Data {
+		if fieldSpec, ok := fields[fieldName]; ok {
+			v = upgradeValue(v, fieldSpec)
+		}
+		data[fieldName] = v
+	}
+	return Row{Data: data}
+}
+
+// CheckEvolution checks that next can be the version that follows s. Rows written under s must
+// stay valid, so next can only add fields that are nullable or have a default, make fields
+// nullable or give required fields a default, add enum symbols, loosen constraints and widen the
+// types of fields that aren't part of the primary key (int to long or double, float to double).
+// Merge policies can't change since replicas must agree on how to merge a field. The same rules
+// apply to the fields of records and the items of arrays. The primary key can't change since it
+// determines where rows are stored. Indexes can be added and removed, but an index can't change
+// its fields and the fields of an index that's kept can't change type, since both determine where
+// its entries are stored. Anything can follow a schema without a primary key, like the genesis
+// schema, since no table can have rows under it.
+func (s Schema) CheckEvolution(next Schema) error {
+	if err := next.Validate(); err != nil {
+		return err
+	}
+	if len(s.PrimaryKey) == 0 {
+		return nil
+	}
+	if !reflect.DeepEqual(s.PrimaryKey, next.PrimaryKey) {
+		return fmt.Errorf("Primary key can't change from %v to %v", s.PrimaryKey, next.PrimaryKey)
+	}
+	isPrimaryKey := make(map[string]bool, len(s.PrimaryKey))
+	for _, fieldName := range s.PrimaryKey {
+		isPrimaryKey[fieldName] = true
+	}
+	if err := checkFieldsEvolution("", s.Fields, next.Fields, isPrimaryKey); err != nil {
+		return err
+	}
+	return s.checkIndexEvolution(next)
+}
+
+func (s Schema) checkIndexEvolution(next Schema) error {
+	for indexName, fieldNames := range s.Indexes {
+		nextFieldNames, ok := next.Indexes[indexName]
+		if !ok {
+			continue
+		} else if !reflect.DeepEqual(fieldNames, nextFieldNames) {
+			return fmt.Errorf(
+				"Fields of index %s can't change from %v to %v",
+				indexName,
+				fieldNames,
+				nextFieldNames,
+			)
+		}
+		for _, fieldName := range fieldNames {
+			if s.Fields[fieldName].Type != next.Fields[fieldName].Type {
+				return fmt.Errorf(
+					"Field %s can't change type since index %s uses it",
+					fieldName,
+					indexName,
+				)
+			}
+		}
+	}
+	return nil
+}
+
+func checkFieldsEvolution(
+	prefix string,
+	fields map[string]FieldSpec,
+	nextFields map[string]FieldSpec,
+	isPrimaryKey map[string]bool,
+) error {
+	for fieldName, fieldSpec := range fields {
+		nextSpec, ok := nextFields[fieldName]
+		if !ok {
+			return fmt.Errorf("Field %s%s can't be removed", prefix, fieldName)
+		}
+		err := checkFieldEvolution(prefix+fieldName, fieldSpec, nextSpec, isPrimaryKey[fieldName])
+		if err != nil {
+			return err
+		}
+	}
+	for fieldName, nextSpec := range nextFields {
+		if _, ok := fields[fieldName]; !ok && !nextSpec.Nullable && nextSpec.Default == nil {
+			return fmt.Errorf(
+				"New field %s%s must be nullable or have a default",
+				prefix,
+				fieldName,
+			)
+		}
+	}
+	return nil
+}
+
+func checkFieldEvolution(fieldName string, spec FieldSpec, next FieldSpec, isKey bool) error {
+	if spec.Nullable && !next.Nullable && next.Default == nil {
+		return fmt.Errorf("Nullable field %s can't become required without a default", fieldName)
+	}
+	if err := checkConstraintEvolution(fieldName, spec, next); err != nil {
+		return err
+	}
+	if policyTags[spec.Merge] != policyTags[next.Merge] {
+		return fmt.Errorf("Merge policy of field %s can't change", fieldName)
+	}
+	if spec.Type != next.Type {
+		if isKey {
+			return fmt.Errorf("Primary key field %s can't change type", fieldName)
+		} else if !isWidening(spec.Type, next.Type) {
+			return fmt.Errorf(
+				"Field %s can't change type from %s to %s",
+				fieldName,
+				spec.Type,
+				next.Type,
+			)
+		}
+		return nil
+	}
+	switch spec.Type {
+	case "enum":
+		symbols := make(map[string]bool, len(next.Symbols))
+		for _, symbol := range next.Symbols {
+			symbols[symbol] = true
+		}
+		for _, symbol := range spec.Symbols {
+			if !symbols[symbol] {
+				return fmt.Errorf(
+					"Symbol %s can't be removed from enum field %s",
+					symbol,
+					fieldName,
+				)
+			}
+		}
+	case "array":
+		return checkFieldEvolution(fieldName+"[]", *spec.Items, *next.Items, false)
+	case "record":
+		return checkFieldsEvolution(fieldName+".", spec.Fields, next.Fields, nil)
+	}
+	return nil
+}
+
+// checkConstraintEvolution checks that the constraints of a field are only loosened, so that
+// values that were valid stay valid
+func checkConstraintEvolution(fieldName string, spec FieldSpec, next FieldSpec) error {
+	if next.Min != nil && (spec.Min == nil || *next.Min > *spec.Min) {
+		return fmt.Errorf("Minimum of field %s can't be raised", fieldName)
+	}
+	if next.Max != nil && (spec.Max == nil || *next.Max < *spec.Max) {
+		return fmt.Errorf("Maximum of field %s can't be lowered", fieldName)
+	}
+	if next.MaxLength != nil && (spec.MaxLength == nil || *next.MaxLength < *spec.MaxLength) {
+		return fmt.Errorf("Maximum length of field %s can't be lowered", fieldName)
+	}
+	if next.Pattern != "" && next.Pattern != spec.Pattern {
+		return fmt.Errorf("Pattern of field %s can't be changed", fieldName)
+	}
+	return nil
+}
+
+// SchemaHistory is the chain of versions a table's schema went through, from the oldest to the
+// latest. Rows written under any version can be read as rows of the latest one.
+type SchemaHistory struct {
+	versions []Schema
+}
+
+// NewSchemaHistory creates a history with s as its only version
+func NewSchemaHistory(s Schema) SchemaHistory {
+	return SchemaHistory{[]Schema{s}}
+}
+
+// NewSchemaHistoryFrom creates a history from versions, oldest first, checking that each version
+// is an allowed evolution of the one before it
+func NewSchemaHistoryFrom(versions []Schema) (SchemaHistory, error) {
+	if len(versions) == 0 {
+		return SchemaHistory{}, fmt.Errorf("Schema history is empty")
+	}
+	for i := 1; i < len(versions); i++ {
+		if versions[i].Version != versions[i-1].Version+1 {
+			return SchemaHistory{}, fmt.Errorf(
+				"Schema version %d can't follow version %d",
+				versions[i].Version,
+				versions[i-1].Version,
+			)
+		}
+		if err := versions[i-1].CheckEvolution(versions[i]); err != nil {
+			return SchemaHistory{}, fmt.Errorf(
+				"Invalid evolution to version %d: %w",
+				versions[i].Version,
+				err,
+			)
+		}
+	}
+	return SchemaHistory{append([]Schema{}, versions...)}, nil
+}
+
+// Latest returns the current version of the schema
+func (h SchemaHistory) Latest() Schema {
+	return h.versions[len(h.versions)-1]
+}
+
+// Versions returns every version of the schema, oldest first
+func (h SchemaHistory) Versions() []Schema {
+	return append([]Schema{}, h.versions...)
+}
+
+// Evolve checks that next is an allowed evolution of the latest version and returns the history
+// with next added as the new latest version. next's version is set to follow the latest one; its
+// parent is left as is since only the caller knows where the latest version is stored.
+func (h SchemaHistory) Evolve(next Schema) (SchemaHistory, error) {
+	latest := h.Latest()
+	if err := latest.CheckEvolution(next); err != nil {
+		return SchemaHistory{}, err
+	}
+	next.Version = latest.Version + 1
+	versions := make([]Schema, len(h.versions), len(h.versions)+1)
+	copy(versions, h.versions)
+	return SchemaHistory{append(versions, next)}, nil
+}
+
+// ValidateRow checks that r is valid under some version of the schema and returns it as a row of
+// the latest version, with defaults filled in. If it isn't valid under any version, the error is
+// the one from the latest version.
+func (h SchemaHistory) ValidateRow(r Row) (Row, error) {
+	row, err := h.Latest().ValidateRow(r)
+	if err == nil {
+		return row, nil
+	}
+	for i := len(h.versions) - 2; i >= 0; i-- {
+		if row, versionErr := h.versions[i].ValidateRow(r); versionErr == nil {
+			return h.Upgrade(row)
+		}
+	}
+	return Row{}, err
+}
+
+// Upgrade converts a row that is valid under some version of the schema into a row of the latest
+// version by widening the values of fields whose type was widened since, including fields of
+// records and items of arrays, and filling in the defaults of fields added since
+func (h SchemaHistory) Upgrade(r Row) (Row, error) {
+	latest := h.Latest()
+	upgraded, err := latest.ValidateRow(upgradeRecord(r, latest.Fields))
+	if err != nil {
+		return Row{}, fmt.Errorf(
+			"Couldn't upgrade row to schema version %d: %w",
+			latest.Version,
+			err,
+		)
+	}
+	return upgraded, nil
+}
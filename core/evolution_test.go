@@ -0,0 +1,235 @@
+package core
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func eventsSchema() Schema {
+	return Schema{
+		Fields: map[string]FieldSpec{
+			"id":    {Type: "int"},
+			"count": {Type: "int"},
+			"ratio": {Type: "float"},
+			"note":  {Type: "string", Nullable: true},
+		},
+		PrimaryKey: []string{"id"},
+	}
+}
+
+func TestCheckEvolution(t *testing.T) {
+	s := eventsSchema()
+	tests := []struct {
+		name    string
+		change  func(fields map[string]FieldSpec)
+		allowed bool
+	}{
+		{"unchanged", func(fields map[string]FieldSpec) {}, true},
+		{"add nullable", func(fields map[string]FieldSpec) {
+			fields["extra"] = FieldSpec{Type: "boolean", Nullable: true}
+		}, true},
+		{"add required", func(fields map[string]FieldSpec) {
+			fields["extra"] = FieldSpec{Type: "boolean"}
+		}, false},
+		{"make nullable", func(fields map[string]FieldSpec) {
+			fields["count"] = FieldSpec{Type: "int", Nullable: true}
+		}, true},
+		{"make required", func(fields map[string]FieldSpec) {
+			fields["note"] = FieldSpec{Type: "string"}
+		}, false},
+		{"widen int", func(fields map[string]FieldSpec) {
+			fields["count"] = FieldSpec{Type: "long"}
+		}, true},
+		{"widen float", func(fields map[string]FieldSpec) {
+			fields["ratio"] = FieldSpec{Type: "double"}
+		}, true},
+		{"narrow", func(fields map[string]FieldSpec) {
+			fields["ratio"] = FieldSpec{Type: "int"}
+		}, false},
+		{"widen primary key", func(fields map[string]FieldSpec) {
+			fields["id"] = FieldSpec{Type: "long"}
+		}, false},
+		{"remove", func(fields map[string]FieldSpec) {
+			delete(fields, "note")
+		}, false},
+	}
+	for _, test := range tests {
+		next := eventsSchema()
+		test.change(next.Fields)
+		err := s.CheckEvolution(next)
+		if test.allowed {
+			assert.NoError(t, err, test.name)
+		} else {
+			assert.Error(t, err, test.name)
+		}
+	}
+
+	next := eventsSchema()
+	next.PrimaryKey = []string{"id", "count"}
+	assert.Error(t, s.CheckEvolution(next))
+
+	assert.NoError(t, GenesisSchema().CheckEvolution(s))
+}
+
+func TestSchemaHistory(t *testing.T) {
+	history := NewSchemaHistory(eventsSchema())
+	next := eventsSchema()
+	next.Fields["count"] = FieldSpec{Type: "long"}
+	next.Fields["ratio"] = FieldSpec{Type: "double"}
+	next.Fields["extra"] = FieldSpec{Type: "boolean", Nullable: true}
+	history, err := history.Evolve(next)
+	require.NoError(t, err)
+	assert.Equal(t, uint32(1), history.Latest().Version)
+	assert.Len(t, history.Versions(), 2)
+
+	oldRow := Row{Data: map[string]interface{}{
+		"id":    int32(1),
+		"count": int32(5),
+		"ratio": float32(0.5),
+	}}
+	_, err = history.Latest().ValidateRow(oldRow)
+	assert.Error(t, err)
+	validated, err := history.ValidateRow(oldRow)
+	require.NoError(t, err)
+	upgraded, err := history.Upgrade(oldRow)
+	require.NoError(t, err)
+	assert.Equal(t, Row{Data: map[string]interface{}{
+		"id":    int32(1),
+		"count": int64(5),
+		"ratio": float64(0.5),
+	}}, upgraded)
+	assert.Equal(t, upgraded, validated)
+
+	_, err = history.ValidateRow(Row{Data: map[string]interface{}{"id": int32(1)}})
+	assert.Error(t, err)
+
+	_, err = history.Evolve(eventsSchema())
+	assert.Error(t, err)
+}
+
+func TestNewSchemaHistoryFrom(t *testing.T) {
+	first := eventsSchema()
+	second := eventsSchema()
+	second.Version = 1
+	second.Fields["count"] = FieldSpec{Type: "long"}
+	_, err := NewSchemaHistoryFrom([]Schema{first, second})
+	assert.NoError(t, err)
+
+	// Versions have to be consecutive
+	second.Version = 2
+	_, err = NewSchemaHistoryFrom([]Schema{first, second})
+	assert.Error(t, err)
+
+	// And each one has to be an allowed evolution of the one before
+	_, err = NewSchemaHistoryFrom([]Schema{second, first})
+	assert.Error(t, err)
+
+	_, err = NewSchemaHistoryFrom(nil)
+	assert.Error(t, err)
+}
+
+func TestCheckNestedEvolution(t *testing.T) {
+	s := ordersSchema()
+
+	next := ordersSchema()
+	next.Fields["status"] = FieldSpec{Type: "enum", Symbols: []string{"open", "shipped", "lost"}}
+	assert.NoError(t, s.CheckEvolution(next))
+	next.Fields["status"] = FieldSpec{Type: "enum", Symbols: []string{"shipped"}}
+	assert.Error(t, s.CheckEvolution(next))
+
+	lineItem := *ordersSchema().Fields["items"].Items
+	lineItem.Fields["quantity"] = FieldSpec{Type: "long"}
+	lineItem.Fields["discount"] = FieldSpec{Type: "decimal", Nullable: true}
+	next = ordersSchema()
+	next.Fields["items"] = FieldSpec{Type: "array", Items: &lineItem}
+	assert.NoError(t, s.CheckEvolution(next))
+
+	history, err := NewSchemaHistory(s).Evolve(next)
+	assert.NoError(t, err)
+	upgraded, err := history.Upgrade(order())
+	assert.NoError(t, err)
+	item := upgraded.Data["items"].([]interface{})[0].(Row)
+	assert.Equal(t, int64(2), item.Data["quantity"])
+
+	lineItem.Fields["required"] = FieldSpec{Type: "string"}
+	assert.Error(t, s.CheckEvolution(next))
+	delete(lineItem.Fields, "required")
+	delete(lineItem.Fields, "sku")
+	assert.Error(t, s.CheckEvolution(next))
+}
+
+func TestCheckConstraintEvolution(t *testing.T) {
+	s := accountsSchema()
+	tests := []struct {
+		name    string
+		change  func(fields map[string]FieldSpec)
+		allowed bool
+	}{
+		{"add required with default", func(fields map[string]FieldSpec) {
+			fields["extra"] = FieldSpec{Type: "boolean", Default: false}
+		}, true},
+		{"require with default", func(fields map[string]FieldSpec) {
+			fields["credit"] = FieldSpec{Type: "decimal", Min: float64Ptr(0), Default: Decimal{
+				big.NewInt(0), 0,
+			}}
+		}, true},
+		{"lower minimum", func(fields map[string]FieldSpec) {
+			fields["age"] = FieldSpec{Type: "int", Min: float64Ptr(-1), Max: float64Ptr(150)}
+		}, true},
+		{"drop maximum", func(fields map[string]FieldSpec) {
+			fields["age"] = FieldSpec{Type: "int", Min: float64Ptr(0)}
+		}, true},
+		{"raise minimum", func(fields map[string]FieldSpec) {
+			fields["age"] = FieldSpec{Type: "int", Min: float64Ptr(1), Max: float64Ptr(150)}
+		}, false},
+		{"lower maximum", func(fields map[string]FieldSpec) {
+			fields["age"] = FieldSpec{Type: "int", Min: float64Ptr(0), Max: float64Ptr(100)}
+		}, false},
+		{"add maximum", func(fields map[string]FieldSpec) {
+			fields["credit"] = FieldSpec{Type: "decimal", Min: float64Ptr(0), Max: float64Ptr(1),
+				Nullable: true}
+		}, false},
+		{"lower maximum length", func(fields map[string]FieldSpec) {
+			fields["handle"] = FieldSpec{
+				Type:      "string",
+				MaxLength: uint32Ptr(4),
+				Pattern:   "^[a-z]+$",
+			}
+		}, false},
+		{"change pattern", func(fields map[string]FieldSpec) {
+			fields["handle"] = FieldSpec{Type: "string", MaxLength: uint32Ptr(8), Pattern: "^[a-z]"}
+		}, false},
+		{"drop pattern", func(fields map[string]FieldSpec) {
+			fields["handle"] = FieldSpec{Type: "string", MaxLength: uint32Ptr(8)}
+		}, true},
+	}
+	for _, test := range tests {
+		next := accountsSchema()
+		test.change(next.Fields)
+		err := s.CheckEvolution(next)
+		if test.allowed {
+			assert.NoError(t, err, test.name)
+		} else {
+			assert.Error(t, err, test.name)
+		}
+	}
+}
+
+func TestUpgradeFillsDefaults(t *testing.T) {
+	next := accountsSchema()
+	next.Fields["verified"] = FieldSpec{Type: "boolean", Default: false}
+	history, err := NewSchemaHistory(accountsSchema()).Evolve(next)
+	require.NoError(t, err)
+	row, err := history.Upgrade(Row{Data: map[string]interface{}{
+		"handle": "bob",
+		"age":    int32(40),
+		"score":  0.5,
+		"tags":   []interface{}{},
+		"plan":   "paid",
+	}})
+	require.NoError(t, err)
+	assert.Equal(t, false, row.Data["verified"])
+}
@@ -0,0 +1,83 @@
+package core
+
+import (
+	"sync"
+	"time"
+)
+
+// HLC is a hybrid logical clock timestamp. Wall is physical time in nanoseconds, Logical orders
+// events that happen within the same nanosecond or while the local clock is behind a remote one,
+// and Replica identifies the writer so that timestamps from different replicas never tie.
+type HLC struct {
+	Wall    uint64
+	Logical uint32
+	Replica string
+}
+
+// Compare returns -1, 0 or 1 depending on whether t happened before, at the same time as or after
+// other
+func (t HLC) Compare(other HLC) int {
+	switch {
+	case t.Wall != other.Wall:
+		return compareUint64(t.Wall, other.Wall)
+	case t.Logical != other.Logical:
+		return compareUint64(uint64(t.Logical), uint64(other.Logical))
+	case t.Replica < other.Replica:
+		return -1
+	case t.Replica > other.Replica:
+		return 1
+	default:
+		return 0
+	}
+}
+
+func compareUint64(a uint64, b uint64) int {
+	if a < b {
+		return -1
+	} else if a > b {
+		return 1
+	}
+	return 0
+}
+
+// Clock hands out increasing hybrid logical clock timestamps for a replica. It's safe for
+// concurrent use.
+type Clock struct {
+	replica string
+	now     func() time.Time
+	last    HLC
+	lock    sync.Mutex
+}
+
+// NewClock creates a clock for replica that reads physical time from time.Now
+func NewClock(replica string) *Clock {
+	return NewClockWithTime(replica, time.Now)
+}
+
+// NewClockWithTime creates a clock for replica that reads physical time from now
+func NewClockWithTime(replica string, now func() time.Time) *Clock {
+	return &Clock{replica: replica, now: now, last: HLC{Replica: replica}}
+}
+
+// Now returns a timestamp later than every timestamp the clock returned or observed before
+func (c *Clock) Now() HLC {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	wall := uint64(c.now().UnixNano())
+	if wall > c.last.Wall {
+		c.last = HLC{wall, 0, c.replica}
+	} else {
+		c.last = HLC{c.last.Wall, c.last.Logical + 1, c.replica}
+	}
+	return c.last
+}
+
+// Observe moves the clock past a timestamp received from another replica, so that later local
+// writes are ordered after it even if the other replica's physical clock is ahead
+func (c *Clock) Observe(t HLC) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if t.Wall > c.last.Wall || (t.Wall == c.last.Wall && t.Logical > c.last.Logical) {
+		c.last = HLC{t.Wall, t.Logical, c.replica}
+	}
+}
@@ -0,0 +1,49 @@
+package core
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHLCCompare(t *testing.T) {
+	assert.Equal(t, -1, HLC{1, 5, "b"}.Compare(HLC{2, 0, "a"}))
+	assert.Equal(t, -1, HLC{1, 0, "b"}.Compare(HLC{1, 1, "a"}))
+	assert.Equal(t, -1, HLC{1, 1, "a"}.Compare(HLC{1, 1, "b"}))
+	assert.Equal(t, 1, HLC{1, 1, "b"}.Compare(HLC{1, 1, "a"}))
+	assert.Equal(t, 0, HLC{1, 1, "a"}.Compare(HLC{1, 1, "a"}))
+}
+
+func TestClockNow(t *testing.T) {
+	now := time.Unix(0, 100)
+	clock := NewClockWithTime("a", func() time.Time { return now })
+	first := clock.Now()
+	assert.Equal(t, HLC{100, 0, "a"}, first)
+
+	// Timestamps keep increasing while physical time stands still or goes back
+	second := clock.Now()
+	assert.Equal(t, HLC{100, 1, "a"}, second)
+	now = time.Unix(0, 50)
+	third := clock.Now()
+	assert.Equal(t, HLC{100, 2, "a"}, third)
+
+	now = time.Unix(0, 200)
+	assert.Equal(t, HLC{200, 0, "a"}, clock.Now())
+}
+
+func TestClockObserve(t *testing.T) {
+	now := time.Unix(0, 100)
+	clock := NewClockWithTime("a", func() time.Time { return now })
+	clock.Now()
+
+	remote := HLC{500, 3, "b"}
+	clock.Observe(remote)
+	next := clock.Now()
+	assert.Equal(t, HLC{500, 4, "a"}, next)
+	assert.Equal(t, 1, next.Compare(remote))
+
+	// Older timestamps don't move the clock back
+	clock.Observe(HLC{10, 0, "b"})
+	assert.Equal(t, HLC{500, 5, "a"}, clock.Now())
+}
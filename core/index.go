@@ -0,0 +1,224 @@
+package core
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/vulturedb/vulture/mst"
+)
+
+// Index keys start with a byte telling whether each field is set, so that rows where an indexed
+// field is null sort before every row where it's set
+const (
+	indexFieldNull byte = 0x00
+	indexFieldSet  byte = 0x01
+)
+
+// IndexKey is an mst.Key made of the fields of a secondary index followed by the primary key of
+// the row, so that rows with the same indexed fields still get distinct keys. Like a PrimaryKey,
+// it sorts the same way as the tuple of its fields.
+type IndexKey struct {
+	encoded []byte
+	values  []interface{}
+}
+
+// Values returns the indexed fields of the key in index order, with nil for null fields
+func (k IndexKey) Values() []interface{} {
+	return k.values
+}
+
+// Bytes returns the order preserving encoding of the key
+func (k IndexKey) Bytes() []byte {
+	return k.encoded
+}
+
+func (k IndexKey) Less(than mst.Key) bool {
+	return bytes.Compare(k.encoded, than.(IndexKey).encoded) < 0
+}
+
+func (k IndexKey) Write(w io.Writer) error {
+	_, err := w.Write(k.encoded)
+	return err
+}
+
+func (k IndexKey) HasPrefix(prefix mst.Key) bool {
+	return bytes.HasPrefix(k.encoded, prefix.(IndexKey).encoded)
+}
+
+func (s Schema) indexFields(indexName string) ([]string, error) {
+	fieldNames, ok := s.Indexes[indexName]
+	if !ok {
+		return nil, fmt.Errorf("Missing index %s", indexName)
+	}
+	return fieldNames, nil
+}
+
+// IndexKeyOf returns the key of a row in the index named indexName
+func (s Schema) IndexKeyOf(indexName string, r Row) (IndexKey, error) {
+	fieldNames, err := s.indexFields(indexName)
+	if err != nil {
+		return IndexKey{}, err
+	}
+	primaryKey, err := s.PrimaryKeyOf(r)
+	if err != nil {
+		return IndexKey{}, err
+	}
+	w := new(bytes.Buffer)
+	values := make([]interface{}, len(fieldNames))
+	for i, fieldName := range fieldNames {
+		v := r.Data[fieldName]
+		if v == nil {
+			w.WriteByte(indexFieldNull)
+			continue
+		}
+		w.WriteByte(indexFieldSet)
+		if err := encodeKeyField(w, fieldName, s.Fields[fieldName], v); err != nil {
+			return IndexKey{}, err
+		}
+		values[i] = v
+	}
+	w.Write(primaryKey.encoded)
+	return IndexKey{w.Bytes(), values}, nil
+}
+
+// IndexKeyReader reads the keys of an index back from their encoding. Keyspaces name it
+// "index-key:<index types>;<primary key types>", with the types of each key separated by commas,
+// see Name.
+type IndexKeyReader struct {
+	types      []string
+	primaryKey PrimaryKeyReader
+}
+
+// IndexEntryValueType names the value type of keyspaces of index entries, see mst.ValueReaderFor
+const IndexEntryValueType = "index-entry"
+
+func init() {
+	mst.RegisterKeyType("index-key", func(arg string) (mst.KeyReader, error) {
+		parts := strings.Split(arg, ";")
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("Expected index types and primary key types")
+		}
+		types, err := parseKeyTypes(parts[0])
+		if err != nil {
+			return nil, err
+		}
+		primaryKeyTypes, err := parseKeyTypes(parts[1])
+		if err != nil {
+			return nil, err
+		}
+		return IndexKeyReader{types, PrimaryKeyReader{primaryKeyTypes}}, nil
+	})
+	mst.RegisterValueType(IndexEntryValueType, func(arg string) (mst.ValueReader, error) {
+		if arg != "" {
+			return nil, fmt.Errorf("Index entries take no argument")
+		}
+		return IndexEntryValueReader{}, nil
+	})
+}
+
+// Name names the key type of keyspaces whose keys are read by kr, see mst.KeyReaderFor
+func (kr IndexKeyReader) Name() string {
+	return "index-key:" + strings.Join(kr.types, ",") + ";" + strings.Join(kr.primaryKey.types, ",")
+}
+
+// IndexKeyReader returns the reader for the keys of the index named indexName
+func (s Schema) IndexKeyReader(indexName string) (IndexKeyReader, error) {
+	fieldNames, err := s.indexFields(indexName)
+	if err != nil {
+		return IndexKeyReader{}, err
+	}
+	types := make([]string, len(fieldNames))
+	for i, fieldName := range fieldNames {
+		types[i] = s.Fields[fieldName].Type
+	}
+	return IndexKeyReader{types, s.PrimaryKeyReader()}, nil
+}
+
+func (kr IndexKeyReader) FromBytes(b []byte) (mst.Key, error) {
+	r := bytes.NewReader(b)
+	values := make([]interface{}, len(kr.types))
+	for i, typeStr := range kr.types {
+		set, err := r.ReadByte()
+		if err != nil {
+			return nil, fmt.Errorf("Couldn't decode index field %d: %w", i, err)
+		} else if set == indexFieldNull {
+			continue
+		} else if set != indexFieldSet {
+			return nil, fmt.Errorf("Invalid presence %d of index field %d", set, i)
+		}
+		v, err := decodeKeyField(r, typeStr)
+		if err != nil {
+			return nil, fmt.Errorf("Couldn't decode index field %d: %w", i, err)
+		}
+		values[i] = v
+	}
+	if _, err := kr.primaryKey.FromBytes(b[len(b)-r.Len():]); err != nil {
+		return nil, err
+	}
+	return IndexKey{append([]byte{}, b...), values}, nil
+}
+
+// IndexEntry is the value of an index key. It points to the row the key was made from and holds
+// the time of the write that made it, so that an entry written again after being deleted on
+// another replica is visible once the replicas merge.
+type IndexEntry struct {
+	rowKey    []byte
+	timestamp HLC
+}
+
+// NewIndexEntry creates an entry for the row with the given primary key, written at timestamp
+func NewIndexEntry(rowKey PrimaryKey, timestamp HLC) IndexEntry {
+	return IndexEntry{rowKey.encoded, timestamp}
+}
+
+// RowKey returns the encoded primary key of the row the entry points to
+func (e IndexEntry) RowKey() []byte {
+	return e.rowKey
+}
+
+func (e IndexEntry) Timestamp() HLC {
+	return e.timestamp
+}
+
+func (e IndexEntry) Write(w io.Writer) error {
+	buf := new(bytes.Buffer)
+	putHLC(buf, e.timestamp)
+	putLength(buf, len(e.rowKey))
+	buf.Write(e.rowKey)
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+// Merge keeps the entry with the later timestamp
+func (e IndexEntry) Merge(with mst.Value) mst.Value {
+	other := with.(IndexEntry)
+	if cmp := e.timestamp.Compare(other.timestamp); cmp > 0 {
+		return e
+	} else if cmp < 0 {
+		return other
+	}
+	if bytes.Compare(e.rowKey, other.rowKey) >= 0 {
+		return e
+	}
+	return other
+}
+
+type IndexEntryValueReader struct{}
+
+func (vr IndexEntryValueReader) FromBytes(b []byte) (mst.Value, error) {
+	r := bytes.NewReader(b)
+	timestamp, err := getHLC(r)
+	if err != nil {
+		return nil, fmt.Errorf("Couldn't decode timestamp of index entry: %w", err)
+	}
+	rowKey, err := getBytes(r)
+	if err != nil {
+		return nil, fmt.Errorf("Couldn't decode row key of index entry: %w", err)
+	}
+	if r.Len() != 0 {
+		return nil, fmt.Errorf("Encoded index entry has %d trailing bytes", r.Len())
+	}
+	return IndexEntry{rowKey, timestamp}, nil
+}
@@ -0,0 +1,240 @@
+package core
+
+import (
+	"bytes"
+	"math/rand"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/vulturedb/vulture/mst"
+)
+
+func indexSchema() Schema {
+	return Schema{
+		Fields: map[string]FieldSpec{
+			"id":    {Type: "long"},
+			"city":  {Type: "string", Nullable: true},
+			"age":   {Type: "int", Max: floatPtr(150)},
+			"tags":  {Type: "array", Items: &FieldSpec{Type: "string"}},
+			"score": {Type: "long", Merge: MergeMax},
+		},
+		PrimaryKey: []string{"id"},
+		Indexes:    map[string][]string{"by_city": {"city", "age"}},
+	}
+}
+
+func floatPtr(f float64) *float64 {
+	return &f
+}
+
+func person(id int64, city interface{}, age int32) Row {
+	return Row{Data: map[string]interface{}{"id": id, "city": city, "age": age}}
+}
+
+func TestValidateIndexes(t *testing.T) {
+	tests := []struct {
+		name    string
+		indexes map[string][]string
+		valid   bool
+	}{
+		{"valid", map[string][]string{"by_city": {"city", "age"}, "by_id": {"id"}}, true},
+		{"empty name", map[string][]string{"": {"city"}}, false},
+		{"no fields", map[string][]string{"by_city": {}}, false},
+		{"missing field", map[string][]string{"by_city": {"country"}}, false},
+		{"array field", map[string][]string{"by_tags": {"tags"}}, false},
+		{"max field", map[string][]string{"by_score": {"score"}}, false},
+		{"repeated field", map[string][]string{"by_city": {"city", "city"}}, false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			s := indexSchema()
+			s.Indexes = test.indexes
+			assert.Equal(t, test.valid, s.Validate() == nil)
+		})
+	}
+}
+
+func TestIndexEvolution(t *testing.T) {
+	s := indexSchema()
+	next := indexSchema()
+	next.Indexes["by_age"] = []string{"age"}
+	assert.NoError(t, s.CheckEvolution(next))
+
+	next = indexSchema()
+	next.Indexes = nil
+	assert.NoError(t, s.CheckEvolution(next))
+
+	next = indexSchema()
+	next.Indexes["by_city"] = []string{"city"}
+	assert.Error(t, s.CheckEvolution(next))
+
+	// Widening an indexed field would change the keys of its entries
+	next = indexSchema()
+	next.Fields["age"] = FieldSpec{Type: "long", Max: floatPtr(150)}
+	assert.Error(t, s.CheckEvolution(next))
+	next.Indexes = nil
+	assert.NoError(t, s.CheckEvolution(next))
+}
+
+func TestIndexKeySortsLikeTuple(t *testing.T) {
+	s := indexSchema()
+	r := rand.New(rand.NewSource(3))
+	cities := []interface{}{nil, "", "a", "ab", "b"}
+	keys := []IndexKey{}
+	for i := 0; i < 200; i++ {
+		row := person(r.Int63n(10), cities[r.Intn(len(cities))], int32(r.Intn(20)-10))
+		key, err := s.IndexKeyOf("by_city", row)
+		require.NoError(t, err)
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i].Less(keys[j]) })
+	for i := 1; i < len(keys); i++ {
+		prev, next := keys[i-1].Values(), keys[i].Values()
+		if prev[0] == nil || next[0] == nil {
+			// Null cities sort first
+			assert.True(t, prev[0] == nil, "%v before %v", prev, next)
+			continue
+		}
+		assert.LessOrEqual(t, compareTuples(prev, next), 0)
+	}
+}
+
+func TestIndexKeyReader(t *testing.T) {
+	s := indexSchema()
+	kr, err := s.IndexKeyReader("by_city")
+	require.NoError(t, err)
+	for _, row := range []Row{person(1, "x", 3), person(-2, nil, 0)} {
+		key, err := s.IndexKeyOf("by_city", row)
+		require.NoError(t, err)
+		decoded, err := kr.FromBytes(key.Bytes())
+		require.NoError(t, err)
+		assert.Equal(t, key, decoded)
+
+		encoded := key.Bytes()
+		for j := 0; j < len(encoded); j++ {
+			_, err := kr.FromBytes(encoded[:j])
+			assert.Error(t, err)
+		}
+		_, err = kr.FromBytes(append(append([]byte{}, encoded...), 0))
+		assert.Error(t, err)
+	}
+	_, err = s.IndexKeyReader("missing")
+	assert.Error(t, err)
+
+	named, err := mst.KeyReaderFor(kr.Name())
+	require.NoError(t, err)
+	assert.Equal(t, kr, named)
+	for _, name := range []string{"index-key:string,int", "index-key:string;", "index-key:;long"} {
+		_, err := mst.KeyReaderFor(name)
+		assert.Error(t, err, name)
+	}
+	vr, err := mst.ValueReaderFor(IndexEntryValueType)
+	require.NoError(t, err)
+	assert.Equal(t, IndexEntryValueReader{}, vr)
+}
+
+func indexEntryBytes(t *testing.T, e IndexEntry) []byte {
+	buf := new(bytes.Buffer)
+	require.NoError(t, e.Write(buf))
+	return buf.Bytes()
+}
+
+func TestIndexEntryMerge(t *testing.T) {
+	s := indexSchema()
+	key, err := s.PrimaryKeyOf(person(1, nil, 0))
+	require.NoError(t, err)
+	older := NewIndexEntry(key, HLC{1, 0, "b"})
+	newer := NewIndexEntry(key, HLC{2, 0, "a"})
+	assert.Equal(t, newer, older.Merge(newer))
+	assert.Equal(t, newer, newer.Merge(older))
+	assert.Equal(t, older, older.Merge(older))
+
+	encoded := indexEntryBytes(t, newer)
+	read, err := IndexEntryValueReader{}.FromBytes(encoded)
+	require.NoError(t, err)
+	assert.Equal(t, encoded, indexEntryBytes(t, read.(IndexEntry)))
+	_, err = IndexEntryValueReader{}.FromBytes(append(encoded, 0))
+	assert.Error(t, err)
+	_, err = IndexEntryValueReader{}.FromBytes(encoded[:len(encoded)-1])
+	assert.Error(t, err)
+}
+
+func TestPredicateMatches(t *testing.T) {
+	s := indexSchema()
+	row := person(1, "b", 30)
+	tests := []struct {
+		pred    Predicate
+		matches bool
+	}{
+		{Predicate{"city", OpEq, "b"}, true},
+		{Predicate{"city", OpEq, "bb"}, false},
+		{Predicate{"city", OpLt, "bb"}, true},
+		{Predicate{"age", OpGe, int32(30)}, true},
+		{Predicate{"age", OpGt, int32(30)}, false},
+		{Predicate{"age", OpLe, int32(-1)}, false},
+		// Values outside the constraints of a field can still be compared
+		{Predicate{"age", OpLt, int32(1000)}, true},
+	}
+	for _, test := range tests {
+		require.NoError(t, s.CheckPredicate(test.pred))
+		matches, err := s.Matches(row, test.pred)
+		require.NoError(t, err)
+		assert.Equal(t, test.matches, matches, "%s", test.pred)
+	}
+
+	matches, err := s.Matches(person(1, nil, 30), Predicate{"city", OpLt, "z"})
+	require.NoError(t, err)
+	assert.False(t, matches)
+
+	assert.Error(t, s.CheckPredicate(Predicate{"country", OpEq, "b"}))
+	assert.Error(t, s.CheckPredicate(Predicate{"age", OpEq, int64(1)}))
+	assert.Error(t, s.CheckPredicate(Predicate{"age", "!=", int32(1)}))
+	assert.Error(t, s.CheckPredicate(Predicate{"tags", OpEq, []interface{}{}}))
+}
+
+func inRange(kr KeyRange, key mst.PrefixKey) bool {
+	opts := kr.Options
+	if opts.Start != nil && key.Less(opts.Start) {
+		return false
+	} else if opts.End != nil && !key.Less(opts.End) {
+		return false
+	}
+	return opts.Prefix == nil || key.HasPrefix(opts.Prefix)
+}
+
+func TestIndexKeyRange(t *testing.T) {
+	s := indexSchema()
+	preds := []Predicate{{"city", OpEq, "b"}, {"age", OpGt, int32(20)}, {"age", OpLe, int32(30)}}
+	kr, err := s.IndexKeyRange("by_city", preds)
+	require.NoError(t, err)
+	assert.Equal(t, 1.5, kr.Fields)
+	for _, row := range []Row{person(1, "b", 21), person(2, "b", 30)} {
+		key, err := s.IndexKeyOf("by_city", row)
+		require.NoError(t, err)
+		assert.True(t, inRange(kr, key), "%v", row)
+	}
+	for _, row := range []Row{
+		person(1, "b", 20), person(2, "b", 31), person(3, "a", 25), person(4, "ba", 25),
+		person(5, nil, 25),
+	} {
+		key, err := s.IndexKeyOf("by_city", row)
+		require.NoError(t, err)
+		assert.False(t, inRange(kr, key), "%v", row)
+	}
+
+	// Predicates on fields that don't lead the index don't narrow the scan
+	kr, err = s.IndexKeyRange("by_city", []Predicate{{"age", OpEq, int32(3)}})
+	require.NoError(t, err)
+	assert.Equal(t, float64(0), kr.Fields)
+	assert.Equal(t, mst.ScanOptions{}, kr.Options)
+
+	kr, err = s.PrimaryKeyRange([]Predicate{{"id", OpEq, int64(3)}})
+	require.NoError(t, err)
+	assert.Equal(t, float64(1), kr.Fields)
+	key, err := s.PrimaryKeyOf(person(3, nil, 0))
+	require.NoError(t, err)
+	assert.True(t, inRange(kr, key))
+}
@@ -0,0 +1,337 @@
+package core
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/vulturedb/vulture/mst"
+)
+
+// Primary key fields are encoded so that comparing the encoded bytes of two keys gives the same
+// result as comparing their fields one by one. Numbers are big endian with their sign flipped so
+// negative numbers sort first, timestamps are numbers of nanoseconds, and strings and bytes are
+// terminated so that a shorter string sorts before any longer string it's a prefix of.
+const (
+	stringEscape     byte = 0x00
+	stringEscapedNul byte = 0xff
+	stringTerminator byte = 0x01
+)
+
+func encodeKeyBytes(w *bytes.Buffer, b []byte) {
+	for _, c := range b {
+		w.WriteByte(c)
+		if c == stringEscape {
+			w.WriteByte(stringEscapedNul)
+		}
+	}
+	w.WriteByte(stringEscape)
+	w.WriteByte(stringTerminator)
+}
+
+func encodeKeyField(w *bytes.Buffer, fieldName string, spec FieldSpec, v interface{}) error {
+	if err := validateValue(fieldName, spec, v); err != nil {
+		return err
+	}
+	buf := make([]byte, 8)
+	switch val := v.(type) {
+	case string:
+		encodeKeyBytes(w, []byte(val))
+	case []byte:
+		encodeKeyBytes(w, val)
+	case time.Time:
+		nanos, err := timestampNanos(val)
+		if err != nil {
+			return err
+		}
+		binary.BigEndian.PutUint64(buf, uint64(nanos)^(1<<63))
+		w.Write(buf)
+	case uuid.UUID:
+		w.Write(val[:])
+	case int32:
+		binary.BigEndian.PutUint32(buf, uint32(val)^(1<<31))
+		w.Write(buf[:4])
+	case int64:
+		binary.BigEndian.PutUint64(buf, uint64(val)^(1<<63))
+		w.Write(buf)
+	case float32:
+		if math.IsNaN(float64(val)) {
+			return fmt.Errorf("Field %s can't be NaN in a key", fieldName)
+		}
+		binary.BigEndian.PutUint32(buf, orderedFloat32Bits(val))
+		w.Write(buf[:4])
+	case float64:
+		if math.IsNaN(val) {
+			return fmt.Errorf("Field %s can't be NaN in a key", fieldName)
+		}
+		binary.BigEndian.PutUint64(buf, orderedFloat64Bits(val))
+		w.Write(buf)
+	case bool:
+		if val {
+			w.WriteByte(1)
+		} else {
+			w.WriteByte(0)
+		}
+	default:
+		return fmt.Errorf("Field %s of type %s can't be part of a key", fieldName, spec.Type)
+	}
+	return nil
+}
+
+// orderedFloat32Bits flips every bit of negative numbers and just the sign bit of positive ones,
+// which makes the bits of floats sort like the floats themselves. Negative zero is treated as zero
+// since they compare equal.
+func orderedFloat32Bits(f float32) uint32 {
+	if f == 0 {
+		f = 0
+	}
+	bits := math.Float32bits(f)
+	if bits&(1<<31) != 0 {
+		return ^bits
+	}
+	return bits | (1 << 31)
+}
+
+func orderedFloat64Bits(f float64) uint64 {
+	if f == 0 {
+		f = 0
+	}
+	bits := math.Float64bits(f)
+	if bits&(1<<63) != 0 {
+		return ^bits
+	}
+	return bits | (1 << 63)
+}
+
+func decodeKeyBytes(r *bytes.Reader) ([]byte, error) {
+	decoded := []byte{}
+	for {
+		b, err := r.ReadByte()
+		if err != nil {
+			return nil, err
+		}
+		if b != stringEscape {
+			decoded = append(decoded, b)
+			continue
+		}
+		next, err := r.ReadByte()
+		if err != nil {
+			return nil, err
+		}
+		if next == stringTerminator {
+			return decoded, nil
+		} else if next != stringEscapedNul {
+			return nil, fmt.Errorf("Invalid escape %d in string", next)
+		}
+		decoded = append(decoded, stringEscape)
+	}
+}
+
+func decodeKeyField(r *bytes.Reader, typeStr string) (interface{}, error) {
+	buf := make([]byte, 8)
+	switch typeStr {
+	case "string", "enum":
+		b, err := decodeKeyBytes(r)
+		if err != nil {
+			return nil, err
+		}
+		return string(b), nil
+	case "bytes":
+		return decodeKeyBytes(r)
+	case "timestamp":
+		if _, err := io.ReadFull(r, buf); err != nil {
+			return nil, err
+		}
+		return time.Unix(0, int64(binary.BigEndian.Uint64(buf)^(1<<63))).UTC(), nil
+	case "uuid":
+		id := uuid.UUID{}
+		if _, err := io.ReadFull(r, id[:]); err != nil {
+			return nil, err
+		}
+		return id, nil
+	case "int":
+		if _, err := io.ReadFull(r, buf[:4]); err != nil {
+			return nil, err
+		}
+		return int32(binary.BigEndian.Uint32(buf) ^ (1 << 31)), nil
+	case "long":
+		if _, err := io.ReadFull(r, buf); err != nil {
+			return nil, err
+		}
+		return int64(binary.BigEndian.Uint64(buf) ^ (1 << 63)), nil
+	case "float":
+		if _, err := io.ReadFull(r, buf[:4]); err != nil {
+			return nil, err
+		}
+		bits := binary.BigEndian.Uint32(buf)
+		if bits&(1<<31) != 0 {
+			bits &^= 1 << 31
+		} else {
+			bits = ^bits
+		}
+		return math.Float32frombits(bits), nil
+	case "double":
+		if _, err := io.ReadFull(r, buf); err != nil {
+			return nil, err
+		}
+		bits := binary.BigEndian.Uint64(buf)
+		if bits&(1<<63) != 0 {
+			bits &^= 1 << 63
+		} else {
+			bits = ^bits
+		}
+		return math.Float64frombits(bits), nil
+	case "boolean":
+		b, err := r.ReadByte()
+		if err != nil {
+			return nil, err
+		} else if b > 1 {
+			return nil, fmt.Errorf("Invalid boolean %d", b)
+		}
+		return b == 1, nil
+	default:
+		return nil, fmt.Errorf("Unsupported key type %s", typeStr)
+	}
+}
+
+// PrimaryKey is an mst.Key made of the primary key fields of a row. It sorts the same way as the
+// tuple of its fields, and the key made of a row's leading primary key fields is a prefix of the
+// row's full key.
+type PrimaryKey struct {
+	encoded []byte
+	values  []interface{}
+}
+
+// Values returns the fields of the key in primary key order
+func (k PrimaryKey) Values() []interface{} {
+	return k.values
+}
+
+// Bytes returns the order preserving encoding of the key
+func (k PrimaryKey) Bytes() []byte {
+	return k.encoded
+}
+
+func (k PrimaryKey) Less(than mst.Key) bool {
+	return bytes.Compare(k.encoded, than.(PrimaryKey).encoded) < 0
+}
+
+func (k PrimaryKey) Write(w io.Writer) error {
+	_, err := w.Write(k.encoded)
+	return err
+}
+
+func (k PrimaryKey) HasPrefix(prefix mst.Key) bool {
+	return bytes.HasPrefix(k.encoded, prefix.(PrimaryKey).encoded)
+}
+
+func (s Schema) primaryKeyTypes() []string {
+	types := make([]string, len(s.PrimaryKey))
+	for i, fieldName := range s.PrimaryKey {
+		types[i] = s.Fields[fieldName].Type
+	}
+	return types
+}
+
+func (s Schema) encodePrimaryKey(r Row, numFields int) (PrimaryKey, error) {
+	w := new(bytes.Buffer)
+	values := make([]interface{}, numFields)
+	for i, fieldName := range s.PrimaryKey[:numFields] {
+		v, ok := r.Data[fieldName]
+		if !ok {
+			return PrimaryKey{}, fmt.Errorf("Primary key field missing %s", fieldName)
+		}
+		err := encodeKeyField(w, fieldName, s.Fields[fieldName], v)
+		if err != nil {
+			return PrimaryKey{}, err
+		}
+		values[i] = v
+	}
+	return PrimaryKey{w.Bytes(), values}, nil
+}
+
+// PrimaryKeyOf returns the primary key of a row. Fields that aren't part of the primary key are
+// ignored.
+func (s Schema) PrimaryKeyOf(r Row) (PrimaryKey, error) {
+	return s.encodePrimaryKey(r, len(s.PrimaryKey))
+}
+
+// PrimaryKeyPrefix returns the key made of the leading primary key fields that are set in r. Every
+// row whose leading fields match has a primary key with this prefix.
+func (s Schema) PrimaryKeyPrefix(r Row) (PrimaryKey, error) {
+	numFields := 0
+	for _, fieldName := range s.PrimaryKey {
+		if _, ok := r.Data[fieldName]; !ok {
+			break
+		}
+		numFields++
+	}
+	for _, fieldName := range s.PrimaryKey[numFields:] {
+		if _, ok := r.Data[fieldName]; ok {
+			return PrimaryKey{}, fmt.Errorf("Primary key field %s is set after a missing one", fieldName)
+		}
+	}
+	return s.encodePrimaryKey(r, numFields)
+}
+
+// PrimaryKeyReader reads the primary keys of a schema back from their encoding. Keyspaces name it
+// "primary-key:<types>", with the types of the primary key fields separated by commas, see Name.
+type PrimaryKeyReader struct {
+	types []string
+}
+
+func init() {
+	mst.RegisterKeyType("primary-key", func(arg string) (mst.KeyReader, error) {
+		types, err := parseKeyTypes(arg)
+		if err != nil {
+			return nil, err
+		}
+		return PrimaryKeyReader{types}, nil
+	})
+}
+
+// parseKeyTypes parses the types of key fields separated by commas
+func parseKeyTypes(s string) ([]string, error) {
+	if s == "" {
+		return nil, fmt.Errorf("Key has no fields")
+	}
+	types := strings.Split(s, ",")
+	for _, typeStr := range types {
+		if !isKeyType(typeStr) {
+			return nil, fmt.Errorf("Key fields can't be of type %s", typeStr)
+		}
+	}
+	return types, nil
+}
+
+// Name names the key type of keyspaces whose keys are read by kr, see mst.KeyReaderFor
+func (kr PrimaryKeyReader) Name() string {
+	return "primary-key:" + strings.Join(kr.types, ",")
+}
+
+// PrimaryKeyReader returns the reader for the primary keys of rows in this schema
+func (s Schema) PrimaryKeyReader() PrimaryKeyReader {
+	return PrimaryKeyReader{s.primaryKeyTypes()}
+}
+
+func (kr PrimaryKeyReader) FromBytes(b []byte) (mst.Key, error) {
+	r := bytes.NewReader(b)
+	values := make([]interface{}, len(kr.types))
+	for i, typeStr := range kr.types {
+		v, err := decodeKeyField(r, typeStr)
+		if err != nil {
+			return nil, fmt.Errorf("Couldn't decode primary key field %d: %w", i, err)
+		}
+		values[i] = v
+	}
+	if r.Len() != 0 {
+		return nil, fmt.Errorf("Encoded primary key has %d trailing bytes", r.Len())
+	}
+	return PrimaryKey{append([]byte{}, b...), values}, nil
+}
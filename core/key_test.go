@@ -0,0 +1,228 @@
+package core
+
+import (
+	"bytes"
+	"math"
+	"math/rand"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/vulturedb/vulture/mst"
+)
+
+var keyTypes = []string{
+	"string", "int", "long", "float", "double", "boolean", "bytes", "timestamp", "uuid",
+}
+
+func keySchema() Schema {
+	fields := map[string]FieldSpec{}
+	for _, typeStr := range keyTypes {
+		fields[typeStr] = FieldSpec{Type: typeStr}
+	}
+	return Schema{Fields: fields, PrimaryKey: keyTypes}
+}
+
+func randomKeyValue(r *rand.Rand, typeStr string) interface{} {
+	switch typeStr {
+	case "string":
+		// A small alphabet including zero bytes so that strings often share prefixes
+		b := make([]byte, r.Intn(4))
+		for i := range b {
+			b[i] = []byte{0, 1, 'a', 0xff}[r.Intn(4)]
+		}
+		return string(b)
+	case "int":
+		return []int32{math.MinInt32, -1, 0, 1, math.MaxInt32, r.Int31() - r.Int31()}[r.Intn(6)]
+	case "long":
+		return []int64{math.MinInt64, -1, 0, 1, math.MaxInt64, r.Int63() - r.Int63()}[r.Intn(6)]
+	case "float":
+		return []float32{
+			float32(math.Inf(-1)), -1.5, 0, 1.5, math.MaxFloat32, math.SmallestNonzeroFloat32,
+			float32(r.NormFloat64()),
+		}[r.Intn(7)]
+	case "double":
+		return []float64{
+			math.Inf(-1), -1.5, 0, math.SmallestNonzeroFloat64, 1.5, math.Inf(1), r.NormFloat64(),
+		}[r.Intn(7)]
+	case "bytes":
+		return []byte(randomKeyValue(r, "string").(string))
+	case "timestamp":
+		return time.Unix(0, r.Int63()-r.Int63()).UTC()
+	case "uuid":
+		id := uuid.UUID{}
+		id[r.Intn(16)] = byte(r.Intn(256))
+		return id
+	default:
+		return r.Intn(2) == 1
+	}
+}
+
+func compareKeyValues(a interface{}, b interface{}) int {
+	less := false
+	switch av := a.(type) {
+	case []byte:
+		return bytes.Compare(av, b.([]byte))
+	case time.Time:
+		if av.Equal(b.(time.Time)) {
+			return 0
+		} else if av.Before(b.(time.Time)) {
+			return -1
+		}
+		return 1
+	case uuid.UUID:
+		bv := b.(uuid.UUID)
+		return bytes.Compare(av[:], bv[:])
+	case string:
+		less = av < b.(string)
+	case int32:
+		less = av < b.(int32)
+	case int64:
+		less = av < b.(int64)
+	case float32:
+		less = av < b.(float32)
+	case float64:
+		less = av < b.(float64)
+	case bool:
+		less = !av && b.(bool)
+	}
+	if less {
+		return -1
+	} else if a == b {
+		return 0
+	}
+	return 1
+}
+
+func compareTuples(a []interface{}, b []interface{}) int {
+	for i := range a {
+		if c := compareKeyValues(a[i], b[i]); c != 0 {
+			return c
+		}
+	}
+	return 0
+}
+
+func TestPrimaryKeySortsLikeTuple(t *testing.T) {
+	schema := keySchema()
+	r := rand.New(rand.NewSource(42))
+	keys := []PrimaryKey{}
+	for i := 0; i < 1000; i++ {
+		data := map[string]interface{}{}
+		for _, typeStr := range keyTypes {
+			data[typeStr] = randomKeyValue(r, typeStr)
+		}
+		key, err := schema.PrimaryKeyOf(Row{Data: data})
+		require.NoError(t, err)
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i].Less(keys[j]) })
+	for i := 1; i < len(keys); i++ {
+		assert.LessOrEqual(t, compareTuples(keys[i-1].Values(), keys[i].Values()), 0)
+	}
+}
+
+func TestPrimaryKeyReader(t *testing.T) {
+	schema := keySchema()
+	kr := schema.PrimaryKeyReader()
+	r := rand.New(rand.NewSource(7))
+	for i := 0; i < 100; i++ {
+		data := map[string]interface{}{}
+		for _, typeStr := range keyTypes {
+			data[typeStr] = randomKeyValue(r, typeStr)
+		}
+		key, err := schema.PrimaryKeyOf(Row{Data: data})
+		require.NoError(t, err)
+		decoded, err := kr.FromBytes(key.Bytes())
+		require.NoError(t, err)
+		assert.Equal(t, key, decoded)
+
+		encoded := key.Bytes()
+		for j := 0; j < len(encoded); j++ {
+			_, err := kr.FromBytes(encoded[:j])
+			assert.Error(t, err)
+		}
+		_, err = kr.FromBytes(append(append([]byte{}, encoded...), 0))
+		assert.Error(t, err)
+	}
+}
+
+func TestPrimaryKeyReaderFor(t *testing.T) {
+	kr := keySchema().PrimaryKeyReader()
+	named, err := mst.KeyReaderFor(kr.Name())
+	require.NoError(t, err)
+	assert.Equal(t, kr, named)
+
+	for _, name := range []string{"primary-key", "primary-key:", "primary-key:int,array"} {
+		_, err := mst.KeyReaderFor(name)
+		assert.Error(t, err, name)
+	}
+}
+
+func TestPrimaryKeyNegativeZero(t *testing.T) {
+	schema := Schema{
+		Fields:     map[string]FieldSpec{"d": {Type: "double"}},
+		PrimaryKey: []string{"d"},
+	}
+	zero, err := schema.PrimaryKeyOf(Row{Data: map[string]interface{}{"d": float64(0)}})
+	require.NoError(t, err)
+	negZero, err := schema.PrimaryKeyOf(Row{Data: map[string]interface{}{"d": math.Copysign(0, -1)}})
+	require.NoError(t, err)
+	assert.Equal(t, zero.Bytes(), negZero.Bytes())
+
+	_, err = schema.PrimaryKeyOf(Row{Data: map[string]interface{}{"d": math.NaN()}})
+	assert.Error(t, err)
+}
+
+func TestPrimaryKeyPrefix(t *testing.T) {
+	schema := Schema{
+		Fields: map[string]FieldSpec{
+			"a": {Type: "string"},
+			"b": {Type: "int"},
+			"c": {Type: "boolean"},
+		},
+		PrimaryKey: []string{"a", "b", "c"},
+	}
+	full, err := schema.PrimaryKeyOf(Row{Data: map[string]interface{}{
+		"a": "ab", "b": int32(-3), "c": true,
+	}})
+	require.NoError(t, err)
+
+	prefix, err := schema.PrimaryKeyPrefix(Row{Data: map[string]interface{}{"a": "ab"}})
+	require.NoError(t, err)
+	assert.True(t, full.HasPrefix(prefix))
+
+	// A string field only matches whole values, not string prefixes
+	prefix, err = schema.PrimaryKeyPrefix(Row{Data: map[string]interface{}{"a": "a"}})
+	require.NoError(t, err)
+	assert.False(t, full.HasPrefix(prefix))
+
+	empty, err := schema.PrimaryKeyPrefix(Row{})
+	require.NoError(t, err)
+	assert.True(t, full.HasPrefix(empty))
+
+	_, err = schema.PrimaryKeyPrefix(Row{Data: map[string]interface{}{"b": int32(1)}})
+	assert.Error(t, err)
+}
+
+func TestPrimaryKeyEnum(t *testing.T) {
+	schema := Schema{
+		Fields: map[string]FieldSpec{
+			"status": {Type: "enum", Symbols: []string{"open", "closed"}},
+		},
+		PrimaryKey: []string{"status"},
+	}
+	require.NoError(t, schema.Validate())
+	key, err := schema.PrimaryKeyOf(Row{Data: map[string]interface{}{"status": "open"}})
+	require.NoError(t, err)
+	decoded, err := schema.PrimaryKeyReader().FromBytes(key.Bytes())
+	require.NoError(t, err)
+	assert.Equal(t, key, decoded)
+
+	_, err = schema.PrimaryKeyOf(Row{Data: map[string]interface{}{"status": "other"}})
+	assert.Error(t, err)
+}
@@ -0,0 +1,753 @@
+package core
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+	"math/big"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/vulturedb/vulture/internal/dots"
+	"github.com/vulturedb/vulture/mst"
+)
+
+// Merge policies a top level field can declare. They decide how concurrent writes to the field on
+// different replicas are combined, see RowValue.
+const (
+	// MergeLWW keeps the value written with the latest hybrid logical clock timestamp. It's the
+	// policy of fields that don't declare one.
+	MergeLWW = "lww"
+	// MergeMax and MergeMin keep the largest or smallest value ever written
+	MergeMax = "max"
+	MergeMin = "min"
+	// MergeGCounter and MergePNCounter count per replica, so that concurrent increments add up.
+	// Grow-only counters can't be decreased.
+	MergeGCounter  = "gcounter"
+	MergePNCounter = "pncounter"
+	// MergeSet treats an array as a set where an element added concurrently with its removal stays
+	MergeSet = "set"
+	// MergeMVR keeps every value written concurrently. The row shows the latest one and the rest
+	// can be read with RowValue.Values.
+	MergeMVR = "mvr"
+)
+
+const (
+	policyLWW byte = iota + 1
+	policyMax
+	policyMin
+	policyGCounter
+	policyPNCounter
+	policySet
+	policyMVR
+)
+
+var policyTags = map[string]byte{
+	"":             policyLWW,
+	MergeLWW:       policyLWW,
+	MergeMax:       policyMax,
+	MergeMin:       policyMin,
+	MergeGCounter:  policyGCounter,
+	MergePNCounter: policyPNCounter,
+	MergeSet:       policySet,
+	MergeMVR:       policyMVR,
+}
+
+// validateMerge checks that a top level field's merge policy exists and works for its type
+func validateMerge(fieldName string, spec FieldSpec, isKey bool) error {
+	tag, ok := policyTags[spec.Merge]
+	if !ok {
+		return fmt.Errorf("Unknown merge policy %s for field %s", spec.Merge, fieldName)
+	}
+	if isKey && tag != policyLWW {
+		return fmt.Errorf("Primary key field %s can't have merge policy %s", fieldName, spec.Merge)
+	}
+	valid := true
+	switch tag {
+	case policyMax, policyMin:
+		switch spec.Type {
+		case "int", "long", "float", "double", "decimal", "string", "timestamp":
+		default:
+			valid = false
+		}
+	case policyGCounter, policyPNCounter:
+		valid = spec.Type == "long"
+	case policySet:
+		valid = spec.Type == "array"
+	}
+	if !valid {
+		return fmt.Errorf(
+			"Merge policy %s doesn't work for field %s of type %s",
+			spec.Merge,
+			fieldName,
+			spec.Type,
+		)
+	}
+	return nil
+}
+
+func putUint64(w *bytes.Buffer, n uint64) {
+	buf := make([]byte, 8)
+	binary.LittleEndian.PutUint64(buf, n)
+	w.Write(buf)
+}
+
+func getUint64(r *bytes.Reader) (uint64, error) {
+	buf := make([]byte, 8)
+	if _, err := io.ReadFull(r, buf); err != nil {
+		return 0, err
+	}
+	return binary.LittleEndian.Uint64(buf), nil
+}
+
+func putString(w *bytes.Buffer, s string) {
+	putLength(w, len(s))
+	w.WriteString(s)
+}
+
+func getString(r *bytes.Reader) (string, error) {
+	b, err := getBytes(r)
+	return string(b), err
+}
+
+func putHLC(w *bytes.Buffer, t HLC) {
+	putUint64(w, t.Wall)
+	putLength(w, int(t.Logical))
+	putString(w, t.Replica)
+}
+
+func getHLC(r *bytes.Reader) (HLC, error) {
+	wall, err := getUint64(r)
+	if err != nil {
+		return HLC{}, err
+	}
+	buf := make([]byte, 4)
+	if _, err := io.ReadFull(r, buf); err != nil {
+		return HLC{}, err
+	}
+	replica, err := getString(r)
+	if err != nil {
+		return HLC{}, err
+	}
+	return HLC{wall, binary.LittleEndian.Uint32(buf), replica}, nil
+}
+
+func encodeValue(v interface{}) ([]byte, error) {
+	w := new(bytes.Buffer)
+	if err := encodeField(w, v); err != nil {
+		return nil, err
+	}
+	return w.Bytes(), nil
+}
+
+// compareValues orders values for the max and min policies. Numbers of different types compare
+// by value since a field's type may have been widened, and NaN sorts before every other number.
+// Values that compare equal, like 1.0 and 1.00, are ordered by their encoding so that every replica
+// agrees on which one to keep.
+func compareValues(a interface{}, b interface{}, aEncoded []byte, bEncoded []byte) int {
+	c := 0
+	aRank, aRat := numberRank(a)
+	bRank, bRat := numberRank(b)
+	switch {
+	case aRank != notNumber && bRank != notNumber:
+		c = compareUint64(uint64(aRank), uint64(bRank))
+		if c == 0 && aRank == finite {
+			c = aRat.Cmp(bRat)
+		}
+	default:
+		switch av := a.(type) {
+		case string:
+			if bv, ok := b.(string); ok {
+				c = strings.Compare(av, bv)
+			}
+		case time.Time:
+			if bv, ok := b.(time.Time); ok {
+				if av.Before(bv) {
+					c = -1
+				} else if av.After(bv) {
+					c = 1
+				}
+			}
+		}
+	}
+	if c == 0 {
+		c = bytes.Compare(aEncoded, bEncoded)
+	}
+	return c
+}
+
+// Numbers are ranked first, in this order, and compared by value within the finite rank
+const (
+	notNumber = iota
+	nan
+	negativeInfinity
+	finite
+	positiveInfinity
+)
+
+func numberRank(v interface{}) (int, *big.Rat) {
+	var f float64
+	switch val := v.(type) {
+	case int32:
+		return finite, new(big.Rat).SetInt64(int64(val))
+	case int64:
+		return finite, new(big.Rat).SetInt64(val)
+	case Decimal:
+		n, _ := numberToRat("decimal", val)
+		return finite, n
+	case float32:
+		f = float64(val)
+	case float64:
+		f = val
+	default:
+		return notNumber, nil
+	}
+	switch {
+	case math.IsNaN(f):
+		return nan, nil
+	case math.IsInf(f, -1):
+		return negativeInfinity, nil
+	case math.IsInf(f, 1):
+		return positiveInfinity, nil
+	default:
+		return finite, new(big.Rat).SetFloat64(f)
+	}
+}
+
+// fieldState is the replicated state of a single field. States merge with states of the same
+// policy.
+type fieldState interface {
+	policy() byte
+	merge(with fieldState) fieldState
+	// value returns the field's value and whether it's set
+	value() (interface{}, bool)
+	write(w *bytes.Buffer)
+}
+
+type lwwState struct {
+	timestamp HLC
+	set       bool
+	val       interface{}
+	encoded   []byte
+}
+
+func (s lwwState) policy() byte {
+	return policyLWW
+}
+
+func (s lwwState) merge(with fieldState) fieldState {
+	other := with.(lwwState)
+	c := s.timestamp.Compare(other.timestamp)
+	if c == 0 {
+		c = bytes.Compare(s.encoded, other.encoded)
+	}
+	if c >= 0 {
+		return s
+	}
+	return other
+}
+
+func (s lwwState) value() (interface{}, bool) {
+	return s.val, s.set
+}
+
+func (s lwwState) write(w *bytes.Buffer) {
+	putHLC(w, s.timestamp)
+	if s.set {
+		w.WriteByte(1)
+		w.Write(s.encoded)
+	} else {
+		w.WriteByte(0)
+	}
+}
+
+// extremeState keeps the largest value for the max policy and the smallest for the min policy
+type extremeState struct {
+	tag     byte
+	val     interface{}
+	encoded []byte
+}
+
+func (s extremeState) policy() byte {
+	return s.tag
+}
+
+func (s extremeState) merge(with fieldState) fieldState {
+	other := with.(extremeState)
+	c := compareValues(s.val, other.val, s.encoded, other.encoded)
+	if s.tag == policyMin {
+		c = -c
+	}
+	if c >= 0 {
+		return s
+	}
+	return other
+}
+
+func (s extremeState) value() (interface{}, bool) {
+	return s.val, true
+}
+
+func (s extremeState) write(w *bytes.Buffer) {
+	w.Write(s.encoded)
+}
+
+// counterState is a PN-counter, or a G-counter if decrements is always empty
+type counterState struct {
+	tag        byte
+	increments dots.Counts
+	decrements dots.Counts
+}
+
+func (s counterState) policy() byte {
+	return s.tag
+}
+
+func (s counterState) merge(with fieldState) fieldState {
+	other := with.(counterState)
+	return counterState{
+		s.tag,
+		s.increments.Merge(other.increments),
+		s.decrements.Merge(other.decrements),
+	}
+}
+
+func (s counterState) value() (interface{}, bool) {
+	return int64(s.increments.Sum() - s.decrements.Sum()), true
+}
+
+func (s counterState) write(w *bytes.Buffer) {
+	s.increments.Write(w)
+	if s.tag == policyPNCounter {
+		s.decrements.Write(w)
+	}
+}
+
+// dottedWrite is what a dottedState keeps about the write of each of its values
+type dottedWrite struct {
+	val       interface{}
+	timestamp HLC
+}
+
+// dottedState is the state of both the add-wins set and the multi-value register, a kernel of
+// values tagged with the writes that added them, see dots.Kernel. Each value's payload is its
+// dottedWrite.
+type dottedState struct {
+	tag    byte
+	kernel dots.Kernel
+}
+
+func (s dottedState) policy() byte {
+	return s.tag
+}
+
+func (s dottedState) merge(with fieldState) fieldState {
+	return dottedState{s.tag, s.kernel.Merge(with.(dottedState).kernel)}
+}
+
+// add returns the state with a new value written by replica at timestamp
+func (s dottedState) add(val interface{}, encoded []byte, timestamp HLC) dottedState {
+	write := dottedWrite{val, timestamp}
+	return dottedState{s.tag, s.kernel.Add(timestamp.Replica, encoded, write)}
+}
+
+// elements returns the distinct values in the state, in the order of their encoding
+func (s dottedState) elements() []interface{} {
+	distinct := s.kernel.Distinct()
+	elements := make([]interface{}, len(distinct))
+	for i, v := range distinct {
+		elements[i] = v.Payload.(dottedWrite).val
+	}
+	return elements
+}
+
+func (s dottedState) value() (interface{}, bool) {
+	if s.tag == policySet {
+		return s.elements(), true
+	}
+	values := s.kernel.Values()
+	if len(values) == 0 {
+		return nil, false
+	}
+	latest := values[0].Payload.(dottedWrite)
+	for _, v := range values[1:] {
+		if write := v.Payload.(dottedWrite); write.timestamp.Compare(latest.timestamp) > 0 {
+			latest = write
+		}
+	}
+	return latest.val, true
+}
+
+func (s dottedState) write(w *bytes.Buffer) {
+	s.kernel.Write(w, func(w *bytes.Buffer, v dots.Value) {
+		putHLC(w, v.Payload.(dottedWrite).timestamp)
+	})
+}
+
+// readDottedWrite decodes the value of v and reads the timestamp of its write
+func readDottedWrite(r *bytes.Reader, v *dots.Value) error {
+	val, encoded, err := readValue(bytes.NewReader(v.Bytes))
+	if err != nil {
+		return err
+	} else if len(encoded) != len(v.Bytes) {
+		return fmt.Errorf("Value has %d trailing bytes", len(v.Bytes)-len(encoded))
+	}
+	timestamp, err := getHLC(r)
+	if err != nil {
+		return err
+	}
+	v.Payload = dottedWrite{val, timestamp}
+	return nil
+}
+
+func readState(r *bytes.Reader, tag byte) (fieldState, error) {
+	switch tag {
+	case policyLWW:
+		timestamp, err := getHLC(r)
+		if err != nil {
+			return nil, err
+		}
+		set, err := r.ReadByte()
+		if err != nil {
+			return nil, err
+		}
+		state := lwwState{timestamp: timestamp, set: set == 1}
+		if state.set {
+			state.val, state.encoded, err = readValue(r)
+		}
+		return state, err
+	case policyMax, policyMin:
+		val, encoded, err := readValue(r)
+		return extremeState{tag, val, encoded}, err
+	case policyGCounter, policyPNCounter:
+		increments, err := dots.ReadCounts(r)
+		if err != nil {
+			return nil, err
+		}
+		decrements := dots.Counts{}
+		if tag == policyPNCounter {
+			decrements, err = dots.ReadCounts(r)
+		}
+		return counterState{tag, increments, decrements}, err
+	case policySet, policyMVR:
+		kernel, err := dots.ReadKernel(r, readDottedWrite)
+		return dottedState{tag, kernel}, err
+	default:
+		return nil, fmt.Errorf("Unknown merge policy %d", tag)
+	}
+}
+
+// readValue decodes a field value along with the bytes it was encoded as
+func readValue(r *bytes.Reader) (interface{}, []byte, error) {
+	start := r.Size() - int64(r.Len())
+	val, err := decodeField(r)
+	if err != nil {
+		return nil, nil, err
+	}
+	end := r.Size() - int64(r.Len())
+	encoded := make([]byte, end-start)
+	if _, err := r.ReadAt(encoded, start); err != nil {
+		return nil, nil, err
+	}
+	return val, encoded, nil
+}
+
+// RowValue is the replicated state of a row, stored as an mst.Value. Each top level field is merged
+// according to the merge policy its schema declares, so replicas that write the same row
+// concurrently converge without losing each other's writes.
+type RowValue struct {
+	fields map[string]fieldState
+}
+
+// NewRowValue creates the state of a row that has never been written
+func NewRowValue() RowValue {
+	return RowValue{map[string]fieldState{}}
+}
+
+// Update returns the state after the replica of timestamp wrote row, which must be valid for
+// schema. Every field takes the value in the row, each according to its policy: counters count the
+// difference to their current value, sets add the elements that are new and remove those that are
+// missing, max and min keep the largest or smallest value and the other policies replace the
+// value. Counters, max and min fields that the row leaves out keep their state.
+func (v RowValue) Update(schema Schema, row Row, timestamp HLC) (RowValue, error) {
+	fields := make(map[string]fieldState, len(schema.Fields))
+	for fieldName, state := range v.fields {
+		fields[fieldName] = state
+	}
+	for fieldName, spec := range schema.Fields {
+		val := row.Data[fieldName]
+		state, err := updateState(fields[fieldName], spec, val, timestamp)
+		if err != nil {
+			return RowValue{}, fmt.Errorf("Couldn't update field %s: %w", fieldName, err)
+		}
+		if state != nil {
+			fields[fieldName] = state
+		}
+	}
+	return RowValue{fields}, nil
+}
+
+// KeepsState reports whether a write that leaves out a field keeps the field's state, which
+// counters, max and min fields do once they have one. Their defaults only apply to new rows, since
+// writing the default would count down to it or be lost in the maximum or minimum.
+func (v RowValue) KeepsState(fieldName string, spec FieldSpec) bool {
+	if _, exists := v.fields[fieldName]; !exists {
+		return false
+	}
+	switch policyTags[spec.Merge] {
+	case policyMax, policyMin, policyGCounter, policyPNCounter:
+		return true
+	default:
+		return false
+	}
+}
+
+// updateState returns the state of a field after a write of val, or nil if the field has no state
+func updateState(
+	state fieldState,
+	spec FieldSpec,
+	val interface{},
+	timestamp HLC,
+) (fieldState, error) {
+	tag := policyTags[spec.Merge]
+	if state != nil && state.policy() != tag {
+		return nil, fmt.Errorf("Field has merge policy %d instead of %d", state.policy(), tag)
+	}
+	var encoded []byte
+	if val != nil {
+		var err error
+		encoded, err = encodeValue(val)
+		if err != nil {
+			return nil, err
+		}
+	}
+	switch tag {
+	case policyMax, policyMin:
+		if val == nil {
+			return state, nil
+		}
+		written := extremeState{tag, val, encoded}
+		if state == nil {
+			return written, nil
+		}
+		return state.merge(written), nil
+	case policyGCounter, policyPNCounter:
+		if val == nil {
+			return state, nil
+		}
+		counter := counterState{tag, dots.Counts{}, dots.Counts{}}
+		if state != nil {
+			counter = state.(counterState)
+		}
+		current, _ := counter.value()
+		delta := val.(int64) - current.(int64)
+		replica := timestamp.Replica
+		if delta >= 0 {
+			counter.increments = counter.increments.With(
+				replica,
+				counter.increments[replica]+uint64(delta),
+			)
+		} else if tag == policyGCounter {
+			return nil, fmt.Errorf(
+				"Grow-only counter can't decrease from %d to %d",
+				current,
+				val,
+			)
+		} else {
+			counter.decrements = counter.decrements.With(
+				replica,
+				counter.decrements[replica]+uint64(-delta),
+			)
+		}
+		return counter, nil
+	case policySet:
+		set := dottedState{tag, dots.NewKernel()}
+		if state != nil {
+			set = state.(dottedState)
+		}
+		elements, _ := val.([]interface{})
+		wanted := make(map[string]interface{}, len(elements))
+		for _, element := range elements {
+			encodedElement, err := encodeValue(element)
+			if err != nil {
+				return nil, err
+			}
+			wanted[string(encodedElement)] = element
+		}
+		for _, element := range set.kernel.Elements() {
+			if _, ok := wanted[string(element)]; ok {
+				delete(wanted, string(element))
+			} else {
+				set = dottedState{tag, set.kernel.Remove(element)}
+			}
+		}
+		added := make([]string, 0, len(wanted))
+		for encodedElement := range wanted {
+			added = append(added, encodedElement)
+		}
+		sort.Strings(added)
+		for _, encodedElement := range added {
+			set = set.add(wanted[encodedElement], []byte(encodedElement), timestamp)
+		}
+		return set, nil
+	case policyMVR:
+		register := dottedState{tag, dots.NewKernel()}
+		if state != nil {
+			register = state.(dottedState)
+		}
+		register = dottedState{tag, register.kernel.RemoveAll()}
+		if val != nil {
+			register = register.add(val, encoded, timestamp)
+		}
+		return register, nil
+	default:
+		return lwwState{timestamp, val != nil, val, encoded}, nil
+	}
+}
+
+// Row returns the row as it's currently seen, i.e. with every field that's set
+func (v RowValue) Row() Row {
+	data := make(map[string]interface{}, len(v.fields))
+	for fieldName, state := range v.fields {
+		if val, ok := state.value(); ok {
+			data[fieldName] = val
+		}
+	}
+	return Row{Data: data}
+}
+
+// Values returns every value a field currently has. Only multi-value registers can have more than
+// one, when replicas wrote them concurrently, and they're returned in the order of their encoding.
+// Row shows the one with the latest timestamp.
+func (v RowValue) Values(fieldName string) []interface{} {
+	state, ok := v.fields[fieldName]
+	if !ok {
+		return nil
+	}
+	if register, ok := state.(dottedState); ok && register.tag == policyMVR {
+		return register.elements()
+	}
+	if val, ok := state.value(); ok {
+		return []interface{}{val}
+	}
+	return nil
+}
+
+// Timestamp returns the latest timestamp of any write the state has seen. Counters, max and min
+// fields don't keep the timestamps of their writes, so they're not taken into account.
+func (v RowValue) Timestamp() HLC {
+	var latest HLC
+	for _, state := range v.fields {
+		switch s := state.(type) {
+		case lwwState:
+			if s.timestamp.Compare(latest) > 0 {
+				latest = s.timestamp
+			}
+		case dottedState:
+			for _, val := range s.kernel.Values() {
+				if timestamp := val.Payload.(dottedWrite).timestamp; timestamp.Compare(latest) > 0 {
+					latest = timestamp
+				}
+			}
+		}
+	}
+	return latest
+}
+
+// Write writes the state with fields, replicas and values in a fixed order, so replicas with the
+// same state write the same bytes
+func (v RowValue) Write(w io.Writer) error {
+	buf := new(bytes.Buffer)
+	fieldNames := make([]string, 0, len(v.fields))
+	for fieldName := range v.fields {
+		fieldNames = append(fieldNames, fieldName)
+	}
+	sort.Strings(fieldNames)
+	putLength(buf, len(fieldNames))
+	for _, fieldName := range fieldNames {
+		state := v.fields[fieldName]
+		putString(buf, fieldName)
+		buf.WriteByte(state.policy())
+		state.write(buf)
+	}
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+// Merge merges the states of the fields of both rows. Fields only one row has state for are kept
+// as they are.
+func (v RowValue) Merge(with mst.Value) mst.Value {
+	other := with.(RowValue)
+	fields := make(map[string]fieldState, len(v.fields)+len(other.fields))
+	for fieldName, state := range v.fields {
+		fields[fieldName] = state
+	}
+	for fieldName, otherState := range other.fields {
+		state, ok := fields[fieldName]
+		switch {
+		case !ok:
+			fields[fieldName] = otherState
+		case state.policy() == otherState.policy():
+			fields[fieldName] = state.merge(otherState)
+		default:
+			// Policies can't change, so this only happens with corrupt states. Keep either one
+			// deterministically.
+			if bytes.Compare(stateBytes(state), stateBytes(otherState)) < 0 {
+				fields[fieldName] = otherState
+			}
+		}
+	}
+	return RowValue{fields}
+}
+
+func stateBytes(state fieldState) []byte {
+	buf := new(bytes.Buffer)
+	buf.WriteByte(state.policy())
+	state.write(buf)
+	return buf.Bytes()
+}
+
+// RowValueReader reads RowValues written by RowValue.Write
+type RowValueReader struct{}
+
+// RowValueType names the value type of keyspaces of rows, see mst.ValueReaderFor
+const RowValueType = "row"
+
+func init() {
+	mst.RegisterValueType(RowValueType, func(arg string) (mst.ValueReader, error) {
+		if arg != "" {
+			return nil, fmt.Errorf("Rows take no argument")
+		}
+		return RowValueReader{}, nil
+	})
+}
+
+func (vr RowValueReader) FromBytes(b []byte) (mst.Value, error) {
+	r := bytes.NewReader(b)
+	n, err := getLength(r)
+	if err != nil {
+		return nil, err
+	}
+	fields := make(map[string]fieldState, n)
+	for i := 0; i < n; i++ {
+		fieldName, err := getString(r)
+		if err != nil {
+			return nil, err
+		}
+		tag, err := r.ReadByte()
+		if err != nil {
+			return nil, err
+		}
+		fields[fieldName], err = readState(r, tag)
+		if err != nil {
+			return nil, fmt.Errorf("Couldn't read field %s: %w", fieldName, err)
+		}
+	}
+	if r.Len() > 0 {
+		return nil, fmt.Errorf("Unexpected %d bytes after row value", r.Len())
+	}
+	return RowValue{fields}, nil
+}
@@ -0,0 +1,263 @@
+package core
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"math/big"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/vulturedb/vulture/mst"
+	"github.com/vulturedb/vulture/mst/msttest"
+)
+
+func countersSchema() Schema {
+	return Schema{
+		Fields: map[string]FieldSpec{
+			"id":      {Type: "string"},
+			"name":    {Type: "string", Nullable: true},
+			"high":    {Type: "double", Merge: MergeMax},
+			"low":     {Type: "decimal", Merge: MergeMin},
+			"visits":  {Type: "long", Merge: MergeGCounter},
+			"balance": {Type: "long", Merge: MergePNCounter},
+			"tags":    {Type: "array", Items: &FieldSpec{Type: "string"}, Merge: MergeSet},
+			"status":  {Type: "string", Nullable: true, Merge: MergeMVR},
+		},
+		PrimaryKey: []string{"id"},
+	}
+}
+
+func rowValueBytes(t *testing.T, v RowValue) []byte {
+	buf := new(bytes.Buffer)
+	require.NoError(t, v.Write(buf))
+	return buf.Bytes()
+}
+
+func TestValidateMerge(t *testing.T) {
+	tests := []struct {
+		name   string
+		change func(s *Schema)
+		valid  bool
+	}{
+		{"valid", func(s *Schema) {}, true},
+		{"explicit lww", func(s *Schema) {
+			s.Fields["name"] = FieldSpec{Type: "string", Merge: MergeLWW}
+		}, true},
+		{"unknown", func(s *Schema) {
+			s.Fields["name"] = FieldSpec{Type: "string", Merge: "sum"}
+		}, false},
+		{"max boolean", func(s *Schema) {
+			s.Fields["name"] = FieldSpec{Type: "boolean", Merge: MergeMax}
+		}, false},
+		{"int counter", func(s *Schema) {
+			s.Fields["visits"] = FieldSpec{Type: "int", Merge: MergeGCounter}
+		}, false},
+		{"string set", func(s *Schema) {
+			s.Fields["tags"] = FieldSpec{Type: "string", Merge: MergeSet}
+		}, false},
+		{"primary key", func(s *Schema) {
+			s.Fields["id"] = FieldSpec{Type: "string", Merge: MergeMax}
+		}, false},
+		{"nested", func(s *Schema) {
+			s.Fields["name"] = FieldSpec{
+				Type:   "record",
+				Fields: map[string]FieldSpec{"first": {Type: "string", Merge: MergeMVR}},
+			}
+		}, false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			s := countersSchema()
+			test.change(&s)
+			assert.Equal(t, test.valid, s.Validate() == nil)
+		})
+	}
+}
+
+func TestMergePolicyEvolution(t *testing.T) {
+	s := countersSchema()
+	next := countersSchema()
+	next.Fields["name"] = FieldSpec{Type: "string", Nullable: true, Merge: MergeLWW}
+	assert.NoError(t, s.CheckEvolution(next))
+	next.Fields["name"] = FieldSpec{Type: "string", Nullable: true, Merge: MergeMVR}
+	assert.Error(t, s.CheckEvolution(next))
+}
+
+func account(fields map[string]interface{}) Row {
+	data := map[string]interface{}{
+		"id":      "a",
+		"high":    float64(0),
+		"low":     NewDecimal(big.NewInt(0), 0),
+		"visits":  int64(0),
+		"balance": int64(0),
+		"tags":    []interface{}{},
+	}
+	for name, v := range fields {
+		data[name] = v
+	}
+	return Row{Data: data}
+}
+
+func TestRowValueUpdate(t *testing.T) {
+	s := countersSchema()
+	v, err := NewRowValue().Update(s, account(map[string]interface{}{
+		"name":    "first",
+		"high":    float64(3),
+		"visits":  int64(2),
+		"balance": int64(10),
+		"tags":    []interface{}{"x", "y"},
+		"status":  "new",
+	}), HLC{1, 0, "a"})
+	require.NoError(t, err)
+	v, err = v.Update(s, account(map[string]interface{}{
+		"high":    float64(1),
+		"low":     NewDecimal(big.NewInt(-5), 1),
+		"visits":  int64(3),
+		"balance": int64(4),
+		"tags":    []interface{}{"y", "z"},
+	}), HLC{2, 0, "a"})
+	require.NoError(t, err)
+	assert.Equal(t, account(map[string]interface{}{
+		"high":    float64(3),
+		"low":     NewDecimal(big.NewInt(-5), 1),
+		"visits":  int64(3),
+		"balance": int64(4),
+		"tags":    []interface{}{"y", "z"},
+	}), v.Row())
+
+	_, err = v.Update(s, account(map[string]interface{}{"visits": int64(1)}), HLC{3, 0, "a"})
+	assert.Error(t, err)
+}
+
+func TestRowValueConcurrentWrites(t *testing.T) {
+	s := countersSchema()
+	base, err := NewRowValue().Update(s, account(map[string]interface{}{
+		"visits": int64(1),
+		"tags":   []interface{}{"x"},
+		"status": "new",
+	}), HLC{1, 0, "a"})
+	require.NoError(t, err)
+	left, err := base.Update(s, account(map[string]interface{}{
+		"name":    "left",
+		"visits":  int64(3),
+		"balance": int64(-2),
+		"tags":    []interface{}{},
+		"status":  "open",
+	}), HLC{2, 0, "a"})
+	require.NoError(t, err)
+	right, err := base.Update(s, account(map[string]interface{}{
+		"name":    "right",
+		"visits":  int64(2),
+		"balance": int64(5),
+		"tags":    []interface{}{"x", "y"},
+		"status":  "closed",
+	}), HLC{2, 0, "b"})
+	require.NoError(t, err)
+
+	merged := left.Merge(right).(RowValue)
+	assert.Equal(t, rowValueBytes(t, merged), rowValueBytes(t, right.Merge(left).(RowValue)))
+	assert.Equal(t, account(map[string]interface{}{
+		"name":    "right",
+		"visits":  int64(4),
+		"balance": int64(3),
+		// Left removed x, which right didn't add again, while y was added concurrently
+		"tags":   []interface{}{"y"},
+		"status": "closed",
+	}), merged.Row())
+	assert.Equal(t, []interface{}{"open", "closed"}, merged.Values("status"))
+	assert.Equal(t, []interface{}{"right"}, merged.Values("name"))
+	assert.Equal(t, HLC{2, 0, "b"}, merged.Timestamp())
+	assert.Equal(t, HLC{}, NewRowValue().Timestamp())
+
+	// A later write replaces every concurrent value
+	resolved, err := merged.Update(s, merged.Row(), HLC{3, 0, "a"})
+	require.NoError(t, err)
+	assert.Equal(t, []interface{}{"closed"}, resolved.Values("status"))
+}
+
+func randomAccount(r *rand.Rand, current Row) Row {
+	tags := []interface{}{}
+	for _, tag := range []string{"x", "y", "z"} {
+		if r.Intn(2) == 0 {
+			tags = append(tags, tag)
+		}
+	}
+	visits, _ := current.Data["visits"].(int64)
+	fields := map[string]interface{}{
+		"name":    fmt.Sprintf("name%d", r.Intn(3)),
+		"high":    []float64{math.NaN(), math.Inf(-1), 1, 2.5}[r.Intn(4)],
+		"low":     NewDecimal(big.NewInt(int64(r.Intn(100))), uint32(r.Intn(3))),
+		"visits":  visits + int64(r.Intn(3)),
+		"balance": int64(r.Intn(20) - 10),
+		"tags":    tags,
+		"status":  nil,
+	}
+	if r.Intn(3) > 0 {
+		fields["status"] = fmt.Sprintf("status%d", r.Intn(3))
+	}
+	return account(fields)
+}
+
+func TestRowValueMergeLaws(t *testing.T) {
+	s := countersSchema()
+	wall := uint64(0)
+	update := func(r *rand.Rand, replica string, v mst.Value) mst.Value {
+		wall++
+		rowValue := v.(RowValue)
+		row := randomAccount(r, rowValue.Row())
+		updated, err := rowValue.Update(s, row, HLC{wall, 0, replica})
+		require.NoError(t, err)
+		return updated
+	}
+	gen := msttest.NewHistory(NewRowValue(), []string{"a", "b", "c"}, update)
+	msttest.Check(t, gen, msttest.Options{Seed: 11, Reader: RowValueReader{}})
+}
+
+func TestRowValueReader(t *testing.T) {
+	s := countersSchema()
+	r := rand.New(rand.NewSource(5))
+	v := NewRowValue()
+	for wall := uint64(1); wall <= 10; wall++ {
+		var err error
+		replica := fmt.Sprintf("r%d", wall%3)
+		v, err = v.Update(s, randomAccount(r, v.Row()), HLC{wall, 0, replica})
+		require.NoError(t, err)
+	}
+	encoded := rowValueBytes(t, v)
+	read, err := RowValueReader{}.FromBytes(encoded)
+	require.NoError(t, err)
+	assert.Equal(t, encoded, rowValueBytes(t, read.(RowValue)))
+
+	_, err = RowValueReader{}.FromBytes(append(encoded, 0))
+	assert.Error(t, err)
+	_, err = RowValueReader{}.FromBytes(encoded[:len(encoded)-1])
+	assert.Error(t, err)
+
+	vr, err := mst.ValueReaderFor(RowValueType)
+	require.NoError(t, err)
+	assert.Equal(t, RowValueReader{}, vr)
+}
+
+func TestCompareValues(t *testing.T) {
+	ordered := []interface{}{
+		math.NaN(),
+		math.Inf(-1),
+		int32(-3),
+		NewDecimal(big.NewInt(-25), 1),
+		int64(1),
+		float32(1.5),
+		math.Inf(1),
+	}
+	for i := 1; i < len(ordered); i++ {
+		a, err := encodeValue(ordered[i-1])
+		require.NoError(t, err)
+		b, err := encodeValue(ordered[i])
+		require.NoError(t, err)
+		assert.Equal(t, -1, compareValues(ordered[i-1], ordered[i], a, b), "%v", ordered[i])
+		assert.Equal(t, 1, compareValues(ordered[i], ordered[i-1], b, a), "%v", ordered[i])
+	}
+}
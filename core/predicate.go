@@ -0,0 +1,212 @@
+package core
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/vulturedb/vulture/mst"
+)
+
+// Op compares the value of a field to the value of a predicate
+type Op string
+
+const (
+	OpEq Op = "="
+	OpLt Op = "<"
+	OpLe Op = "<="
+	OpGt Op = ">"
+	OpGe Op = ">="
+)
+
+// Predicate selects the rows whose field compares to Value as Op says. Fields of key types are
+// compared in key order, and rows where the field is null never match.
+type Predicate struct {
+	Field string
+	Op    Op
+	Value interface{}
+}
+
+func (p Predicate) String() string {
+	return fmt.Sprintf("%s %s %v", p.Field, p.Op, p.Value)
+}
+
+// comparableSpec returns spec without its constraints, since values outside of them can still be
+// compared to the values of a field
+func comparableSpec(spec FieldSpec) FieldSpec {
+	return FieldSpec{Type: spec.Type, Symbols: spec.Symbols}
+}
+
+func (s Schema) encodeComparable(w *bytes.Buffer, fieldName string, v interface{}) error {
+	return encodeKeyField(w, fieldName, comparableSpec(s.Fields[fieldName]), v)
+}
+
+// CheckPredicate checks that p names a field of a type that can be compared and that its value is
+// of that type
+func (s Schema) CheckPredicate(p Predicate) error {
+	fieldSpec, ok := s.Fields[p.Field]
+	if !ok {
+		return fmt.Errorf("Unknown field %s", p.Field)
+	} else if !isKeyType(fieldSpec.Type) {
+		return fmt.Errorf("Field %s of type %s can't be compared", p.Field, fieldSpec.Type)
+	}
+	switch p.Op {
+	case OpEq, OpLt, OpLe, OpGt, OpGe:
+	default:
+		return fmt.Errorf("Unknown operator %s", p.Op)
+	}
+	return s.encodeComparable(new(bytes.Buffer), p.Field, p.Value)
+}
+
+// Matches returns whether r matches p, which has to pass CheckPredicate
+func (s Schema) Matches(r Row, p Predicate) (bool, error) {
+	v := r.Data[p.Field]
+	if v == nil {
+		return false, nil
+	}
+	rowEncoded := new(bytes.Buffer)
+	if err := s.encodeComparable(rowEncoded, p.Field, v); err != nil {
+		return false, err
+	}
+	predEncoded := new(bytes.Buffer)
+	if err := s.encodeComparable(predEncoded, p.Field, p.Value); err != nil {
+		return false, err
+	}
+	cmp := bytes.Compare(rowEncoded.Bytes(), predEncoded.Bytes())
+	switch p.Op {
+	case OpEq:
+		return cmp == 0, nil
+	case OpLt:
+		return cmp < 0, nil
+	case OpLe:
+		return cmp <= 0, nil
+	case OpGt:
+		return cmp > 0, nil
+	case OpGe:
+		return cmp >= 0, nil
+	default:
+		return false, fmt.Errorf("Unknown operator %s", p.Op)
+	}
+}
+
+// KeyRange is the part of a tree's keys a scan has to visit to find every row matching some
+// predicates. Fields is the number of leading key fields the predicates constrain, where an
+// equality counts fully and a range on the field after them counts half, so ranges with more
+// fields narrow the scan further.
+type KeyRange struct {
+	Options mst.ScanOptions
+	Fields  float64
+}
+
+// prefixEnd returns the smallest key larger than every key with the given prefix, or nil if there
+// is none
+func prefixEnd(prefix []byte) []byte {
+	end := append([]byte{}, prefix...)
+	for i := len(end) - 1; i >= 0; i-- {
+		if end[i] < 0xff {
+			end[i]++
+			return end[:i+1]
+		}
+	}
+	return nil
+}
+
+// keyRange finds the range of keys over fieldNames whose leading fields match the equality
+// predicates and whose next field matches the range predicates. encode writes a field's value
+// like the key does and key wraps encoded bytes in the key type.
+func (s Schema) keyRange(
+	fieldNames []string,
+	preds []Predicate,
+	encode func(w *bytes.Buffer, fieldName string, v interface{}) error,
+	key func(encoded []byte) mst.Key,
+) (KeyRange, error) {
+	w := new(bytes.Buffer)
+	numEqual := 0
+	for _, fieldName := range fieldNames {
+		found := false
+		for _, p := range preds {
+			if p.Field == fieldName && p.Op == OpEq {
+				if err := encode(w, fieldName, p.Value); err != nil {
+					return KeyRange{}, err
+				}
+				found = true
+				break
+			}
+		}
+		if !found {
+			break
+		}
+		numEqual++
+	}
+	prefix := append([]byte{}, w.Bytes()...)
+	kr := KeyRange{Fields: float64(numEqual)}
+	if numEqual > 0 {
+		kr.Options.Prefix = key(prefix)
+	}
+	if numEqual == len(fieldNames) {
+		return kr, nil
+	}
+	var start, end []byte
+	for _, p := range preds {
+		if p.Field != fieldNames[numEqual] || p.Op == OpEq {
+			continue
+		}
+		bound := bytes.NewBuffer(append([]byte{}, prefix...))
+		if err := encode(bound, p.Field, p.Value); err != nil {
+			return KeyRange{}, err
+		}
+		var lower, upper []byte
+		switch p.Op {
+		case OpGe:
+			lower = bound.Bytes()
+		case OpGt:
+			lower = prefixEnd(bound.Bytes())
+		case OpLt:
+			upper = bound.Bytes()
+		case OpLe:
+			upper = prefixEnd(bound.Bytes())
+		}
+		if lower != nil && (start == nil || bytes.Compare(lower, start) > 0) {
+			start = lower
+		}
+		if upper != nil && (end == nil || bytes.Compare(upper, end) < 0) {
+			end = upper
+		}
+		kr.Fields = float64(numEqual) + 0.5
+	}
+	if start != nil {
+		kr.Options.Start = key(start)
+	}
+	if end != nil {
+		kr.Options.End = key(end)
+	}
+	return kr, nil
+}
+
+// PrimaryKeyRange returns the range of primary keys whose rows can match every predicate in preds.
+// Rows in the range still have to be checked against the predicates.
+func (s Schema) PrimaryKeyRange(preds []Predicate) (KeyRange, error) {
+	return s.keyRange(
+		s.PrimaryKey,
+		preds,
+		s.encodeComparable,
+		func(encoded []byte) mst.Key { return PrimaryKey{encoded: encoded} },
+	)
+}
+
+// IndexKeyRange returns the range of keys of the index named indexName whose rows can match every
+// predicate in preds. Rows in the range still have to be checked against the predicates.
+func (s Schema) IndexKeyRange(indexName string, preds []Predicate) (KeyRange, error) {
+	fieldNames, err := s.indexFields(indexName)
+	if err != nil {
+		return KeyRange{}, err
+	}
+	return s.keyRange(
+		fieldNames,
+		preds,
+		func(w *bytes.Buffer, fieldName string, v interface{}) error {
+			w.WriteByte(indexFieldSet)
+			return s.encodeComparable(w, fieldName, v)
+		},
+		func(encoded []byte) mst.Key { return IndexKey{encoded: encoded} },
+	)
+}
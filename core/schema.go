@@ -2,73 +2,205 @@ package core
 
 import "fmt"
 
+// FieldSpec describes a field of a row. Besides its type, enums list their allowed Symbols, arrays
+// describe their elements with Items and records describe their own fields with Fields.
+//
+// Each type is held in a Row as a specific Go type:
+//
+//	string     string
+//	int        int32
+//	long       int64
+//	float      float32
+//	double     float64
+//	boolean    bool
+//	bytes      []byte
+//	timestamp  time.Time
+//	uuid       uuid.UUID
+//	decimal    Decimal
+//	enum       string, one of Symbols
+//	record     Row, validated against Fields
+//	array      []interface{}, where every element is valid for Items
+//
+// Fields can also be constrained. Min and Max bound numbers, MaxLength bounds the number of
+// characters of strings, bytes of bytes fields and items of arrays, and strings have to match
+// Pattern, a regular expression, when it's set. Default is used for the field when a row leaves it
+// out, so fields with a default can be left out even if they're required.
+//
+// Merge is the merge policy of a top level field, one of the Merge constants, and decides how
+// concurrent writes to it on different replicas are combined. Fields without one are
+// last-writer-wins.
 type FieldSpec struct {
-	Type     string `mapstructure:"type"`
-	Nullable bool   `mapstructure:"nullable"`
+	Type      string               `mapstructure:"type"`
+	Nullable  bool                 `mapstructure:"nullable"`
+	Symbols   []string             `mapstructure:"symbols"`
+	Items     *FieldSpec           `mapstructure:"items"`
+	Fields    map[string]FieldSpec `mapstructure:"fields"`
+	Default   interface{}          `mapstructure:"default"`
+	Min       *float64             `mapstructure:"min"`
+	Max       *float64             `mapstructure:"max"`
+	MaxLength *uint32              `mapstructure:"maxLength"`
+	Pattern   string               `mapstructure:"pattern"`
+	Merge     string               `mapstructure:"merge"`
 }
 
+// Schema describes the rows of a table. Schemas form a chain of versions, where each version is
+// an allowed evolution of its parent (see CheckEvolution). Parent is the content hash of the
+// previous version and is nil for the first one.
+//
+// Indexes names the secondary indexes of the table and lists the fields each one is ordered by.
+// Rows can be found by the leading fields of an index like they can by those of the primary key.
+// Indexed fields have to be last-writer-wins fields of a type that can be part of a key, but
+// unlike primary key fields they can be nullable.
 type Schema struct {
 	Fields     map[string]FieldSpec `mapstructure:"fields"`
 	PrimaryKey []string             `mapstructure:"primaryKey"`
+	Indexes    map[string][]string  `mapstructure:"indexes"`
+	Version    uint32               `mapstructure:"version"`
+	Parent     []byte               `mapstructure:"parent"`
 }
 
 func GenesisSchema() Schema {
 	return Schema{Fields: map[string]FieldSpec{}}
 }
 
-func createError(fieldName string, expectedType string, v interface{}) error {
-	return fmt.Errorf("Invalid type %T for field %s. Expected %s.", v, fieldName, expectedType)
-}
-
-func isValidType(fieldName string, typeStr string, v interface{}) error {
-	switch typeStr {
-	case "string":
-		if _, ok := v.(string); !ok {
-			return createError(fieldName, "string", v)
+func validateSpec(fieldName string, spec FieldSpec) error {
+	switch spec.Type {
+	case "string", "int", "long", "float", "double", "boolean", "bytes", "timestamp", "uuid",
+		"decimal":
+	case "enum":
+		if len(spec.Symbols) == 0 {
+			return fmt.Errorf("Enum field %s has no symbols", fieldName)
 		}
-	case "int":
-		if _, ok := v.(int32); !ok {
-			return createError(fieldName, "int32", v)
+		seen := make(map[string]bool, len(spec.Symbols))
+		for _, symbol := range spec.Symbols {
+			if seen[symbol] {
+				return fmt.Errorf("Enum field %s repeats symbol %s", fieldName, symbol)
+			}
+			seen[symbol] = true
 		}
-	case "long":
-		if _, ok := v.(int64); !ok {
-			return createError(fieldName, "int64", v)
+	case "array":
+		if spec.Items == nil {
+			return fmt.Errorf("Array field %s has no item type", fieldName)
+		} else if spec.Items.Nullable {
+			return fmt.Errorf("Array field %s can't have nullable items", fieldName)
+		} else if spec.Items.Merge != "" {
+			return fmt.Errorf("Items of array field %s can't have a merge policy", fieldName)
 		}
-	case "float":
-		if _, ok := v.(float32); !ok {
-			return createError(fieldName, "float32", v)
+		if err := validateSpec(fieldName+"[]", *spec.Items); err != nil {
+			return err
 		}
-	case "double":
-		if _, ok := v.(float64); !ok {
-			return createError(fieldName, "float64", v)
+	case "record":
+		if len(spec.Fields) == 0 {
+			return fmt.Errorf("Record field %s has no fields", fieldName)
 		}
-	case "boolean":
-		if _, ok := v.(bool); !ok {
-			return createError(fieldName, "bool", v)
+		for subName, subSpec := range spec.Fields {
+			if err := validateSpec(fieldName+"."+subName, subSpec); err != nil {
+				return err
+			}
+			if subSpec.Merge != "" {
+				return fmt.Errorf("Field %s.%s can't have a merge policy", fieldName, subName)
+			}
 		}
 	default:
-		return fmt.Errorf("Unsupported type %s", typeStr)
+		return fmt.Errorf("Unsupported type %s for field %s", spec.Type, fieldName)
 	}
-	return nil
+	return validateConstraints(fieldName, spec)
 }
 
-func (s Schema) ValidateRow(r Row) error {
+// isKeyType returns whether fields of the type can be part of a primary key, i.e. whether the key
+// codec can encode them in order
+func isKeyType(typeStr string) bool {
+	switch typeStr {
+	case "string", "int", "long", "float", "double", "boolean", "bytes", "timestamp", "uuid",
+		"enum":
+		return true
+	default:
+		return false
+	}
+}
+
+// Validate checks that the schema itself is usable for a table, i.e. that every field has a known
+// type and merge policy, that the primary key is made of required last-writer-wins fields and that
+// indexes are made of fields that can be part of a key
+func (s Schema) Validate() error {
+	if len(s.PrimaryKey) == 0 {
+		return fmt.Errorf("Primary key is empty")
+	}
+	isKey := make(map[string]bool, len(s.PrimaryKey))
+	for _, fieldName := range s.PrimaryKey {
+		isKey[fieldName] = true
+	}
 	for fieldName, fieldSpec := range s.Fields {
-		v, ok := r.Data[fieldName]
+		if err := validateSpec(fieldName, fieldSpec); err != nil {
+			return err
+		}
+		if err := validateMerge(fieldName, fieldSpec, isKey[fieldName]); err != nil {
+			return err
+		}
+	}
+	seen := make(map[string]bool, len(s.PrimaryKey))
+	for _, fieldName := range s.PrimaryKey {
+		fieldSpec, ok := s.Fields[fieldName]
 		if !ok {
-			if !fieldSpec.Nullable {
-				return fmt.Errorf("Required field missing %s", fieldName)
-			}
-		} else {
-			if err := isValidType(fieldName, fieldSpec.Type, v); err != nil {
-				return err
-			}
+			return fmt.Errorf("Primary key field %s isn't in the schema", fieldName)
+		} else if fieldSpec.Nullable {
+			return fmt.Errorf("Primary key field %s is nullable", fieldName)
+		} else if !isKeyType(fieldSpec.Type) {
+			return fmt.Errorf("Primary key field %s can't be of type %s", fieldName, fieldSpec.Type)
+		} else if seen[fieldName] {
+			return fmt.Errorf("Primary key field %s is repeated", fieldName)
 		}
+		seen[fieldName] = true
 	}
-	for fieldName := range r.Data {
-		if _, ok := s.Fields[fieldName]; !ok {
-			return fmt.Errorf("Extraneous field %s", fieldName)
+	for indexName, fieldNames := range s.Indexes {
+		if err := s.validateIndex(indexName, fieldNames); err != nil {
+			return err
 		}
 	}
 	return nil
 }
+
+func (s Schema) validateIndex(indexName string, fieldNames []string) error {
+	if indexName == "" {
+		return fmt.Errorf("Index name is empty")
+	} else if len(fieldNames) == 0 {
+		return fmt.Errorf("Index %s has no fields", indexName)
+	}
+	seen := make(map[string]bool, len(fieldNames))
+	for _, fieldName := range fieldNames {
+		fieldSpec, ok := s.Fields[fieldName]
+		if !ok {
+			return fmt.Errorf("Field %s of index %s isn't in the schema", fieldName, indexName)
+		} else if !isKeyType(fieldSpec.Type) {
+			return fmt.Errorf(
+				"Field %s of index %s can't be of type %s",
+				fieldName,
+				indexName,
+				fieldSpec.Type,
+			)
+		} else if policyTags[fieldSpec.Merge] != policyLWW {
+			// Fields with other policies can end up with values no single write made, which
+			// wouldn't have an entry in the index
+			return fmt.Errorf(
+				"Field %s of index %s has to be last-writer-wins",
+				fieldName,
+				indexName,
+			)
+		} else if seen[fieldName] {
+			return fmt.Errorf("Field %s of index %s is repeated", fieldName, indexName)
+		}
+		seen[fieldName] = true
+	}
+	return nil
+}
+
+// ValidateRow checks r against the schema and returns it with defaults filled in for the fields
+// it leaves out. Every problem with the row is reported at once in a *ValidationError.
+func (s Schema) ValidateRow(r Row) (Row, error) {
+	v := &validator{}
+	data := v.checkFields("", s.Fields, r.Data)
+	if err := v.err(); err != nil {
+		return Row{}, err
+	}
+	return Row{Data: data}, nil
+}
@@ -0,0 +1,101 @@
+package core
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func ordersSchema() Schema {
+	lineItem := FieldSpec{Type: "record", Fields: map[string]FieldSpec{
+		"sku":      {Type: "string"},
+		"quantity": {Type: "int"},
+		"price":    {Type: "decimal"},
+		"note":     {Type: "string", Nullable: true},
+	}}
+	return Schema{
+		Fields: map[string]FieldSpec{
+			"id":      {Type: "uuid"},
+			"placed":  {Type: "timestamp"},
+			"status":  {Type: "enum", Symbols: []string{"open", "shipped"}},
+			"items":   {Type: "array", Items: &lineItem},
+			"receipt": {Type: "bytes", Nullable: true},
+		},
+		PrimaryKey: []string{"id"},
+	}
+}
+
+func order() Row {
+	return Row{Data: map[string]interface{}{
+		"id":     uuid.MustParse("6ba7b810-9dad-11d1-80b4-00c04fd430c8"),
+		"placed": time.Unix(1600000000, 0).UTC(),
+		"status": "open",
+		"items": []interface{}{
+			Row{Data: map[string]interface{}{
+				"sku":      "abc",
+				"quantity": int32(2),
+				"price":    NewDecimal(big.NewInt(999), 2),
+			}},
+		},
+	}}
+}
+
+func TestValidateRichSchema(t *testing.T) {
+	assert.NoError(t, ordersSchema().Validate())
+
+	invalid := []FieldSpec{
+		{Type: "enum"},
+		{Type: "enum", Symbols: []string{"a", "a"}},
+		{Type: "array"},
+		{Type: "array", Items: &FieldSpec{Type: "int", Nullable: true}},
+		{Type: "array", Items: &FieldSpec{Type: "unknown"}},
+		{Type: "record"},
+		{Type: "record", Fields: map[string]FieldSpec{"x": {Type: "enum"}}},
+	}
+	for _, spec := range invalid {
+		schema := ordersSchema()
+		schema.Fields["invalid"] = spec
+		assert.Error(t, schema.Validate(), spec.Type)
+	}
+
+	// Only types the key codec can order can be in a primary key
+	schema := ordersSchema()
+	schema.PrimaryKey = []string{"items"}
+	assert.Error(t, schema.Validate())
+}
+
+func TestValidateRichRow(t *testing.T) {
+	schema := ordersSchema()
+	validated, err := schema.ValidateRow(order())
+	assert.NoError(t, err)
+	assert.Equal(t, order(), validated)
+
+	invalid := []func(data map[string]interface{}){
+		func(data map[string]interface{}) { data["id"] = "6ba7b810-9dad-11d1-80b4-00c04fd430c8" },
+		func(data map[string]interface{}) { data["placed"] = int64(0) },
+		func(data map[string]interface{}) { data["status"] = "lost" },
+		func(data map[string]interface{}) { data["receipt"] = "receipt" },
+		func(data map[string]interface{}) { data["items"] = []interface{}{int32(1)} },
+		func(data map[string]interface{}) {
+			data["items"].([]interface{})[0].(Row).Data["quantity"] = int64(2)
+		},
+		func(data map[string]interface{}) {
+			delete(data["items"].([]interface{})[0].(Row).Data, "sku")
+		},
+		func(data map[string]interface{}) {
+			data["items"].([]interface{})[0].(Row).Data["extra"] = true
+		},
+		func(data map[string]interface{}) {
+			data["items"].([]interface{})[0].(Row).Data["price"] = Decimal{}
+		},
+	}
+	for _, change := range invalid {
+		row := order()
+		change(row.Data)
+		_, err := schema.ValidateRow(row)
+		assert.Error(t, err)
+	}
+}
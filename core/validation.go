@@ -0,0 +1,306 @@
+package core
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+)
+
+// FieldError is a problem with a single field of a row. Fields of records are named by their
+// path, like address.city, and items of arrays by their index, like tags[2].
+type FieldError struct {
+	Field   string
+	Message string
+}
+
+func (e FieldError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Field, e.Message)
+}
+
+// ValidationError lists every field of a row that isn't valid under a schema, sorted by field
+type ValidationError struct {
+	Errors []FieldError
+}
+
+func (e *ValidationError) Error() string {
+	messages := make([]string, len(e.Errors))
+	for i, fieldErr := range e.Errors {
+		messages[i] = fieldErr.Error()
+	}
+	return fmt.Sprintf("Invalid row: %s", strings.Join(messages, "; "))
+}
+
+// Fields returns the names of the fields that failed validation
+func (e *ValidationError) Fields() []string {
+	fields := make([]string, len(e.Errors))
+	for i, fieldErr := range e.Errors {
+		fields[i] = fieldErr.Field
+	}
+	return fields
+}
+
+// validator collects the problems with a row so they can all be reported together
+type validator struct {
+	errs []FieldError
+}
+
+func (v *validator) fail(fieldName string, format string, args ...interface{}) {
+	v.errs = append(v.errs, FieldError{fieldName, fmt.Sprintf(format, args...)})
+}
+
+func (v *validator) err() error {
+	if len(v.errs) == 0 {
+		return nil
+	}
+	sort.SliceStable(v.errs, func(i, j int) bool { return v.errs[i].Field < v.errs[j].Field })
+	return &ValidationError{v.errs}
+}
+
+func (v *validator) failType(fieldName string, expectedType string, value interface{}) {
+	v.fail(fieldName, "Invalid type %T. Expected %s.", value, expectedType)
+}
+
+// checkFields checks the fields of a row or record and returns them with defaults filled in.
+// prefix is prepended to field names so that fields of nested records can be told apart.
+func (v *validator) checkFields(
+	prefix string,
+	fields map[string]FieldSpec,
+	data map[string]interface{},
+) map[string]interface{} {
+	checked := make(map[string]interface{}, len(fields))
+	for fieldName, fieldSpec := range fields {
+		value, ok := data[fieldName]
+		if ok {
+			checked[fieldName] = v.checkValue(prefix+fieldName, fieldSpec, value)
+		} else if fieldSpec.Default != nil {
+			checked[fieldName] = fieldSpec.Default
+		} else if !fieldSpec.Nullable {
+			v.fail(prefix+fieldName, "Required field missing")
+		}
+	}
+	for fieldName := range data {
+		if _, ok := fields[fieldName]; !ok {
+			v.fail(prefix+fieldName, "Extraneous field")
+		}
+	}
+	return checked
+}
+
+// checkValue checks a single value against its spec and returns it, with defaults filled in if
+// it's a record or holds records
+func (v *validator) checkValue(fieldName string, spec FieldSpec, value interface{}) interface{} {
+	switch spec.Type {
+	case "string":
+		s, ok := value.(string)
+		if !ok {
+			v.failType(fieldName, "string", value)
+			return value
+		}
+		v.checkLength(fieldName, spec, utf8.RuneCountInString(s))
+		if spec.Pattern != "" {
+			pattern, err := compilePattern(spec.Pattern)
+			if err != nil {
+				v.fail(fieldName, "Invalid pattern %s: %s", spec.Pattern, err)
+			} else if !pattern.MatchString(s) {
+				v.fail(fieldName, "Value %q doesn't match pattern %s", s, spec.Pattern)
+			}
+		}
+	case "int", "long", "float", "double", "decimal":
+		n, ok := numberToRat(spec.Type, value)
+		if !ok {
+			v.failType(fieldName, goTypeNames[spec.Type], value)
+			return value
+		}
+		v.checkRange(fieldName, spec, value, n)
+	case "boolean":
+		if _, ok := value.(bool); !ok {
+			v.failType(fieldName, "bool", value)
+		}
+	case "bytes":
+		b, ok := value.([]byte)
+		if !ok {
+			v.failType(fieldName, "[]byte", value)
+			return value
+		}
+		v.checkLength(fieldName, spec, len(b))
+	case "timestamp":
+		if _, ok := value.(time.Time); !ok {
+			v.failType(fieldName, "time.Time", value)
+		}
+	case "uuid":
+		if _, ok := value.(uuid.UUID); !ok {
+			v.failType(fieldName, "uuid.UUID", value)
+		}
+	case "enum":
+		symbol, ok := value.(string)
+		if !ok {
+			v.failType(fieldName, "string", value)
+			return value
+		}
+		for _, s := range spec.Symbols {
+			if s == symbol {
+				return value
+			}
+		}
+		v.fail(fieldName, "Invalid symbol %s. Expected one of %s.", symbol, spec.Symbols)
+	case "array":
+		items, ok := value.([]interface{})
+		if !ok {
+			v.failType(fieldName, "[]interface{}", value)
+			return value
+		}
+		v.checkLength(fieldName, spec, len(items))
+		checked := make([]interface{}, len(items))
+		for i, item := range items {
+			checked[i] = v.checkValue(fmt.Sprintf("%s[%d]", fieldName, i), *spec.Items, item)
+		}
+		return checked
+	case "record":
+		record, ok := value.(Row)
+		if !ok {
+			v.failType(fieldName, "core.Row", value)
+			return value
+		}
+		return Row{Data: v.checkFields(fieldName+".", spec.Fields, record.Data)}
+	default:
+		v.fail(fieldName, "Unsupported type %s", spec.Type)
+	}
+	return value
+}
+
+func (v *validator) checkLength(fieldName string, spec FieldSpec, length int) {
+	if spec.MaxLength != nil && length > int(*spec.MaxLength) {
+		v.fail(fieldName, "Length %d is more than the maximum %d", length, *spec.MaxLength)
+	}
+}
+
+func (v *validator) checkRange(fieldName string, spec FieldSpec, value interface{}, n *big.Rat) {
+	if spec.Min == nil && spec.Max == nil {
+		return
+	}
+	if n == nil {
+		v.fail(fieldName, "Value %v is out of range", value)
+		return
+	}
+	if spec.Min != nil && n.Cmp(new(big.Rat).SetFloat64(*spec.Min)) < 0 {
+		v.fail(fieldName, "Value %v is less than the minimum %v", value, *spec.Min)
+	}
+	if spec.Max != nil && n.Cmp(new(big.Rat).SetFloat64(*spec.Max)) > 0 {
+		v.fail(fieldName, "Value %v is more than the maximum %v", value, *spec.Max)
+	}
+}
+
+var goTypeNames = map[string]string{
+	"int":     "int32",
+	"long":    "int64",
+	"float":   "float32",
+	"double":  "float64",
+	"decimal": "core.Decimal",
+}
+
+// numberToRat checks that value is a number of the given type and returns it exactly. The number
+// is nil for NaN and infinities, which can't be compared to bounds.
+func numberToRat(typeStr string, value interface{}) (*big.Rat, bool) {
+	switch val := value.(type) {
+	case int32:
+		return new(big.Rat).SetInt64(int64(val)), typeStr == "int"
+	case int64:
+		return new(big.Rat).SetInt64(val), typeStr == "long"
+	case float32:
+		return floatToRat(float64(val)), typeStr == "float"
+	case float64:
+		return floatToRat(val), typeStr == "double"
+	case Decimal:
+		if val.Unscaled == nil || typeStr != "decimal" {
+			return nil, false
+		}
+		scale := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(val.Scale)), nil)
+		return new(big.Rat).SetFrac(val.Unscaled, scale), true
+	default:
+		return nil, false
+	}
+}
+
+func floatToRat(f float64) *big.Rat {
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return nil
+	}
+	return new(big.Rat).SetFloat64(f)
+}
+
+// patterns caches compiled patterns since the same few are used to validate every row
+var patterns sync.Map
+
+func compilePattern(pattern string) (*regexp.Regexp, error) {
+	if re, ok := patterns.Load(pattern); ok {
+		return re.(*regexp.Regexp), nil
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, err
+	}
+	patterns.Store(pattern, re)
+	return re, nil
+}
+
+// validateValue checks a single value against its spec
+func validateValue(fieldName string, spec FieldSpec, value interface{}) error {
+	v := &validator{}
+	v.checkValue(fieldName, spec, value)
+	return v.err()
+}
+
+// validateConstraints checks that the constraints of a field make sense for its type and that its
+// default is a valid value for it
+func validateConstraints(fieldName string, spec FieldSpec) error {
+	if spec.Min != nil || spec.Max != nil {
+		if _, isNumber := goTypeNames[spec.Type]; !isNumber {
+			return fmt.Errorf(
+				"Field %s of type %s can't have a minimum or maximum",
+				fieldName,
+				spec.Type,
+			)
+		}
+		for _, bound := range []*float64{spec.Min, spec.Max} {
+			if bound != nil && floatToRat(*bound) == nil {
+				return fmt.Errorf("Field %s has an invalid bound %v", fieldName, *bound)
+			}
+		}
+		if spec.Min != nil && spec.Max != nil && *spec.Min > *spec.Max {
+			return fmt.Errorf("Field %s has a minimum larger than its maximum", fieldName)
+		}
+	}
+	if spec.MaxLength != nil {
+		switch spec.Type {
+		case "string", "bytes", "array":
+		default:
+			return fmt.Errorf(
+				"Field %s of type %s can't have a maximum length",
+				fieldName,
+				spec.Type,
+			)
+		}
+	}
+	if spec.Pattern != "" {
+		if spec.Type != "string" {
+			return fmt.Errorf("Field %s of type %s can't have a pattern", fieldName, spec.Type)
+		}
+		if _, err := compilePattern(spec.Pattern); err != nil {
+			return fmt.Errorf("Field %s has an invalid pattern: %w", fieldName, err)
+		}
+	}
+	if spec.Default != nil {
+		if err := validateValue(fieldName, spec, spec.Default); err != nil {
+			return fmt.Errorf("Invalid default for field %s: %w", fieldName, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,127 @@
+package core
+
+import (
+	"errors"
+	"math"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func float64Ptr(f float64) *float64 {
+	return &f
+}
+
+func uint32Ptr(n uint32) *uint32 {
+	return &n
+}
+
+func accountsSchema() Schema {
+	return Schema{
+		Fields: map[string]FieldSpec{
+			"handle": {Type: "string", MaxLength: uint32Ptr(8), Pattern: "^[a-z]+$"},
+			"age":    {Type: "int", Min: float64Ptr(0), Max: float64Ptr(150)},
+			"score":  {Type: "double", Min: float64Ptr(-1), Max: float64Ptr(1), Default: 0.0},
+			"credit": {Type: "decimal", Min: float64Ptr(0), Nullable: true},
+			"tags": {
+				Type:      "array",
+				Items:     &FieldSpec{Type: "string", MaxLength: uint32Ptr(3)},
+				MaxLength: uint32Ptr(2),
+				Default:   []interface{}{},
+			},
+			"plan": {Type: "enum", Symbols: []string{"free", "paid"}, Default: "free"},
+		},
+		PrimaryKey: []string{"handle"},
+	}
+}
+
+func TestValidateRowConstraints(t *testing.T) {
+	schema := accountsSchema()
+	require.NoError(t, schema.Validate())
+
+	row, err := schema.ValidateRow(Row{Data: map[string]interface{}{
+		"handle": "alice",
+		"age":    int32(30),
+		"tags":   []interface{}{"a", "bcd"},
+	}})
+	require.NoError(t, err)
+	assert.Equal(t, Row{Data: map[string]interface{}{
+		"handle": "alice",
+		"age":    int32(30),
+		"score":  0.0,
+		"tags":   []interface{}{"a", "bcd"},
+		"plan":   "free",
+	}}, row)
+
+	_, err = schema.ValidateRow(Row{Data: map[string]interface{}{
+		"handle": "Alice_in_wonderland",
+		"age":    int32(-1),
+		"score":  math.NaN(),
+		"credit": NewDecimal(big.NewInt(-1), 2),
+		"tags":   []interface{}{"a", "bcde", "f"},
+		"plan":   "gold",
+		"extra":  true,
+	}})
+	validationErr := &ValidationError{}
+	require.True(t, errors.As(err, &validationErr))
+	assert.Equal(t, []string{
+		"age",
+		"credit",
+		"extra",
+		"handle",
+		"handle",
+		"plan",
+		"score",
+		"tags",
+		"tags[1]",
+	}, validationErr.Fields())
+}
+
+func TestValidateRowDefaultsInRecords(t *testing.T) {
+	schema := Schema{
+		Fields: map[string]FieldSpec{
+			"id": {Type: "long"},
+			"address": {Type: "record", Fields: map[string]FieldSpec{
+				"city":    {Type: "string"},
+				"country": {Type: "string", Default: "NZ"},
+			}},
+		},
+		PrimaryKey: []string{"id"},
+	}
+	require.NoError(t, schema.Validate())
+	row, err := schema.ValidateRow(Row{Data: map[string]interface{}{
+		"id":      int64(1),
+		"address": Row{Data: map[string]interface{}{"city": "Wellington"}},
+	}})
+	require.NoError(t, err)
+	assert.Equal(t, "NZ", row.Data["address"].(Row).Data["country"])
+
+	_, err = schema.ValidateRow(Row{Data: map[string]interface{}{
+		"id":      int64(1),
+		"address": Row{Data: map[string]interface{}{}},
+	}})
+	validationErr := &ValidationError{}
+	require.True(t, errors.As(err, &validationErr))
+	assert.Equal(t, []string{"address.city"}, validationErr.Fields())
+}
+
+func TestValidateConstraints(t *testing.T) {
+	invalid := []FieldSpec{
+		{Type: "string", Min: float64Ptr(1)},
+		{Type: "int", MaxLength: uint32Ptr(1)},
+		{Type: "int", Pattern: "a"},
+		{Type: "string", Pattern: "("},
+		{Type: "int", Min: float64Ptr(2), Max: float64Ptr(1)},
+		{Type: "double", Max: float64Ptr(math.Inf(1))},
+		{Type: "int", Default: int64(1)},
+		{Type: "int", Max: float64Ptr(5), Default: int32(6)},
+		{Type: "string", MaxLength: uint32Ptr(1), Default: "ab"},
+	}
+	for _, spec := range invalid {
+		schema := accountsSchema()
+		schema.Fields["invalid"] = spec
+		assert.Error(t, schema.Validate(), "%+v", spec)
+	}
+}
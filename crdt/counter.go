@@ -0,0 +1,96 @@
+package crdt
+
+import (
+	"io"
+
+	"github.com/vulturedb/vulture/internal/dots"
+	"github.com/vulturedb/vulture/mst"
+)
+
+// GCounter is a grow-only counter. Each replica counts its own increments, and merging keeps the
+// highest count seen from every replica, so concurrent increments add up.
+type GCounter struct {
+	increments dots.Counts
+}
+
+func NewGCounter() GCounter {
+	return GCounter{dots.Counts{}}
+}
+
+// Increment returns the counter after replica counted n more
+func (c GCounter) Increment(replica string, n uint64) GCounter {
+	return GCounter{c.increments.With(replica, c.increments[replica]+n)}
+}
+
+func (c GCounter) Value() uint64 {
+	return c.increments.Sum()
+}
+
+func (c GCounter) Write(w io.Writer) error {
+	e := &encoder{}
+	c.increments.Write(&e.buf)
+	return e.writeTo(w)
+}
+
+func (c GCounter) Merge(with mst.Value) mst.Value {
+	return GCounter{c.increments.Merge(with.(GCounter).increments)}
+}
+
+type GCounterValueReader struct{}
+
+func (vr GCounterValueReader) FromBytes(b []byte) (mst.Value, error) {
+	d := newDecoder(b)
+	c := GCounter{d.counts()}
+	if err := d.finish("GCounter"); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// PNCounter is a counter that can also be decremented. It's a pair of grow-only counters, one for
+// increments and one for decrements, and its value is their difference.
+type PNCounter struct {
+	increments dots.Counts
+	decrements dots.Counts
+}
+
+func NewPNCounter() PNCounter {
+	return PNCounter{dots.Counts{}, dots.Counts{}}
+}
+
+// Increment returns the counter after replica added n
+func (c PNCounter) Increment(replica string, n uint64) PNCounter {
+	return PNCounter{c.increments.With(replica, c.increments[replica]+n), c.decrements}
+}
+
+// Decrement returns the counter after replica subtracted n
+func (c PNCounter) Decrement(replica string, n uint64) PNCounter {
+	return PNCounter{c.increments, c.decrements.With(replica, c.decrements[replica]+n)}
+}
+
+func (c PNCounter) Value() int64 {
+	return int64(c.increments.Sum() - c.decrements.Sum())
+}
+
+func (c PNCounter) Write(w io.Writer) error {
+	e := &encoder{}
+	c.increments.Write(&e.buf)
+	c.decrements.Write(&e.buf)
+	return e.writeTo(w)
+}
+
+func (c PNCounter) Merge(with mst.Value) mst.Value {
+	other := with.(PNCounter)
+	return PNCounter{c.increments.Merge(other.increments), c.decrements.Merge(other.decrements)}
+}
+
+type PNCounterValueReader struct{}
+
+func (vr PNCounterValueReader) FromBytes(b []byte) (mst.Value, error) {
+	d := newDecoder(b)
+	c := PNCounter{d.counts(), d.counts()}
+	if err := d.finish("PNCounter"); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
@@ -0,0 +1,35 @@
+package crdt
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGCounterConcurrentIncrements(t *testing.T) {
+	base := NewGCounter().Increment("a", 2)
+	left := base.Increment("a", 3)
+	right := base.Increment("b", 4)
+	assert.Equal(t, uint64(9), left.Merge(right).(GCounter).Value())
+	// Merging the same increments again doesn't count them twice
+	assert.Equal(t, uint64(9), left.Merge(right).Merge(right).(GCounter).Value())
+}
+
+func TestPNCounter(t *testing.T) {
+	base := NewPNCounter().Increment("a", 5)
+	left := base.Decrement("a", 7)
+	right := base.Increment("b", 1).Decrement("b", 1)
+	assert.Equal(t, int64(-2), left.Value())
+	assert.Equal(t, int64(-2), left.Merge(right).(PNCounter).Value())
+}
+
+func TestCounterZeroIncrements(t *testing.T) {
+	// Counting nothing leaves the counter as it was
+	assert.Equal(
+		t,
+		valueBytes(t, NewGCounter()),
+		valueBytes(t, NewGCounter().Increment("a", 0)),
+	)
+	_, err := GCounterValueReader{}.FromBytes([]byte{1, 0, 0})
+	assert.Error(t, err)
+}
@@ -0,0 +1,134 @@
+package crdt
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+
+	"github.com/vulturedb/vulture/internal/dots"
+)
+
+// encoder writes the little endian, length prefixed encodings every value in this package uses
+type encoder struct {
+	buf bytes.Buffer
+}
+
+func (e *encoder) uint32(n uint32) {
+	buf := make([]byte, 4)
+	binary.LittleEndian.PutUint32(buf, n)
+	e.buf.Write(buf)
+}
+
+func (e *encoder) uint64(n uint64) {
+	buf := make([]byte, 8)
+	binary.LittleEndian.PutUint64(buf, n)
+	e.buf.Write(buf)
+}
+
+func (e *encoder) bytes(b []byte) {
+	e.uint32(uint32(len(b)))
+	e.buf.Write(b)
+}
+
+func (e *encoder) string(s string) {
+	e.bytes([]byte(s))
+}
+
+func (e *encoder) writeTo(w io.Writer) error {
+	_, err := w.Write(e.buf.Bytes())
+	return err
+}
+
+// decoder reads what an encoder wrote. The first error sticks, so a value can be read field by
+// field and checked once at the end with finish.
+type decoder struct {
+	r   *bytes.Reader
+	err error
+}
+
+func newDecoder(b []byte) *decoder {
+	return &decoder{r: bytes.NewReader(b)}
+}
+
+func (d *decoder) read(n int) []byte {
+	if d.err != nil {
+		return nil
+	}
+	if n > d.r.Len() {
+		d.err = fmt.Errorf("Expected %d more bytes, got %d", n, d.r.Len())
+		return nil
+	}
+	buf := make([]byte, n)
+	_, d.err = io.ReadFull(d.r, buf)
+	return buf
+}
+
+func (d *decoder) uint32() uint32 {
+	buf := d.read(4)
+	if buf == nil {
+		return 0
+	}
+	return binary.LittleEndian.Uint32(buf)
+}
+
+func (d *decoder) uint64() uint64 {
+	buf := d.read(8)
+	if buf == nil {
+		return 0
+	}
+	return binary.LittleEndian.Uint64(buf)
+}
+
+func (d *decoder) bytes() []byte {
+	n := d.uint32()
+	return d.read(int(n))
+}
+
+func (d *decoder) string() string {
+	return string(d.bytes())
+}
+
+// length reads the number of entries of a collection, checking that each of them could take at
+// least minSize bytes so that corrupt input can't make it allocate arbitrarily much
+func (d *decoder) length(minSize int) int {
+	n := int(d.uint32())
+	if d.err == nil && n*minSize > d.r.Len() {
+		d.err = fmt.Errorf("%d entries don't fit in the remaining %d bytes", n, d.r.Len())
+	}
+	if d.err != nil {
+		return 0
+	}
+	return n
+}
+
+// finish returns the first error, naming the type being read, or an error if bytes are left over
+func (d *decoder) finish(typeName string) error {
+	if d.err == nil && d.r.Len() > 0 {
+		d.err = fmt.Errorf("Unexpected %d trailing bytes", d.r.Len())
+	}
+	if d.err != nil {
+		return fmt.Errorf("Couldn't read %s: %w", typeName, d.err)
+	}
+	return nil
+}
+
+// counts reads counts written by dots.Counts.Write
+func (d *decoder) counts() dots.Counts {
+	if d.err != nil {
+		return nil
+	}
+	var c dots.Counts
+	c, d.err = dots.ReadCounts(d.r)
+	return c
+}
+
+// kernel reads a kernel written by dots.Kernel.Write without payloads
+func (d *decoder) kernel() dots.Kernel {
+	if d.err != nil {
+		return dots.Kernel{}
+	}
+	var k dots.Kernel
+	k, d.err = dots.ReadKernel(d.r, nil)
+	return k
+}
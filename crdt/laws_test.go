@@ -0,0 +1,93 @@
+package crdt
+
+import (
+	"bytes"
+	"fmt"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/vulturedb/vulture/mst"
+	"github.com/vulturedb/vulture/mst/msttest"
+)
+
+var replicas = []string{"a", "b", "c"}
+
+func valueBytes(t *testing.T, v mst.Value) []byte {
+	buf := new(bytes.Buffer)
+	require.NoError(t, v.Write(buf))
+	return buf.Bytes()
+}
+
+func randomElement(r *rand.Rand) []byte {
+	return []byte(fmt.Sprintf("e%d", r.Intn(5)))
+}
+
+func TestLWWRegisterLaws(t *testing.T) {
+	update := func(r *rand.Rand, replica string, v mst.Value) mst.Value {
+		// Timestamps are coarse so that replicas often tie
+		return v.Merge(NewLWWRegister(uint64(r.Intn(4)), replica, randomElement(r)))
+	}
+	gen := msttest.NewHistory(NewLWWRegister(0, "", nil), replicas, update)
+	msttest.Check(t, gen, msttest.Options{Seed: 1, Reader: LWWRegisterValueReader{}})
+}
+
+func TestMVRegisterLaws(t *testing.T) {
+	update := func(r *rand.Rand, replica string, v mst.Value) mst.Value {
+		return v.(MVRegister).Set(replica, randomElement(r))
+	}
+	gen := msttest.NewHistory(NewMVRegister(), replicas, update)
+	msttest.Check(t, gen, msttest.Options{Seed: 2, Reader: MVRegisterValueReader{}})
+}
+
+func TestGCounterLaws(t *testing.T) {
+	update := func(r *rand.Rand, replica string, v mst.Value) mst.Value {
+		return v.(GCounter).Increment(replica, uint64(r.Intn(3)))
+	}
+	gen := msttest.NewHistory(NewGCounter(), replicas, update)
+	msttest.Check(t, gen, msttest.Options{Seed: 3, Reader: GCounterValueReader{}})
+}
+
+func TestPNCounterLaws(t *testing.T) {
+	update := func(r *rand.Rand, replica string, v mst.Value) mst.Value {
+		if r.Intn(2) == 0 {
+			return v.(PNCounter).Decrement(replica, uint64(r.Intn(3)))
+		}
+		return v.(PNCounter).Increment(replica, uint64(r.Intn(3)))
+	}
+	gen := msttest.NewHistory(NewPNCounter(), replicas, update)
+	msttest.Check(t, gen, msttest.Options{Seed: 4, Reader: PNCounterValueReader{}})
+}
+
+func TestGSetLaws(t *testing.T) {
+	update := func(r *rand.Rand, replica string, v mst.Value) mst.Value {
+		return v.(GSet).Add(randomElement(r))
+	}
+	gen := msttest.NewHistory(NewGSet(), replicas, update)
+	msttest.Check(t, gen, msttest.Options{Seed: 5, Reader: GSetValueReader{}})
+}
+
+func TestORSetLaws(t *testing.T) {
+	update := func(r *rand.Rand, replica string, v mst.Value) mst.Value {
+		if r.Intn(3) == 0 {
+			return v.(ORSet).Remove(randomElement(r))
+		}
+		return v.(ORSet).Add(replica, randomElement(r))
+	}
+	gen := msttest.NewHistory(NewORSet(), replicas, update)
+	msttest.Check(t, gen, msttest.Options{Seed: 6, Reader: ORSetValueReader{}})
+}
+
+func TestMaxMinRegisterLaws(t *testing.T) {
+	updateMax := func(r *rand.Rand, replica string, v mst.Value) mst.Value {
+		return v.Merge(MaxRegister(r.Int63() - r.Int63()))
+	}
+	gen := msttest.NewHistory(MaxRegister(0), replicas, updateMax)
+	msttest.Check(t, gen, msttest.Options{Seed: 7, Reader: MaxRegisterValueReader{}})
+	updateMin := func(r *rand.Rand, replica string, v mst.Value) mst.Value {
+		return v.Merge(MinRegister(r.Int63() - r.Int63()))
+	}
+	gen = msttest.NewHistory(MinRegister(0), replicas, updateMin)
+	msttest.Check(t, gen, msttest.Options{Seed: 8, Reader: MinRegisterValueReader{}})
+}
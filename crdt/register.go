@@ -0,0 +1,176 @@
+package crdt
+
+import (
+	"bytes"
+	"io"
+	"strings"
+
+	"github.com/vulturedb/vulture/internal/dots"
+	"github.com/vulturedb/vulture/mst"
+)
+
+// LWWRegister is a last-writer-wins register. Merging keeps the value with the later timestamp,
+// and ties are broken by the replica that wrote the value and then by the value itself, so every
+// replica picks the same one. Unlike mst.LWWBytes, two replicas writing at the same timestamp
+// don't depend on the bytes they wrote to resolve the tie.
+type LWWRegister struct {
+	timestamp uint64
+	replica   string
+	value     []byte
+}
+
+func NewLWWRegister(timestamp uint64, replica string, value []byte) LWWRegister {
+	return LWWRegister{timestamp, replica, value}
+}
+
+func (r LWWRegister) Timestamp() uint64 {
+	return r.timestamp
+}
+
+func (r LWWRegister) Replica() string {
+	return r.replica
+}
+
+func (r LWWRegister) Value() []byte {
+	return r.value
+}
+
+func (r LWWRegister) Write(w io.Writer) error {
+	e := &encoder{}
+	e.uint64(r.timestamp)
+	e.string(r.replica)
+	e.bytes(r.value)
+	return e.writeTo(w)
+}
+
+func (r LWWRegister) Merge(with mst.Value) mst.Value {
+	other := with.(LWWRegister)
+	c := compareUint64(r.timestamp, other.timestamp)
+	if c == 0 {
+		c = strings.Compare(r.replica, other.replica)
+	}
+	if c == 0 {
+		c = bytes.Compare(r.value, other.value)
+	}
+	if c >= 0 {
+		return r
+	}
+	return other
+}
+
+type LWWRegisterValueReader struct{}
+
+func (vr LWWRegisterValueReader) FromBytes(b []byte) (mst.Value, error) {
+	d := newDecoder(b)
+	r := LWWRegister{d.uint64(), d.string(), d.bytes()}
+	if err := d.finish("LWWRegister"); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+func compareUint64(a uint64, b uint64) int {
+	if a < b {
+		return -1
+	} else if a > b {
+		return 1
+	}
+	return 0
+}
+
+// MVRegister is a multi-value register. Writes replace every value the writing replica has seen,
+// while values written concurrently on different replicas are all kept until a later write
+// replaces them.
+type MVRegister struct {
+	kernel dots.Kernel
+}
+
+func NewMVRegister() MVRegister {
+	return MVRegister{dots.NewKernel()}
+}
+
+// Set returns the register after replica wrote value
+func (r MVRegister) Set(replica string, value []byte) MVRegister {
+	return MVRegister{r.kernel.RemoveAll().Add(replica, value, nil)}
+}
+
+// Values returns every value written concurrently, sorted by their bytes. It's empty if the
+// register has never been written.
+func (r MVRegister) Values() [][]byte {
+	return r.kernel.Elements()
+}
+
+func (r MVRegister) Write(w io.Writer) error {
+	e := &encoder{}
+	r.kernel.Write(&e.buf, nil)
+	return e.writeTo(w)
+}
+
+func (r MVRegister) Merge(with mst.Value) mst.Value {
+	return MVRegister{r.kernel.Merge(with.(MVRegister).kernel)}
+}
+
+type MVRegisterValueReader struct{}
+
+func (vr MVRegisterValueReader) FromBytes(b []byte) (mst.Value, error) {
+	d := newDecoder(b)
+	r := MVRegister{d.kernel()}
+	if err := d.finish("MVRegister"); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// MaxRegister holds the largest number ever written to it
+type MaxRegister int64
+
+func (r MaxRegister) Write(w io.Writer) error {
+	e := &encoder{}
+	e.uint64(uint64(r))
+	return e.writeTo(w)
+}
+
+func (r MaxRegister) Merge(with mst.Value) mst.Value {
+	if other := with.(MaxRegister); other > r {
+		return other
+	}
+	return r
+}
+
+type MaxRegisterValueReader struct{}
+
+func (vr MaxRegisterValueReader) FromBytes(b []byte) (mst.Value, error) {
+	d := newDecoder(b)
+	r := MaxRegister(d.uint64())
+	if err := d.finish("MaxRegister"); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// MinRegister holds the smallest number ever written to it
+type MinRegister int64
+
+func (r MinRegister) Write(w io.Writer) error {
+	e := &encoder{}
+	e.uint64(uint64(r))
+	return e.writeTo(w)
+}
+
+func (r MinRegister) Merge(with mst.Value) mst.Value {
+	if other := with.(MinRegister); other < r {
+		return other
+	}
+	return r
+}
+
+type MinRegisterValueReader struct{}
+
+func (vr MinRegisterValueReader) FromBytes(b []byte) (mst.Value, error) {
+	d := newDecoder(b)
+	r := MinRegister(d.uint64())
+	if err := d.finish("MinRegister"); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
@@ -0,0 +1,46 @@
+package crdt
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLWWRegister(t *testing.T) {
+	older := NewLWWRegister(1, "b", []byte("old"))
+	newer := NewLWWRegister(2, "a", []byte("new"))
+	assert.Equal(t, newer, older.Merge(newer))
+	assert.Equal(t, newer, newer.Merge(older))
+
+	// Ties are broken by replica before value
+	a := NewLWWRegister(3, "a", []byte("z"))
+	b := NewLWWRegister(3, "b", []byte("a"))
+	assert.Equal(t, b, a.Merge(b))
+	assert.Equal(t, b, b.Merge(a))
+}
+
+func TestMVRegister(t *testing.T) {
+	base := NewMVRegister().Set("a", []byte("first"))
+	left := base.Set("a", []byte("left"))
+	right := base.Set("b", []byte("right"))
+	merged := left.Merge(right).(MVRegister)
+	assert.Equal(t, [][]byte{[]byte("left"), []byte("right")}, merged.Values())
+
+	// A write that has seen both concurrent values replaces them
+	resolved := merged.Set("c", []byte("resolved"))
+	assert.Equal(t, [][]byte{[]byte("resolved")}, resolved.Values())
+	assert.Equal(t, resolved.Values(), resolved.Merge(left).(MVRegister).Values())
+
+	assert.Empty(t, NewMVRegister().Values())
+}
+
+func TestMaxMinRegister(t *testing.T) {
+	assert.Equal(t, MaxRegister(3), MaxRegister(-5).Merge(MaxRegister(3)))
+	assert.Equal(t, MinRegister(-5), MinRegister(-5).Merge(MinRegister(3)))
+
+	read, err := MinRegisterValueReader{}.FromBytes(valueBytes(t, MinRegister(-5)))
+	assert.NoError(t, err)
+	assert.Equal(t, MinRegister(-5), read)
+	_, err = MaxRegisterValueReader{}.FromBytes([]byte{1, 2, 3})
+	assert.Error(t, err)
+}
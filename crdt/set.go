@@ -0,0 +1,136 @@
+package crdt
+
+import (
+	"bytes"
+	"io"
+	"sort"
+
+	"github.com/vulturedb/vulture/internal/dots"
+	"github.com/vulturedb/vulture/mst"
+)
+
+// GSet is a grow-only set of byte strings. Merging takes the union.
+type GSet struct {
+	elements map[string]bool
+}
+
+func NewGSet(elements ...[]byte) GSet {
+	s := GSet{map[string]bool{}}
+	for _, element := range elements {
+		s.elements[string(element)] = true
+	}
+	return s
+}
+
+// Add returns the set with element added
+func (s GSet) Add(element []byte) GSet {
+	return GSet{s.union(map[string]bool{string(element): true})}
+}
+
+func (s GSet) Contains(element []byte) bool {
+	return s.elements[string(element)]
+}
+
+// Elements returns the elements of the set, sorted by their bytes
+func (s GSet) Elements() [][]byte {
+	elements := make([][]byte, 0, len(s.elements))
+	for element := range s.elements {
+		elements = append(elements, []byte(element))
+	}
+	sort.Slice(elements, func(i, j int) bool {
+		return bytes.Compare(elements[i], elements[j]) < 0
+	})
+	return elements
+}
+
+func (s GSet) union(other map[string]bool) map[string]bool {
+	elements := make(map[string]bool, len(s.elements)+len(other))
+	for element := range s.elements {
+		elements[element] = true
+	}
+	for element := range other {
+		elements[element] = true
+	}
+	return elements
+}
+
+func (s GSet) Write(w io.Writer) error {
+	e := &encoder{}
+	elements := s.Elements()
+	e.uint32(uint32(len(elements)))
+	for _, element := range elements {
+		e.bytes(element)
+	}
+	return e.writeTo(w)
+}
+
+func (s GSet) Merge(with mst.Value) mst.Value {
+	return GSet{s.union(with.(GSet).elements)}
+}
+
+type GSetValueReader struct{}
+
+func (vr GSetValueReader) FromBytes(b []byte) (mst.Value, error) {
+	d := newDecoder(b)
+	n := d.length(4)
+	s := NewGSet()
+	for i := 0; i < n; i++ {
+		s.elements[string(d.bytes())] = true
+	}
+	if err := d.finish("GSet"); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// ORSet is an observed-remove set of byte strings. Removing an element only removes the adds the
+// removing replica has seen, so an element added concurrently with its removal stays in the set.
+type ORSet struct {
+	kernel dots.Kernel
+}
+
+func NewORSet() ORSet {
+	return ORSet{dots.NewKernel()}
+}
+
+// Add returns the set after replica added element
+func (s ORSet) Add(replica string, element []byte) ORSet {
+	// Replacing the adds of the element that were already seen keeps the set from growing with
+	// every add of the same element
+	return ORSet{s.kernel.Remove(element).Add(replica, element, nil)}
+}
+
+// Remove returns the set without element
+func (s ORSet) Remove(element []byte) ORSet {
+	return ORSet{s.kernel.Remove(element)}
+}
+
+func (s ORSet) Contains(element []byte) bool {
+	return s.kernel.Contains(element)
+}
+
+// Elements returns the elements of the set, sorted by their bytes
+func (s ORSet) Elements() [][]byte {
+	return s.kernel.Elements()
+}
+
+func (s ORSet) Write(w io.Writer) error {
+	e := &encoder{}
+	s.kernel.Write(&e.buf, nil)
+	return e.writeTo(w)
+}
+
+func (s ORSet) Merge(with mst.Value) mst.Value {
+	return ORSet{s.kernel.Merge(with.(ORSet).kernel)}
+}
+
+type ORSetValueReader struct{}
+
+func (vr ORSetValueReader) FromBytes(b []byte) (mst.Value, error) {
+	d := newDecoder(b)
+	s := ORSet{d.kernel()}
+	if err := d.finish("ORSet"); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
@@ -0,0 +1,40 @@
+package crdt
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGSet(t *testing.T) {
+	left := NewGSet([]byte("a")).Add([]byte("b"))
+	right := NewGSet([]byte("c"), []byte("a"))
+	merged := left.Merge(right).(GSet)
+	assert.Equal(t, [][]byte{[]byte("a"), []byte("b"), []byte("c")}, merged.Elements())
+	assert.True(t, merged.Contains([]byte("c")))
+	assert.False(t, merged.Contains([]byte("d")))
+	// Adding doesn't change the set it's called on
+	assert.False(t, left.Contains([]byte("c")))
+}
+
+func TestORSetAddWins(t *testing.T) {
+	base := NewORSet().Add("a", []byte("x")).Add("a", []byte("y"))
+	removed := base.Remove([]byte("x")).Remove([]byte("y"))
+	readded := base.Add("b", []byte("x"))
+
+	merged := removed.Merge(readded).(ORSet)
+	assert.True(t, merged.Contains([]byte("x")))
+	assert.False(t, merged.Contains([]byte("y")))
+	assert.Equal(t, [][]byte{[]byte("x")}, merged.Elements())
+
+	// Once the removal has seen the add, it sticks
+	assert.False(t, merged.Remove([]byte("x")).Merge(readded).(ORSet).Contains([]byte("x")))
+}
+
+func TestORSetAddReplacesSeenAdds(t *testing.T) {
+	s := NewORSet()
+	for i := 0; i < 10; i++ {
+		s = s.Add("a", []byte("x"))
+	}
+	assert.Len(t, s.kernel.Values(), 1)
+}
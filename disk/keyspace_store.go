@@ -0,0 +1,147 @@
+package disk
+
+import (
+	"crypto"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/vulturedb/vulture/internal/files"
+	"github.com/vulturedb/vulture/mst"
+)
+
+const (
+	keyspacesFileName = "KEYSPACES"
+	keyspacesDirName  = "keyspaces"
+)
+
+// KeyspaceStore keeps the keyspaces of a server in a JSON file in a data directory, which is
+// replaced atomically like the root file
+type KeyspaceStore struct {
+	path string
+}
+
+type encodedKeyspace struct {
+	Name       string `json:"name"`
+	Base       uint   `json:"base"`
+	Hash       uint   `json:"hash"`
+	KeyType    string `json:"key_type"`
+	ValueType  string `json:"value_type"`
+	Aggregator string `json:"aggregator,omitempty"`
+}
+
+// NewKeyspaceStore creates a KeyspaceStore in dir, creating dir if it doesn't exist
+func NewKeyspaceStore(dir string) (*KeyspaceStore, error) {
+	err := os.MkdirAll(dir, 0755)
+	if err != nil {
+		return nil, fmt.Errorf("Couldn't create data directory: %w", err)
+	}
+	return &KeyspaceStore{filepath.Join(dir, keyspacesFileName)}, nil
+}
+
+var _ mst.KeyspaceStore = &KeyspaceStore{}
+
+// Keyspaces returns the keyspaces that were last set, or none if they never were
+func (s *KeyspaceStore) Keyspaces() ([]mst.Keyspace, error) {
+	raw, err := ioutil.ReadFile(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return []mst.Keyspace{}, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("Couldn't read keyspaces: %w", err)
+	}
+	encoded := []encodedKeyspace{}
+	err = json.Unmarshal(raw, &encoded)
+	if err != nil {
+		return nil, fmt.Errorf("Couldn't decode keyspaces: %w", err)
+	}
+	keyspaces := make([]mst.Keyspace, len(encoded))
+	for i, ks := range encoded {
+		keyspaces[i] = mst.Keyspace{
+			Name:       ks.Name,
+			Base:       mst.Base(ks.Base),
+			Hash:       crypto.Hash(ks.Hash),
+			KeyType:    ks.KeyType,
+			ValueType:  ks.ValueType,
+			Aggregator: ks.Aggregator,
+		}
+	}
+	return keyspaces, nil
+}
+
+func (s *KeyspaceStore) SetKeyspaces(keyspaces []mst.Keyspace) error {
+	encoded := make([]encodedKeyspace, len(keyspaces))
+	for i, ks := range keyspaces {
+		encoded[i] = encodedKeyspace{
+			Name:       ks.Name,
+			Base:       uint(ks.Base),
+			Hash:       uint(ks.Hash),
+			KeyType:    ks.KeyType,
+			ValueType:  ks.ValueType,
+			Aggregator: ks.Aggregator,
+		}
+	}
+	raw, err := json.MarshalIndent(encoded, "", "  ")
+	if err != nil {
+		return fmt.Errorf("Couldn't encode keyspaces: %w", err)
+	}
+	err = files.WriteAtomic(s.path, append(raw, '\n'))
+	if err != nil {
+		return fmt.Errorf("Couldn't write keyspaces: %w", err)
+	}
+	return nil
+}
+
+// Storage keeps the nodes, root and snapshots of each keyspace in its own directory under a data
+// directory. The default keyspace uses the data directory itself, so data directories from before
+// there were keyspaces open as the default keyspace.
+type Storage struct {
+	dir string
+}
+
+func NewStorage(dir string) *Storage {
+	return &Storage{dir}
+}
+
+func (s *Storage) keyspaceDir(name string) string {
+	if name == mst.DefaultKeyspace {
+		return s.dir
+	}
+	return filepath.Join(s.dir, keyspacesDirName, name)
+}
+
+// Open opens the stores of a keyspace, creating them if they don't exist
+func (s *Storage) Open(
+	ks mst.Keyspace,
+	kr mst.KeyReader,
+	vr mst.ValueReader,
+) (mst.NodeStore, mst.RootStore, mst.SnapshotStore, error) {
+	dir := s.keyspaceDir(ks.Name)
+	nodes, err := NewNodeStore(dir, ks.Hash, kr, vr)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	roots, err := NewRootStore(dir)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	snapshots, err := NewSnapshotStore(dir)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	return nodes, roots, snapshots, nil
+}
+
+// Remove deletes the directory of a keyspace. The default keyspace can't be removed.
+func (s *Storage) Remove(ks mst.Keyspace) error {
+	if ks.Name == mst.DefaultKeyspace {
+		return fmt.Errorf("The default keyspace can't be removed")
+	}
+	err := os.RemoveAll(s.keyspaceDir(ks.Name))
+	if err != nil {
+		return fmt.Errorf("Couldn't remove keyspace %s: %w", ks.Name, err)
+	}
+	return nil
+}
@@ -0,0 +1,175 @@
+package disk
+
+import (
+	"bytes"
+	"crypto"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/benbjohnson/immutable"
+
+	"github.com/vulturedb/vulture/internal/files"
+	"github.com/vulturedb/vulture/mst"
+)
+
+const nodesDirName = "nodes"
+
+// NodeStore is a content addressed mst.NodeStore that keeps every node in its own file in a data
+// directory. Nodes are keyed by the hash of their mst.HashableNode, so hashes match those of an
+// mst.LocalNodeStore using the same hash function.
+//
+// Files on disk are shared by every version of the store, but each version keeps its own set of
+// the hashes it holds. Remove only drops a hash from that set, so older versions of the store, and
+// the trees that use them, can still read the node. Files that no version needs anymore stay on
+// disk until they're garbage collected.
+type NodeStore struct {
+	dir    string
+	hash   crypto.Hash
+	kr     mst.KeyReader
+	vr     mst.ValueReader
+	hashes *immutable.Map
+}
+
+// NewNodeStore opens the node store in dir, creating it if it doesn't exist. Any files left behind
+// by writes that were interrupted by a crash are cleaned up.
+func NewNodeStore(
+	dir string,
+	hash crypto.Hash,
+	kr mst.KeyReader,
+	vr mst.ValueReader,
+) (*NodeStore, error) {
+	nodesDir := filepath.Join(dir, nodesDirName)
+	err := os.MkdirAll(nodesDir, 0755)
+	if err != nil {
+		return nil, fmt.Errorf("Couldn't create nodes directory: %w", err)
+	}
+	err = files.RemoveTempFiles(nodesDir)
+	if err != nil {
+		return nil, fmt.Errorf("Couldn't clean up temporary files: %w", err)
+	}
+
+	stored, err := listNodes(nodesDir)
+	if err != nil {
+		return nil, err
+	}
+	hashes := immutable.NewMap(nil)
+	for _, k := range stored {
+		hashes = hashes.Set(string(k), struct{}{})
+	}
+	return &NodeStore{nodesDir, hash, kr, vr, hashes}, nil
+}
+
+// listNodes returns the hashes of all nodes stored in dir
+func listNodes(dir string) ([][]byte, error) {
+	hashes := [][]byte{}
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() {
+			return err
+		}
+		k, err := hex.DecodeString(info.Name())
+		if err != nil {
+			return fmt.Errorf("Unexpected file %s: %w", path, err)
+		}
+		hashes = append(hashes, k)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("Couldn't list nodes: %w", err)
+	}
+	return hashes, nil
+}
+
+var _ mst.CollectableNodeStore = &NodeStore{}
+
+func (ns *NodeStore) withHashes(hashes *immutable.Map) *NodeStore {
+	return &NodeStore{ns.dir, ns.hash, ns.kr, ns.vr, hashes}
+}
+
+// nodePath shards nodes into subdirectories by the first byte of their hash so that no single
+// directory gets too big
+func (ns *NodeStore) nodePath(k []byte) string {
+	name := hex.EncodeToString(k)
+	return filepath.Join(ns.dir, name[:2], name)
+}
+
+func (ns *NodeStore) hashNode(n *mst.Node) []byte {
+	wn := mst.HashableNode(*n)
+	return mst.HashWritable(&wn, ns.hash)
+}
+
+func (ns *NodeStore) Get(k []byte) (*mst.Node, error) {
+	if _, ok := ns.hashes.Get(string(k)); len(k) == 0 || !ok {
+		return nil, fmt.Errorf("%w: %s", mst.ErrNodeNotFound, hex.EncodeToString(k))
+	}
+	raw, err := ioutil.ReadFile(ns.nodePath(k))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("%w: %s", mst.ErrNodeNotFound, hex.EncodeToString(k))
+	} else if err != nil {
+		return nil, fmt.Errorf("Couldn't read node: %w", err)
+	}
+	n, err := mst.DecodeNode(bytes.NewReader(raw), ns.kr, ns.vr)
+	if err != nil {
+		return nil, fmt.Errorf("Couldn't decode node %s: %w", hex.EncodeToString(k), err)
+	}
+	if actual := ns.hashNode(n); !bytes.Equal(actual, k) {
+		return nil, fmt.Errorf(
+			"Node %s is corrupt, its contents hash to %s",
+			hex.EncodeToString(k),
+			hex.EncodeToString(actual),
+		)
+	}
+	return n, nil
+}
+
+func (ns *NodeStore) Put(n *mst.Node) (mst.NodeStore, []byte, error) {
+	k := ns.hashNode(n)
+	path := ns.nodePath(k)
+	_, err := os.Stat(path)
+	if errors.Is(err, os.ErrNotExist) {
+		buf := new(bytes.Buffer)
+		err = mst.EncodeNode(n, buf)
+		if err != nil {
+			return nil, nil, fmt.Errorf("Couldn't encode node: %w", err)
+		}
+		err = os.MkdirAll(filepath.Dir(path), 0755)
+		if err == nil {
+			err = files.WriteAtomic(path, buf.Bytes())
+		}
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("Couldn't write node: %w", err)
+	}
+	return ns.withHashes(ns.hashes.Set(string(k), struct{}{})), k, nil
+}
+
+func (ns *NodeStore) Remove(k []byte) (mst.NodeStore, error) {
+	return ns.withHashes(ns.hashes.Delete(string(k))), nil
+}
+
+func (ns *NodeStore) Size() (uint, error) {
+	return uint(ns.hashes.Len()), nil
+}
+
+// Hashes returns the hashes of every node on disk, whether or not this version of the store holds
+// them
+func (ns *NodeStore) Hashes() ([][]byte, error) {
+	return listNodes(ns.dir)
+}
+
+// Collect deletes the files of the given nodes. Every version of the store loses them, not just
+// the one that's returned.
+func (ns *NodeStore) Collect(hashes [][]byte) (mst.NodeStore, error) {
+	remaining := ns.hashes
+	for _, k := range hashes {
+		err := os.Remove(ns.nodePath(k))
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return nil, fmt.Errorf("Couldn't delete node: %w", err)
+		}
+		remaining = remaining.Delete(string(k))
+	}
+	return ns.withHashes(remaining), nil
+}
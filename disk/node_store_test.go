@@ -0,0 +1,311 @@
+package disk
+
+import (
+	"bytes"
+	"crypto"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/vulturedb/vulture/internal/files"
+	"github.com/vulturedb/vulture/mst"
+)
+
+func tempDir(t *testing.T) string {
+	dir, err := ioutil.TempDir("", "vulture-disk-test")
+	require.NoError(t, err)
+	t.Cleanup(func() { os.RemoveAll(dir) })
+	return dir
+}
+
+func newTestStore(t *testing.T, dir string) *NodeStore {
+	store, err := NewNodeStore(dir, crypto.SHA256, mst.UInt32KeyReader{}, mst.UInt32ValueReader{})
+	require.NoError(t, err)
+	return store
+}
+
+func buildTree(t *testing.T, tree *mst.MerkleSearchTree, n int) *mst.MerkleSearchTree {
+	var err error
+	for i := 0; i < n; i++ {
+		tree, err = tree.Put(mst.UInt32(i), mst.UInt32(i*2))
+		require.NoError(t, err)
+	}
+	tree, err = tree.Delete(mst.UInt32(0))
+	require.NoError(t, err)
+	return tree
+}
+
+func TestNodeStoreReopen(t *testing.T) {
+	dir := tempDir(t)
+	tree := buildTree(t, mst.NewMST(mst.Base4, crypto.SHA256, newTestStore(t, dir)), 200)
+
+	// Hashes match the in memory store
+	local := buildTree(t, mst.NewLocalMST(mst.Base4, crypto.SHA256), 200)
+	assert.Equal(t, local.RootHash(), tree.RootHash())
+
+	reopened := mst.NewMSTWithRoot(tree.RootHash(), mst.Base4, crypto.SHA256, newTestStore(t, dir))
+	val, err := reopened.Get(mst.UInt32(0))
+	require.NoError(t, err)
+	assert.Nil(t, val)
+	for i := 1; i < 200; i++ {
+		val, err := reopened.Get(mst.UInt32(i))
+		require.NoError(t, err)
+		assert.Equal(t, mst.UInt32(i*2), val)
+	}
+}
+
+func TestNodeStoreKeepsOldVersions(t *testing.T) {
+	dir := tempDir(t)
+	before := buildTree(t, mst.NewMST(mst.Base4, crypto.SHA256, newTestStore(t, dir)), 100)
+	after, err := before.Put(mst.UInt32(50), mst.UInt32(1000))
+	require.NoError(t, err)
+
+	// The new version doesn't hold the nodes it replaced but the old one still reads them
+	beforeSize, err := before.NodeStore().Size()
+	require.NoError(t, err)
+	afterNodes, err := after.NumNodes()
+	require.NoError(t, err)
+	afterSize, err := after.NodeStore().Size()
+	require.NoError(t, err)
+	assert.Equal(t, afterNodes, afterSize)
+	assert.Less(t, afterSize, beforeSize+afterNodes)
+
+	val, err := before.Get(mst.UInt32(50))
+	require.NoError(t, err)
+	assert.Equal(t, mst.UInt32(100), val)
+	val, err = after.Get(mst.UInt32(50))
+	require.NoError(t, err)
+	assert.Equal(t, mst.UInt32(1000), val)
+}
+
+func TestNodeStoreNotFound(t *testing.T) {
+	store := newTestStore(t, tempDir(t))
+	_, err := store.Get([]byte{1, 2, 3})
+	assert.True(t, errors.Is(err, mst.ErrNodeNotFound))
+}
+
+func TestNodeStoreDetectsCorruption(t *testing.T) {
+	dir := tempDir(t)
+	store := newTestStore(t, dir)
+	n := mst.NewNode(0, nil, []mst.Child{mst.NewChild(mst.UInt32(1), mst.UInt32(1), nil)})
+	withN, k, err := store.Put(n)
+	require.NoError(t, err)
+
+	other := mst.NewNode(0, nil, []mst.Child{mst.NewChild(mst.UInt32(1), mst.UInt32(2), nil)})
+	withBoth, otherK, err := withN.Put(other)
+	require.NoError(t, err)
+	raw, err := ioutil.ReadFile(store.nodePath(otherK))
+	require.NoError(t, err)
+	require.NoError(t, ioutil.WriteFile(store.nodePath(k), raw, 0644))
+
+	_, err = withBoth.Get(k)
+	assert.Error(t, err)
+	assert.False(t, errors.Is(err, mst.ErrNodeNotFound))
+}
+
+func TestNodeStoreRemovesTempFiles(t *testing.T) {
+	dir := tempDir(t)
+	store := newTestStore(t, dir)
+	n := mst.NewNode(0, nil, []mst.Child{mst.NewChild(mst.UInt32(1), mst.UInt32(1), nil)})
+	_, k, err := store.Put(n)
+	require.NoError(t, err)
+
+	// Simulate a crash in the middle of writing a node
+	tmpPath := filepath.Join(filepath.Dir(store.nodePath(k)), "abcd.123"+files.TempSuffix)
+	require.NoError(t, ioutil.WriteFile(tmpPath, []byte{1, 2}, 0644))
+
+	reopened := newTestStore(t, dir)
+	_, err = os.Stat(tmpPath)
+	assert.True(t, errors.Is(err, os.ErrNotExist))
+	size, err := reopened.Size()
+	require.NoError(t, err)
+	assert.Equal(t, uint(1), size)
+	_, err = reopened.Get(k)
+	assert.NoError(t, err)
+}
+
+func TestRootStore(t *testing.T) {
+	dir := tempDir(t)
+	roots, err := NewRootStore(dir)
+	require.NoError(t, err)
+	root, err := roots.Root()
+	require.NoError(t, err)
+	assert.Nil(t, root)
+
+	require.NoError(t, roots.SetRoot([]byte{1, 2, 3}))
+	reopened, err := NewRootStore(dir)
+	require.NoError(t, err)
+	root, err = reopened.Root()
+	require.NoError(t, err)
+	assert.Equal(t, []byte{1, 2, 3}, root)
+
+	require.NoError(t, reopened.SetRoot(nil))
+	root, err = reopened.Root()
+	require.NoError(t, err)
+	assert.Nil(t, root)
+}
+
+func TestSnapshotStore(t *testing.T) {
+	dir := tempDir(t)
+	snapshots, err := NewSnapshotStore(dir)
+	require.NoError(t, err)
+	stored, err := snapshots.Snapshots()
+	require.NoError(t, err)
+	assert.Empty(t, stored)
+
+	created := time.Date(2020, 5, 1, 12, 0, 0, 0, time.UTC)
+	expected := []mst.Snapshot{
+		{Name: "first", Root: []byte{1, 2, 3}, Created: created},
+		{Name: "empty", Root: nil, Created: created.Add(time.Hour)},
+	}
+	require.NoError(t, snapshots.SetSnapshots(expected))
+	reopened, err := NewSnapshotStore(dir)
+	require.NoError(t, err)
+	stored, err = reopened.Snapshots()
+	require.NoError(t, err)
+	assert.Equal(t, expected, stored)
+}
+
+func TestNodeStoreCollectGarbage(t *testing.T) {
+	dir := tempDir(t)
+	before := buildTree(t, mst.NewMST(mst.Base4, crypto.SHA256, newTestStore(t, dir)), 100)
+	after, err := before.Put(mst.UInt32(50), mst.UInt32(1000))
+	require.NoError(t, err)
+
+	// Both versions are on disk until the old one is collected
+	onDisk, err := newTestStore(t, dir).Size()
+	require.NoError(t, err)
+	afterNodes, err := after.NumNodes()
+	require.NoError(t, err)
+	assert.Greater(t, onDisk, afterNodes)
+
+	store, collected, err := mst.CollectGarbage(after.NodeStore(), []*mst.MerkleSearchTree{after})
+	require.NoError(t, err)
+	assert.Equal(t, onDisk-afterNodes, collected)
+
+	// The old version's nodes are gone from every version of the store
+	_, err = before.Get(mst.UInt32(50))
+	assert.Error(t, err)
+
+	reopened := mst.NewMSTWithRoot(after.RootHash(), mst.Base4, crypto.SHA256, newTestStore(t, dir))
+	size, err := reopened.NodeStore().Size()
+	require.NoError(t, err)
+	assert.Equal(t, afterNodes, size)
+	val, err := after.WithNodeStore(store).Get(mst.UInt32(50))
+	require.NoError(t, err)
+	assert.Equal(t, mst.UInt32(1000), val)
+	val, err = reopened.Get(mst.UInt32(99))
+	require.NoError(t, err)
+	assert.Equal(t, mst.UInt32(198), val)
+}
+
+func TestNodeStoreRestoreArchive(t *testing.T) {
+	local := buildTree(t, mst.NewLocalMST(mst.Base4, crypto.SHA256), 200)
+	archive := new(bytes.Buffer)
+	require.NoError(t, local.Export(archive))
+
+	dir := tempDir(t)
+	restored, err := mst.Import(
+		archive,
+		newTestStore(t, dir),
+		mst.UInt32KeyReader{},
+		mst.UInt32ValueReader{},
+	)
+	require.NoError(t, err)
+	assert.Equal(t, local.RootHash(), restored.RootHash())
+	size, err := restored.NodeStore().Size()
+	require.NoError(t, err)
+	numNodes, err := local.NumNodes()
+	require.NoError(t, err)
+	assert.Equal(t, numNodes, size)
+
+	reopened := restored.WithNodeStore(newTestStore(t, dir))
+	for i := 1; i < 200; i++ {
+		val, err := reopened.Get(mst.UInt32(i))
+		require.NoError(t, err)
+		assert.Equal(t, mst.UInt32(i*2), val)
+	}
+}
+
+func TestKeyspaceStore(t *testing.T) {
+	dir := tempDir(t)
+	keyspaces, err := NewKeyspaceStore(dir)
+	require.NoError(t, err)
+	stored, err := keyspaces.Keyspaces()
+	require.NoError(t, err)
+	assert.Empty(t, stored)
+
+	expected := []mst.Keyspace{
+		{Name: "events", Base: mst.Base4, Hash: crypto.SHA256, KeyType: "int64", ValueType: "lww"},
+		{
+			Name:       "counters",
+			Base:       mst.Base16,
+			Hash:       crypto.SHA512,
+			KeyType:    "string",
+			ValueType:  "uint32",
+			Aggregator: "uint32",
+		},
+	}
+	require.NoError(t, keyspaces.SetKeyspaces(expected))
+	reopened, err := NewKeyspaceStore(dir)
+	require.NoError(t, err)
+	stored, err = reopened.Keyspaces()
+	require.NoError(t, err)
+	assert.Equal(t, expected, stored)
+}
+
+func TestStorageKeepsKeyspacesApart(t *testing.T) {
+	dir := tempDir(t)
+	storage := NewStorage(dir)
+	open := func(name string) (*mst.MerkleSearchTree, mst.RootStore) {
+		ks := mst.Keyspace{
+			Name:      name,
+			Base:      mst.Base4,
+			Hash:      crypto.SHA256,
+			KeyType:   "uint32",
+			ValueType: "uint32",
+		}
+		nodes, roots, snapshots, err := storage.Open(
+			ks,
+			mst.UInt32KeyReader{},
+			mst.UInt32ValueReader{},
+		)
+		require.NoError(t, err)
+		require.NotNil(t, snapshots)
+		root, err := roots.Root()
+		require.NoError(t, err)
+		tree, err := ks.Tree(root, nodes)
+		require.NoError(t, err)
+		return tree, roots
+	}
+
+	defaultTree, defaultRoots := open(mst.DefaultKeyspace)
+	defaultTree = buildTree(t, defaultTree, 50)
+	require.NoError(t, defaultRoots.SetRoot(defaultTree.RootHash()))
+	other, otherRoots := open("other")
+	assert.Nil(t, other.RootHash())
+	other = buildTree(t, other, 20)
+	require.NoError(t, otherRoots.SetRoot(other.RootHash()))
+
+	// The default keyspace is kept in the data directory itself
+	reopened := newTestStore(t, dir)
+	val, err := defaultTree.WithNodeStore(reopened).Get(mst.UInt32(40))
+	require.NoError(t, err)
+	assert.Equal(t, mst.UInt32(80), val)
+
+	reopenedOther, _ := open("other")
+	assert.Equal(t, other.RootHash(), reopenedOther.RootHash())
+	require.NoError(t, storage.Remove(mst.Keyspace{Name: "other"}))
+	removed, _ := open("other")
+	assert.Nil(t, removed.RootHash())
+	reopenedDefault, _ := open(mst.DefaultKeyspace)
+	assert.Equal(t, defaultTree.RootHash(), reopenedDefault.RootHash())
+
+	assert.Error(t, storage.Remove(mst.Keyspace{Name: mst.DefaultKeyspace}))
+}
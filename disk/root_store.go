@@ -0,0 +1,59 @@
+package disk
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/vulturedb/vulture/internal/files"
+	"github.com/vulturedb/vulture/mst"
+)
+
+const rootFileName = "ROOT"
+
+// RootStore keeps the root hash of a tree in a file in a data directory. The file is replaced
+// atomically, so after a crash it always holds a root that was fully written.
+type RootStore struct {
+	path string
+}
+
+// NewRootStore creates a RootStore in dir, creating dir if it doesn't exist
+func NewRootStore(dir string) (*RootStore, error) {
+	err := os.MkdirAll(dir, 0755)
+	if err != nil {
+		return nil, fmt.Errorf("Couldn't create data directory: %w", err)
+	}
+	return &RootStore{filepath.Join(dir, rootFileName)}, nil
+}
+
+var _ mst.RootStore = &RootStore{}
+
+// Root returns the last root that was set, or nil if there isn't one
+func (s *RootStore) Root() ([]byte, error) {
+	raw, err := ioutil.ReadFile(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("Couldn't read root: %w", err)
+	}
+	root, err := hex.DecodeString(strings.TrimSpace(string(raw)))
+	if err != nil {
+		return nil, fmt.Errorf("Couldn't decode root: %w", err)
+	}
+	if len(root) == 0 {
+		return nil, nil
+	}
+	return root, nil
+}
+
+func (s *RootStore) SetRoot(root []byte) error {
+	err := files.WriteAtomic(s.path, []byte(hex.EncodeToString(root)+"\n"))
+	if err != nil {
+		return fmt.Errorf("Couldn't write root: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,87 @@
+package disk
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/vulturedb/vulture/internal/files"
+	"github.com/vulturedb/vulture/mst"
+)
+
+const snapshotsFileName = "SNAPSHOTS"
+
+// SnapshotStore keeps the snapshots of a tree in a JSON file in a data directory, which is
+// replaced atomically like the root file
+type SnapshotStore struct {
+	path string
+}
+
+type encodedSnapshot struct {
+	Name    string    `json:"name"`
+	Root    string    `json:"root"`
+	Created time.Time `json:"created"`
+}
+
+// NewSnapshotStore creates a SnapshotStore in dir, creating dir if it doesn't exist
+func NewSnapshotStore(dir string) (*SnapshotStore, error) {
+	err := os.MkdirAll(dir, 0755)
+	if err != nil {
+		return nil, fmt.Errorf("Couldn't create data directory: %w", err)
+	}
+	return &SnapshotStore{filepath.Join(dir, snapshotsFileName)}, nil
+}
+
+var _ mst.SnapshotStore = &SnapshotStore{}
+
+// Snapshots returns the snapshots that were last set, or none if they never were
+func (s *SnapshotStore) Snapshots() ([]mst.Snapshot, error) {
+	raw, err := ioutil.ReadFile(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return []mst.Snapshot{}, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("Couldn't read snapshots: %w", err)
+	}
+	encoded := []encodedSnapshot{}
+	err = json.Unmarshal(raw, &encoded)
+	if err != nil {
+		return nil, fmt.Errorf("Couldn't decode snapshots: %w", err)
+	}
+	snapshots := make([]mst.Snapshot, len(encoded))
+	for i, snapshot := range encoded {
+		root, err := hex.DecodeString(snapshot.Root)
+		if err != nil {
+			return nil, fmt.Errorf("Couldn't decode root of snapshot %s: %w", snapshot.Name, err)
+		}
+		if len(root) == 0 {
+			root = nil
+		}
+		snapshots[i] = mst.Snapshot{Name: snapshot.Name, Root: root, Created: snapshot.Created}
+	}
+	return snapshots, nil
+}
+
+func (s *SnapshotStore) SetSnapshots(snapshots []mst.Snapshot) error {
+	encoded := make([]encodedSnapshot, len(snapshots))
+	for i, snapshot := range snapshots {
+		encoded[i] = encodedSnapshot{
+			Name:    snapshot.Name,
+			Root:    hex.EncodeToString(snapshot.Root),
+			Created: snapshot.Created,
+		}
+	}
+	raw, err := json.MarshalIndent(encoded, "", "  ")
+	if err != nil {
+		return fmt.Errorf("Couldn't encode snapshots: %w", err)
+	}
+	err = files.WriteAtomic(s.path, append(raw, '\n'))
+	if err != nil {
+		return fmt.Errorf("Couldn't write snapshots: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,57 @@
+package disk
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/vulturedb/vulture/internal/files"
+)
+
+const tablesFileName = "TABLES"
+
+// TableStore keeps the tables of a server in a JSON file in a data directory, as the ID of the
+// latest version of each one's schema by name. The file is replaced atomically like the root file.
+type TableStore struct {
+	path string
+}
+
+// NewTableStore creates a TableStore in dir, creating dir if it doesn't exist
+func NewTableStore(dir string) (*TableStore, error) {
+	err := os.MkdirAll(dir, 0755)
+	if err != nil {
+		return nil, fmt.Errorf("Couldn't create data directory: %w", err)
+	}
+	return &TableStore{filepath.Join(dir, tablesFileName)}, nil
+}
+
+// Tables returns the tables that were last set, or none if they never were
+func (s *TableStore) Tables() (map[string][]byte, error) {
+	raw, err := ioutil.ReadFile(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return map[string][]byte{}, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("Couldn't read tables: %w", err)
+	}
+	tables := map[string][]byte{}
+	err = json.Unmarshal(raw, &tables)
+	if err != nil {
+		return nil, fmt.Errorf("Couldn't decode tables: %w", err)
+	}
+	return tables, nil
+}
+
+func (s *TableStore) SetTables(tables map[string][]byte) error {
+	raw, err := json.MarshalIndent(tables, "", "  ")
+	if err != nil {
+		return fmt.Errorf("Couldn't encode tables: %w", err)
+	}
+	err = files.WriteAtomic(s.path, append(raw, '\n'))
+	if err != nil {
+		return fmt.Errorf("Couldn't write tables: %w", err)
+	}
+	return nil
+}
@@ -35,7 +35,7 @@ require (
 	golang.org/x/text v0.3.3 // indirect
 	golang.org/x/tools/gopls v0.4.4 // indirect
 	golang.org/x/xerrors v0.0.0-20200804184101-5ec99f83aff1 // indirect
-	google.golang.org/genproto v0.0.0-20200923140941-5646d36feee1 // indirect
+	google.golang.org/genproto v0.0.0-20200923140941-5646d36feee1
 	google.golang.org/grpc v1.32.0
 	google.golang.org/grpc/cmd/protoc-gen-go-grpc v0.0.0-20200922230038-4e932bbcb079 // indirect
 	google.golang.org/protobuf v1.25.0
@@ -0,0 +1,146 @@
+package dots
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"sort"
+)
+
+// Counts maps replicas to a number that only grows, like the increments of a counter or the
+// latest write of a replica a value has seen. Replicas with a zero count are left out so that
+// equal counts are always written the same way. Counts are never modified in place.
+type Counts map[string]uint64
+
+func (c Counts) Merge(other Counts) Counts {
+	merged := make(Counts, len(c)+len(other))
+	for replica, n := range c {
+		merged[replica] = n
+	}
+	for replica, n := range other {
+		if n > merged[replica] {
+			merged[replica] = n
+		}
+	}
+	return merged
+}
+
+// With returns the counts with the count of replica set to n
+func (c Counts) With(replica string, n uint64) Counts {
+	updated := c.Merge(nil)
+	if n > 0 {
+		updated[replica] = n
+	} else {
+		delete(updated, replica)
+	}
+	return updated
+}
+
+func (c Counts) Sum() uint64 {
+	total := uint64(0)
+	for _, n := range c {
+		total += n
+	}
+	return total
+}
+
+// Write writes the counts sorted by replica
+func (c Counts) Write(w *bytes.Buffer) {
+	replicas := make([]string, 0, len(c))
+	for replica := range c {
+		replicas = append(replicas, replica)
+	}
+	sort.Strings(replicas)
+	putUint32(w, uint32(len(replicas)))
+	for _, replica := range replicas {
+		putBytes(w, []byte(replica))
+		putUint64(w, c[replica])
+	}
+}
+
+// ReadCounts reads counts written by Counts.Write
+func ReadCounts(r *bytes.Reader) (Counts, error) {
+	n, err := getLength(r, 12)
+	if err != nil {
+		return nil, err
+	}
+	c := make(Counts, n)
+	for i := 0; i < n; i++ {
+		replica, err := getBytes(r)
+		if err != nil {
+			return nil, err
+		}
+		count, err := getUint64(r)
+		if err != nil {
+			return nil, err
+		}
+		if count > 0 {
+			c[string(replica)] = count
+		}
+	}
+	return c, nil
+}
+
+func putUint32(w *bytes.Buffer, n uint32) {
+	buf := make([]byte, 4)
+	binary.LittleEndian.PutUint32(buf, n)
+	w.Write(buf)
+}
+
+func putUint64(w *bytes.Buffer, n uint64) {
+	buf := make([]byte, 8)
+	binary.LittleEndian.PutUint64(buf, n)
+	w.Write(buf)
+}
+
+func putBytes(w *bytes.Buffer, b []byte) {
+	putUint32(w, uint32(len(b)))
+	w.Write(b)
+}
+
+func read(r *bytes.Reader, n int) ([]byte, error) {
+	if n > r.Len() {
+		return nil, fmt.Errorf("Expected %d more bytes, got %d", n, r.Len())
+	}
+	buf := make([]byte, n)
+	_, err := io.ReadFull(r, buf)
+	return buf, err
+}
+
+func getUint32(r *bytes.Reader) (uint32, error) {
+	buf, err := read(r, 4)
+	if err != nil {
+		return 0, err
+	}
+	return binary.LittleEndian.Uint32(buf), nil
+}
+
+func getUint64(r *bytes.Reader) (uint64, error) {
+	buf, err := read(r, 8)
+	if err != nil {
+		return 0, err
+	}
+	return binary.LittleEndian.Uint64(buf), nil
+}
+
+func getBytes(r *bytes.Reader) ([]byte, error) {
+	n, err := getUint32(r)
+	if err != nil {
+		return nil, err
+	}
+	return read(r, int(n))
+}
+
+// getLength reads the number of entries of a collection, checking that each of them could take at
+// least minSize bytes so that corrupt input can't make it allocate arbitrarily much
+func getLength(r *bytes.Reader, minSize int) (int, error) {
+	n, err := getUint32(r)
+	if err != nil {
+		return 0, err
+	}
+	if int(n)*minSize > r.Len() {
+		return 0, fmt.Errorf("%d entries don't fit in the remaining %d bytes", n, r.Len())
+	}
+	return int(n), nil
+}
@@ -0,0 +1,186 @@
+package dots
+
+import (
+	"bytes"
+	"sort"
+)
+
+// Dot identifies a single write by the replica that made it and how many writes that replica had
+// made by then
+type Dot struct {
+	Replica string
+	Counter uint64
+}
+
+// Value is a value of a kernel along with the dot of the write that added it. Payload isn't looked
+// at by the kernel, it's kept for whatever else its user knows about the write, like the decoded
+// value or its timestamp.
+type Value struct {
+	Dot     Dot
+	Bytes   []byte
+	Payload interface{}
+}
+
+// Kernel is the state of observed-remove sets and multi-value registers. Every value is tagged
+// with the dot of the write that added it, and the context holds every dot the kernel has seen.
+// When merging, a value that's missing on one side was either removed there, if that side has
+// seen its dot, or added concurrently otherwise. Values are kept sorted so that equal kernels are
+// written the same way. Both the crdt package and the merge policies of rows in core keep their
+// sets and multi-value registers in kernels, so they merge and encode them the same way.
+type Kernel struct {
+	values  []Value
+	context Counts
+}
+
+func NewKernel() Kernel {
+	return Kernel{nil, Counts{}}
+}
+
+func sortedKernel(values []Value, context Counts) Kernel {
+	sort.Slice(values, func(i, j int) bool {
+		if c := bytes.Compare(values[i].Bytes, values[j].Bytes); c != 0 {
+			return c < 0
+		} else if values[i].Dot.Replica != values[j].Dot.Replica {
+			return values[i].Dot.Replica < values[j].Dot.Replica
+		}
+		return values[i].Dot.Counter < values[j].Dot.Counter
+	})
+	return Kernel{values, context}
+}
+
+func (k Kernel) has(d Dot) bool {
+	for _, v := range k.values {
+		if v.Dot == d {
+			return true
+		}
+	}
+	return false
+}
+
+func (k Kernel) seen(d Dot) bool {
+	return k.context[d.Replica] >= d.Counter
+}
+
+// Add returns the kernel with value added by a new write of replica
+func (k Kernel) Add(replica string, value []byte, payload interface{}) Kernel {
+	d := Dot{replica, k.context[replica] + 1}
+	values := make([]Value, len(k.values), len(k.values)+1)
+	copy(values, k.values)
+	values = append(values, Value{d, append([]byte{}, value...), payload})
+	return sortedKernel(values, k.context.With(replica, d.Counter))
+}
+
+// Remove returns the kernel without value. Its dots stay in the context, so the removal wins over
+// merges with kernels that still have them.
+func (k Kernel) Remove(value []byte) Kernel {
+	values := []Value{}
+	for _, v := range k.values {
+		if !bytes.Equal(v.Bytes, value) {
+			values = append(values, v)
+		}
+	}
+	return Kernel{values, k.context}
+}
+
+func (k Kernel) RemoveAll() Kernel {
+	return Kernel{nil, k.context}
+}
+
+func (k Kernel) Contains(value []byte) bool {
+	i := sort.Search(len(k.values), func(i int) bool {
+		return bytes.Compare(k.values[i].Bytes, value) >= 0
+	})
+	return i < len(k.values) && bytes.Equal(k.values[i].Bytes, value)
+}
+
+// Values returns every value in the kernel, sorted by their bytes. Values added concurrently by
+// different writes are there once for each of them.
+func (k Kernel) Values() []Value {
+	return k.values
+}
+
+// Distinct returns the first of the values with the same bytes, sorted by their bytes
+func (k Kernel) Distinct() []Value {
+	distinct := []Value{}
+	for i, v := range k.values {
+		if i == 0 || !bytes.Equal(v.Bytes, k.values[i-1].Bytes) {
+			distinct = append(distinct, v)
+		}
+	}
+	return distinct
+}
+
+// Elements returns the distinct values in the kernel, sorted by their bytes
+func (k Kernel) Elements() [][]byte {
+	distinct := k.Distinct()
+	elements := make([][]byte, len(distinct))
+	for i, v := range distinct {
+		elements[i] = v.Bytes
+	}
+	return elements
+}
+
+func (k Kernel) Merge(other Kernel) Kernel {
+	values := []Value{}
+	for _, v := range k.values {
+		if other.has(v.Dot) || !other.seen(v.Dot) {
+			values = append(values, v)
+		}
+	}
+	for _, v := range other.values {
+		if !k.has(v.Dot) && !k.seen(v.Dot) {
+			values = append(values, v)
+		}
+	}
+	return sortedKernel(values, k.context.Merge(other.context))
+}
+
+// Write writes the context and then every value. If writePayload isn't nil, it writes what's
+// needed to read the payload of a value back right after the value.
+func (k Kernel) Write(w *bytes.Buffer, writePayload func(w *bytes.Buffer, v Value)) {
+	k.context.Write(w)
+	putUint32(w, uint32(len(k.values)))
+	for _, v := range k.values {
+		putBytes(w, []byte(v.Dot.Replica))
+		putUint64(w, v.Dot.Counter)
+		putBytes(w, v.Bytes)
+		if writePayload != nil {
+			writePayload(w, v)
+		}
+	}
+}
+
+// ReadKernel reads a kernel written by Kernel.Write, with readPayload reading and setting the
+// payload of each value if it isn't nil
+func ReadKernel(
+	r *bytes.Reader,
+	readPayload func(r *bytes.Reader, v *Value) error,
+) (Kernel, error) {
+	context, err := ReadCounts(r)
+	if err != nil {
+		return Kernel{}, err
+	}
+	n, err := getLength(r, 16)
+	if err != nil {
+		return Kernel{}, err
+	}
+	values := make([]Value, n)
+	for i := range values {
+		v := &values[i]
+		replica, err := getBytes(r)
+		if err == nil {
+			v.Dot.Replica = string(replica)
+			v.Dot.Counter, err = getUint64(r)
+		}
+		if err == nil {
+			v.Bytes, err = getBytes(r)
+		}
+		if err == nil && readPayload != nil {
+			err = readPayload(r, v)
+		}
+		if err != nil {
+			return Kernel{}, err
+		}
+	}
+	return sortedKernel(values, context), nil
+}
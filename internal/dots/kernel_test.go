@@ -0,0 +1,52 @@
+package dots
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func kernelBytes(k Kernel, writePayload func(w *bytes.Buffer, v Value)) []byte {
+	buf := new(bytes.Buffer)
+	k.Write(buf, writePayload)
+	return buf.Bytes()
+}
+
+func TestKernelMerge(t *testing.T) {
+	base := NewKernel().Add("a", []byte("x"), nil)
+	// a removes x while b adds y and x again without having seen a's removal
+	left := base.Remove([]byte("x"))
+	right := base.Add("b", []byte("y"), nil).Add("b", []byte("x"), nil)
+
+	merged := left.Merge(right)
+	assert.Equal(t, kernelBytes(merged, nil), kernelBytes(right.Merge(left), nil))
+	assert.Equal(t, [][]byte{[]byte("x"), []byte("y")}, merged.Elements())
+	// Only b's add of x survives, since a's was removed
+	assert.Len(t, merged.Values(), 2)
+	assert.Equal(t, Counts{"a": 1, "b": 2}, merged.context)
+	assert.Equal(t, kernelBytes(merged, nil), kernelBytes(merged.Merge(merged), nil))
+}
+
+func TestReadKernel(t *testing.T) {
+	k := NewKernel().Add("a", []byte("x"), byte(1)).Add("b", []byte("x"), byte(2))
+	k = k.Add("a", []byte("w"), byte(3))
+	writePayload := func(w *bytes.Buffer, v Value) { w.WriteByte(v.Payload.(byte)) }
+	raw := kernelBytes(k, writePayload)
+
+	read, err := ReadKernel(bytes.NewReader(raw), func(r *bytes.Reader, v *Value) error {
+		var err error
+		v.Payload, err = r.ReadByte()
+		return err
+	})
+	require.NoError(t, err)
+	assert.Equal(t, k, read)
+	assert.Equal(t, []Value{k.Values()[0], k.Values()[1]}, read.Distinct())
+
+	_, err = ReadKernel(bytes.NewReader(raw[:len(raw)-1]), nil)
+	assert.Error(t, err)
+	counts, err := ReadCounts(bytes.NewReader(raw))
+	require.NoError(t, err)
+	assert.Equal(t, Counts{"a": 2, "b": 1}, counts)
+}
@@ -0,0 +1,66 @@
+package files
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// TempSuffix marks files that are still being written. They're only renamed into place once
+// they've been synced, so any that are left over after a crash are garbage.
+const TempSuffix = ".tmp"
+
+// WriteAtomic writes data to path so that path either keeps its old contents or has all of
+// data, even if the process crashes part way through.
+func WriteAtomic(path string, data []byte) error {
+	dir := filepath.Dir(path)
+	f, err := ioutil.TempFile(dir, filepath.Base(path)+".*"+TempSuffix)
+	if err != nil {
+		return err
+	}
+	tmpPath := f.Name()
+	_, err = f.Write(data)
+	if err == nil {
+		err = f.Sync()
+	}
+	closeErr := f.Close()
+	if err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(tmpPath, path)
+	}
+	if err != nil {
+		os.Remove(tmpPath)
+		return err
+	}
+	return syncDir(dir)
+}
+
+// syncDir makes sure renames into dir survive a crash
+func syncDir(dir string) error {
+	d, err := os.Open(dir)
+	if err != nil {
+		return err
+	}
+	err = d.Sync()
+	closeErr := d.Close()
+	if err != nil {
+		return err
+	}
+	return closeErr
+}
+
+// RemoveTempFiles deletes every temporary file under dir left behind by an interrupted WriteAtomic
+func RemoveTempFiles(dir string) error {
+	return filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if !info.IsDir() && strings.HasSuffix(path, TempSuffix) {
+			return os.Remove(path)
+		}
+		return nil
+	})
+}
@@ -0,0 +1,126 @@
+package ipfs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/ipfs/go-cid"
+	cbor "github.com/ipfs/go-ipld-cbor"
+	ipld "github.com/ipfs/go-ipld-format"
+
+	"github.com/vulturedb/vulture/internal/files"
+)
+
+// LocalDAG is an ipld.DAGService of CBOR nodes that doesn't need an IPFS node. Nodes are kept in
+// memory and, if it has a directory, in a file per node named by its CID, so that they're still
+// there after a restart.
+type LocalDAG struct {
+	dir   string
+	nodes map[cid.Cid]ipld.Node
+	lock  sync.RWMutex
+}
+
+// NewLocalDAG creates a DAG that keeps its nodes in dir, or only in memory if dir is empty. Nodes
+// that were still being written when the process last stopped are removed.
+func NewLocalDAG(dir string) (*LocalDAG, error) {
+	if dir != "" {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return nil, err
+		}
+		if err := files.RemoveTempFiles(dir); err != nil {
+			return nil, err
+		}
+	}
+	return &LocalDAG{dir: dir, nodes: make(map[cid.Cid]ipld.Node)}, nil
+}
+
+func (d *LocalDAG) nodePath(c cid.Cid) string {
+	return filepath.Join(d.dir, c.String())
+}
+
+func (d *LocalDAG) Get(ctx context.Context, c cid.Cid) (ipld.Node, error) {
+	d.lock.RLock()
+	nd, exists := d.nodes[c]
+	d.lock.RUnlock()
+	if exists {
+		return nd, nil
+	} else if d.dir == "" {
+		return nil, ipld.ErrNotFound
+	}
+	raw, err := ioutil.ReadFile(d.nodePath(c))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, ipld.ErrNotFound
+	} else if err != nil {
+		return nil, err
+	}
+	decoded, err := cbor.Decode(raw, c.Prefix().MhType, c.Prefix().MhLength)
+	if err != nil {
+		return nil, fmt.Errorf("Couldn't decode node %s: %w", c, err)
+	}
+	if !decoded.Cid().Equals(c) {
+		return nil, fmt.Errorf("Node %s is stored as %s", c, decoded.Cid())
+	}
+	d.lock.Lock()
+	d.nodes[c] = decoded
+	d.lock.Unlock()
+	return decoded, nil
+}
+
+func (d *LocalDAG) GetMany(ctx context.Context, cids []cid.Cid) <-chan *ipld.NodeOption {
+	out := make(chan *ipld.NodeOption, len(cids))
+	for _, c := range cids {
+		nd, err := d.Get(ctx, c)
+		out <- &ipld.NodeOption{Node: nd, Err: err}
+	}
+	close(out)
+	return out
+}
+
+func (d *LocalDAG) Add(ctx context.Context, nd ipld.Node) error {
+	if d.dir != "" {
+		if err := files.WriteAtomic(d.nodePath(nd.Cid()), nd.RawData()); err != nil {
+			return err
+		}
+	}
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	d.nodes[nd.Cid()] = nd
+	return nil
+}
+
+func (d *LocalDAG) AddMany(ctx context.Context, nds []ipld.Node) error {
+	for _, nd := range nds {
+		if err := d.Add(ctx, nd); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (d *LocalDAG) Remove(ctx context.Context, c cid.Cid) error {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	delete(d.nodes, c)
+	if d.dir == "" {
+		return nil
+	}
+	err := os.Remove(d.nodePath(c))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	return err
+}
+
+func (d *LocalDAG) RemoveMany(ctx context.Context, cids []cid.Cid) error {
+	for _, c := range cids {
+		if err := d.Remove(ctx, c); err != nil {
+			return err
+		}
+	}
+	return nil
+}
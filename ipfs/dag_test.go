@@ -0,0 +1,43 @@
+package ipfs
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	cbor "github.com/ipfs/go-ipld-cbor"
+	mh "github.com/multiformats/go-multihash"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/vulturedb/vulture/internal/files"
+)
+
+func TestLocalDAGRemovesTempFiles(t *testing.T) {
+	dir, err := ioutil.TempDir("", "dag")
+	require.NoError(t, err)
+	t.Cleanup(func() { os.RemoveAll(dir) })
+	dag, err := NewLocalDAG(dir)
+	require.NoError(t, err)
+	nd, err := cbor.WrapObject(map[string]string{"name": "users"}, mh.SHA2_256, -1)
+	require.NoError(t, err)
+	require.NoError(t, dag.Add(context.Background(), nd))
+
+	// Simulate a crash in the middle of writing a node
+	tmpPath := filepath.Join(dir, "abcd.123"+files.TempSuffix)
+	require.NoError(t, ioutil.WriteFile(tmpPath, []byte{1, 2}, 0644))
+
+	reopened, err := NewLocalDAG(dir)
+	require.NoError(t, err)
+	_, err = os.Stat(tmpPath)
+	assert.True(t, errors.Is(err, os.ErrNotExist))
+	read, err := reopened.Get(context.Background(), nd.Cid())
+	require.NoError(t, err)
+	assert.Equal(t, nd.RawData(), read.RawData())
+	entries, err := ioutil.ReadDir(dir)
+	require.NoError(t, err)
+	assert.Len(t, entries, 1)
+}
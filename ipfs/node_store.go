@@ -3,6 +3,7 @@ package ipfs
 import (
 	"bytes"
 	"context"
+	"errors"
 	"fmt"
 
 	"github.com/ipfs/go-cid"
@@ -33,27 +34,46 @@ func linkToHash(l *node.Link) []byte {
 }
 
 type iPFSMSTChild struct {
-	Key   []byte     `mapstructure:"key"`
-	Value []byte     `mapstructure:"value"`
-	High  *node.Link `mapstructure:"high"`
+	Key       []byte     `mapstructure:"key"`
+	Value     []byte     `mapstructure:"value"`
+	High      *node.Link `mapstructure:"high"`
+	Tombstone bool       `mapstructure:"tombstone"`
+	Deleted   []byte     `mapstructure:"deleted"`
 }
 
-func newIPFSMSTChild(c mst.Child) iPFSMSTChild {
-	keyBuf := new(bytes.Buffer)
-	valBuf := new(bytes.Buffer)
-	err := c.Key().Write(keyBuf)
+func writableToBytes(w mst.Writable, what string) ([]byte, error) {
+	buf := new(bytes.Buffer)
+	err := w.Write(buf)
 	if err != nil {
-		panic(fmt.Errorf("Couldn't write key to byte buffer: %s", err))
+		return nil, fmt.Errorf("Couldn't write %s to byte buffer: %w", what, err)
 	}
-	err = c.Value().Write(valBuf)
+	return buf.Bytes(), nil
+}
+
+func newIPFSMSTChild(c mst.Child) (iPFSMSTChild, error) {
+	key, err := writableToBytes(c.Key(), "key")
 	if err != nil {
-		panic(fmt.Errorf("Couldn't write value to byte buffer: %s", err))
+		return iPFSMSTChild{}, err
 	}
 	high, err := hashToLink(c.High())
 	if err != nil {
-		panic(fmt.Errorf("Couldn't create link from high hash: %s", err))
+		return iPFSMSTChild{}, fmt.Errorf("Couldn't create link from high hash: %w", err)
+	}
+	child := iPFSMSTChild{Key: key, High: high}
+	if tombstone, isTombstone := c.Value().(mst.Tombstone); isTombstone {
+		child.Tombstone = true
+		child.Value, err = writableToBytes(tombstone.Value(), "value")
+		if err != nil {
+			return iPFSMSTChild{}, err
+		}
+		child.Deleted, err = writableToBytes(tombstone.Deleted(), "deleted value")
+	} else {
+		child.Value, err = writableToBytes(c.Value(), "value")
 	}
-	return iPFSMSTChild{keyBuf.Bytes(), valBuf.Bytes(), high}
+	if err != nil {
+		return iPFSMSTChild{}, err
+	}
+	return child, nil
 }
 
 func (c iPFSMSTChild) toMSTChild(kr mst.KeyReader, vr mst.ValueReader) (mst.Child, error) {
@@ -65,26 +85,50 @@ func (c iPFSMSTChild) toMSTChild(kr mst.KeyReader, vr mst.ValueReader) (mst.Chil
 	if err != nil {
 		return mst.Child{}, err
 	}
+	if c.Tombstone {
+		deleted, err := vr.FromBytes(c.Deleted)
+		if err != nil {
+			return mst.Child{}, err
+		}
+		v = mst.NewTombstone(v, deleted)
+	}
 	return mst.NewChild(k, v, linkToHash(c.High)), nil
 }
 
 type iPFSMSTNode struct {
-	Level    uint32         `mapstructure:"level"`
-	Low      *node.Link     `mapstructure:"low"`
-	Children []iPFSMSTChild `mapstructure:"children"`
+	Level     uint32           `mapstructure:"level"`
+	Low       *node.Link       `mapstructure:"low"`
+	Children  []iPFSMSTChild   `mapstructure:"children"`
+	Summaries []iPFSMSTSummary `mapstructure:"summaries"`
 }
 
-func newIPFSMSTNode(n *mst.Node) *iPFSMSTNode {
+type iPFSMSTSummary struct {
+	Count    uint64  `mapstructure:"count"`
+	Measured uint64  `mapstructure:"measured"`
+	Sum      float64 `mapstructure:"sum"`
+	Min      float64 `mapstructure:"min"`
+	Max      float64 `mapstructure:"max"`
+}
+
+func newIPFSMSTNode(n *mst.Node) (*iPFSMSTNode, error) {
 	nChildren := n.Children()
 	children := make([]iPFSMSTChild, len(nChildren))
 	for i, nChild := range nChildren {
-		children[i] = newIPFSMSTChild(nChild)
+		var err error
+		children[i], err = newIPFSMSTChild(nChild)
+		if err != nil {
+			return nil, err
+		}
 	}
 	low, err := hashToLink(n.Low())
 	if err != nil {
-		panic(fmt.Errorf("Couldn't create link from low hash: %s", err))
+		return nil, fmt.Errorf("Couldn't create link from low hash: %w", err)
 	}
-	return &iPFSMSTNode{n.Level(), low, children}
+	var summaries []iPFSMSTSummary
+	for _, s := range n.Summaries() {
+		summaries = append(summaries, iPFSMSTSummary(s))
+	}
+	return &iPFSMSTNode{n.Level(), low, children, summaries}, nil
 }
 
 func (n *iPFSMSTNode) toMSTNode(kr mst.KeyReader, vr mst.ValueReader) (*mst.Node, error) {
@@ -96,7 +140,11 @@ func (n *iPFSMSTNode) toMSTNode(kr mst.KeyReader, vr mst.ValueReader) (*mst.Node
 			return nil, err
 		}
 	}
-	return mst.NewNode(n.Level, linkToHash(n.Low), children), nil
+	var summaries []mst.Summary
+	for _, s := range n.Summaries {
+		summaries = append(summaries, mst.Summary(s))
+	}
+	return mst.NewNode(n.Level, linkToHash(n.Low), children).WithSummaries(summaries), nil
 }
 
 type IPFSMSTNodeStore struct {
@@ -117,61 +165,65 @@ func NewIPFSMSTNodeStore(
 	return &IPFSMSTNodeStore{ctx, dagService, multihashType, keyReader, valReader}
 }
 
-// TODO: Potentially change the node store interface to return errors and have the mst methods also
-// return errors. Panicing here is pretty dangerous since it's likely that stuff could go wrong.
 // TODO: The immutable interface is a little weird for an I/O based store like
 // IPFS. Figure out why this is and implement a way to have multiple references
 // to the IPFS store.
 
-func (s *IPFSMSTNodeStore) Get(k []byte) *mst.Node {
+func (s *IPFSMSTNodeStore) Get(k []byte) (*mst.Node, error) {
 	_, ndCid, err := cid.CidFromBytes(k)
 	if err != nil {
-		panic(fmt.Errorf("Couldn't create cid: %s", err))
+		return nil, fmt.Errorf("Couldn't create cid: %w", err)
 	}
 	nd, err := s.dagService.Get(s.ctx, ndCid)
-	if err != nil {
-		panic(fmt.Errorf("Couldn't get node: %s", err))
+	if err == node.ErrNotFound {
+		return nil, fmt.Errorf("%w: %s", mst.ErrNodeNotFound, ndCid)
+	} else if err != nil {
+		return nil, fmt.Errorf("Couldn't get node: %w", err)
 	}
 	raw, _, err := nd.Resolve([]string{})
 	if err != nil {
-		panic(fmt.Errorf("Couldn't resolve node: %s", err))
+		return nil, fmt.Errorf("Couldn't resolve node: %w", err)
 	}
 	node := &iPFSMSTNode{}
 	err = unmarshal(node, raw)
 	if err != nil {
-		panic(fmt.Errorf("Couldn't unmarshal node: %s", err))
+		return nil, fmt.Errorf("Couldn't unmarshal node: %w", err)
 	}
 	mstNode, err := node.toMSTNode(s.keyReader, s.valReader)
 	if err != nil {
-		panic(fmt.Errorf("Couldn't convert to mst node: %s", err))
+		return nil, fmt.Errorf("Couldn't convert to mst node: %w", err)
 	}
-	return mstNode
+	return mstNode, nil
 }
 
-func (s *IPFSMSTNodeStore) Put(n *mst.Node) (mst.NodeStore, []byte) {
-	nd, err := cbor.WrapObject(newIPFSMSTNode(n), s.multihashType, -1)
+func (s *IPFSMSTNodeStore) Put(n *mst.Node) (mst.NodeStore, []byte, error) {
+	ipfsNode, err := newIPFSMSTNode(n)
+	if err != nil {
+		return nil, nil, err
+	}
+	nd, err := cbor.WrapObject(ipfsNode, s.multihashType, -1)
 	if err != nil {
-		panic(fmt.Errorf("Couldn't wrap object: %s", err))
+		return nil, nil, fmt.Errorf("Couldn't wrap object: %w", err)
 	}
 	err = s.dagService.Add(s.ctx, nd)
 	if err != nil {
-		panic(fmt.Errorf("Couldn't add node: %s", err))
+		return nil, nil, fmt.Errorf("Couldn't add node: %w", err)
 	}
-	return s, nd.Cid().Bytes()
+	return s, nd.Cid().Bytes(), nil
 }
 
-func (s *IPFSMSTNodeStore) Remove(k []byte) mst.NodeStore {
+func (s *IPFSMSTNodeStore) Remove(k []byte) (mst.NodeStore, error) {
 	_, ndCid, err := cid.CidFromBytes(k)
 	if err != nil {
-		panic(fmt.Errorf("Couldn't create cid: %s", err))
+		return nil, fmt.Errorf("Couldn't create cid: %w", err)
 	}
 	err = s.dagService.Remove(s.ctx, ndCid)
 	if err != nil {
-		panic(fmt.Errorf("Couldn't remove node: %s", err))
+		return nil, fmt.Errorf("Couldn't remove node: %w", err)
 	}
-	return s
+	return s, nil
 }
 
-func (s *IPFSMSTNodeStore) Size() uint {
-	panic("Cannot call Size on an IPFSMSTNodeStore")
+func (s *IPFSMSTNodeStore) Size() (uint, error) {
+	return 0, errors.New("Cannot call Size on an IPFSMSTNodeStore")
 }
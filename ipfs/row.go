@@ -0,0 +1,206 @@
+package ipfs
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/ipfs/go-cid"
+	cbor "github.com/ipfs/go-ipld-cbor"
+	ipld "github.com/ipfs/go-ipld-format"
+	mh "github.com/multiformats/go-multihash"
+
+	"github.com/vulturedb/vulture/core"
+)
+
+// Rows are stored as CBOR maps from field names to values. Types CBOR has no counterpart for are
+// stored as the closest thing it has: timestamps as nanoseconds since the epoch, UUIDs as their 16
+// bytes and decimals as strings like -12.50. Since several types share a representation, rows are
+// read back with their schema.
+
+func valueToCBOR(spec core.FieldSpec, v interface{}) (interface{}, error) {
+	switch val := v.(type) {
+	case string, int32, int64, float32, float64, bool, []byte:
+		return val, nil
+	case time.Time:
+		return val.UnixNano(), nil
+	case uuid.UUID:
+		return val[:], nil
+	case core.Decimal:
+		return val.String(), nil
+	case []interface{}:
+		items := make([]interface{}, len(val))
+		for i, item := range val {
+			var err error
+			items[i], err = valueToCBOR(*spec.Items, item)
+			if err != nil {
+				return nil, err
+			}
+		}
+		return items, nil
+	case core.Row:
+		return fieldsToCBOR(spec.Fields, val)
+	default:
+		return nil, fmt.Errorf("Unsupported field value %v of type %T", v, v)
+	}
+}
+
+func fieldsToCBOR(
+	fields map[string]core.FieldSpec,
+	r core.Row,
+) (map[string]interface{}, error) {
+	m := make(map[string]interface{}, len(r.Data))
+	for name, v := range r.Data {
+		var err error
+		m[name], err = valueToCBOR(fields[name], v)
+		if err != nil {
+			return nil, fmt.Errorf("Couldn't convert field %s: %w", name, err)
+		}
+	}
+	return m, nil
+}
+
+func cborInt(raw interface{}) (int64, error) {
+	switch val := raw.(type) {
+	case int:
+		return int64(val), nil
+	case int64:
+		return val, nil
+	case uint64:
+		return int64(val), nil
+	default:
+		return 0, fmt.Errorf("Expected an integer, got %T", raw)
+	}
+}
+
+func cborFloat(raw interface{}) (float64, error) {
+	switch val := raw.(type) {
+	case float32:
+		return float64(val), nil
+	case float64:
+		return val, nil
+	default:
+		return 0, fmt.Errorf("Expected a float, got %T", raw)
+	}
+}
+
+func valueFromCBOR(spec core.FieldSpec, raw interface{}) (interface{}, error) {
+	switch spec.Type {
+	case "string", "enum":
+		if s, ok := raw.(string); ok {
+			return s, nil
+		}
+	case "int":
+		n, err := cborInt(raw)
+		return int32(n), err
+	case "long":
+		return cborInt(raw)
+	case "timestamp":
+		n, err := cborInt(raw)
+		return time.Unix(0, n).UTC(), err
+	case "float":
+		f, err := cborFloat(raw)
+		return float32(f), err
+	case "double":
+		return cborFloat(raw)
+	case "boolean":
+		if b, ok := raw.(bool); ok {
+			return b, nil
+		}
+	case "bytes":
+		if b, ok := raw.([]byte); ok {
+			return b, nil
+		}
+	case "uuid":
+		if b, ok := raw.([]byte); ok {
+			return uuid.FromBytes(b)
+		}
+	case "decimal":
+		if s, ok := raw.(string); ok {
+			return core.ParseDecimal(s)
+		}
+	case "array":
+		if rawItems, ok := raw.([]interface{}); ok {
+			items := make([]interface{}, len(rawItems))
+			for i, rawItem := range rawItems {
+				var err error
+				items[i], err = valueFromCBOR(*spec.Items, rawItem)
+				if err != nil {
+					return nil, fmt.Errorf("Couldn't convert item %d: %w", i, err)
+				}
+			}
+			return items, nil
+		}
+	case "record":
+		if m, ok := raw.(map[string]interface{}); ok {
+			return fieldsFromCBOR(spec.Fields, m)
+		}
+	default:
+		return nil, fmt.Errorf("Unsupported type %s", spec.Type)
+	}
+	return nil, fmt.Errorf("Unexpected %T for a value of type %s", raw, spec.Type)
+}
+
+func fieldsFromCBOR(fields map[string]core.FieldSpec, m map[string]interface{}) (core.Row, error) {
+	data := make(map[string]interface{}, len(m))
+	for name, raw := range m {
+		spec, ok := fields[name]
+		if !ok {
+			return core.Row{}, fmt.Errorf("Extraneous field %s", name)
+		}
+		v, err := valueFromCBOR(spec, raw)
+		if err != nil {
+			return core.Row{}, fmt.Errorf("Couldn't convert field %s: %w", name, err)
+		}
+		data[name] = v
+	}
+	return core.Row{Data: data}, nil
+}
+
+// PutRow stores a row that's valid under schema
+func PutRow(c context.Context, a ipld.NodeAdder, schema core.Schema, r core.Row) (cid.Cid, error) {
+	r, err := schema.ValidateRow(r)
+	if err != nil {
+		return cid.Undef, err
+	}
+	m, err := fieldsToCBOR(schema.Fields, r)
+	if err != nil {
+		return cid.Undef, err
+	}
+	nd, err := cbor.WrapObject(m, mh.SHA2_256, -1)
+	if err != nil {
+		return cid.Undef, err
+	}
+	err = a.Add(c, nd)
+	if err != nil {
+		return cid.Undef, err
+	}
+	return nd.Cid(), nil
+}
+
+// GetRow reads back a row stored by PutRow with the same schema
+func GetRow(
+	c context.Context,
+	a ipld.NodeGetter,
+	schema core.Schema,
+	cid cid.Cid,
+) (core.Row, error) {
+	nd, err := a.Get(c, cid)
+	if err != nil {
+		return core.Row{}, err
+	}
+	raw, _, err := nd.Resolve([]string{})
+	if err != nil {
+		return core.Row{}, err
+	}
+	m, ok := raw.(map[string]interface{})
+	if !ok {
+		return core.Row{}, fmt.Errorf("Expected a map for a row, got %T", raw)
+	}
+	r, err := fieldsFromCBOR(schema.Fields, m)
+	if err != nil {
+		return core.Row{}, err
+	}
+	return schema.ValidateRow(r)
+}
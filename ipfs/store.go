@@ -2,6 +2,7 @@ package ipfs
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/mitchellh/mapstructure"
 
@@ -15,11 +16,12 @@ import (
 )
 
 func RegisterTypes() {
-	cbor.RegisterCborType(core.FieldSpec{})
-	cbor.RegisterCborType(core.Schema{})
+	cbor.RegisterCborType(iPFSFieldSpec{})
+	cbor.RegisterCborType(iPFSSchema{})
 
 	cbor.RegisterCborType(iPFSMSTChild{})
 	cbor.RegisterCborType(iPFSMSTNode{})
+	cbor.RegisterCborType(iPFSMSTSummary{})
 
 	cbor.RegisterCborType(format.Link{})
 }
@@ -33,12 +35,160 @@ func unmarshal(v interface{}, m interface{}) error {
 	if err != nil {
 		return err
 	}
-	decoder.Decode(m)
-	return nil
+	return decoder.Decode(m)
+}
+
+// iPFSFieldSpec is how a core.FieldSpec is stored. Defaults are stored the same way as the values
+// of rows, see PutRow.
+type iPFSFieldSpec struct {
+	Type      string                   `mapstructure:"type"`
+	Nullable  bool                     `mapstructure:"nullable"`
+	Symbols   []string                 `mapstructure:"symbols"`
+	Items     *iPFSFieldSpec           `mapstructure:"items"`
+	Fields    map[string]iPFSFieldSpec `mapstructure:"fields"`
+	Default   interface{}              `mapstructure:"default"`
+	Min       *float64                 `mapstructure:"min"`
+	Max       *float64                 `mapstructure:"max"`
+	MaxLength *uint32                  `mapstructure:"maxLength"`
+	Pattern   string                   `mapstructure:"pattern"`
+	Merge     string                   `mapstructure:"merge"`
+}
+
+func newIPFSFieldSpecs(fields map[string]core.FieldSpec) (map[string]iPFSFieldSpec, error) {
+	if fields == nil {
+		return nil, nil
+	}
+	ipfsFields := make(map[string]iPFSFieldSpec, len(fields))
+	for name, spec := range fields {
+		ipfsSpec, err := newIPFSFieldSpec(spec)
+		if err != nil {
+			return nil, fmt.Errorf("Couldn't convert field %s: %w", name, err)
+		}
+		ipfsFields[name] = *ipfsSpec
+	}
+	return ipfsFields, nil
+}
+
+func newIPFSFieldSpec(spec core.FieldSpec) (*iPFSFieldSpec, error) {
+	fields, err := newIPFSFieldSpecs(spec.Fields)
+	if err != nil {
+		return nil, err
+	}
+	ipfsSpec := &iPFSFieldSpec{
+		Type:      spec.Type,
+		Nullable:  spec.Nullable,
+		Symbols:   spec.Symbols,
+		Fields:    fields,
+		Min:       spec.Min,
+		Max:       spec.Max,
+		MaxLength: spec.MaxLength,
+		Pattern:   spec.Pattern,
+		Merge:     spec.Merge,
+	}
+	if spec.Items != nil {
+		ipfsSpec.Items, err = newIPFSFieldSpec(*spec.Items)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if spec.Default != nil {
+		ipfsSpec.Default, err = valueToCBOR(spec, spec.Default)
+		if err != nil {
+			return nil, fmt.Errorf("Couldn't convert default: %w", err)
+		}
+	}
+	return ipfsSpec, nil
+}
+
+func fieldSpecsFromIPFS(ipfsFields map[string]iPFSFieldSpec) (map[string]core.FieldSpec, error) {
+	if ipfsFields == nil {
+		return nil, nil
+	}
+	fields := make(map[string]core.FieldSpec, len(ipfsFields))
+	for name, ipfsSpec := range ipfsFields {
+		spec, err := ipfsSpec.toFieldSpec()
+		if err != nil {
+			return nil, fmt.Errorf("Couldn't convert field %s: %w", name, err)
+		}
+		fields[name] = spec
+	}
+	return fields, nil
+}
+
+func (s *iPFSFieldSpec) toFieldSpec() (core.FieldSpec, error) {
+	fields, err := fieldSpecsFromIPFS(s.Fields)
+	if err != nil {
+		return core.FieldSpec{}, err
+	}
+	spec := core.FieldSpec{
+		Type:      s.Type,
+		Nullable:  s.Nullable,
+		Symbols:   s.Symbols,
+		Fields:    fields,
+		Min:       s.Min,
+		Max:       s.Max,
+		MaxLength: s.MaxLength,
+		Pattern:   s.Pattern,
+		Merge:     s.Merge,
+	}
+	if s.Items != nil {
+		items, err := s.Items.toFieldSpec()
+		if err != nil {
+			return core.FieldSpec{}, err
+		}
+		spec.Items = &items
+	}
+	if s.Default != nil {
+		spec.Default, err = valueFromCBOR(spec, s.Default)
+		if err != nil {
+			return core.FieldSpec{}, fmt.Errorf("Couldn't convert default: %w", err)
+		}
+	}
+	return spec, nil
+}
+
+// iPFSSchema is how a core.Schema is stored, with its parent as a link so that the versions of a
+// schema form a chain in the DAG
+type iPFSSchema struct {
+	Fields     map[string]iPFSFieldSpec `mapstructure:"fields"`
+	PrimaryKey []string                 `mapstructure:"primaryKey"`
+	Indexes    map[string][]string      `mapstructure:"indexes"`
+	Version    uint32                   `mapstructure:"version"`
+	Parent     *format.Link             `mapstructure:"parent"`
+}
+
+func newIPFSSchema(s core.Schema) (*iPFSSchema, error) {
+	fields, err := newIPFSFieldSpecs(s.Fields)
+	if err != nil {
+		return nil, err
+	}
+	parent, err := hashToLink(s.Parent)
+	if err != nil {
+		return nil, fmt.Errorf("Couldn't create link from parent: %w", err)
+	}
+	return &iPFSSchema{fields, s.PrimaryKey, s.Indexes, s.Version, parent}, nil
+}
+
+func (s *iPFSSchema) toSchema() (core.Schema, error) {
+	fields, err := fieldSpecsFromIPFS(s.Fields)
+	if err != nil {
+		return core.GenesisSchema(), err
+	}
+	return core.Schema{
+		Fields:     fields,
+		PrimaryKey: s.PrimaryKey,
+		Indexes:    s.Indexes,
+		Version:    s.Version,
+		Parent:     linkToHash(s.Parent),
+	}, nil
 }
 
 func PutSchema(c context.Context, a ipld.NodeAdder, s core.Schema) (cid.Cid, error) {
-	nd, err := cbor.WrapObject(s, mh.SHA2_256, -1)
+	ipfsSchema, err := newIPFSSchema(s)
+	if err != nil {
+		return cid.Undef, err
+	}
+	nd, err := cbor.WrapObject(ipfsSchema, mh.SHA2_256, -1)
 	if err != nil {
 		return cid.Undef, err
 	}
@@ -58,9 +208,95 @@ func GetSchema(c context.Context, a ipld.NodeGetter, cid cid.Cid) (core.Schema,
 	if err != nil {
 		return core.GenesisSchema(), err
 	}
-	s := &core.Schema{}
+	s := &iPFSSchema{}
 	if err = unmarshal(s, raw); err != nil {
 		return core.GenesisSchema(), err
 	}
-	return *s, nil
+	return s.toSchema()
+}
+
+// EvolveSchema stores next as the version that follows the schema at parent, after checking that
+// it's an allowed evolution of it, and returns the CID of the new version
+func EvolveSchema(
+	c context.Context,
+	dag ipld.DAGService,
+	parent cid.Cid,
+	next core.Schema,
+) (cid.Cid, error) {
+	parentSchema, err := GetSchema(c, dag, parent)
+	if err != nil {
+		return cid.Undef, fmt.Errorf("Couldn't get parent schema: %w", err)
+	}
+	if err = parentSchema.CheckEvolution(next); err != nil {
+		return cid.Undef, err
+	}
+	next.Version = parentSchema.Version + 1
+	next.Parent = parent.Bytes()
+	return PutSchema(c, dag, next)
+}
+
+// GetSchemaHistory follows the parent links of the schema at head and returns every version of it
+func GetSchemaHistory(
+	c context.Context,
+	a ipld.NodeGetter,
+	head cid.Cid,
+) (core.SchemaHistory, error) {
+	versions := []core.Schema{}
+	next := head
+	for {
+		s, err := GetSchema(c, a, next)
+		if err != nil {
+			return core.SchemaHistory{}, fmt.Errorf("Couldn't get schema %s: %w", next, err)
+		}
+		versions = append(versions, s)
+		if s.Parent == nil {
+			break
+		}
+		_, next, err = cid.CidFromBytes(s.Parent)
+		if err != nil {
+			return core.SchemaHistory{}, fmt.Errorf("Couldn't create cid: %w", err)
+		}
+	}
+	for i, j := 0, len(versions)-1; i < j; i, j = i+1, j-1 {
+		versions[i], versions[j] = versions[j], versions[i]
+	}
+	return core.NewSchemaHistoryFrom(versions)
+}
+
+// SchemaStore keeps the versions of schemas in a DAG, where each version links to the CID of the
+// one it evolved from. IDs of schemas are the bytes of their CIDs.
+type SchemaStore struct {
+	dag ipld.DAGService
+}
+
+func NewSchemaStore(dag ipld.DAGService) *SchemaStore {
+	return &SchemaStore{dag}
+}
+
+func (s *SchemaStore) PutSchema(schema core.Schema) ([]byte, error) {
+	c, err := PutSchema(context.Background(), s.dag, schema)
+	if err != nil {
+		return nil, err
+	}
+	return c.Bytes(), nil
+}
+
+func (s *SchemaStore) EvolveSchema(parent []byte, next core.Schema) ([]byte, error) {
+	_, parentCid, err := cid.CidFromBytes(parent)
+	if err != nil {
+		return nil, fmt.Errorf("Couldn't create cid: %w", err)
+	}
+	c, err := EvolveSchema(context.Background(), s.dag, parentCid, next)
+	if err != nil {
+		return nil, err
+	}
+	return c.Bytes(), nil
+}
+
+func (s *SchemaStore) SchemaHistory(head []byte) (core.SchemaHistory, error) {
+	_, headCid, err := cid.CidFromBytes(head)
+	if err != nil {
+		return core.SchemaHistory{}, fmt.Errorf("Couldn't create cid: %w", err)
+	}
+	return GetSchemaHistory(context.Background(), s.dag, headCid)
 }
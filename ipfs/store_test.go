@@ -0,0 +1,72 @@
+package ipfs
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/vulturedb/vulture/core"
+)
+
+func usersSchema() core.Schema {
+	return core.Schema{
+		Fields: map[string]core.FieldSpec{
+			"id":   {Type: "int"},
+			"name": {Type: "string"},
+		},
+		PrimaryKey: []string{"id"},
+	}
+}
+
+func TestSchemaStoreHistory(t *testing.T) {
+	RegisterTypes()
+	dir, err := ioutil.TempDir("", "schemas")
+	require.NoError(t, err)
+	t.Cleanup(func() { os.RemoveAll(dir) })
+	dag, err := NewLocalDAG(dir)
+	require.NoError(t, err)
+	store := NewSchemaStore(dag)
+
+	v0 := usersSchema()
+	head0, err := store.PutSchema(v0)
+	require.NoError(t, err)
+
+	v1 := usersSchema()
+	v1.Fields["email"] = core.FieldSpec{Type: "string", Nullable: true}
+	head1, err := store.EvolveSchema(head0, v1)
+	require.NoError(t, err)
+
+	v2 := usersSchema()
+	v2.Fields["email"] = core.FieldSpec{Type: "string", Nullable: true}
+	v2.Indexes = map[string][]string{"by_email": {"email"}}
+	head2, err := store.EvolveSchema(head1, v2)
+	require.NoError(t, err)
+
+	invalid := usersSchema()
+	invalid.Fields["name"] = core.FieldSpec{Type: "int"}
+	_, err = store.EvolveSchema(head2, invalid)
+	assert.Error(t, err)
+
+	// The versions are read back from the files of the DAG
+	reopened, err := NewLocalDAG(dir)
+	require.NoError(t, err)
+	history, err := NewSchemaStore(reopened).SchemaHistory(head2)
+	require.NoError(t, err)
+	versions := history.Versions()
+	require.Len(t, versions, 3)
+	parents := [][]byte{nil, head0, head1}
+	for i, v := range versions {
+		assert.Equal(t, uint32(i), v.Version)
+		assert.Equal(t, parents[i], v.Parent)
+		assert.Equal(t, v.PrimaryKey, []string{"id"})
+	}
+	assert.Contains(t, versions[1].Fields, "email")
+	assert.Equal(t, v2.Indexes, history.Latest().Indexes)
+
+	history, err = NewSchemaStore(reopened).SchemaHistory(head1)
+	require.NoError(t, err)
+	assert.Len(t, history.Versions(), 2)
+}
@@ -0,0 +1,290 @@
+package mst
+
+import (
+	"encoding/hex"
+	"fmt"
+	"io"
+	"math"
+	"strings"
+)
+
+// Aggregator measures the values that trees summarize with sums, minimums and maximums, for
+// example by picking a numeric field out of each value.
+type Aggregator interface {
+	// Name identifies what the aggregator measures. Trees can only be merged if their aggregators
+	// have the same name.
+	Name() string
+	// Measure returns the number v stands for, or false if it has none
+	Measure(v Value) (float64, bool)
+}
+
+// UInt32Aggregator measures UInt32 values as themselves
+type UInt32Aggregator struct{}
+
+func (a UInt32Aggregator) Name() string {
+	return "uint32"
+}
+
+func (a UInt32Aggregator) Measure(v Value) (float64, bool) {
+	n, ok := v.(UInt32)
+	return float64(n), ok
+}
+
+// aggregatorKinds makes the aggregators registered with RegisterAggregator, by kind
+var aggregatorKinds = map[string]func(arg string) (Aggregator, error){}
+
+// RegisterAggregator adds a kind of aggregator for values that this package doesn't know about.
+// AggregatorFor returns newAggregator(arg) for names of the form "kind:arg", and the aggregator's
+// Name has to be that name. It's meant to be called from init functions.
+func RegisterAggregator(kind string, newAggregator func(arg string) (Aggregator, error)) {
+	aggregatorKinds[kind] = newAggregator
+}
+
+// AggregatorFor returns one of the built in aggregators or a registered one by name
+func AggregatorFor(name string) (Aggregator, error) {
+	switch name {
+	case "uint32":
+		return UInt32Aggregator{}, nil
+	}
+	kind, arg := splitKind(name)
+	newAggregator, ok := aggregatorKinds[kind]
+	if !ok {
+		return nil, fmt.Errorf("Unknown aggregator %s", name)
+	}
+	a, err := newAggregator(arg)
+	if err != nil {
+		return nil, fmt.Errorf("Invalid aggregator %s: %w", name, err)
+	}
+	return a, nil
+}
+
+// splitKind splits a registered name of the form "kind:arg" into its kind and argument
+func splitKind(name string) (string, string) {
+	parts := strings.SplitN(name, ":", 2)
+	if len(parts) == 1 {
+		return parts[0], ""
+	}
+	return parts[0], parts[1]
+}
+
+func aggregatorName(a Aggregator) string {
+	if a == nil {
+		return ""
+	}
+	return a.Name()
+}
+
+// Summary aggregates the visible values of a range of keys
+type Summary struct {
+	// Count is the number of keys
+	Count uint64
+	// Measured is the number of values the aggregator measured, which Sum, Min and Max are over.
+	// Min and Max are 0 if it's 0.
+	Measured uint64
+	Sum      float64
+	Min      float64
+	Max      float64
+}
+
+// Merge returns the summary of the keys of both summaries, which can't overlap
+func (s Summary) Merge(with Summary) Summary {
+	merged := Summary{
+		Count:    s.Count + with.Count,
+		Measured: s.Measured + with.Measured,
+		Sum:      s.Sum + with.Sum,
+		Min:      s.Min,
+		Max:      s.Max,
+	}
+	if s.Measured == 0 || (with.Measured > 0 && with.Min < s.Min) {
+		merged.Min = with.Min
+	}
+	if s.Measured == 0 || (with.Measured > 0 && with.Max > s.Max) {
+		merged.Max = with.Max
+	}
+	return merged
+}
+
+// add returns the summary with the value of one more key. Deleted keys aren't counted.
+func (s Summary) add(v Value, a Aggregator) (Summary, error) {
+	v, err := visibleValue(v)
+	if err != nil || v == nil {
+		return s, err
+	}
+	added := Summary{Count: 1}
+	if a != nil {
+		if m, ok := a.Measure(v); ok {
+			added = Summary{Count: 1, Measured: 1, Sum: m, Min: m, Max: m}
+		}
+	}
+	return s.Merge(added), nil
+}
+
+func (s Summary) Write(w io.Writer) error {
+	for _, n := range []uint64{
+		s.Count,
+		s.Measured,
+		math.Float64bits(s.Sum),
+		math.Float64bits(s.Min),
+		math.Float64bits(s.Max),
+	} {
+		err := putUint64(n, w)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func readSummary(r io.Reader) (Summary, error) {
+	fields := make([]uint64, 5)
+	for i := range fields {
+		var err error
+		fields[i], err = getUint64(r)
+		if err != nil {
+			return Summary{}, err
+		}
+	}
+	return Summary{
+		Count:    fields[0],
+		Measured: fields[1],
+		Sum:      math.Float64frombits(fields[2]),
+		Min:      math.Float64frombits(fields[3]),
+		Max:      math.Float64frombits(fields[4]),
+	}, nil
+}
+
+// summary returns the summary of every key in the subtree n is the root of. n has to have
+// summaries.
+func (n *Node) summary(a Aggregator) (Summary, error) {
+	s := n.summaries[0]
+	for i, child := range n.children {
+		var err error
+		s, err = s.add(child.value, a)
+		if err != nil {
+			return Summary{}, err
+		}
+		s = s.Merge(n.summaries[i+1])
+	}
+	return s, nil
+}
+
+// summarize returns n with the summaries of its subtrees, whose roots are read with get and have
+// to have summaries already
+func summarize(n *Node, a Aggregator, get func([]byte) (*Node, error)) (*Node, error) {
+	summaries := make([]Summary, len(n.children)+1)
+	for i := range summaries {
+		hash := n.childAt(uint(i))
+		if hash == nil {
+			continue
+		}
+		sub, err := get(hash)
+		if err != nil {
+			return nil, err
+		}
+		if sub.summaries == nil {
+			return nil, fmt.Errorf("Node %s has no aggregates", hex.EncodeToString(hash))
+		}
+		summaries[i], err = sub.summary(a)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return n.WithSummaries(summaries), nil
+}
+
+// putNode puts n into store. If the tree has an aggregator, n's subtrees are summarized first.
+func (t *MerkleSearchTree) putNode(store NodeStore, n *Node) (NodeStore, []byte, error) {
+	if t.aggregator != nil {
+		var err error
+		n, err = summarize(n, t.aggregator, func(hash []byte) (*Node, error) {
+			return t.getNodeMaybe(hash, store)
+		})
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+	return store.Put(n)
+}
+
+// WithAggregator returns the tree with every node it writes keeping summaries of its subtrees, as
+// measured by a. Nodes that are already in the tree have to have been written with the same
+// aggregator, so it should be set while the tree is still empty.
+func (t *MerkleSearchTree) WithAggregator(a Aggregator) *MerkleSearchTree {
+	return &MerkleSearchTree{
+		root:       t.root,
+		base:       t.base,
+		hash:       t.hash,
+		store:      t.store,
+		aggregator: a,
+	}
+}
+
+// Aggregator returns the aggregator of the tree, or nil if it doesn't keep aggregates
+func (t *MerkleSearchTree) Aggregator() Aggregator {
+	return t.aggregator
+}
+
+func (t *MerkleSearchTree) aggregate(hash []byte, start Key, end Key) (Summary, error) {
+	if hash == nil {
+		return Summary{}, nil
+	}
+	n, err := t.store.Get(hash)
+	if err != nil {
+		return Summary{}, err
+	}
+	s := Summary{}
+	for i := 0; i <= len(n.children); i++ {
+		// Bounds that every key of the subtree is within are dropped, so subtrees without bounds
+		// are entirely in the range
+		subStart, subEnd := start, end
+		if i > 0 {
+			key := n.children[i-1].key
+			if end != nil && !key.Less(end) {
+				break
+			}
+			if start == nil || !key.Less(start) {
+				s, err = s.add(n.children[i-1].value, t.aggregator)
+				if err != nil {
+					return Summary{}, err
+				}
+				subStart = nil
+			}
+		}
+		if i < len(n.children) {
+			key := n.children[i].key
+			if start != nil && !start.Less(key) {
+				continue
+			}
+			if end != nil && !end.Less(key) {
+				subEnd = nil
+			}
+		}
+		if subStart == nil && subEnd == nil && t.aggregator != nil && n.summaries != nil {
+			s = s.Merge(n.summaries[i])
+			continue
+		}
+		sub, err := t.aggregate(n.childAt(uint(i)), subStart, subEnd)
+		if err != nil {
+			return Summary{}, err
+		}
+		s = s.Merge(sub)
+	}
+	return s, nil
+}
+
+// Aggregate summarizes the keys from start up to but not including end. Either can be nil to
+// leave that side unbounded. Subtrees that are entirely in the range are summarized by their
+// parent if the tree has an aggregator, so only the nodes on the paths to start and end are read.
+// Otherwise every node in the range is read and values aren't measured.
+func (t *MerkleSearchTree) Aggregate(start Key, end Key) (Summary, error) {
+	return t.aggregate(t.root, start, end)
+}
+
+// Count returns the number of keys from start up to but not including end, like Aggregate
+func (t *MerkleSearchTree) Count(start Key, end Key) (uint64, error) {
+	s, err := t.Aggregate(start, end)
+	if err != nil {
+		return 0, err
+	}
+	return s.Count, nil
+}
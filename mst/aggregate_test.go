@@ -0,0 +1,161 @@
+package mst
+
+import (
+	"crypto"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Counts the nodes read from a store
+type getCountingNodeStore struct {
+	NodeStore
+	gets *int
+}
+
+func (ns getCountingNodeStore) Get(k []byte) (*Node, error) {
+	*ns.gets++
+	return ns.NodeStore.Get(k)
+}
+
+func mustAggregate(t *testing.T, tree *MerkleSearchTree, start Key, end Key) Summary {
+	s, err := tree.Aggregate(start, end)
+	require.NoError(t, err)
+	return s
+}
+
+// expectedSummary summarizes the keys of tree from start up to end by getting each of them
+func expectedSummary(t *testing.T, tree *MerkleSearchTree, start UInt32, end UInt32) Summary {
+	s := Summary{}
+	for key := start; key < end; key++ {
+		val := mustGet(t, tree, key)
+		if val != nil {
+			var err error
+			s, err = s.add(val, UInt32Aggregator{})
+			require.NoError(t, err)
+		}
+	}
+	return s
+}
+
+func aggregateRunner(t *testing.T, base Base, iters, elems, keyMod int) {
+	rand.Seed(42)
+	for i := 0; i < iters; i++ {
+		aggregated := NewLocalMST(base, crypto.SHA256).WithAggregator(UInt32Aggregator{})
+		plain := NewLocalMST(base, crypto.SHA256)
+		b := NewBatch()
+		for j := 0; j < elems; j++ {
+			key, val := genKeyVal(keyMod)
+			if rand.Intn(4) == 0 {
+				aggregated = mustDelete(t, aggregated, key)
+				plain = mustDelete(t, plain, key)
+				b.Delete(key)
+			} else {
+				aggregated = mustPut(t, aggregated, key, val)
+				plain = mustPut(t, plain, key, val)
+				b.Put(key, val)
+			}
+		}
+		empty := NewLocalMST(base, crypto.SHA256).WithAggregator(UInt32Aggregator{})
+		batched := mustApply(t, empty, b)
+		assert.Equal(t, aggregated.RootHash(), batched.RootHash())
+		assertNoLeakedNodes(t, aggregated)
+
+		for j := 0; j < 20; j++ {
+			start := UInt32(rand.Intn(keyMod))
+			end := start + UInt32(rand.Intn(keyMod-int(start)+1))
+			expected := expectedSummary(t, plain, start, end)
+			assert.Equal(t, expected, mustAggregate(t, aggregated, start, end))
+			assert.Equal(t, Summary{Count: expected.Count}, mustAggregate(t, plain, start, end))
+		}
+		expected := expectedSummary(t, plain, 0, UInt32(keyMod))
+		assert.Equal(t, expected, mustAggregate(t, aggregated, nil, nil))
+		assert.Equal(t, expected, mustAggregate(t, aggregated, UInt32(0), nil))
+		assert.Equal(t, expected, mustAggregate(t, aggregated, nil, UInt32(keyMod)))
+		count, err := aggregated.Count(nil, nil)
+		require.NoError(t, err)
+		assert.Equal(t, expected.Count, count)
+	}
+}
+
+func TestAggregateBase2(t *testing.T) {
+	aggregateRunner(t, Base2, 10, 500, 200)
+}
+
+func TestAggregateBase32(t *testing.T) {
+	aggregateRunner(t, Base32, 10, 500, 200)
+}
+
+func TestAggregateMergeAndLoad(t *testing.T) {
+	rand.Seed(42)
+	l := NewLocalMST(Base4, crypto.SHA256).WithAggregator(UInt32Aggregator{})
+	r := NewLocalMST(Base4, crypto.SHA256).WithAggregator(UInt32Aggregator{})
+	all := NewLocalMST(Base4, crypto.SHA256)
+	for i := 0; i < 300; i++ {
+		key, val := genKeyVal(500)
+		l = mustPut(t, l, key, val)
+		all = mustPut(t, all, key, val)
+		key, val = genKeyVal(500)
+		r = mustPut(t, r, key, val)
+		all = mustPut(t, all, key, val)
+	}
+	merged := mustMerge(t, l, r)
+	assert.Equal(t, expectedSummary(t, all, 0, 500), mustAggregate(t, merged, nil, nil))
+
+	loader := NewBulkLoader(Base4, crypto.SHA256, NewLocalNodeStore(crypto.SHA256))
+	loader.SetAggregator(UInt32Aggregator{})
+	it := all.Scan(ScanOptions{})
+	for it.Next() {
+		require.NoError(t, loader.Add(it.Key(), it.Value()))
+	}
+	require.NoError(t, it.Err())
+	loaded, err := loader.Tree()
+	require.NoError(t, err)
+	assert.Equal(t, merged.RootHash(), loaded.RootHash())
+	assert.Equal(t, "uint32", loaded.Aggregator().Name())
+
+	// Summaries are part of the hash
+	assert.NotEqual(t, all.RootHash(), merged.RootHash())
+}
+
+func TestAggregateNodeReads(t *testing.T) {
+	tree := NewLocalMST(Base4, crypto.SHA256).WithAggregator(UInt32Aggregator{})
+	for i := 0; i < 5000; i++ {
+		tree = mustPut(t, tree, UInt32(i), UInt32(i))
+	}
+	root, err := tree.store.Get(tree.root)
+	require.NoError(t, err)
+
+	gets := 0
+	counting := tree.WithNodeStore(getCountingNodeStore{tree.store, &gets})
+	s := mustAggregate(t, counting, UInt32(1000), UInt32(4000))
+	assert.Equal(t, Summary{Count: 3000, Measured: 3000, Sum: 7498500, Min: 1000, Max: 3999}, s)
+	// At most the nodes on the paths to both ends are read
+	assert.LessOrEqual(t, gets, 2*int(root.level+1))
+
+	plainGets := 0
+	plain := tree.WithAggregator(nil).WithNodeStore(getCountingNodeStore{tree.store, &plainGets})
+	count, err := plain.Count(UInt32(1000), UInt32(4000))
+	require.NoError(t, err)
+	assert.Equal(t, uint64(3000), count)
+	assert.Greater(t, plainGets, 10*gets)
+}
+
+func TestAggregateMergeDiffAggregator(t *testing.T) {
+	lInd := NewLocalMST(Base4, crypto.SHA256).WithAggregator(UInt32Aggregator{})
+	rInd := NewLocalMST(Base4, crypto.SHA256)
+	_, err := lInd.Merge(rInd)
+	assert.Error(t, err)
+	assert.Equal(t, `Mismatching aggregators. "uint32" vs ""`, err.Error())
+}
+
+func TestSummaryMerge(t *testing.T) {
+	empty := Summary{Count: 2}
+	s := Summary{Count: 3, Measured: 2, Sum: 1, Min: -2, Max: 3}
+	assert.Equal(t, Summary{Count: 5, Measured: 2, Sum: 1, Min: -2, Max: 3}, empty.Merge(s))
+	assert.Equal(t, Summary{Count: 5, Measured: 2, Sum: 1, Min: -2, Max: 3}, s.Merge(empty))
+	other := Summary{Count: 1, Measured: 1, Sum: 5, Min: 5, Max: 5}
+	assert.Equal(t, Summary{Count: 4, Measured: 3, Sum: 6, Min: -2, Max: 5}, s.Merge(other))
+}
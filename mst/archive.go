@@ -0,0 +1,179 @@
+package mst
+
+import (
+	"bytes"
+	"crypto"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// Archives hold a tree in a single self-describing stream, similar to a CAR file. They start with
+// a header of the magic bytes, the format version, the root hash, the tree's base and its hash
+// function. Every node reachable from the root follows as a block of its hash and its EncodeNode
+// encoding, parents before their children.
+var archiveMagic = []byte("vulture-mst")
+
+const archiveVersion uint32 = 1
+
+// ArchiveHeader describes the tree stored in an archive
+type ArchiveHeader struct {
+	Root []byte
+	Base Base
+	Hash crypto.Hash
+}
+
+func writeArchiveHeader(header ArchiveHeader, w io.Writer) error {
+	err := putBytes(archiveMagic, w)
+	if err != nil {
+		return err
+	}
+	err = putUint32(archiveVersion, w)
+	if err != nil {
+		return err
+	}
+	err = putHash(header.Root, w)
+	if err != nil {
+		return err
+	}
+	err = putUint32(uint32(header.Base), w)
+	if err != nil {
+		return err
+	}
+	return putUint32(uint32(header.Hash), w)
+}
+
+// ReadArchiveHeader reads the header at the start of an archive
+func ReadArchiveHeader(r io.Reader) (ArchiveHeader, error) {
+	magic, err := getBytes(r)
+	if err != nil {
+		return ArchiveHeader{}, fmt.Errorf("Couldn't read archive header: %w", err)
+	}
+	if !bytes.Equal(magic, archiveMagic) {
+		return ArchiveHeader{}, fmt.Errorf("Not a tree archive")
+	}
+	version, err := getUint32(r)
+	if err != nil {
+		return ArchiveHeader{}, fmt.Errorf("Couldn't read archive header: %w", err)
+	}
+	if version != archiveVersion {
+		return ArchiveHeader{}, fmt.Errorf("Unsupported archive version %d", version)
+	}
+	root, err := getHash(r)
+	if err != nil {
+		return ArchiveHeader{}, fmt.Errorf("Couldn't read archive header: %w", err)
+	}
+	base, err := getUint32(r)
+	if err != nil {
+		return ArchiveHeader{}, fmt.Errorf("Couldn't read archive header: %w", err)
+	}
+	if base < uint32(Base2) || base > uint32(Base32) {
+		return ArchiveHeader{}, fmt.Errorf("Invalid base %d", base)
+	}
+	hash, err := getUint32(r)
+	if err != nil {
+		return ArchiveHeader{}, fmt.Errorf("Couldn't read archive header: %w", err)
+	}
+	if !crypto.Hash(hash).Available() {
+		return ArchiveHeader{}, fmt.Errorf("Unsupported hash function %d", hash)
+	}
+	return ArchiveHeader{root, Base(base), crypto.Hash(hash)}, nil
+}
+
+func (t *MerkleSearchTree) exportNode(hash []byte, w io.Writer) error {
+	if hash == nil {
+		return nil
+	}
+	n, err := t.store.Get(hash)
+	if err != nil {
+		return err
+	}
+	buf := new(bytes.Buffer)
+	err = EncodeNode(n, buf)
+	if err != nil {
+		return err
+	}
+	err = putBytes(hash, w)
+	if err != nil {
+		return err
+	}
+	err = putBytes(buf.Bytes(), w)
+	if err != nil {
+		return err
+	}
+	err = t.exportNode(n.low, w)
+	if err != nil {
+		return err
+	}
+	for _, child := range n.children {
+		err = t.exportNode(child.high, w)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Export writes the tree to w as an archive that Import can restore
+func (t *MerkleSearchTree) Export(w io.Writer) error {
+	err := writeArchiveHeader(ArchiveHeader{t.root, t.base, t.hash}, w)
+	if err != nil {
+		return err
+	}
+	return t.exportNode(t.root, w)
+}
+
+// Import restores a tree from an archive written by Export, putting its nodes into store. Every
+// node is checked against its hash and every node of the tree has to be in the archive or already
+// be in store. The returned tree uses the base and hash function of the archive.
+func Import(r io.Reader, store NodeStore, kr KeyReader, vr ValueReader) (*MerkleSearchTree, error) {
+	header, err := ReadArchiveHeader(r)
+	if err != nil {
+		return nil, err
+	}
+	for {
+		expected, err := getBytes(r)
+		if errors.Is(err, io.EOF) {
+			break
+		} else if err != nil {
+			return nil, fmt.Errorf("Couldn't read block: %w", err)
+		}
+		raw, err := getBytes(r)
+		if err != nil {
+			return nil, fmt.Errorf("Couldn't read block: %w", err)
+		}
+		blockReader := bytes.NewReader(raw)
+		n, err := DecodeNode(blockReader, kr, vr)
+		if err == nil && blockReader.Len() > 0 {
+			err = fmt.Errorf("%d trailing bytes", blockReader.Len())
+		}
+		if err != nil {
+			return nil, fmt.Errorf("Couldn't decode node %s: %w", hex.EncodeToString(expected), err)
+		}
+		wn := HashableNode(*n)
+		if actual := HashWritable(&wn, header.Hash); !bytes.Equal(actual, expected) {
+			return nil, fmt.Errorf(
+				"Node %s is corrupt, its contents hash to %s",
+				hex.EncodeToString(expected),
+				hex.EncodeToString(actual),
+			)
+		}
+		var stored []byte
+		store, stored, err = store.Put(n)
+		if err != nil {
+			return nil, err
+		}
+		if !bytes.Equal(stored, expected) {
+			return nil, fmt.Errorf("Node store doesn't use the archive's hash function")
+		}
+	}
+	missing, err := FindMissingNodes(store, header.Root)
+	if err != nil {
+		return nil, err
+	}
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("Archive is missing %d nodes", len(missing))
+	}
+	return NewMSTWithRoot(header.Root, header.Base, header.Hash, store), nil
+}
@@ -0,0 +1,89 @@
+package mst
+
+import (
+	"bytes"
+	"crypto"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func exportTree(t *testing.T, tree *MerkleSearchTree) []byte {
+	buf := new(bytes.Buffer)
+	require.NoError(t, tree.Export(buf))
+	return buf.Bytes()
+}
+
+func importTree(archive []byte, store NodeStore) (*MerkleSearchTree, error) {
+	return Import(bytes.NewReader(archive), store, UInt32KeyReader{}, UInt32ValueReader{})
+}
+
+func TestArchiveRoundTrip(t *testing.T) {
+	rand.Seed(42)
+	tree := NewLocalMST(Base4, crypto.SHA256)
+	for i := 0; i < 500; i++ {
+		key, val := genKeyVal(1000)
+		tree = mustPut(t, tree, key, val)
+		if rand.Intn(5) == 0 {
+			tree = mustDelete(t, tree, key)
+		}
+	}
+	restored, err := importTree(exportTree(t, tree), NewLocalNodeStore(crypto.SHA256))
+	require.NoError(t, err)
+	assert.Equal(t, tree.RootHash(), restored.RootHash())
+	assert.Equal(t, Base4, restored.Base())
+	assert.Equal(t, crypto.SHA256, restored.Hash())
+	for key := 0; key < 1000; key++ {
+		assert.Equal(t, mustGet(t, tree, UInt32(key)), mustGet(t, restored, UInt32(key)))
+	}
+	assertNoLeakedNodes(t, restored)
+
+	empty := NewLocalMST(Base16, crypto.SHA512)
+	restored, err = importTree(exportTree(t, empty), NewLocalNodeStore(crypto.SHA512))
+	require.NoError(t, err)
+	assert.Nil(t, restored.RootHash())
+	assert.Equal(t, Base16, restored.Base())
+	assert.Equal(t, crypto.SHA512, restored.Hash())
+}
+
+func TestArchiveHeader(t *testing.T) {
+	tree := NewLocalMST(Base8, crypto.SHA256)
+	tree = mustPut(t, tree, UInt32(1), UInt32(2))
+	header, err := ReadArchiveHeader(bytes.NewReader(exportTree(t, tree)))
+	require.NoError(t, err)
+	assert.Equal(t, ArchiveHeader{tree.RootHash(), Base8, crypto.SHA256}, header)
+
+	_, err = ReadArchiveHeader(bytes.NewReader([]byte("not an archive")))
+	assert.Error(t, err)
+}
+
+func TestArchiveVerifiesNodes(t *testing.T) {
+	tree := NewLocalMST(Base2, crypto.SHA256)
+	for i := 0; i < 100; i++ {
+		tree = mustPut(t, tree, UInt32(i), UInt32(i))
+	}
+	archive := exportTree(t, tree)
+
+	// Changing any byte of a block either breaks its encoding or its hash
+	header := new(bytes.Buffer)
+	err := writeArchiveHeader(ArchiveHeader{tree.RootHash(), Base2, crypto.SHA256}, header)
+	require.NoError(t, err)
+	for i := header.Len(); i < len(archive); i++ {
+		corrupt := append([]byte{}, archive...)
+		corrupt[i]++
+		_, err := importTree(corrupt, NewLocalNodeStore(crypto.SHA256))
+		assert.Error(t, err, "byte %d", i)
+	}
+
+	// Cutting the archive short either cuts a block or leaves out nodes
+	for _, length := range []int{len(archive) / 2, len(archive) - 1} {
+		_, err = importTree(archive[:length], NewLocalNodeStore(crypto.SHA256))
+		assert.Error(t, err)
+	}
+
+	// The store has to hash nodes the same way
+	_, err = importTree(archive, NewLocalNodeStore(crypto.SHA512))
+	assert.Error(t, err)
+}
@@ -0,0 +1,137 @@
+package mst
+
+import (
+	"crypto"
+	"encoding/hex"
+	"fmt"
+	"sort"
+)
+
+type batchWrite struct {
+	key Key
+	// val is nil for deletes
+	val Value
+}
+
+// Batch is a list of puts and deletes to apply to a tree at once with Apply
+type Batch struct {
+	writes []batchWrite
+}
+
+func NewBatch() *Batch {
+	return &Batch{}
+}
+
+// Put adds a put of val at key to the batch
+func (b *Batch) Put(key Key, val Value) {
+	b.writes = append(b.writes, batchWrite{key, val})
+}
+
+// Delete adds a delete of key to the batch
+func (b *Batch) Delete(key Key) {
+	b.writes = append(b.writes, batchWrite{key, nil})
+}
+
+// Len returns the number of writes in the batch
+func (b *Batch) Len() int {
+	return len(b.writes)
+}
+
+// batchStore buffers the changes a batch makes to a store. Nodes that one write of the batch adds
+// and a later one replaces never reach the underlying store, so applying the batch to it only adds
+// the nodes of the final tree and removes the nodes that tree no longer uses. Unlike other stores,
+// it's modified in place and returns itself, since only the latest version is ever used.
+type batchStore struct {
+	base    NodeStore
+	hash    crypto.Hash
+	added   map[string]*Node
+	removed map[string]bool
+}
+
+func newBatchStore(base NodeStore, hash crypto.Hash) *batchStore {
+	return &batchStore{base, hash, map[string]*Node{}, map[string]bool{}}
+}
+
+func (s *batchStore) Get(k []byte) (*Node, error) {
+	if n, ok := s.added[string(k)]; ok {
+		return n, nil
+	} else if s.removed[string(k)] {
+		return nil, fmt.Errorf("%w: %s", ErrNodeNotFound, hex.EncodeToString(k))
+	}
+	return s.base.Get(k)
+}
+
+func (s *batchStore) Put(n *Node) (NodeStore, []byte, error) {
+	wn := HashableNode(*n)
+	k := HashWritable(&wn, s.hash)
+	s.added[string(k)] = n
+	delete(s.removed, string(k))
+	return s, k, nil
+}
+
+func (s *batchStore) Remove(k []byte) (NodeStore, error) {
+	delete(s.added, string(k))
+	s.removed[string(k)] = true
+	return s, nil
+}
+
+func (s *batchStore) Size() (uint, error) {
+	return 0, fmt.Errorf("Size isn't known while a batch is being applied")
+}
+
+func sortedHashes(hashes []string) []string {
+	sort.Strings(hashes)
+	return hashes
+}
+
+// commit applies the buffered changes to the underlying store in a deterministic order
+func (s *batchStore) commit() (NodeStore, error) {
+	store := s.base
+	var err error
+	removed := make([]string, 0, len(s.removed))
+	for k := range s.removed {
+		removed = append(removed, k)
+	}
+	for _, k := range sortedHashes(removed) {
+		store, err = store.Remove([]byte(k))
+		if err != nil {
+			return nil, err
+		}
+	}
+	added := make([]string, 0, len(s.added))
+	for k := range s.added {
+		added = append(added, k)
+	}
+	for _, k := range sortedHashes(added) {
+		store, _, err = store.Put(s.added[k])
+		if err != nil {
+			return nil, err
+		}
+	}
+	return store, nil
+}
+
+// Apply applies the writes of a batch in order and returns the resulting tree. Writes behave the
+// same as calling Put and Delete one after the other, but the tree's store only sees the nodes of
+// the resulting tree, so a batch costs one set of store changes no matter how many of its writes
+// touch the same nodes. If a write fails, the store is left unchanged.
+func (t *MerkleSearchTree) Apply(b *Batch) (*MerkleSearchTree, error) {
+	batchStore := newBatchStore(t.store, t.hash)
+	tree := t.withStoreAndRoot(batchStore, t.root)
+	var err error
+	for _, write := range b.writes {
+		if write.val == nil {
+			tree, err = tree.Delete(write.key)
+		} else {
+			tree, err = tree.Put(write.key, write.val)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	store, err := batchStore.commit()
+	if err != nil {
+		return nil, fmt.Errorf("Couldn't commit batch: %w", err)
+	}
+	return t.withStoreAndRoot(store, tree.root), nil
+}
@@ -0,0 +1,103 @@
+package mst
+
+import (
+	"crypto"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Counts the nodes put into a store
+type putCountingNodeStore struct {
+	NodeStore
+	puts *int
+}
+
+func (ns putCountingNodeStore) Put(n *Node) (NodeStore, []byte, error) {
+	*ns.puts++
+	store, k, err := ns.NodeStore.Put(n)
+	return putCountingNodeStore{store, ns.puts}, k, err
+}
+
+func (ns putCountingNodeStore) Remove(k []byte) (NodeStore, error) {
+	store, err := ns.NodeStore.Remove(k)
+	return putCountingNodeStore{store, ns.puts}, err
+}
+
+func mustApply(t *testing.T, tree *MerkleSearchTree, b *Batch) *MerkleSearchTree {
+	newTree, err := tree.Apply(b)
+	require.NoError(t, err)
+	return newTree
+}
+
+func batchRunner(t *testing.T, base Base, iters, elems, keyMod int) {
+	rand.Seed(42)
+	for i := 0; i < iters; i++ {
+		tree := NewLocalMST(base, crypto.SHA256)
+		for j := 0; j < elems/2; j++ {
+			key, val := genKeyVal(keyMod)
+			tree = mustPut(t, tree, key, val)
+		}
+		sequential := tree
+		b := NewBatch()
+		for j := 0; j < elems; j++ {
+			key, val := genKeyVal(keyMod)
+			if rand.Intn(4) == 0 {
+				b.Delete(key)
+				sequential = mustDelete(t, sequential, key)
+			} else {
+				b.Put(key, val)
+				sequential = mustPut(t, sequential, key, val)
+			}
+		}
+		assert.Equal(t, elems, b.Len())
+		batched := mustApply(t, tree, b)
+		assert.Equal(t, sequential.RootHash(), batched.RootHash())
+		for key := 0; key < keyMod; key++ {
+			assert.Equal(t, mustGet(t, sequential, UInt32(key)), mustGet(t, batched, UInt32(key)))
+		}
+		assertNoLeakedNodes(t, batched)
+	}
+}
+
+func TestMSTBatchBase2(t *testing.T) {
+	batchRunner(t, Base2, 20, 200, 100)
+}
+
+func TestMSTBatchBase32(t *testing.T) {
+	batchRunner(t, Base32, 20, 200, 100)
+}
+
+func TestMSTBatchOrder(t *testing.T) {
+	tree := NewLocalMST(Base4, crypto.SHA256)
+	b := NewBatch()
+	b.Put(UInt32(1), UInt32(5))
+	b.Delete(UInt32(1))
+	b.Put(UInt32(2), UInt32(5))
+	b.Delete(UInt32(3))
+	tree = mustApply(t, tree, b)
+	assert.Nil(t, mustGet(t, tree, UInt32(1)))
+	assert.Equal(t, UInt32(5), mustGet(t, tree, UInt32(2)))
+	assert.Nil(t, mustGet(t, tree, UInt32(3)))
+	assertNoLeakedNodes(t, tree)
+
+	empty := NewLocalMST(Base4, crypto.SHA256)
+	assert.Equal(t, empty.RootHash(), mustApply(t, empty, NewBatch()).RootHash())
+}
+
+func TestMSTBatchOnlyStoresFinalNodes(t *testing.T) {
+	puts := 0
+	tree := NewLocalMST(Base2, crypto.SHA256)
+	tree = tree.WithNodeStore(putCountingNodeStore{tree.NodeStore(), &puts})
+	b := NewBatch()
+	for key := 0; key < 100; key++ {
+		b.Put(UInt32(key), UInt32(key))
+	}
+	tree = mustApply(t, tree, b)
+	numNodes, err := tree.NumNodes()
+	require.NoError(t, err)
+	assert.Equal(t, int(numNodes), puts)
+	assertNoLeakedNodes(t, tree)
+}
@@ -0,0 +1,126 @@
+package mst
+
+import (
+	"bytes"
+	"crypto"
+	"fmt"
+	"io"
+	"sort"
+)
+
+// A catalog commits to every keyspace of a database under a single root. It's a tree from
+// keyspace names to CatalogEntry values, so two databases hold the same data exactly if their
+// catalogs have the same root, and the keyspaces that differ are the entries that differ. Catalogs
+// only depend on the roots and schemas of the keyspaces, so they can be rebuilt instead of stored,
+// and putting the changed entry of a keyspace gives the same root as rebuilding the catalog.
+
+// CatalogEntry is what a catalog holds for a keyspace: the root of its tree, nil if it's empty,
+// and the ID of its schema. That's the hash of the keyspace's spec, see CatalogEntryFor, unless
+// the keyspace holds something that has a schema of its own, like the rows of a table.
+type CatalogEntry struct {
+	Root   []byte
+	Schema []byte
+}
+
+func (e CatalogEntry) Write(w io.Writer) error {
+	err := putHash(e.Root, w)
+	if err != nil {
+		return err
+	}
+	return putHash(e.Schema, w)
+}
+
+// Merge returns with, so that putting the entry of a keyspace replaces the one in the catalog.
+// Catalogs are compared with each other but never merged.
+func (e CatalogEntry) Merge(with Value) Value {
+	return with.(CatalogEntry)
+}
+
+type CatalogEntryReader struct{}
+
+func (vr CatalogEntryReader) FromBytes(b []byte) (Value, error) {
+	r := bytes.NewReader(b)
+	root, err := getHash(r)
+	if err != nil {
+		return nil, fmt.Errorf("Couldn't read catalog entry: %w", err)
+	}
+	schema, err := getHash(r)
+	if err != nil {
+		return nil, fmt.Errorf("Couldn't read catalog entry: %w", err)
+	}
+	if r.Len() > 0 {
+		return nil, fmt.Errorf("Catalog entry has %d trailing bytes", r.Len())
+	}
+	return CatalogEntry{root, schema}, nil
+}
+
+// Write writes everything that describes the keyspace
+func (k Keyspace) Write(w io.Writer) error {
+	err := putBytes([]byte(k.Name), w)
+	if err != nil {
+		return err
+	}
+	err = putUint32(uint32(k.Base), w)
+	if err != nil {
+		return err
+	}
+	err = putUint32(uint32(k.Hash), w)
+	if err != nil {
+		return err
+	}
+	for _, s := range []string{k.KeyType, k.ValueType, k.Aggregator} {
+		err = putBytes([]byte(s), w)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// SchemaHash identifies the description of the keyspace, so that keyspaces of the same name on
+// different servers can be checked to hold the same kind of tree
+func (k Keyspace) SchemaHash(h crypto.Hash) []byte {
+	return HashWritable(k, h)
+}
+
+// CatalogEntryFor returns the entry of a keyspace whose tree is at root, in a catalog that uses
+// the hash function h
+func CatalogEntryFor(ks Keyspace, root []byte, h crypto.Hash) CatalogEntry {
+	return CatalogEntry{Root: root, Schema: ks.SchemaHash(h)}
+}
+
+// BuildCatalog builds the catalog of a database from the entries of its keyspaces by name, kept in
+// memory. Entries of dropped keyspaces have to be removed by building the catalog again, since
+// deleting them would leave tombstones that other catalogs don't have.
+func BuildCatalog(
+	base Base,
+	hash crypto.Hash,
+	entries map[string]CatalogEntry,
+) (*MerkleSearchTree, error) {
+	names := make([]string, 0, len(entries))
+	for name := range entries {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	loader := NewBulkLoader(base, hash, NewLocalNodeStore(hash))
+	for _, name := range names {
+		err := loader.Add(String(name), entries[name])
+		if err != nil {
+			return nil, err
+		}
+	}
+	return loader.Tree()
+}
+
+// CatalogEntries returns every entry of a catalog by keyspace name
+func CatalogEntries(catalog *MerkleSearchTree) (map[string]CatalogEntry, error) {
+	entries := make(map[string]CatalogEntry)
+	it := catalog.Scan(ScanOptions{})
+	for it.Next() {
+		entries[string(it.Key().(String))] = it.Value().(CatalogEntry)
+	}
+	if err := it.Err(); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
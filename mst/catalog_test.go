@@ -0,0 +1,96 @@
+package mst
+
+import (
+	"bytes"
+	"crypto"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func mustBuildCatalog(t *testing.T, entries map[string]CatalogEntry) *MerkleSearchTree {
+	catalog, err := BuildCatalog(Base4, crypto.SHA256, entries)
+	require.NoError(t, err)
+	return catalog
+}
+
+func testCatalogEntries(n int) map[string]CatalogEntry {
+	entries := make(map[string]CatalogEntry, n)
+	for i := 0; i < n; i++ {
+		ks := testKeyspace()
+		ks.Name = fmt.Sprintf("keyspace%d", i)
+		root := HashWritable(UInt32(i), crypto.SHA256)
+		entries[ks.Name] = CatalogEntryFor(ks, root, crypto.SHA256)
+	}
+	return entries
+}
+
+func TestCatalogEntryReader(t *testing.T) {
+	for _, entry := range []CatalogEntry{
+		CatalogEntryFor(testKeyspace(), []byte{1, 2, 3}, crypto.SHA256),
+		CatalogEntryFor(testKeyspace(), nil, crypto.SHA256),
+	} {
+		buf := new(bytes.Buffer)
+		require.NoError(t, entry.Write(buf))
+		read, err := CatalogEntryReader{}.FromBytes(buf.Bytes())
+		require.NoError(t, err)
+		assert.Equal(t, entry, read)
+	}
+	_, err := CatalogEntryReader{}.FromBytes([]byte{0, 0, 0, 0, 0, 0, 0, 0, 1})
+	assert.Error(t, err)
+	_, err = CatalogEntryReader{}.FromBytes([]byte{1, 0, 0, 0})
+	assert.Error(t, err)
+}
+
+func TestKeyspaceSchemaHash(t *testing.T) {
+	ks := testKeyspace()
+	assert.Equal(t, ks.SchemaHash(crypto.SHA256), testKeyspace().SchemaHash(crypto.SHA256))
+	for _, modify := range []func(*Keyspace){
+		func(ks *Keyspace) { ks.Name = "other" },
+		func(ks *Keyspace) { ks.Base = Base16 },
+		func(ks *Keyspace) { ks.Hash = crypto.SHA512 },
+		func(ks *Keyspace) { ks.KeyType = "string" },
+		func(ks *Keyspace) { ks.ValueType = "lww" },
+		func(ks *Keyspace) { ks.Aggregator = "" },
+	} {
+		other := testKeyspace()
+		modify(&other)
+		assert.NotEqual(t, ks.SchemaHash(crypto.SHA256), other.SchemaHash(crypto.SHA256))
+	}
+}
+
+func TestCatalog(t *testing.T) {
+	entries := testCatalogEntries(100)
+	catalog := mustBuildCatalog(t, entries)
+	read, err := CatalogEntries(catalog)
+	require.NoError(t, err)
+	assert.Equal(t, entries, read)
+
+	// The root only depends on the entries
+	assert.Equal(t, catalog.RootHash(), mustBuildCatalog(t, testCatalogEntries(100)).RootHash())
+	assert.Nil(t, mustBuildCatalog(t, map[string]CatalogEntry{}).RootHash())
+
+	// Keyspaces that differ are the changes between both catalogs
+	changed := testCatalogEntries(100)
+	changed["keyspace7"] = CatalogEntryFor(testKeyspace(), []byte{7}, crypto.SHA256)
+	delete(changed, "keyspace42")
+	other := mustBuildCatalog(t, changed)
+	assert.NotEqual(t, catalog.RootHash(), other.RootHash())
+	changes, err := catalog.Diff(other)
+	require.NoError(t, err)
+	require.Len(t, changes, 2)
+	assert.Equal(t, String("keyspace42"), changes[0].Key)
+	assert.Equal(t, String("keyspace7"), changes[1].Key)
+
+	// Putting the entries that changed replaces them in place
+	updated := catalog
+	for _, name := range []string{"keyspace7", "keyspace100"} {
+		changed[name] = CatalogEntryFor(testKeyspace(), []byte(name), crypto.SHA256)
+		updated, err = updated.Put(String(name), changed[name])
+		require.NoError(t, err)
+	}
+	changed["keyspace42"] = entries["keyspace42"]
+	assert.Equal(t, mustBuildCatalog(t, changed).RootHash(), updated.RootHash())
+}
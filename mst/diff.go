@@ -0,0 +1,202 @@
+package mst
+
+import (
+	"bytes"
+	"fmt"
+)
+
+type ChangeType int
+
+const (
+	Added ChangeType = iota
+	Removed
+	Changed
+)
+
+func (c ChangeType) String() string {
+	switch c {
+	case Added:
+		return "added"
+	case Removed:
+		return "removed"
+	case Changed:
+		return "changed"
+	default:
+		return fmt.Sprintf("ChangeType(%d)", int(c))
+	}
+}
+
+// Change describes how a key differs between two trees. From is nil for added keys and To is nil
+// for removed keys.
+type Change struct {
+	Type ChangeType
+	Key  Key
+	From Value
+	To   Value
+}
+
+// diffItem is either a subtree that hasn't been expanded yet or a single child
+type diffItem struct {
+	hash  []byte
+	node  *Node
+	child *Child
+}
+
+// diffCursor walks a tree in order, only expanding subtrees when asked to. The next item is at the
+// end of items.
+type diffCursor struct {
+	tree  *MerkleSearchTree
+	items []diffItem
+}
+
+func newDiffCursor(tree *MerkleSearchTree) *diffCursor {
+	c := &diffCursor{tree: tree}
+	c.pushSubtree(tree.root)
+	return c
+}
+
+func (c *diffCursor) pushSubtree(hash []byte) {
+	if hash != nil {
+		c.items = append(c.items, diffItem{hash: hash})
+	}
+}
+
+func (c *diffCursor) peek() (*diffItem, bool) {
+	if len(c.items) == 0 {
+		return nil, false
+	}
+	return &c.items[len(c.items)-1], true
+}
+
+func (c *diffCursor) pop() {
+	c.items = c.items[:len(c.items)-1]
+}
+
+func (c *diffCursor) load(item *diffItem) (*Node, error) {
+	if item.node == nil {
+		n, err := c.tree.store.Get(item.hash)
+		if err != nil {
+			return nil, err
+		}
+		item.node = n
+	}
+	return item.node, nil
+}
+
+// expand replaces the subtree at the top of the cursor with its children and their subtrees
+func (c *diffCursor) expand() error {
+	item, _ := c.peek()
+	n, err := c.load(item)
+	if err != nil {
+		return err
+	}
+	c.pop()
+	for i := len(n.children) - 1; i >= 0; i-- {
+		c.pushSubtree(n.children[i].high)
+		c.items = append(c.items, diffItem{child: &n.children[i]})
+	}
+	c.pushSubtree(n.low)
+	return nil
+}
+
+// changeOf returns the change from one stored value of key to another, or nil if what a reader
+// sees is the same for both
+func changeOf(key Key, fromStored Value, toStored Value) (*Change, error) {
+	from, err := visibleValue(fromStored)
+	if err != nil {
+		return nil, err
+	}
+	to, err := visibleValue(toStored)
+	if err != nil {
+		return nil, err
+	}
+	if from == nil && to != nil {
+		return &Change{Added, key, nil, to}, nil
+	} else if from != nil && to == nil {
+		return &Change{Removed, key, from, nil}, nil
+	} else if from == nil || to == nil {
+		return nil, nil
+	}
+	equal, err := valuesEqual(from, to)
+	if err != nil || equal {
+		return nil, err
+	}
+	return &Change{Changed, key, from, to}, nil
+}
+
+// Diff returns the changes that turn t into other in key order. Subtrees with the same hash in
+// both trees are skipped without being read, so diffing similar trees only reads the nodes that
+// differ. Deleted keys are treated as if they don't exist.
+func (t *MerkleSearchTree) Diff(other *MerkleSearchTree) ([]Change, error) {
+	if t.base != other.base {
+		return nil, fmt.Errorf("Mismatching bases. 2^%d vs 2^%d", t.base, other.base)
+	} else if t.hash != other.hash {
+		return nil, fmt.Errorf("Mismatching hash functions. %s vs %s", t.hash, other.hash)
+	}
+
+	changes := []Change{}
+	l, r := newDiffCursor(t), newDiffCursor(other)
+	for {
+		lItem, hasL := l.peek()
+		rItem, hasR := r.peek()
+		if !hasL && !hasR {
+			return changes, nil
+		}
+
+		// Expand subtrees until both sides are at a child, skipping identical subtrees
+		var err error
+		if hasL && hasR && lItem.child == nil && rItem.child == nil {
+			if bytes.Equal(lItem.hash, rItem.hash) {
+				l.pop()
+				r.pop()
+				continue
+			}
+			// Only expand the higher of the two subtrees since the lower one might be the same as
+			// one of its descendants.
+			var lNode, rNode *Node
+			lNode, err = l.load(lItem)
+			if err != nil {
+				return nil, err
+			}
+			rNode, err = r.load(rItem)
+			if err != nil {
+				return nil, err
+			}
+			if lNode.level >= rNode.level {
+				err = l.expand()
+			}
+			if err == nil && rNode.level >= lNode.level {
+				err = r.expand()
+			}
+		} else if hasL && lItem.child == nil {
+			err = l.expand()
+		} else if hasR && rItem.child == nil {
+			err = r.expand()
+		} else if !hasR || (hasL && lItem.child.key.Less(rItem.child.key)) {
+			var val Value
+			val, err = visibleValue(lItem.child.value)
+			if val != nil {
+				changes = append(changes, Change{Removed, lItem.child.key, val, nil})
+			}
+			l.pop()
+		} else if !hasL || rItem.child.key.Less(lItem.child.key) {
+			var val Value
+			val, err = visibleValue(rItem.child.value)
+			if val != nil {
+				changes = append(changes, Change{Added, rItem.child.key, nil, val})
+			}
+			r.pop()
+		} else {
+			var change *Change
+			change, err = changeOf(lItem.child.key, lItem.child.value, rItem.child.value)
+			if change != nil {
+				changes = append(changes, *change)
+			}
+			l.pop()
+			r.pop()
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+}
@@ -0,0 +1,124 @@
+package mst
+
+import (
+	"crypto"
+	"math/rand"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func mustDiff(t *testing.T, tree *MerkleSearchTree, other *MerkleSearchTree) []Change {
+	changes, err := tree.Diff(other)
+	require.NoError(t, err)
+	return changes
+}
+
+func expectedDiff(from map[UInt32]Value, to map[UInt32]Value) []Change {
+	changes := []Change{}
+	for key, fromVal := range from {
+		if toVal, exists := to[key]; !exists {
+			changes = append(changes, Change{Removed, key, fromVal, nil})
+		} else if fromVal != toVal {
+			changes = append(changes, Change{Changed, key, fromVal, toVal})
+		}
+	}
+	for key, toVal := range to {
+		if _, exists := from[key]; !exists {
+			changes = append(changes, Change{Added, key, nil, toVal})
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].Key.Less(changes[j].Key)
+	})
+	return changes
+}
+
+func diffRunner(t *testing.T, base Base, iters, elems, keyMod int) {
+	rand.Seed(42)
+	for i := 0; i < iters; i++ {
+		lInd := NewLocalMST(base, crypto.SHA256)
+		lCollected := map[UInt32]Value{}
+		for j := 0; j < elems; j++ {
+			key, val := genKeyVal(keyMod)
+			lInd = mustPut(t, lInd, key, val)
+			lCollected[key] = mustGet(t, lInd, key)
+		}
+
+		// Make some changes on top of the left tree so that most subtrees are shared
+		rInd := lInd
+		rCollected := map[UInt32]Value{}
+		for key, val := range lCollected {
+			rCollected[key] = val
+		}
+		for j := 0; j < elems/10; j++ {
+			key, val := genKeyVal(keyMod * 2)
+			if rand.Intn(3) == 0 {
+				rInd = mustDelete(t, rInd, key)
+				delete(rCollected, key)
+			} else {
+				rInd = mustPut(t, rInd, key, val)
+				// Writes that are older than a previous delete don't bring the key back
+				if val := mustGet(t, rInd, key); val != nil {
+					rCollected[key] = val
+				}
+			}
+		}
+
+		assert.Equal(t, expectedDiff(lCollected, rCollected), mustDiff(t, lInd, rInd))
+		assert.Equal(t, expectedDiff(rCollected, lCollected), mustDiff(t, rInd, lInd))
+		assert.Equal(t, []Change{}, mustDiff(t, lInd, lInd))
+	}
+}
+
+func TestMSTDiffBase2(t *testing.T) {
+	diffRunner(t, Base2, 20, 500, 200)
+}
+
+func TestMSTDiffBase16(t *testing.T) {
+	diffRunner(t, Base16, 20, 500, 200)
+}
+
+func TestMSTDiffEmpty(t *testing.T) {
+	empty := NewLocalMST(Base16, crypto.SHA256)
+	index := mustPut(t, empty, UInt32(1), UInt32(2))
+	assert.Equal(t, []Change{{Added, UInt32(1), nil, UInt32(2)}}, mustDiff(t, empty, index))
+	assert.Equal(t, []Change{{Removed, UInt32(1), UInt32(2), nil}}, mustDiff(t, index, empty))
+	assert.Equal(t, []Change{}, mustDiff(t, empty, empty))
+}
+
+func TestMSTDiffSkipsIdenticalSubtrees(t *testing.T) {
+	lInd := NewLocalMST(Base2, crypto.SHA256)
+	for i := 0; i < 1000; i++ {
+		lInd = mustPut(t, lInd, UInt32(i), UInt32(i))
+	}
+	rInd := mustPut(t, lInd, UInt32(500), UInt32(1000))
+	numNodes, err := lInd.NumNodes()
+	require.NoError(t, err)
+
+	gets := 0
+	lInd = lInd.WithNodeStore(countingNodeStore{lInd.store, &gets})
+	rInd = rInd.WithNodeStore(countingNodeStore{rInd.store, &gets})
+	changes := mustDiff(t, lInd, rInd)
+	assert.Equal(t, []Change{{Changed, UInt32(500), UInt32(500), UInt32(1000)}}, changes)
+	assert.Less(t, uint(gets), numNodes/10)
+}
+
+func TestMSTDiffStoreError(t *testing.T) {
+	lInd := NewLocalMST(Base16, crypto.SHA256)
+	lInd = mustPut(t, lInd, UInt32(1), UInt32(1))
+	rInd := mustPut(t, lInd, UInt32(2), UInt32(2))
+	rInd = rInd.WithNodeStore(&failingNodeStore{rInd.store, false, true})
+	_, err := lInd.Diff(rInd)
+	assert.Error(t, err)
+}
+
+func TestMSTDiffDiffBase(t *testing.T) {
+	lInd := NewLocalMST(Base2, crypto.SHA256)
+	rInd := NewLocalMST(Base32, crypto.SHA256)
+	_, err := lInd.Diff(rInd)
+	assert.Error(t, err)
+	assert.Equal(t, "Mismatching bases. 2^1 vs 2^5", err.Error())
+}
@@ -0,0 +1,126 @@
+package mst
+
+import (
+	"errors"
+	"fmt"
+)
+
+// CollectableNodeStore is a NodeStore that can list and permanently delete the nodes it holds,
+// which garbage collection needs.
+type CollectableNodeStore interface {
+	NodeStore
+	// Hashes returns the hashes of every node held by the store, including nodes that are only
+	// still there because another version of the store might need them.
+	Hashes() ([][]byte, error)
+	// Collect permanently deletes nodes and returns a version of the store without them. Unlike
+	// Remove, the nodes are gone from every version of the store that shares storage with this one.
+	Collect(hashes [][]byte) (NodeStore, error)
+}
+
+func markReachable(store NodeStore, hash []byte, marked map[string]struct{}) error {
+	if hash == nil {
+		return nil
+	}
+	if _, ok := marked[string(hash)]; ok {
+		return nil
+	}
+	n, err := store.Get(hash)
+	if errors.Is(err, ErrNodeNotFound) {
+		// Trees that are still being synced are allowed to be missing nodes
+		return nil
+	} else if err != nil {
+		return err
+	}
+	marked[string(hash)] = struct{}{}
+	err = markReachable(store, n.low, marked)
+	if err != nil {
+		return err
+	}
+	for _, child := range n.children {
+		err = markReachable(store, child.high, marked)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GarbageCollector collects the garbage of a store in phases, so that the nodes reachable from
+// live trees can be marked without blocking writes. It only ever deletes nodes the store held when
+// the collector was created, and only if they're still unreachable when swept.
+type GarbageCollector struct {
+	hashes [][]byte
+	marked map[string]struct{}
+}
+
+// NewGarbageCollector lists the nodes of store that might be garbage. Nothing should write to the
+// storage behind store while it runs.
+func NewGarbageCollector(store NodeStore) (*GarbageCollector, error) {
+	collectable, ok := store.(CollectableNodeStore)
+	if !ok {
+		return nil, fmt.Errorf("Node store %T can't be garbage collected", store)
+	}
+	hashes, err := collectable.Hashes()
+	if err != nil {
+		return nil, err
+	}
+	return &GarbageCollector{hashes, make(map[string]struct{})}, nil
+}
+
+// Mark marks every node reachable from the root of one of the live trees. Each live tree is
+// walked with its own NodeStore, so trees using other versions of the store can be included.
+// Nodes marked by an earlier call aren't walked again, so marking the current trees right before
+// sweeping only reads the nodes written since the last call.
+func (c *GarbageCollector) Mark(live []*MerkleSearchTree) error {
+	for _, tree := range live {
+		err := markReachable(tree.store, tree.root, c.marked)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Sweep deletes every node listed when the collector was created that hasn't been marked from
+// store, which has to be a version of the collector's store, and returns the store without them
+// along with the number of nodes deleted. Every tree that might still be read has to have been
+// marked, and nothing should write to the storage behind store while it runs.
+func (c *GarbageCollector) Sweep(store NodeStore) (NodeStore, uint, error) {
+	collectable, ok := store.(CollectableNodeStore)
+	if !ok {
+		return nil, 0, fmt.Errorf("Node store %T can't be garbage collected", store)
+	}
+	garbage := [][]byte{}
+	for _, hash := range c.hashes {
+		if _, ok := c.marked[string(hash)]; !ok {
+			garbage = append(garbage, hash)
+		}
+	}
+	if len(garbage) == 0 {
+		return store, 0, nil
+	}
+	store, err := collectable.Collect(garbage)
+	if err != nil {
+		return nil, 0, err
+	}
+	return store, uint(len(garbage)), nil
+}
+
+// CollectGarbage deletes every node in store that isn't reachable from the root of one of the live
+// trees, and returns the store without them along with the number of nodes deleted. Each live
+// tree is walked with its own NodeStore, so trees using other versions of store can be included.
+//
+// Nothing else should write to the storage behind store while it runs, otherwise a node that was
+// just written but isn't reachable from a live root yet could be deleted. Use a GarbageCollector
+// to only block writes while listing and sweeping.
+func CollectGarbage(store NodeStore, live []*MerkleSearchTree) (NodeStore, uint, error) {
+	c, err := NewGarbageCollector(store)
+	if err != nil {
+		return nil, 0, err
+	}
+	err = c.Mark(live)
+	if err != nil {
+		return nil, 0, err
+	}
+	return c.Sweep(store)
+}
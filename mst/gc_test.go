@@ -0,0 +1,111 @@
+package mst
+
+import (
+	"crypto"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func mustCollectGarbage(
+	t *testing.T,
+	store NodeStore,
+	live ...*MerkleSearchTree,
+) (NodeStore, uint) {
+	store, collected, err := CollectGarbage(store, live)
+	require.NoError(t, err)
+	return store, collected
+}
+
+func TestCollectGarbage(t *testing.T) {
+	first := NewLocalMST(Base4, crypto.SHA256)
+	for i := 0; i < 100; i++ {
+		first = mustPut(t, first, UInt32(i), UInt32(i))
+	}
+	// Build a second tree in the same store so that the store holds both
+	second := first.WithRoot(nil)
+	for i := 100; i < 150; i++ {
+		second = mustPut(t, second, UInt32(i), UInt32(i))
+	}
+	firstNodes, err := first.NumNodes()
+	require.NoError(t, err)
+	secondNodes, err := second.NumNodes()
+	require.NoError(t, err)
+
+	// Nothing is collected while both trees are live
+	store, collected := mustCollectGarbage(t, second.store, second, first.WithNodeStore(second.store))
+	assert.Equal(t, uint(0), collected)
+	size, err := store.Size()
+	require.NoError(t, err)
+	assert.Equal(t, firstNodes+secondNodes, size)
+
+	store, collected = mustCollectGarbage(t, second.store, second)
+	assert.Equal(t, firstNodes, collected)
+	second = second.WithNodeStore(store)
+	assertNoLeakedNodes(t, second)
+	for i := 100; i < 150; i++ {
+		assert.Equal(t, UInt32(i), mustGet(t, second, UInt32(i)))
+	}
+
+	// Collecting with no live trees empties the store
+	store, collected = mustCollectGarbage(t, store)
+	assert.Equal(t, secondNodes, collected)
+	size, err = store.Size()
+	require.NoError(t, err)
+	assert.Equal(t, uint(0), size)
+}
+
+func TestCollectGarbagePartialTree(t *testing.T) {
+	index := NewLocalMST(Base4, crypto.SHA256)
+	for i := 0; i < 100; i++ {
+		index = mustPut(t, index, UInt32(i), UInt32(i))
+	}
+	numNodes, err := index.NumNodes()
+	require.NoError(t, err)
+
+	// A tree that's still being synced only has some of its nodes, here just the root
+	root, err := index.store.Get(index.RootHash())
+	require.NoError(t, err)
+	store, _, err := NewLocalNodeStore(crypto.SHA256).Put(root)
+	require.NoError(t, err)
+	syncing := NewMSTWithRoot(index.RootHash(), Base4, crypto.SHA256, store)
+	_, collected := mustCollectGarbage(t, index.store, syncing)
+	assert.Equal(t, numNodes-1, collected)
+}
+
+func TestCollectGarbageUnsupported(t *testing.T) {
+	index := NewLocalMST(Base4, crypto.SHA256)
+	index = mustPut(t, index, UInt32(1), UInt32(1))
+	_, _, err := CollectGarbage(&failingNodeStore{index.store, false, false}, nil)
+	assert.Error(t, err)
+}
+
+func TestGarbageCollectorWritesWhileMarking(t *testing.T) {
+	index := NewLocalMST(Base4, crypto.SHA256)
+	for i := 0; i < 100; i++ {
+		index = mustPut(t, index, UInt32(i), UInt32(i))
+	}
+	numNodes, err := index.NumNodes()
+	require.NoError(t, err)
+	c, err := NewGarbageCollector(index.store)
+	require.NoError(t, err)
+	// Nothing is live when marking, so every listed node is garbage at first
+	require.NoError(t, c.Mark(nil))
+
+	// Writing the same keys again while marking puts the same nodes back, and the new key adds
+	// nodes that weren't listed
+	rebuilt := index.WithRoot(nil)
+	for i := 0; i <= 100; i++ {
+		rebuilt = mustPut(t, rebuilt, UInt32(i), UInt32(i))
+	}
+	require.NoError(t, c.Mark([]*MerkleSearchTree{rebuilt}))
+	store, collected, err := c.Sweep(rebuilt.store)
+	require.NoError(t, err)
+	assert.Less(t, collected, numNodes)
+	rebuilt = rebuilt.WithNodeStore(store)
+	for i := 0; i <= 100; i++ {
+		assert.Equal(t, UInt32(i), mustGet(t, rebuilt, UInt32(i)))
+	}
+	assertNoLeakedNodes(t, rebuilt)
+}
@@ -0,0 +1,199 @@
+package mst
+
+import (
+	"fmt"
+	"sort"
+)
+
+// PrefixKey is implemented by keys that can be scanned by prefix. All keys with a given prefix must
+// sort next to each other, at or after the prefix itself.
+type PrefixKey interface {
+	Key
+	HasPrefix(prefix Key) bool
+}
+
+// ScanOptions bounds a scan over a tree. Start is inclusive and End is exclusive, and either may be
+// nil to leave that side unbounded. If Prefix is set, only keys with that prefix are returned,
+// which requires the keys in the tree to implement PrefixKey.
+type ScanOptions struct {
+	Start   Key
+	End     Key
+	Prefix  Key
+	Reverse bool
+}
+
+type iteratorFrame struct {
+	node *Node
+	// For forward scans this is the index of the next child to return, for reverse scans it's one
+	// past it.
+	at uint
+}
+
+// Iterator walks over the key/value pairs of a tree in order. Nodes are only read from the
+// NodeStore when the iterator reaches them, so iterating over a large tree doesn't need to load it
+// into memory. Deleted keys are skipped.
+type Iterator struct {
+	tree    *MerkleSearchTree
+	opts    ScanOptions
+	stack   []iteratorFrame
+	started bool
+	done    bool
+	key     Key
+	value   Value
+	err     error
+}
+
+// Scan returns an iterator over the key/value pairs in the tree that fall within opts
+func (t *MerkleSearchTree) Scan(opts ScanOptions) *Iterator {
+	return &Iterator{tree: t, opts: opts}
+}
+
+func (it *Iterator) hasPrefix(key Key) bool {
+	prefixKey, ok := key.(PrefixKey)
+	if !ok {
+		it.err = fmt.Errorf("Key %v doesn't support prefix scans", key)
+		return false
+	}
+	return prefixKey.HasPrefix(it.opts.Prefix)
+}
+
+// afterStart returns whether key is at or after the lower bound of the scan
+func (it *Iterator) afterStart(key Key) bool {
+	if it.opts.Start != nil && key.Less(it.opts.Start) {
+		return false
+	}
+	return it.opts.Prefix == nil || !key.Less(it.opts.Prefix)
+}
+
+// beforeEnd returns whether key is before the upper bound of the scan
+func (it *Iterator) beforeEnd(key Key) bool {
+	if it.opts.End != nil && !key.Less(it.opts.End) {
+		return false
+	}
+	return it.opts.Prefix == nil || key.Less(it.opts.Prefix) || it.hasPrefix(key)
+}
+
+func (it *Iterator) getNode(hash []byte) *Node {
+	n, err := it.tree.store.Get(hash)
+	if err != nil {
+		it.err = err
+		return nil
+	}
+	return n
+}
+
+// seek pushes the path from hash down to where the scan starts
+func (it *Iterator) seek(hash []byte) {
+	for hash != nil && it.err == nil {
+		n := it.getNode(hash)
+		if n == nil {
+			return
+		}
+		var i int
+		if it.opts.Reverse {
+			i = sort.Search(len(n.children), func(i int) bool {
+				return !it.beforeEnd(n.children[i].key)
+			})
+		} else {
+			i = sort.Search(len(n.children), func(i int) bool {
+				return it.afterStart(n.children[i].key)
+			})
+		}
+		it.stack = append(it.stack, iteratorFrame{n, uint(i)})
+		hash = n.childAt(uint(i))
+	}
+}
+
+// descend pushes the path from hash down to its first key, or its last key for reverse scans
+func (it *Iterator) descend(hash []byte) {
+	for hash != nil && it.err == nil {
+		n := it.getNode(hash)
+		if n == nil {
+			return
+		}
+		if it.opts.Reverse {
+			at := uint(len(n.children))
+			it.stack = append(it.stack, iteratorFrame{n, at})
+			hash = n.childAt(at)
+		} else {
+			it.stack = append(it.stack, iteratorFrame{n, 0})
+			hash = n.low
+		}
+	}
+}
+
+// step moves to the next child in the scan regardless of whether it's deleted
+func (it *Iterator) step() (Child, bool) {
+	for len(it.stack) > 0 {
+		top := &it.stack[len(it.stack)-1]
+		var child Child
+		if it.opts.Reverse {
+			if top.at == 0 {
+				it.stack = it.stack[:len(it.stack)-1]
+				continue
+			}
+			top.at--
+			child = top.node.children[top.at]
+			if !it.afterStart(child.key) {
+				return Child{}, false
+			}
+		} else {
+			if top.at >= uint(len(top.node.children)) {
+				it.stack = it.stack[:len(it.stack)-1]
+				continue
+			}
+			child = top.node.children[top.at]
+			top.at++
+			if !it.beforeEnd(child.key) {
+				return Child{}, false
+			}
+		}
+		it.descend(top.node.childAt(top.at))
+		return child, it.err == nil
+	}
+	return Child{}, false
+}
+
+// Next advances the iterator and returns whether there is a key/value pair to read. It returns
+// false at the end of the scan or if reading from the NodeStore failed, which Err reports.
+func (it *Iterator) Next() bool {
+	if it.done {
+		return false
+	}
+	if !it.started {
+		it.started = true
+		it.seek(it.tree.root)
+	}
+	for it.err == nil {
+		child, ok := it.step()
+		if !ok {
+			break
+		}
+		val, err := visibleValue(child.value)
+		if err != nil {
+			it.err = err
+		} else if val != nil {
+			it.key = child.key
+			it.value = val
+			return true
+		}
+	}
+	it.done = true
+	it.stack = nil
+	it.key = nil
+	it.value = nil
+	return false
+}
+
+func (it *Iterator) Key() Key {
+	return it.key
+}
+
+func (it *Iterator) Value() Value {
+	return it.value
+}
+
+// Err returns the error that stopped the iterator, if any
+func (it *Iterator) Err() error {
+	return it.err
+}
@@ -0,0 +1,199 @@
+package mst
+
+import (
+	"crypto"
+	"io"
+	"math/rand"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type stringKey string
+
+func (k stringKey) Less(than Key) bool {
+	return k < than.(stringKey)
+}
+
+func (k stringKey) Write(w io.Writer) error {
+	_, err := w.Write([]byte(k))
+	return err
+}
+
+func (k stringKey) HasPrefix(prefix Key) bool {
+	return strings.HasPrefix(string(k), string(prefix.(stringKey)))
+}
+
+type countingNodeStore struct {
+	NodeStore
+	gets *int
+}
+
+func (ns countingNodeStore) Get(k []byte) (*Node, error) {
+	*ns.gets++
+	return ns.NodeStore.Get(k)
+}
+
+type keyValue struct {
+	key   Key
+	value Value
+}
+
+func collectScan(t *testing.T, it *Iterator) []keyValue {
+	kvs := []keyValue{}
+	for it.Next() {
+		kvs = append(kvs, keyValue{it.Key(), it.Value()})
+	}
+	require.NoError(t, it.Err())
+	return kvs
+}
+
+func expectedScan(
+	t *testing.T,
+	collected map[UInt32]Value,
+	start, end UInt32,
+	reverse bool,
+) []keyValue {
+	kvs := []keyValue{}
+	for key, val := range collected {
+		if visible := mustBeVisible(t, val); key >= start && key < end && visible != nil {
+			kvs = append(kvs, keyValue{key, visible})
+		}
+	}
+	sort.Slice(kvs, func(i, j int) bool {
+		if reverse {
+			return kvs[j].key.Less(kvs[i].key)
+		}
+		return kvs[i].key.Less(kvs[j].key)
+	})
+	return kvs
+}
+
+func scanRunner(t *testing.T, base Base, iters, elems, keyMod int) {
+	rand.Seed(42)
+	for i := 0; i < iters; i++ {
+		index := NewLocalMST(base, crypto.SHA256)
+		collected := map[UInt32]Value{}
+		for j := 0; j < elems; j++ {
+			key, val := genKeyVal(keyMod)
+			oVal, exists := collected[key]
+			if rand.Intn(4) == 0 {
+				index = mustDelete(t, index, key)
+				if exists {
+					tombstone, err := tombstoneFor(oVal)
+					require.NoError(t, err)
+					if tombstone != nil {
+						collected[key] = tombstone
+					}
+				}
+			} else {
+				index = mustPut(t, index, key, val)
+				if exists {
+					collected[key] = mergeValues(oVal, val)
+				} else {
+					collected[key] = val
+				}
+			}
+		}
+
+		all := collectScan(t, index.Scan(ScanOptions{}))
+		assert.Equal(t, expectedScan(t, collected, 0, UInt32(keyMod), false), all)
+		all = collectScan(t, index.Scan(ScanOptions{Reverse: true}))
+		assert.Equal(t, expectedScan(t, collected, 0, UInt32(keyMod), true), all)
+
+		for j := 0; j < 20; j++ {
+			start := UInt32(rand.Intn(keyMod))
+			end := UInt32(rand.Intn(keyMod))
+			for _, reverse := range []bool{false, true} {
+				kvs := collectScan(t, index.Scan(ScanOptions{Start: start, End: end, Reverse: reverse}))
+				assert.Equal(t, expectedScan(t, collected, start, end, reverse), kvs)
+				kvs = collectScan(t, index.Scan(ScanOptions{Start: start, Reverse: reverse}))
+				assert.Equal(t, expectedScan(t, collected, start, UInt32(keyMod), reverse), kvs)
+				kvs = collectScan(t, index.Scan(ScanOptions{End: end, Reverse: reverse}))
+				assert.Equal(t, expectedScan(t, collected, 0, end, reverse), kvs)
+			}
+		}
+	}
+}
+
+func TestMSTScanBase2(t *testing.T) {
+	scanRunner(t, Base2, 20, 500, 200)
+}
+
+func TestMSTScanBase16(t *testing.T) {
+	scanRunner(t, Base16, 20, 500, 200)
+}
+
+func TestMSTScanEmpty(t *testing.T) {
+	index := NewLocalMST(Base16, crypto.SHA256)
+	it := index.Scan(ScanOptions{})
+	assert.False(t, it.Next())
+	assert.NoError(t, it.Err())
+}
+
+func TestMSTScanPrefix(t *testing.T) {
+	index := NewLocalMST(Base4, crypto.SHA256)
+	words := []string{"a", "ab", "abc", "abd", "ac", "b", "ba", "bab", "c"}
+	for _, word := range words {
+		index = mustPut(t, index, stringKey(word), UInt32(len(word)))
+	}
+
+	kvs := collectScan(t, index.Scan(ScanOptions{Prefix: stringKey("ab")}))
+	assert.Equal(t, []keyValue{
+		{stringKey("ab"), UInt32(2)},
+		{stringKey("abc"), UInt32(3)},
+		{stringKey("abd"), UInt32(3)},
+	}, kvs)
+
+	kvs = collectScan(t, index.Scan(ScanOptions{Prefix: stringKey("b"), Reverse: true}))
+	assert.Equal(t, []keyValue{
+		{stringKey("bab"), UInt32(3)},
+		{stringKey("ba"), UInt32(2)},
+		{stringKey("b"), UInt32(1)},
+	}, kvs)
+
+	kvs = collectScan(t, index.Scan(ScanOptions{Prefix: stringKey("ab"), Start: stringKey("abd")}))
+	assert.Equal(t, []keyValue{{stringKey("abd"), UInt32(3)}}, kvs)
+
+	kvs = collectScan(t, index.Scan(ScanOptions{Prefix: stringKey("z")}))
+	assert.Equal(t, []keyValue{}, kvs)
+}
+
+func TestMSTScanPrefixUnsupported(t *testing.T) {
+	index := NewLocalMST(Base4, crypto.SHA256)
+	index = mustPut(t, index, UInt32(1), UInt32(1))
+	it := index.Scan(ScanOptions{Prefix: UInt32(1)})
+	assert.False(t, it.Next())
+	assert.Error(t, it.Err())
+}
+
+func TestMSTScanIsLazy(t *testing.T) {
+	index := NewLocalMST(Base2, crypto.SHA256)
+	for i := 0; i < 1000; i++ {
+		index = mustPut(t, index, UInt32(i), UInt32(i))
+	}
+	numNodes, err := index.NumNodes()
+	require.NoError(t, err)
+
+	gets := 0
+	index = index.WithNodeStore(countingNodeStore{index.store, &gets})
+	it := index.Scan(ScanOptions{Start: UInt32(500)})
+	require.True(t, it.Next())
+	assert.Equal(t, UInt32(500), it.Key())
+	assert.Less(t, uint(gets), numNodes/10)
+}
+
+func TestMSTScanStoreError(t *testing.T) {
+	index := NewLocalMST(Base16, crypto.SHA256)
+	for i := 0; i < 100; i++ {
+		index = mustPut(t, index, UInt32(i), UInt32(i))
+	}
+	index = index.WithNodeStore(&failingNodeStore{index.store, false, true})
+	it := index.Scan(ScanOptions{})
+	assert.False(t, it.Next())
+	assert.Error(t, it.Err())
+	assert.False(t, it.Next())
+}
@@ -0,0 +1,77 @@
+package mst
+
+import (
+	"crypto"
+	"fmt"
+	"regexp"
+)
+
+// DefaultKeyspace is the keyspace that requests without a keyspace use
+const DefaultKeyspace = "default"
+
+var keyspaceNamePattern = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+// Keyspace describes a named tree: its shape and how its keys and values are read. Key and value
+// types and aggregators are named the way KeyReaderFor, ValueReaderFor and AggregatorFor name
+// them, and an empty aggregator keeps no aggregates.
+type Keyspace struct {
+	Name       string
+	Base       Base
+	Hash       crypto.Hash
+	KeyType    string
+	ValueType  string
+	Aggregator string
+}
+
+// KeyspaceStore persists the keyspaces of a server. SetKeyspaces replaces every keyspace at once.
+type KeyspaceStore interface {
+	Keyspaces() ([]Keyspace, error)
+	SetKeyspaces([]Keyspace) error
+}
+
+// Codecs returns the readers of the keyspace's keys and values and its aggregator, which is nil if
+// it doesn't have one
+func (k Keyspace) Codecs() (KeyReader, ValueReader, Aggregator, error) {
+	kr, err := KeyReaderFor(k.KeyType)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	vr, err := ValueReaderFor(k.ValueType)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	if k.Aggregator == "" {
+		return kr, vr, nil, nil
+	}
+	a, err := AggregatorFor(k.Aggregator)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	return kr, vr, a, nil
+}
+
+// Validate checks that the keyspace has a name that can be used as a file name, a valid base, an
+// available hash function and known codecs
+func (k Keyspace) Validate() error {
+	if !keyspaceNamePattern.MatchString(k.Name) {
+		return fmt.Errorf(
+			"Invalid keyspace name %q, only letters, digits, _ and - are allowed",
+			k.Name,
+		)
+	} else if k.Base < Base2 || k.Base > Base32 {
+		return fmt.Errorf("Invalid base %d", k.Base)
+	} else if !k.Hash.Available() {
+		return fmt.Errorf("Unsupported hash function %d", k.Hash)
+	}
+	_, _, _, err := k.Codecs()
+	return err
+}
+
+// Tree returns the keyspace's tree at root, with nodes in store
+func (k Keyspace) Tree(root []byte, store NodeStore) (*MerkleSearchTree, error) {
+	_, _, a, err := k.Codecs()
+	if err != nil {
+		return nil, err
+	}
+	return NewMSTWithRoot(root, k.Base, k.Hash, store).WithAggregator(a), nil
+}
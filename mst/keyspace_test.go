@@ -0,0 +1,71 @@
+package mst
+
+import (
+	"crypto"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testKeyspace() Keyspace {
+	return Keyspace{
+		Name:       "events_2020-05",
+		Base:       Base4,
+		Hash:       crypto.SHA256,
+		KeyType:    "uint32",
+		ValueType:  "uint32",
+		Aggregator: "uint32",
+	}
+}
+
+func TestKeyspaceValidate(t *testing.T) {
+	assert.NoError(t, testKeyspace().Validate())
+	withoutAggregator := testKeyspace()
+	withoutAggregator.Aggregator = ""
+	assert.NoError(t, withoutAggregator.Validate())
+
+	invalid := map[string]func(*Keyspace){
+		"empty name":      func(ks *Keyspace) { ks.Name = "" },
+		"path name":       func(ks *Keyspace) { ks.Name = "../events" },
+		"zero base":       func(ks *Keyspace) { ks.Base = 0 },
+		"large base":      func(ks *Keyspace) { ks.Base = Base32 + 1 },
+		"missing hash":    func(ks *Keyspace) { ks.Hash = 0 },
+		"key type":        func(ks *Keyspace) { ks.KeyType = "float" },
+		"value type":      func(ks *Keyspace) { ks.ValueType = "" },
+		"aggregator type": func(ks *Keyspace) { ks.Aggregator = "sum" },
+	}
+	for name, modify := range invalid {
+		ks := testKeyspace()
+		modify(&ks)
+		assert.Error(t, ks.Validate(), name)
+	}
+}
+
+func TestKeyspaceTree(t *testing.T) {
+	ks := testKeyspace()
+	kr, vr, a, err := ks.Codecs()
+	require.NoError(t, err)
+	assert.Equal(t, UInt32KeyReader{}, kr)
+	assert.Equal(t, UInt32ValueReader{}, vr)
+	assert.Equal(t, UInt32Aggregator{}, a)
+
+	tree, err := ks.Tree(nil, NewLocalNodeStore(crypto.SHA256))
+	require.NoError(t, err)
+	for i := 0; i < 100; i++ {
+		tree = mustPut(t, tree, UInt32(i), UInt32(i))
+	}
+	assert.Equal(t, Base4, tree.Base())
+	assert.Equal(t, crypto.SHA256, tree.Hash())
+	assert.Equal(t, uint64(4950), uint64(mustAggregate(t, tree, nil, nil).Sum))
+
+	reopened, err := ks.Tree(tree.RootHash(), tree.NodeStore())
+	require.NoError(t, err)
+	assert.Equal(t, tree.RootHash(), reopened.RootHash())
+	assert.Equal(t, "uint32", reopened.Aggregator().Name())
+
+	ks.Aggregator = ""
+	_, _, a, err = ks.Codecs()
+	require.NoError(t, err)
+	assert.Nil(t, a)
+}
@@ -1,6 +1,8 @@
 package mst
 
 import (
+	"encoding/binary"
+	"fmt"
 	"io"
 )
 
@@ -44,3 +46,21 @@ func (f UInt32) Merge(with Value) Value {
 		return with
 	}
 }
+
+type UInt32KeyReader struct{}
+
+func (kr UInt32KeyReader) FromBytes(b []byte) (Key, error) {
+	if len(b) != 4 {
+		return nil, fmt.Errorf("Expected 4 bytes for UInt32, got %d", len(b))
+	}
+	return UInt32(binary.LittleEndian.Uint32(b)), nil
+}
+
+type UInt32ValueReader struct{}
+
+func (vr UInt32ValueReader) FromBytes(b []byte) (Value, error) {
+	if len(b) != 4 {
+		return nil, fmt.Errorf("Expected 4 bytes for UInt32, got %d", len(b))
+	}
+	return UInt32(binary.LittleEndian.Uint32(b)), nil
+}
@@ -0,0 +1,99 @@
+package mst
+
+import (
+	"crypto"
+	"fmt"
+)
+
+// BulkLoader builds a tree from keys that are added in sorted order. Since the shape of a tree
+// only depends on its keys, the tree can be built bottom-up one key at a time and ends up with the
+// same root hash as putting the keys one by one, without rewriting any node. Every node is put
+// into the store exactly once.
+type BulkLoader struct {
+	base  Base
+	hash  crypto.Hash
+	store NodeStore
+	// The nodes on the path to the last key added, from the root down. Levels strictly decrease
+	// and the subtree after the last child of each node is still being built.
+	open       []*Node
+	last       Key
+	aggregator Aggregator
+}
+
+func NewBulkLoader(base Base, hash crypto.Hash, store NodeStore) *BulkLoader {
+	return &BulkLoader{base: base, hash: hash, store: store}
+}
+
+// SetAggregator makes the loader build a tree that keeps aggregates measured by a, see
+// MerkleSearchTree.WithAggregator. It has to be called before any key is added.
+func (l *BulkLoader) SetAggregator(a Aggregator) {
+	l.aggregator = a
+}
+
+// closeTop puts the deepest open node into the store, with high as the subtree after its last
+// child, and returns its hash
+func (l *BulkLoader) closeTop(high []byte) ([]byte, error) {
+	n := l.open[len(l.open)-1]
+	l.open = l.open[:len(l.open)-1]
+	n.children[len(n.children)-1].high = high
+	if l.aggregator != nil {
+		var err error
+		n, err = summarize(n, l.aggregator, l.store.Get)
+		if err != nil {
+			return nil, err
+		}
+	}
+	store, hash, err := l.store.Put(n)
+	if err != nil {
+		return nil, err
+	}
+	l.store = store
+	return hash, nil
+}
+
+// Add adds a key to the tree. Keys have to be added in sorted order. Adding the same key more
+// than once merges its values like Put does.
+func (l *BulkLoader) Add(key Key, val Value) error {
+	if l.last != nil {
+		if keysEqual(key, l.last) {
+			top := l.open[len(l.open)-1]
+			i := len(top.children) - 1
+			top.children[i].value = mergeValues(top.children[i].value, val)
+			return nil
+		} else if key.Less(l.last) {
+			return fmt.Errorf("Keys have to be added in sorted order, got %v after %v", key, l.last)
+		}
+	}
+	level := l.base.LeadingZeros(HashWritable(key, l.hash))
+	// Everything below the new key's level is to its left, so it's complete
+	var low []byte
+	for len(l.open) > 0 && l.open[len(l.open)-1].level < level {
+		var err error
+		low, err = l.closeTop(low)
+		if err != nil {
+			return err
+		}
+	}
+	if len(l.open) > 0 && l.open[len(l.open)-1].level == level {
+		top := l.open[len(l.open)-1]
+		top.children[len(top.children)-1].high = low
+		top.children = append(top.children, Child{key, val, nil})
+	} else {
+		l.open = append(l.open, NewNode(level, low, []Child{{key, val, nil}}))
+	}
+	l.last = key
+	return nil
+}
+
+// Tree finishes loading and returns the tree of every key added. The loader can't be used after.
+func (l *BulkLoader) Tree() (*MerkleSearchTree, error) {
+	var root []byte
+	for len(l.open) > 0 {
+		var err error
+		root, err = l.closeTop(root)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return NewMSTWithRoot(root, l.base, l.hash, l.store).WithAggregator(l.aggregator), nil
+}
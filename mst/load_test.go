@@ -0,0 +1,67 @@
+package mst
+
+import (
+	"crypto"
+	"math/rand"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func bulkLoadRunner(t *testing.T, base Base, iters, elems, keyMod int) {
+	rand.Seed(42)
+	for i := 0; i < iters; i++ {
+		incremental := NewLocalMST(base, crypto.SHA256)
+		keys := make([]UInt32, elems)
+		vals := make([]UInt32, elems)
+		for j := 0; j < elems; j++ {
+			keys[j], vals[j] = genKeyVal(keyMod)
+			incremental = mustPut(t, incremental, keys[j], vals[j])
+		}
+		order := make([]int, elems)
+		for j := range order {
+			order[j] = j
+		}
+		sort.SliceStable(order, func(a, b int) bool { return keys[order[a]] < keys[order[b]] })
+
+		puts := 0
+		store := putCountingNodeStore{NewLocalNodeStore(crypto.SHA256), &puts}
+		loader := NewBulkLoader(base, crypto.SHA256, store)
+		for _, j := range order {
+			require.NoError(t, loader.Add(keys[j], vals[j]))
+		}
+		loaded, err := loader.Tree()
+		require.NoError(t, err)
+		assert.Equal(t, incremental.RootHash(), loaded.RootHash())
+		for key := 0; key < keyMod; key++ {
+			assert.Equal(t, mustGet(t, incremental, UInt32(key)), mustGet(t, loaded, UInt32(key)))
+		}
+		numNodes, err := loaded.NumNodes()
+		require.NoError(t, err)
+		assert.Equal(t, int(numNodes), puts)
+		assertNoLeakedNodes(t, loaded)
+	}
+}
+
+func TestBulkLoadBase2(t *testing.T) {
+	bulkLoadRunner(t, Base2, 20, 500, 1000)
+}
+
+func TestBulkLoadBase32(t *testing.T) {
+	bulkLoadRunner(t, Base32, 20, 500, 1000)
+}
+
+func TestBulkLoadEmpty(t *testing.T) {
+	loaded, err := NewBulkLoader(Base4, crypto.SHA256, NewLocalNodeStore(crypto.SHA256)).Tree()
+	require.NoError(t, err)
+	assert.Nil(t, loaded.RootHash())
+	assert.Nil(t, mustGet(t, loaded, UInt32(1)))
+}
+
+func TestBulkLoadUnsorted(t *testing.T) {
+	loader := NewBulkLoader(Base4, crypto.SHA256, NewLocalNodeStore(crypto.SHA256))
+	require.NoError(t, loader.Add(UInt32(2), UInt32(1)))
+	assert.Error(t, loader.Add(UInt32(1), UInt32(1)))
+}
@@ -0,0 +1,210 @@
+package msttest
+
+import (
+	"bytes"
+	"crypto"
+	"fmt"
+	"math/rand"
+	"testing"
+
+	"github.com/vulturedb/vulture/mst"
+)
+
+// ValueGenerator returns a random value. Every value a generator returns during a check may be
+// merged with every other one, so they must be states that replicas can reach together. Values
+// that tag writes with the replica that made them, like crdt.ORSet, can't be built independently
+// of each other; use NewHistory for those.
+type ValueGenerator func(r *rand.Rand) mst.Value
+
+// NewHistory returns a generator that simulates replicas which update their own copy of a value,
+// starting from empty, and sometimes merge in another replica's copy first. Each value it returns
+// is the latest copy of the replica that was just updated.
+func NewHistory(
+	empty mst.Value,
+	replicas []string,
+	update func(r *rand.Rand, replica string, v mst.Value) mst.Value,
+) ValueGenerator {
+	states := make([]mst.Value, len(replicas))
+	for i := range states {
+		states[i] = empty
+	}
+	return func(r *rand.Rand) mst.Value {
+		i := r.Intn(len(states))
+		if j := r.Intn(len(states)); r.Intn(3) == 0 {
+			states[i] = states[i].Merge(states[j])
+		}
+		states[i] = update(r, replicas[i], states[i])
+		return states[i]
+	}
+}
+
+// Options configures a check. Zero fields are set to their defaults.
+type Options struct {
+	// Seed seeds the random source passed to the generator
+	Seed int64
+	// Iterations is how many sets of values the laws are checked for, 100 by default
+	Iterations int
+	// Keys is how many keys each of the trees merged by the tree check has, 50 by default
+	Keys int
+	// Reader is used to also check that values read back from their bytes are written the same
+	// way, if it's set
+	Reader mst.ValueReader
+}
+
+func (o Options) withDefaults() Options {
+	if o.Iterations == 0 {
+		o.Iterations = 100
+	}
+	if o.Keys == 0 {
+		o.Keys = 50
+	}
+	return o
+}
+
+func valueBytes(v mst.Value) ([]byte, error) {
+	buf := new(bytes.Buffer)
+	if err := v.Write(buf); err != nil {
+		return nil, fmt.Errorf("Couldn't write value: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// mustEqual returns an error describing how law is broken if a and b aren't written the same way
+func mustEqual(law string, a mst.Value, b mst.Value, values ...mst.Value) error {
+	aBytes, err := valueBytes(a)
+	if err != nil {
+		return err
+	}
+	bBytes, err := valueBytes(b)
+	if err != nil {
+		return err
+	}
+	if bytes.Equal(aBytes, bBytes) {
+		return nil
+	}
+	msg := fmt.Sprintf("%s: got %x and %x", law, aBytes, bBytes)
+	for i, v := range values {
+		vBytes, err := valueBytes(v)
+		if err != nil {
+			return err
+		}
+		msg += fmt.Sprintf(", value %d is %x", i, vBytes)
+	}
+	return fmt.Errorf("%s", msg)
+}
+
+// CheckLaws checks that merging values the generator returns is commutative, associative and
+// idempotent, that it doesn't modify the values being merged, that writing a value is
+// deterministic and, if opts has a Reader, that values survive a round trip through their bytes.
+// Values are compared by the bytes they write, since that's what the tree hashes.
+func CheckLaws(gen ValueGenerator, opts Options) error {
+	opts = opts.withDefaults()
+	r := rand.New(rand.NewSource(opts.Seed))
+	for i := 0; i < opts.Iterations; i++ {
+		a, b, c := gen(r), gen(r), gen(r)
+		aBytes, err := valueBytes(a)
+		if err != nil {
+			return err
+		}
+		bBytes, err := valueBytes(b)
+		if err != nil {
+			return err
+		}
+		if err := mustEqual("Write isn't deterministic", a, a); err != nil {
+			return err
+		}
+		ab := a.Merge(b)
+		if err := mustEqual("Merge isn't commutative", ab, b.Merge(a), a, b); err != nil {
+			return err
+		}
+		err = mustEqual("Merge isn't associative", ab.Merge(c), a.Merge(b.Merge(c)), a, b, c)
+		if err != nil {
+			return err
+		}
+		if err := mustEqual("Merge isn't idempotent", a.Merge(a), a, a); err != nil {
+			return err
+		}
+		for _, operand := range []struct {
+			v      mst.Value
+			before []byte
+		}{{a, aBytes}, {b, bBytes}} {
+			if after, err := valueBytes(operand.v); err != nil {
+				return err
+			} else if !bytes.Equal(operand.before, after) {
+				return fmt.Errorf("Merge modified a value from %x to %x", operand.before, after)
+			}
+		}
+		if opts.Reader != nil {
+			read, err := opts.Reader.FromBytes(aBytes)
+			if err != nil {
+				return fmt.Errorf("Couldn't read value %x: %w", aBytes, err)
+			}
+			if err := mustEqual("Value changed after reading it", a, read); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// CheckTreeMerge builds pairs of trees from overlapping keys, values the generator returns and a
+// few deletes, and checks that merging them in either order gives the same root hash
+func CheckTreeMerge(gen ValueGenerator, opts Options) error {
+	opts = opts.withDefaults()
+	r := rand.New(rand.NewSource(opts.Seed))
+	for i := 0; i < opts.Iterations/10+1; i++ {
+		trees := [2]*mst.MerkleSearchTree{}
+		for j := range trees {
+			tree := mst.NewLocalMST(mst.Base4, crypto.SHA256)
+			for k := 0; k < opts.Keys; k++ {
+				// Keys are drawn from twice as many as each tree has, so that the trees share
+				// about half of them
+				key := mst.UInt32(r.Intn(2 * opts.Keys))
+				var err error
+				if r.Intn(10) == 0 {
+					tree, err = tree.Delete(key)
+				} else {
+					tree, err = tree.Put(key, gen(r))
+				}
+				if err != nil {
+					return fmt.Errorf("Couldn't build tree: %w", err)
+				}
+			}
+			trees[j] = tree
+		}
+		left, err := trees[0].Merge(trees[1])
+		if err != nil {
+			return fmt.Errorf("Couldn't merge trees: %w", err)
+		}
+		right, err := trees[1].Merge(trees[0])
+		if err != nil {
+			return fmt.Errorf("Couldn't merge trees: %w", err)
+		}
+		if !bytes.Equal(left.RootHash(), right.RootHash()) {
+			return fmt.Errorf(
+				"Merging trees in different orders gave root hashes %x and %x",
+				left.RootHash(),
+				right.RootHash(),
+			)
+		}
+	}
+	return nil
+}
+
+// Check runs every check on the values the generator returns and fails t if one of them breaks.
+// It's meant to be called from the tests of a custom mst.Value:
+//
+//	func TestMyValue(t *testing.T) {
+//		msttest.Check(t, func(r *rand.Rand) mst.Value {
+//			return MyValue(r.Int63())
+//		}, msttest.Options{Reader: MyValueReader{}})
+//	}
+func Check(t testing.TB, gen ValueGenerator, opts Options) {
+	t.Helper()
+	if err := CheckLaws(gen, opts); err != nil {
+		t.Fatal(err)
+	}
+	if err := CheckTreeMerge(gen, opts); err != nil {
+		t.Fatal(err)
+	}
+}
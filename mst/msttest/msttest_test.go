@@ -0,0 +1,115 @@
+package msttest
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/vulturedb/vulture/mst"
+)
+
+func TestCheckBuiltinValues(t *testing.T) {
+	Check(t, func(r *rand.Rand) mst.Value {
+		return mst.UInt32(r.Intn(100))
+	}, Options{Reader: mst.UInt32ValueReader{}})
+	Check(t, func(r *rand.Rand) mst.Value {
+		return mst.NewLWWBytes(uint64(r.Intn(10)), []byte(fmt.Sprintf("v%d", r.Intn(10))))
+	}, Options{Reader: mst.LWWBytesValueReader{}})
+}
+
+// firstWins keeps the value it's called on, which isn't commutative
+type firstWins uint32
+
+func (v firstWins) Write(w io.Writer) error {
+	return binary.Write(w, binary.LittleEndian, uint32(v))
+}
+
+func (v firstWins) Merge(with mst.Value) mst.Value {
+	return v
+}
+
+// sum adds values up, which isn't idempotent
+type sum uint32
+
+func (v sum) Write(w io.Writer) error {
+	return binary.Write(w, binary.LittleEndian, uint32(v))
+}
+
+func (v sum) Merge(with mst.Value) mst.Value {
+	return v + with.(sum)
+}
+
+// union merges sets into the receiver's map instead of a new one
+type union map[byte]bool
+
+func (v union) Write(w io.Writer) error {
+	for i := 0; i < 256; i++ {
+		if v[byte(i)] {
+			if _, err := w.Write([]byte{byte(i)}); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (v union) Merge(with mst.Value) mst.Value {
+	for element := range with.(union) {
+		v[element] = true
+	}
+	return v
+}
+
+func TestCheckLawsFindsBrokenValues(t *testing.T) {
+	err := CheckLaws(func(r *rand.Rand) mst.Value {
+		return firstWins(r.Intn(100))
+	}, Options{})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "commutative")
+
+	err = CheckLaws(func(r *rand.Rand) mst.Value {
+		return sum(r.Intn(100))
+	}, Options{})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "idempotent")
+
+	err = CheckLaws(func(r *rand.Rand) mst.Value {
+		return union{byte(r.Intn(256)): true}
+	}, Options{})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "modified")
+
+	err = CheckLaws(func(r *rand.Rand) mst.Value {
+		return mst.UInt32(r.Intn(100))
+	}, Options{Reader: mst.LWWBytesValueReader{}})
+	assert.Error(t, err)
+}
+
+func TestCheckTreeMergeFindsBrokenValues(t *testing.T) {
+	err := CheckTreeMerge(func(r *rand.Rand) mst.Value {
+		return firstWins(r.Intn(100))
+	}, Options{})
+	assert.Error(t, err)
+}
+
+func TestNewHistory(t *testing.T) {
+	seen := map[string]bool{}
+	gen := NewHistory(mst.UInt32(0), []string{"a", "b"}, func(
+		r *rand.Rand,
+		replica string,
+		v mst.Value,
+	) mst.Value {
+		seen[replica] = true
+		return v.Merge(mst.UInt32(r.Intn(100)))
+	})
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 20; i++ {
+		gen(r)
+	}
+	assert.Equal(t, map[string]bool{"a": true, "b": true}, seen)
+}
@@ -33,10 +33,18 @@ type Node struct {
 	level    uint32
 	low      []byte
 	children []Child
+	// Summaries of the subtrees at low and at the high of each child, in that order. They're only
+	// kept by trees with an aggregator and are nil otherwise.
+	summaries []Summary
 }
 
 func NewNode(level uint32, low []byte, children []Child) *Node {
-	return &Node{level, low, children}
+	return &Node{level: level, low: low, children: children}
+}
+
+// WithSummaries returns the node with the given summaries of its subtrees, see Summaries
+func (n *Node) WithSummaries(summaries []Summary) *Node {
+	return &Node{n.level, n.low, n.children, summaries}
 }
 
 func (n *Node) Level() uint32 {
@@ -53,6 +61,17 @@ func (n *Node) Children() []Child {
 	return children
 }
 
+// Summaries returns the summaries of the subtrees at the node's low and at the high of each of its
+// children, or nil if the node doesn't have any
+func (n *Node) Summaries() []Summary {
+	if n.summaries == nil {
+		return nil
+	}
+	summaries := make([]Summary, len(n.summaries))
+	copy(summaries, n.summaries)
+	return summaries
+}
+
 func (n *Node) find(key Key) uint {
 	i := sort.Search(len(n.children), func(i int) bool {
 		return key.Less(n.children[i].key)
@@ -101,7 +120,7 @@ func (n *Node) withHashAt(hash []byte, at uint) *Node {
 func (n *Node) withMergedValueAt(val Value, at uint) *Node {
 	newChildren := make([]Child, len(n.children))
 	copy(newChildren, n.children)
-	newChildren[at].value = n.children[at].value.Merge(val)
+	newChildren[at].value = mergeValues(n.children[at].value, val)
 	return &Node{
 		level:    n.level,
 		low:      n.low,
@@ -2,7 +2,11 @@ package mst
 
 import (
 	"crypto"
+	"encoding/hex"
+	"errors"
+	"fmt"
 	"io"
+	"time"
 
 	"github.com/benbjohnson/immutable"
 )
@@ -10,76 +14,43 @@ import (
 // Only used for computing the hash for a given node
 type HashableNode Node
 
-func hashToWrite(hash []byte, hashSize int) []byte {
-	if hash == nil {
-		return make([]byte, hashSize)
-	}
-	return hash
-}
-
+// Write writes the node the way it's hashed, which is the way EncodeNode writes it. Keys and values
+// are length prefixed and tombstones are flagged, so no two different nodes are written the same
+// way.
 func (n *HashableNode) Write(w io.Writer) error {
-	// TODO: Test this if it's ever used in something production related. It really shouldn't be since
-	// this node store is mainly meant for testing. Persistent node stores will need to think more
-	// about the serialization format.
-	err := putUint32(n.level, w)
-	if err != nil {
-		return err
-	}
-
-	// Write key/values first
-	err = putUint32(uint32(len(n.children)), w)
-	if err != nil {
-		return err
-	}
+	return EncodeNode((*Node)(n), w)
+}
 
-	for _, child := range n.children {
-		err = child.key.Write(w)
-		if err != nil {
-			return err
-		}
-		err = child.value.Write(w)
-		if err != nil {
-			return err
-		}
-	}
+// ErrNodeNotFound is returned by a NodeStore when it doesn't hold a node for the given hash.
+var ErrNodeNotFound = errors.New("Node not found")
 
-	// Assumes all hash sizes are exactly the same
-	hashSize := 0
-	if n.low != nil {
-		hashSize = len(n.low)
-	} else {
-		for _, child := range n.children {
-			if child.high != nil {
-				hashSize = len(child.high)
-				break
-			}
-		}
-	}
+// NodeStore stores the nodes of one or more trees keyed by their hash. Any I/O, encoding or
+// decoding failures are returned to the caller rather than panicking.
+type NodeStore interface {
+	Get([]byte) (*Node, error)
+	Put(*Node) (NodeStore, []byte, error)
+	Remove([]byte) (NodeStore, error)
+	Size() (uint, error)
+}
 
-	// Don't waste space on zeroed out hashes
-	if hashSize > 0 {
-		if n.low != nil {
-			_, err = w.Write(hashToWrite(n.low, hashSize))
-			if err != nil {
-				return err
-			}
-		}
+// RootStore persists the root hash of a tree so that it can be reopened from its NodeStore later.
+// A nil root is an empty tree.
+type RootStore interface {
+	Root() ([]byte, error)
+	SetRoot([]byte) error
+}
 
-		for _, child := range n.children {
-			_, err = w.Write(hashToWrite(child.high, hashSize))
-			if err != nil {
-				return err
-			}
-		}
-	}
-	return nil
+// Snapshot names a root of a tree so that it can still be read after the tree has changed
+type Snapshot struct {
+	Name    string
+	Root    []byte
+	Created time.Time
 }
 
-type NodeStore interface {
-	Get([]byte) *Node
-	Put(*Node) (NodeStore, []byte)
-	Remove([]byte) NodeStore
-	Size() uint
+// SnapshotStore persists the snapshots of a tree. SetSnapshots replaces every snapshot at once.
+type SnapshotStore interface {
+	Snapshots() ([]Snapshot, error)
+	SetSnapshots([]Snapshot) error
 }
 
 type LocalNodeStore struct {
@@ -95,41 +66,71 @@ func (ns *LocalNodeStore) withDict(dict *immutable.Map) NodeStore {
 	return &LocalNodeStore{dict: dict, hash: ns.hash}
 }
 
-func (ns *LocalNodeStore) Get(k []byte) *Node {
+func (ns *LocalNodeStore) Get(k []byte) (*Node, error) {
 	val, ok := ns.dict.Get(string(k))
 	if ok {
-		return val.(*Node)
+		return val.(*Node), nil
 	} else {
-		return nil
+		return nil, fmt.Errorf("%w: %s", ErrNodeNotFound, hex.EncodeToString(k))
 	}
 }
 
-func (ns *LocalNodeStore) Put(n *Node) (NodeStore, []byte) {
+func (ns *LocalNodeStore) Put(n *Node) (NodeStore, []byte, error) {
 	wn := HashableNode(*n)
 	k := HashWritable(&wn, ns.hash)
-	return ns.withDict(ns.dict.Set(string(k), n)), k
+	return ns.withDict(ns.dict.Set(string(k), n)), k, nil
+}
+
+func (ns *LocalNodeStore) Remove(k []byte) (NodeStore, error) {
+	return ns.withDict(ns.dict.Delete(string(k))), nil
 }
 
-func (ns *LocalNodeStore) Remove(k []byte) NodeStore {
-	return ns.withDict(ns.dict.Delete(string(k)))
+func (ns *LocalNodeStore) Size() (uint, error) {
+	return uint(ns.dict.Len()), nil
 }
 
-func (ns *LocalNodeStore) Size() uint {
-	return uint(ns.dict.Len())
+// Hashes returns the hashes of the nodes in this version of the store. Versions of a
+// LocalNodeStore don't share anything that needs to be collected.
+func (ns *LocalNodeStore) Hashes() ([][]byte, error) {
+	hashes := make([][]byte, 0, ns.dict.Len())
+	itr := ns.dict.Iterator()
+	for !itr.Done() {
+		k, _ := itr.Next()
+		hashes = append(hashes, []byte(k.(string)))
+	}
+	return hashes, nil
+}
+
+func (ns *LocalNodeStore) Collect(hashes [][]byte) (NodeStore, error) {
+	dict := ns.dict
+	for _, k := range hashes {
+		dict = dict.Delete(string(k))
+	}
+	return ns.withDict(dict), nil
 }
 
-func FindMissingNodes(ns NodeStore, hash []byte) [][]byte {
+// FindMissingNodes returns the hashes of all nodes reachable from hash that aren't in ns. Missing
+// nodes are not an error, but any other failure to read from ns is.
+func FindMissingNodes(ns NodeStore, hash []byte) ([][]byte, error) {
 	if hash == nil {
-		return [][]byte{}
+		return [][]byte{}, nil
 	}
-	n := ns.Get(hash)
-	if n == nil {
-		return [][]byte{hash}
+	n, err := ns.Get(hash)
+	if errors.Is(err, ErrNodeNotFound) {
+		return [][]byte{hash}, nil
+	} else if err != nil {
+		return nil, err
+	}
+	missingNodes, err := FindMissingNodes(ns, n.low)
+	if err != nil {
+		return nil, err
 	}
-	missingNodes := [][]byte{}
-	missingNodes = append(missingNodes, FindMissingNodes(ns, n.low)...)
 	for _, child := range n.children {
-		missingNodes = append(missingNodes, FindMissingNodes(ns, child.high)...)
+		missingChildNodes, err := FindMissingNodes(ns, child.high)
+		if err != nil {
+			return nil, err
+		}
+		missingNodes = append(missingNodes, missingChildNodes...)
 	}
-	return missingNodes
+	return missingNodes, nil
 }
@@ -2,6 +2,7 @@ package mst
 
 import (
 	"crypto"
+	"errors"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -9,15 +10,63 @@ import (
 
 func TestFindMissingNodesEmpty(t *testing.T) {
 	ns := NewLocalNodeStore(crypto.SHA256)
-	assert.Equal(t, [][]byte{}, FindMissingNodes(ns, nil))
-	assert.Equal(t, [][]byte{{1, 2, 3}}, FindMissingNodes(ns, []byte{1, 2, 3}))
+	missing, err := FindMissingNodes(ns, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, [][]byte{}, missing)
+	missing, err = FindMissingNodes(ns, []byte{1, 2, 3})
+	assert.NoError(t, err)
+	assert.Equal(t, [][]byte{{1, 2, 3}}, missing)
 }
 
 func TestFindMissingNodesSomeChildren(t *testing.T) {
 	ns := NewLocalNodeStore(crypto.SHA256)
-	nChild := &Node{0, nil, []Child{{UInt32(1), UInt32(2), []byte{1, 2, 3}}}}
-	ns, hChild := ns.Put(nChild)
-	nRoot := &Node{1, []byte{2, 3, 4}, []Child{{UInt32(3), UInt32(4), hChild}}}
-	ns, hRoot := ns.Put(nRoot)
-	assert.Equal(t, [][]byte{{2, 3, 4}, {1, 2, 3}}, FindMissingNodes(ns, hRoot))
+	nChild := NewNode(0, nil, []Child{{UInt32(1), UInt32(2), []byte{1, 2, 3}}})
+	ns, hChild, err := ns.Put(nChild)
+	assert.NoError(t, err)
+	nRoot := NewNode(1, []byte{2, 3, 4}, []Child{{UInt32(3), UInt32(4), hChild}})
+	ns, hRoot, err := ns.Put(nRoot)
+	assert.NoError(t, err)
+	missing, err := FindMissingNodes(ns, hRoot)
+	assert.NoError(t, err)
+	assert.Equal(t, [][]byte{{2, 3, 4}, {1, 2, 3}}, missing)
+}
+
+func TestFindMissingNodesStoreError(t *testing.T) {
+	ns := &failingNodeStore{NewLocalNodeStore(crypto.SHA256), false, true}
+	_, err := FindMissingNodes(ns, []byte{1, 2, 3})
+	assert.Error(t, err)
+	assert.Equal(t, "Couldn't get node", err.Error())
+}
+
+// failingNodeStore wraps a NodeStore and fails Puts and/or Gets, as an I/O bound store might
+type failingNodeStore struct {
+	NodeStore
+	failPut bool
+	failGet bool
+}
+
+func (ns *failingNodeStore) Get(k []byte) (*Node, error) {
+	if ns.failGet {
+		return nil, errors.New("Couldn't get node")
+	}
+	return ns.NodeStore.Get(k)
+}
+
+func (ns *failingNodeStore) Put(n *Node) (NodeStore, []byte, error) {
+	if ns.failPut {
+		return nil, nil, errors.New("Couldn't put node")
+	}
+	store, hash, err := ns.NodeStore.Put(n)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &failingNodeStore{store, ns.failPut, ns.failGet}, hash, nil
+}
+
+func (ns *failingNodeStore) Remove(k []byte) (NodeStore, error) {
+	store, err := ns.NodeStore.Remove(k)
+	if err != nil {
+		return nil, err
+	}
+	return &failingNodeStore{store, ns.failPut, ns.failGet}, nil
 }
@@ -0,0 +1,91 @@
+package mst
+
+import (
+	"bytes"
+	"crypto"
+	"encoding/hex"
+	"fmt"
+)
+
+// Proof is the path of nodes from the root of a tree down to the node that holds a key, or down to
+// the gap where the key would be if it existed.
+type Proof []*Node
+
+// Prove returns the proof for key. It proves that key is in the tree if it exists and that it's
+// absent otherwise.
+func (t *MerkleSearchTree) Prove(key Key) (Proof, error) {
+	keyLevel := t.leadingZeros(key)
+	proof := Proof{}
+	hash := t.root
+	for hash != nil {
+		n, err := t.store.Get(hash)
+		if err != nil {
+			return nil, err
+		}
+		proof = append(proof, n)
+		i := n.find(key)
+		if i > 0 && keysEqual(key, n.children[i-1].key) {
+			break
+		}
+		if n.level <= keyLevel {
+			// The key can only be at its own level, so it can't be further down
+			break
+		}
+		hash = n.childAt(i)
+	}
+	return proof, nil
+}
+
+// VerifyProof checks that proof leads from rootHash to key and returns the key's value, or nil if
+// the proof shows that the key isn't in the tree. Nodes are hashed as HashableNodes, so only proofs
+// from stores that address nodes that way, like LocalNodeStore, can be verified.
+func VerifyProof(
+	rootHash []byte,
+	key Key,
+	proof Proof,
+	hash crypto.Hash,
+	base Base,
+) (Value, error) {
+	keyLevel := base.LeadingZeros(HashWritable(key, hash))
+	expected := rootHash
+	for i, n := range proof {
+		if expected == nil {
+			return nil, fmt.Errorf("Proof has %d extra nodes", len(proof)-i)
+		}
+		wn := HashableNode(*n)
+		actual := HashWritable(&wn, hash)
+		if !bytes.Equal(actual, expected) {
+			return nil, fmt.Errorf(
+				"Node %d of proof has hash %s, expected %s",
+				i,
+				hex.EncodeToString(actual),
+				hex.EncodeToString(expected),
+			)
+		}
+		if len(n.children) == 0 {
+			return nil, fmt.Errorf("Node %d of proof has no children", i)
+		}
+
+		isLast := i == len(proof)-1
+		j := n.find(key)
+		if j > 0 && keysEqual(key, n.children[j-1].key) {
+			if n.level != keyLevel {
+				return nil, fmt.Errorf("Key found at level %d, expected %d", n.level, keyLevel)
+			} else if !isLast {
+				return nil, fmt.Errorf("Proof has %d extra nodes", len(proof)-i-1)
+			}
+			return visibleValue(n.children[j-1].value)
+		}
+		if n.level <= keyLevel {
+			if !isLast {
+				return nil, fmt.Errorf("Proof has %d extra nodes", len(proof)-i-1)
+			}
+			return nil, nil
+		}
+		expected = n.childAt(j)
+	}
+	if expected != nil {
+		return nil, fmt.Errorf("Proof is missing node %s", hex.EncodeToString(expected))
+	}
+	return nil, nil
+}
@@ -0,0 +1,131 @@
+package mst
+
+import (
+	"bytes"
+	"crypto"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func mustProve(t *testing.T, tree *MerkleSearchTree, key Key) Proof {
+	proof, err := tree.Prove(key)
+	require.NoError(t, err)
+	return proof
+}
+
+func proofRunner(t *testing.T, base Base, elems, keyMod int) {
+	rand.Seed(42)
+	index := NewLocalMST(base, crypto.SHA256)
+	for i := 0; i < elems; i++ {
+		key, val := genKeyVal(keyMod)
+		if rand.Intn(4) == 0 {
+			index = mustDelete(t, index, key)
+		} else {
+			index = mustPut(t, index, key, val)
+		}
+	}
+	for i := 0; i < keyMod; i++ {
+		key := UInt32(i)
+		proof := mustProve(t, index, key)
+		val, err := VerifyProof(index.RootHash(), key, proof, index.hash, base)
+		require.NoError(t, err)
+		assert.Equal(t, mustGet(t, index, key), val)
+	}
+}
+
+func TestMSTProofBase2(t *testing.T) {
+	proofRunner(t, Base2, 500, 400)
+}
+
+func TestMSTProofBase16(t *testing.T) {
+	proofRunner(t, Base16, 500, 400)
+}
+
+func TestMSTProofEmpty(t *testing.T) {
+	index := NewLocalMST(Base16, crypto.SHA256)
+	proof := mustProve(t, index, UInt32(1))
+	assert.Empty(t, proof)
+	val, err := VerifyProof(nil, UInt32(1), proof, crypto.SHA256, Base16)
+	require.NoError(t, err)
+	assert.Nil(t, val)
+}
+
+func TestMSTProofTampered(t *testing.T) {
+	index := NewLocalMST(Base2, crypto.SHA256)
+	for i := 0; i < 100; i++ {
+		index = mustPut(t, index, UInt32(i), UInt32(i))
+	}
+	root := index.RootHash()
+	proof := mustProve(t, index, UInt32(42))
+	require.NotEmpty(t, proof)
+
+	// A changed value no longer hashes to the root
+	last := proof[len(proof)-1]
+	at := last.find(UInt32(42)) - 1
+	tampered := append(Proof{}, proof...)
+	tampered[len(proof)-1] = last.withMergedValueAt(UInt32(1000), at)
+	_, err := VerifyProof(root, UInt32(42), tampered, crypto.SHA256, Base2)
+	assert.Error(t, err)
+
+	// Truncated and padded proofs are rejected
+	if len(proof) > 1 {
+		_, err = VerifyProof(root, UInt32(42), proof[:len(proof)-1], crypto.SHA256, Base2)
+		assert.Error(t, err)
+	}
+	_, err = VerifyProof(root, UInt32(42), append(proof, proof[0]), crypto.SHA256, Base2)
+	assert.Error(t, err)
+
+	// The proof for one key doesn't prove anything about another
+	_, err = VerifyProof(index.RootHash(), UInt32(42), proof, crypto.SHA256, Base2)
+	require.NoError(t, err)
+	other := mustPut(t, index, UInt32(1000), UInt32(1))
+	_, err = VerifyProof(other.RootHash(), UInt32(42), proof, crypto.SHA256, Base2)
+	assert.Error(t, err)
+}
+
+func TestMSTProofForged(t *testing.T) {
+	// Find a key that's at the same level as its first byte alone
+	var key, forgedKey String
+	for c := byte('a'); ; c++ {
+		key, forgedKey = String([]byte{c, 'b'}), String([]byte{c})
+		level := Base16.LeadingZeros(HashWritable(key, crypto.SHA256))
+		if level == Base16.LeadingZeros(HashWritable(forgedKey, crypto.SHA256)) {
+			break
+		}
+	}
+	value := NewLWWBytes(0x0102030405060708, []byte("x"))
+	index := mustPut(t, NewLocalMST(Base16, crypto.SHA256), key, value)
+	proof := mustProve(t, index, key)
+	require.Len(t, proof, 1)
+
+	// Moving the last byte of the key into the value writes the same bytes without length prefixes
+	raw := new(bytes.Buffer)
+	require.NoError(t, value.Write(raw))
+	forgedValue, err := LWWBytesValueReader{}.FromBytes(append([]byte("b"), raw.Bytes()...))
+	require.NoError(t, err)
+	node := proof[0]
+	forged := NewNode(
+		node.level,
+		node.low,
+		[]Child{{forgedKey, forgedValue, node.children[0].high}},
+	)
+	_, err = VerifyProof(index.RootHash(), forgedKey, Proof{forged}, crypto.SHA256, Base16)
+	assert.Error(t, err)
+
+	// Neither can a tombstone pass for a value that's written the same way, nor a node with
+	// summaries for one without
+	tombstone := Tombstone{value, value}
+	raw.Reset()
+	require.NoError(t, tombstone.Write(raw))
+	lookalike, err := LWWBytesValueReader{}.FromBytes(raw.Bytes())
+	require.NoError(t, err)
+	hashWith := func(value Value, summaries []Summary) []byte {
+		n := NewNode(node.level, node.low, []Child{{key, value, nil}}).WithSummaries(summaries)
+		return HashWritable((*HashableNode)(n), crypto.SHA256)
+	}
+	assert.NotEqual(t, hashWith(tombstone, nil), hashWith(lookalike, nil))
+	assert.NotEqual(t, hashWith(value, nil), hashWith(value, []Summary{}))
+}
@@ -2,6 +2,7 @@ package mst
 
 import (
 	"encoding/binary"
+	"fmt"
 	"io"
 )
 
@@ -15,3 +16,247 @@ func putUint32(n uint32, w io.Writer) error {
 	_, err := w.Write(buf)
 	return err
 }
+
+func getUint32(r io.Reader) (uint32, error) {
+	buf := make([]byte, 4)
+	_, err := io.ReadFull(r, buf)
+	if err != nil {
+		return 0, err
+	}
+	return binary.LittleEndian.Uint32(buf), nil
+}
+
+func putUint64(n uint64, w io.Writer) error {
+	buf := make([]byte, 8)
+	binary.LittleEndian.PutUint64(buf, n)
+	_, err := w.Write(buf)
+	return err
+}
+
+func getUint64(r io.Reader) (uint64, error) {
+	buf := make([]byte, 8)
+	_, err := io.ReadFull(r, buf)
+	if err != nil {
+		return 0, err
+	}
+	return binary.LittleEndian.Uint64(buf), nil
+}
+
+func putBytes(b []byte, w io.Writer) error {
+	err := putUint32(uint32(len(b)), w)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(b)
+	return err
+}
+
+// maxEncodedBytes bounds the length prefixes DecodeNode accepts so that corrupt input can't make it
+// allocate arbitrarily large buffers.
+const maxEncodedBytes = 1 << 26
+
+func getBytes(r io.Reader) ([]byte, error) {
+	n, err := getUint32(r)
+	if err != nil {
+		return nil, err
+	}
+	if n > maxEncodedBytes {
+		return nil, fmt.Errorf("Encoded length %d is too large", n)
+	}
+	buf := make([]byte, n)
+	_, err = io.ReadFull(r, buf)
+	if err != nil {
+		return nil, err
+	}
+	return buf, nil
+}
+
+// putHash writes a possibly nil hash, which is encoded the same way as an empty one
+func putHash(hash []byte, w io.Writer) error {
+	return putBytes(hash, w)
+}
+
+func getHash(r io.Reader) ([]byte, error) {
+	hash, err := getBytes(r)
+	if err != nil || len(hash) == 0 {
+		return nil, err
+	}
+	return hash, nil
+}
+
+const (
+	encodedValue     byte = 0
+	encodedTombstone byte = 1
+)
+
+// EncodeNode writes n in a format that DecodeNode can read back. Every field is length prefixed and
+// tombstones are flagged, so nodes can be stored and read back without losing anything. Nodes are
+// hashed the same way, see HashableNode. Summaries follow the children if the node has any, so
+// nodes without them are encoded and hashed the same way they were before trees kept aggregates.
+func EncodeNode(n *Node, w io.Writer) error {
+	err := putUint32(n.level, w)
+	if err != nil {
+		return err
+	}
+	err = putHash(n.low, w)
+	if err != nil {
+		return err
+	}
+	err = putUint32(uint32(len(n.children)), w)
+	if err != nil {
+		return err
+	}
+	for _, child := range n.children {
+		err = encodeChild(child, w)
+		if err != nil {
+			return err
+		}
+	}
+	if n.summaries == nil {
+		return nil
+	}
+	err = putUint32(uint32(len(n.summaries)), w)
+	if err != nil {
+		return err
+	}
+	for _, summary := range n.summaries {
+		err = summary.Write(w)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func encodeChild(c Child, w io.Writer) error {
+	key, err := writableBytes(c.key)
+	if err != nil {
+		return err
+	}
+	err = putBytes(key, w)
+	if err != nil {
+		return err
+	}
+
+	values := []Value{c.value}
+	kind := encodedValue
+	if tombstone, isTombstone := c.value.(Tombstone); isTombstone {
+		values = []Value{tombstone.value, tombstone.deleted}
+		kind = encodedTombstone
+	}
+	_, err = w.Write([]byte{kind})
+	if err != nil {
+		return err
+	}
+	for _, v := range values {
+		b, err := writableBytes(v)
+		if err != nil {
+			return err
+		}
+		err = putBytes(b, w)
+		if err != nil {
+			return err
+		}
+	}
+	return putHash(c.high, w)
+}
+
+// DecodeNode reads a node written by EncodeNode
+func DecodeNode(r io.Reader, kr KeyReader, vr ValueReader) (*Node, error) {
+	level, err := getUint32(r)
+	if err != nil {
+		return nil, err
+	}
+	low, err := getHash(r)
+	if err != nil {
+		return nil, err
+	}
+	numChildren, err := getUint32(r)
+	if err != nil {
+		return nil, err
+	}
+	if numChildren == 0 {
+		return nil, fmt.Errorf("Encoded node has no children")
+	}
+	children := []Child{}
+	for i := uint32(0); i < numChildren; i++ {
+		child, err := decodeChild(r, kr, vr)
+		if err != nil {
+			return nil, err
+		}
+		children = append(children, child)
+	}
+	summaries, err := decodeSummaries(r, numChildren+1)
+	if err != nil {
+		return nil, err
+	}
+	return &Node{level, low, children, summaries}, nil
+}
+
+// decodeSummaries reads the summaries that follow the children of an encoded node, if there are
+// any
+func decodeSummaries(r io.Reader, expected uint32) ([]Summary, error) {
+	numSummaries, err := getUint32(r)
+	if err == io.EOF {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	if numSummaries != expected {
+		return nil, fmt.Errorf("Expected %d summaries, got %d", expected, numSummaries)
+	}
+	summaries := make([]Summary, numSummaries)
+	for i := range summaries {
+		summaries[i], err = readSummary(r)
+		if err == io.EOF {
+			return nil, io.ErrUnexpectedEOF
+		} else if err != nil {
+			return nil, err
+		}
+	}
+	return summaries, nil
+}
+
+func decodeChild(r io.Reader, kr KeyReader, vr ValueReader) (Child, error) {
+	rawKey, err := getBytes(r)
+	if err != nil {
+		return Child{}, err
+	}
+	key, err := kr.FromBytes(rawKey)
+	if err != nil {
+		return Child{}, err
+	}
+
+	kind := make([]byte, 1)
+	_, err = io.ReadFull(r, kind)
+	if err != nil {
+		return Child{}, err
+	}
+	numValues := 1
+	if kind[0] == encodedTombstone {
+		numValues = 2
+	} else if kind[0] != encodedValue {
+		return Child{}, fmt.Errorf("Unknown value kind %d", kind[0])
+	}
+	values := make([]Value, numValues)
+	for i := range values {
+		rawValue, err := getBytes(r)
+		if err != nil {
+			return Child{}, err
+		}
+		values[i], err = vr.FromBytes(rawValue)
+		if err != nil {
+			return Child{}, err
+		}
+	}
+	value := values[0]
+	if kind[0] == encodedTombstone {
+		value = Tombstone{values[0], values[1]}
+	}
+
+	high, err := getHash(r)
+	if err != nil {
+		return Child{}, err
+	}
+	return Child{key, value, high}, nil
+}
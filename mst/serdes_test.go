@@ -5,6 +5,7 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestPutGetUInt32(t *testing.T) {
@@ -15,3 +16,36 @@ func TestPutGetUInt32(t *testing.T) {
 	// Little endian over here
 	assert.Equal(t, buf.Bytes(), []byte{0b01000101, 0b00000000, 0b00000000, 0b00000000})
 }
+
+func TestEncodeDecodeNode(t *testing.T) {
+	nodes := []*Node{
+		NewNode(0, nil, []Child{NewChild(UInt32(1), UInt32(2), nil)}),
+		NewNode(3, []byte{1, 2, 3}, []Child{
+			NewChild(UInt32(1), UInt32(2), nil),
+			NewChild(UInt32(5), NewTombstone(UInt32(7), UInt32(7)), []byte{4, 5, 6}),
+			NewChild(UInt32(9), NewTombstone(UInt32(8), UInt32(3)), []byte{7, 8, 9}),
+		}),
+		NewNode(1, nil, []Child{NewChild(UInt32(1), UInt32(2), []byte{1})}).WithSummaries(
+			[]Summary{{}, {Count: 2, Measured: 1, Sum: 1.5, Min: 1.5, Max: 1.5}},
+		),
+	}
+	for _, n := range nodes {
+		buf := new(bytes.Buffer)
+		require.NoError(t, EncodeNode(n, buf))
+		decoded, err := DecodeNode(buf, UInt32KeyReader{}, UInt32ValueReader{})
+		require.NoError(t, err)
+		assert.Equal(t, n, decoded)
+		assert.Equal(t, 0, buf.Len())
+	}
+}
+
+func TestDecodeNodeTruncated(t *testing.T) {
+	n := NewNode(1, []byte{1, 2, 3}, []Child{NewChild(UInt32(1), UInt32(2), []byte{4, 5, 6})})
+	buf := new(bytes.Buffer)
+	require.NoError(t, EncodeNode(n, buf))
+	encoded := buf.Bytes()
+	for i := 0; i < len(encoded); i++ {
+		_, err := DecodeNode(bytes.NewReader(encoded[:i]), UInt32KeyReader{}, UInt32ValueReader{})
+		assert.Error(t, err)
+	}
+}
@@ -0,0 +1,121 @@
+package mst
+
+import (
+	"bytes"
+	"io"
+)
+
+// Tombstone is stored in place of a value once its key has been deleted. It keeps the merged value
+// of every write it has seen alongside the merged value that was visible when the key was deleted.
+// A write that is already covered by the deleted value under Merge is older than the delete, so
+// the key stays deleted. Any other write resurrects the key.
+//
+// Merging tombstones merges both halves independently, so merges stay commutative, associative and
+// idempotent as long as the underlying Value's Merge is.
+type Tombstone struct {
+	value   Value
+	deleted Value
+}
+
+func NewTombstone(value Value, deleted Value) Tombstone {
+	return Tombstone{value, deleted}
+}
+
+// Value returns the merged value of every write the tombstone has seen
+func (t Tombstone) Value() Value {
+	return t.value
+}
+
+// Deleted returns the merged value that was visible when the key was deleted
+func (t Tombstone) Deleted() Value {
+	return t.deleted
+}
+
+// IsDeleted returns whether the key is still deleted, i.e. no write has happened since the delete.
+// It errors if either half of the tombstone can't be written.
+func (t Tombstone) IsDeleted() (bool, error) {
+	return valuesEqual(t.value, t.deleted)
+}
+
+func (t Tombstone) Write(w io.Writer) error {
+	// Length prefix both halves so that tombstones can't be confused with each other
+	for _, v := range []Value{t.value, t.deleted} {
+		b, err := writableBytes(v)
+		if err != nil {
+			return err
+		}
+		err = putUint32(uint32(len(b)), w)
+		if err != nil {
+			return err
+		}
+		_, err = w.Write(b)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (t Tombstone) Merge(with Value) Value {
+	return mergeValues(t, with)
+}
+
+func writableBytes(obj Writable) ([]byte, error) {
+	buf := new(bytes.Buffer)
+	err := obj.Write(buf)
+	if err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func valuesEqual(v1 Value, v2 Value) (bool, error) {
+	b1, err := writableBytes(v1)
+	if err != nil {
+		return false, err
+	}
+	b2, err := writableBytes(v2)
+	if err != nil {
+		return false, err
+	}
+	return bytes.Equal(b1, b2), nil
+}
+
+// mergeValues merges two values that may be tombstones. Values that aren't tombstones are treated
+// as tombstones that have never been deleted.
+func mergeValues(v1 Value, v2 Value) Value {
+	t1, isT1 := v1.(Tombstone)
+	t2, isT2 := v2.(Tombstone)
+	if isT1 && isT2 {
+		return Tombstone{t1.value.Merge(t2.value), t1.deleted.Merge(t2.deleted)}
+	} else if isT1 {
+		return Tombstone{t1.value.Merge(v2), t1.deleted}
+	} else if isT2 {
+		return Tombstone{v1.Merge(t2.value), t2.deleted}
+	}
+	return v1.Merge(v2)
+}
+
+// tombstoneFor returns the tombstone that deletes a stored value, or nil if it's already deleted
+func tombstoneFor(v Value) (Value, error) {
+	if t, isT := v.(Tombstone); isT {
+		deleted, err := t.IsDeleted()
+		if err != nil || deleted {
+			return nil, err
+		}
+		return Tombstone{t.value, t.value}, nil
+	}
+	return Tombstone{v, v}, nil
+}
+
+// visibleValue returns what a reader should see for a stored value, which is nil for deleted keys
+func visibleValue(v Value) (Value, error) {
+	if t, isT := v.(Tombstone); isT {
+		deleted, err := t.IsDeleted()
+		if err != nil || deleted {
+			return nil, err
+		}
+		return t.value, nil
+	}
+	return v, nil
+}
@@ -0,0 +1,186 @@
+package mst
+
+import (
+	"bytes"
+	"crypto"
+	"errors"
+	"io"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func mustBeDeleted(t *testing.T, tombstone Tombstone) bool {
+	deleted, err := tombstone.IsDeleted()
+	require.NoError(t, err)
+	return deleted
+}
+
+func mustBeVisible(t *testing.T, v Value) Value {
+	visible, err := visibleValue(v)
+	require.NoError(t, err)
+	return visible
+}
+
+// unwritableValue is a value that fails to be written, as one that wraps a broken encoder might
+type unwritableValue struct{}
+
+func (v unwritableValue) Write(w io.Writer) error {
+	return errors.New("Can't write value")
+}
+
+func (v unwritableValue) Merge(with Value) Value {
+	return v
+}
+
+func TestTombstoneIsDeleted(t *testing.T) {
+	assert.True(t, mustBeDeleted(t, NewTombstone(UInt32(5), UInt32(5))))
+	assert.False(t, mustBeDeleted(t, NewTombstone(UInt32(7), UInt32(5))))
+}
+
+func TestTombstoneUnwritableValue(t *testing.T) {
+	tombstone := NewTombstone(unwritableValue{}, UInt32(5))
+	_, err := tombstone.IsDeleted()
+	assert.Error(t, err)
+	_, err = visibleValue(tombstone)
+	assert.Error(t, err)
+	_, err = tombstoneFor(tombstone)
+	assert.Error(t, err)
+	_, err = changeOf(UInt32(1), tombstone, UInt32(5))
+	assert.Error(t, err)
+}
+
+func TestTombstoneMergeOlderWrite(t *testing.T) {
+	merged := mergeValues(NewTombstone(UInt32(5), UInt32(5)), UInt32(3))
+	assert.Equal(t, NewTombstone(UInt32(5), UInt32(5)), merged)
+	merged = mergeValues(UInt32(3), NewTombstone(UInt32(5), UInt32(5)))
+	assert.Equal(t, NewTombstone(UInt32(5), UInt32(5)), merged)
+	assert.Nil(t, mustBeVisible(t, merged))
+}
+
+func TestTombstoneMergeNewerWrite(t *testing.T) {
+	merged := mergeValues(NewTombstone(UInt32(5), UInt32(5)), UInt32(7))
+	assert.Equal(t, NewTombstone(UInt32(7), UInt32(5)), merged)
+	assert.Equal(t, UInt32(7), mustBeVisible(t, merged))
+}
+
+func genTombstoneOrValue() Value {
+	value := UInt32(rand.Uint32() % 100)
+	if rand.Intn(2) == 0 {
+		return value
+	}
+	deleted := UInt32(rand.Uint32() % 100)
+	if value < deleted {
+		value, deleted = deleted, value
+	}
+	return NewTombstone(value, deleted)
+}
+
+func assertValuesEqual(t *testing.T, expected Value, actual Value) {
+	expectedBytes, err := writableBytes(expected)
+	require.NoError(t, err)
+	actualBytes, err := writableBytes(actual)
+	require.NoError(t, err)
+	assert.True(t, bytes.Equal(expectedBytes, actualBytes), "%v != %v", expected, actual)
+}
+
+func TestTombstoneMergeLaws(t *testing.T) {
+	rand.Seed(42)
+	for i := 0; i < 1000; i++ {
+		a, b, c := genTombstoneOrValue(), genTombstoneOrValue(), genTombstoneOrValue()
+		assertValuesEqual(t, mergeValues(a, b), mergeValues(b, a))
+		assertValuesEqual(t, mergeValues(mergeValues(a, b), c), mergeValues(a, mergeValues(b, c)))
+		assertValuesEqual(t, mergeValues(a, a), a)
+	}
+}
+
+func TestMSTDelete(t *testing.T) {
+	index := NewLocalMST(Base4, crypto.SHA256)
+	for i := 0; i < 100; i++ {
+		index = mustPut(t, index, UInt32(i), UInt32(i))
+	}
+	for i := 0; i < 100; i += 2 {
+		index = mustDelete(t, index, UInt32(i))
+	}
+	for i := 0; i < 100; i++ {
+		if i%2 == 0 {
+			assert.Nil(t, mustGet(t, index, UInt32(i)))
+		} else {
+			assert.Equal(t, UInt32(i), mustGet(t, index, UInt32(i)))
+		}
+	}
+	assertNoLeakedNodes(t, index)
+}
+
+func TestMSTDeleteMissing(t *testing.T) {
+	index := NewLocalMST(Base4, crypto.SHA256)
+	index = mustPut(t, index, UInt32(1), UInt32(1))
+	deleted := mustDelete(t, index, UInt32(2))
+	assert.Equal(t, index.RootHash(), deleted.RootHash())
+
+	// Deleting twice is the same as deleting once
+	deleted = mustDelete(t, index, UInt32(1))
+	deletedTwice := mustDelete(t, deleted, UInt32(1))
+	assert.Equal(t, deleted.RootHash(), deletedTwice.RootHash())
+}
+
+func TestMSTPutAfterDelete(t *testing.T) {
+	index := NewLocalMST(Base4, crypto.SHA256)
+	index = mustPut(t, index, UInt32(1), UInt32(5))
+	index = mustDelete(t, index, UInt32(1))
+
+	// UInt32 merges by max so a smaller value is older than the delete
+	older := mustPut(t, index, UInt32(1), UInt32(3))
+	assert.Nil(t, mustGet(t, older, UInt32(1)))
+
+	newer := mustPut(t, index, UInt32(1), UInt32(7))
+	assert.Equal(t, UInt32(7), mustGet(t, newer, UInt32(1)))
+
+	// And it can be deleted again
+	newer = mustDelete(t, newer, UInt32(1))
+	assert.Nil(t, mustGet(t, newer, UInt32(1)))
+}
+
+func TestMSTMergeDelete(t *testing.T) {
+	lInd := NewLocalMST(Base4, crypto.SHA256)
+	for i := 0; i < 100; i++ {
+		lInd = mustPut(t, lInd, UInt32(i), UInt32(i))
+	}
+	rInd := lInd
+	for i := 0; i < 100; i += 3 {
+		lInd = mustDelete(t, lInd, UInt32(i))
+	}
+	// The right tree writes a newer value for one of the deleted keys
+	rInd = mustPut(t, rInd, UInt32(3), UInt32(1000))
+
+	lrInd := mustMerge(t, lInd, rInd)
+	rlInd := mustMerge(t, rInd, lInd)
+	assert.Equal(t, lrInd.RootHash(), rlInd.RootHash())
+	for i := 0; i < 100; i++ {
+		expected := Value(UInt32(i))
+		if i == 3 {
+			expected = UInt32(1000)
+		} else if i%3 == 0 {
+			expected = nil
+		}
+		assert.Equal(t, expected, mustGet(t, lrInd, UInt32(i)))
+		assert.Equal(t, expected, mustGet(t, rlInd, UInt32(i)))
+	}
+	assertNoLeakedNodes(t, lrInd)
+	assertNoLeakedNodes(t, rlInd)
+}
+
+func TestMSTMergeDeleteUnseen(t *testing.T) {
+	// A tree that never saw the key at all shouldn't bring it back either
+	lInd := NewLocalMST(Base4, crypto.SHA256)
+	lInd = mustPut(t, lInd, UInt32(1), UInt32(1))
+	lInd = mustDelete(t, lInd, UInt32(1))
+	rInd := NewLocalMST(Base4, crypto.SHA256)
+	rInd = mustPut(t, rInd, UInt32(2), UInt32(2))
+
+	mInd := mustMerge(t, rInd, lInd)
+	assert.Nil(t, mustGet(t, mInd, UInt32(1)))
+	assert.Equal(t, UInt32(2), mustGet(t, mInd, UInt32(2)))
+}
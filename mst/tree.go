@@ -4,15 +4,17 @@ import (
 	"bytes"
 	"crypto"
 	"encoding/hex"
+	"errors"
 	"fmt"
 	"strings"
 )
 
 type MerkleSearchTree struct {
-	root  []byte
-	base  Base
-	hash  crypto.Hash
-	store NodeStore
+	root       []byte
+	base       Base
+	hash       crypto.Hash
+	store      NodeStore
+	aggregator Aggregator
 }
 
 func NewLocalMST(base Base, hash crypto.Hash) *MerkleSearchTree {
@@ -43,28 +45,30 @@ func (t *MerkleSearchTree) NodeStore() NodeStore {
 
 func (t *MerkleSearchTree) WithNodeStore(store NodeStore) *MerkleSearchTree {
 	return &MerkleSearchTree{
-		root:  t.root,
-		base:  t.base,
-		hash:  t.hash,
-		store: store,
+		root:       t.root,
+		base:       t.base,
+		hash:       t.hash,
+		store:      store,
+		aggregator: t.aggregator,
 	}
 }
 
 func (t *MerkleSearchTree) WithRoot(root []byte) *MerkleSearchTree {
 	return &MerkleSearchTree{
-		root:  root,
-		base:  t.base,
-		hash:  t.hash,
-		store: t.store,
+		root:       root,
+		base:       t.base,
+		hash:       t.hash,
+		store:      t.store,
+		aggregator: t.aggregator,
 	}
 }
 
-func (t *MerkleSearchTree) getNodeMaybe(hash []byte, store NodeStore) *Node {
-	res := t.store.Get(hash)
-	if res == nil {
-		res = store.Get(hash)
+func (t *MerkleSearchTree) getNodeMaybe(hash []byte, store NodeStore) (*Node, error) {
+	res, err := t.store.Get(hash)
+	if errors.Is(err, ErrNodeNotFound) {
+		return store.Get(hash)
 	}
-	return res
+	return res, err
 }
 
 // Gets nodes from t.store but modifies store
@@ -72,21 +76,30 @@ func (t *MerkleSearchTree) split(
 	store NodeStore,
 	nodeHash []byte,
 	key Key,
-) (NodeStore, []byte, []byte) {
+) (NodeStore, []byte, []byte, error) {
 	if nodeHash == nil {
-		return store, nil, nil
+		return store, nil, nil, nil
+	}
+	n, err := t.getNodeMaybe(nodeHash, store)
+	if err != nil {
+		return nil, nil, nil, err
 	}
-	n := t.getNodeMaybe(nodeHash, store)
 	child, i := n.findChild(key)
 	if i > 0 && keysEqual(key, n.children[i-1].key) {
 		panic(fmt.Errorf("Trying to get split node but key matches. Key: %v, Level: %d", key, n.level))
 	}
-	store = store.Remove(nodeHash)
+	store, err = store.Remove(nodeHash)
+	if err != nil {
+		return nil, nil, nil, err
+	}
 	lChildren := make([]Child, i)
 	rChildren := make([]Child, uint(len(n.children))-i)
 	copy(lChildren, n.children[:i])
 	copy(rChildren, n.children[i:])
-	store, l, r := t.split(store, child, key)
+	store, l, r, err := t.split(store, child, key)
+	if err != nil {
+		return nil, nil, nil, err
+	}
 	var lHash, rHash []byte
 	if len(lChildren) == 0 {
 		lHash = l
@@ -97,7 +110,10 @@ func (t *MerkleSearchTree) split(
 			children: lChildren,
 		}
 		lNode = lNode.withHashAt(l, i)
-		store, lHash = store.Put(lNode)
+		store, lHash, err = t.putNode(store, lNode)
+		if err != nil {
+			return nil, nil, nil, err
+		}
 	}
 	if len(rChildren) == 0 {
 		rHash = r
@@ -107,9 +123,12 @@ func (t *MerkleSearchTree) split(
 			low:      r,
 			children: rChildren,
 		}
-		store, rHash = store.Put(rNode)
+		store, rHash, err = t.putNode(store, rNode)
+		if err != nil {
+			return nil, nil, nil, err
+		}
 	}
-	return store, lHash, rHash
+	return store, lHash, rHash, nil
 }
 
 func (t *MerkleSearchTree) leadingZeros(key Key) uint32 {
@@ -122,7 +141,7 @@ func (t *MerkleSearchTree) put(
 	key Key,
 	val Value,
 	atLevel uint32,
-) (NodeStore, []byte) {
+) (NodeStore, []byte, error) {
 	var newNode *Node = nil
 	if nodeHash == nil {
 		newNode = &Node{
@@ -131,26 +150,44 @@ func (t *MerkleSearchTree) put(
 			children: []Child{{key, val, nil}},
 		}
 	} else {
-		n := store.Get(nodeHash)
+		n, err := store.Get(nodeHash)
+		if err != nil {
+			return nil, nil, err
+		}
 		if atLevel < n.level {
-			store = store.Remove(nodeHash)
+			store, err = store.Remove(nodeHash)
+			if err != nil {
+				return nil, nil, err
+			}
 			childHash, i := n.findChild(key)
-			store, childHash = t.put(store, childHash, key, val, atLevel)
+			store, childHash, err = t.put(store, childHash, key, val, atLevel)
+			if err != nil {
+				return nil, nil, err
+			}
 			newNode = n.withHashAt(childHash, i)
 		} else if atLevel == n.level {
-			store = store.Remove(nodeHash)
+			store, err = store.Remove(nodeHash)
+			if err != nil {
+				return nil, nil, err
+			}
 			i := n.find(key)
 			if i > 0 && keysEqual(key, n.children[i-1].key) {
 				newNode = n.withMergedValueAt(val, i-1)
 			} else {
 				var l, r []byte
-				store, l, r = t.split(store, n.childAt(i), key)
+				store, l, r, err = t.split(store, n.childAt(i), key)
+				if err != nil {
+					return nil, nil, err
+				}
 				newNode = n.withChildInsertedAt(key, val, r, i)
 				newNode = newNode.withHashAt(l, i)
 			}
 		} else {
 			var l, r []byte
-			store, l, r = t.split(store, nodeHash, key)
+			store, l, r, err = t.split(store, nodeHash, key)
+			if err != nil {
+				return nil, nil, err
+			}
 			newNode = &Node{
 				level:    atLevel,
 				low:      l,
@@ -158,19 +195,22 @@ func (t *MerkleSearchTree) put(
 			}
 		}
 	}
-	return store.Put(newNode)
+	return t.putNode(store, newNode)
 }
 
-func (t *MerkleSearchTree) get(nodeHash []byte, key Key) Value {
+func (t *MerkleSearchTree) get(nodeHash []byte, key Key) (Value, error) {
 	if nodeHash == nil {
-		return nil
+		return nil, nil
+	}
+	n, err := t.store.Get(nodeHash)
+	if err != nil {
+		return nil, err
 	}
-	n := t.store.Get(nodeHash)
 	i := n.find(key)
 	var recurNode []byte = nil
 	if i > 0 {
 		if keysEqual(key, n.children[i-1].key) {
-			return n.children[i-1].value
+			return n.children[i-1].value, nil
 		}
 		recurNode = n.children[i-1].high
 	} else {
@@ -184,23 +224,39 @@ func (t *MerkleSearchTree) merge(
 	store NodeStore,
 	l []byte,
 	r []byte,
-) (NodeStore, []byte) {
+) (NodeStore, []byte, error) {
 	if l == nil && r != nil {
-		// Recursively insert entire subtree into store
-		rNode := with.store.Get(r)
-		store, _ = t.merge(with, store, nil, rNode.low)
+		// Recursively insert entire subtree into store. The subtree may be the result of splitting
+		// a node of with, in which case it's only in store.
+		rNode, err := with.getNodeMaybe(r, store)
+		if err != nil {
+			return nil, nil, err
+		}
+		store, _, err = t.merge(with, store, nil, rNode.low)
+		if err != nil {
+			return nil, nil, err
+		}
 		for _, rChild := range rNode.children {
-			store, _ = t.merge(with, store, nil, rChild.high)
+			store, _, err = t.merge(with, store, nil, rChild.high)
+			if err != nil {
+				return nil, nil, err
+			}
 		}
-		return store.Put(rNode)
+		return t.putNode(store, rNode)
 	} else if r == nil || bytes.Equal(l, r) {
-		return store, l
+		return store, l, nil
 	}
 
-	lNode := store.Get(l)
+	lNode, err := store.Get(l)
+	if err != nil {
+		return nil, nil, err
+	}
 	// When we split in certain cases, the split results are only added to t.store and not store
 	// (since we never want to mutate with.store).
-	rNode := with.getNodeMaybe(r, store)
+	rNode, err := with.getNodeMaybe(r, store)
+	if err != nil {
+		return nil, nil, err
+	}
 
 	var level uint32
 	var lLow, rLow []byte = l, r
@@ -226,21 +282,27 @@ func (t *MerkleSearchTree) merge(
 	for i := 0; lCur <= lN && rCur <= rN; i++ {
 		var nextNode, interNode []byte = nil, nil
 		if lCur == lN && rCur == rN {
-			store, nextNode = t.merge(with, store, lLow, rLow)
+			store, nextNode, err = t.merge(with, store, lLow, rLow)
 			lCur++
 			rCur++
 		} else if lCur == lN {
 			rChild := rNode.children[rCur]
 			children = append(children, Child{rChild.key, rChild.value, nil})
-			store, interNode, lLow = t.split(store, lLow, rChild.key)
-			store, nextNode = t.merge(with, store, interNode, rLow)
+			store, interNode, lLow, err = t.split(store, lLow, rChild.key)
+			if err != nil {
+				return nil, nil, err
+			}
+			store, nextNode, err = t.merge(with, store, interNode, rLow)
 			rLow = rChild.high
 			rCur++
 		} else if rCur == rN {
 			lChild := lNode.children[lCur]
 			children = append(children, Child{lChild.key, lChild.value, nil})
-			store, interNode, rLow = with.split(store, rLow, lChild.key)
-			store, nextNode = t.merge(with, store, lLow, interNode)
+			store, interNode, rLow, err = with.split(store, rLow, lChild.key)
+			if err != nil {
+				return nil, nil, err
+			}
+			store, nextNode, err = t.merge(with, store, lLow, interNode)
 			lLow = lChild.high
 			lCur++
 		} else {
@@ -248,19 +310,25 @@ func (t *MerkleSearchTree) merge(
 			rChild := rNode.children[rCur]
 			if lChild.key.Less(rChild.key) {
 				children = append(children, Child{lChild.key, lChild.value, nil})
-				store, interNode, rLow = with.split(store, rLow, lChild.key)
-				store, nextNode = t.merge(with, store, lLow, interNode)
+				store, interNode, rLow, err = with.split(store, rLow, lChild.key)
+				if err != nil {
+					return nil, nil, err
+				}
+				store, nextNode, err = t.merge(with, store, lLow, interNode)
 				lLow = lChild.high
 				lCur++
 			} else if rChild.key.Less(lChild.key) {
 				children = append(children, Child{rChild.key, rChild.value, nil})
-				store, interNode, lLow = t.split(store, lLow, rChild.key)
-				store, nextNode = t.merge(with, store, interNode, rLow)
+				store, interNode, lLow, err = t.split(store, lLow, rChild.key)
+				if err != nil {
+					return nil, nil, err
+				}
+				store, nextNode, err = t.merge(with, store, interNode, rLow)
 				rLow = rChild.high
 				rCur++
 			} else {
-				store, nextNode = t.merge(with, store, lLow, rLow)
-				mergedValue := lChild.value.Merge(rChild.value)
+				store, nextNode, err = t.merge(with, store, lLow, rLow)
+				mergedValue := mergeValues(lChild.value, rChild.value)
 				children = append(children, Child{lChild.key, mergedValue, nil})
 				lLow = lChild.high
 				rLow = rChild.high
@@ -268,6 +336,9 @@ func (t *MerkleSearchTree) merge(
 				rCur++
 			}
 		}
+		if err != nil {
+			return nil, nil, err
+		}
 		if i == 0 {
 			low = nextNode
 		} else {
@@ -283,57 +354,106 @@ func (t *MerkleSearchTree) merge(
 		// In this specific case we want to clean up the left node from the store
 		// because the left tree was at a higher level and it will be replaced by
 		// the node we are creating below.
-		store = store.Remove(l)
+		store, err = store.Remove(l)
+		if err != nil {
+			return nil, nil, err
+		}
 	}
-	return store.Put(&Node{
+	return t.putNode(store, &Node{
 		level:    level,
 		low:      low,
 		children: children,
 	})
 }
 
-func (t *MerkleSearchTree) printInOrder(nodeHash []byte, height uint32) {
+func (t *MerkleSearchTree) printInOrder(nodeHash []byte, height uint32) error {
 	if nodeHash == nil {
-		return
+		return nil
 	}
 	fmt.Printf("%s\n", hex.EncodeToString(nodeHash))
-	n := t.store.Get(nodeHash)
-	t.printInOrder(n.low, height)
+	n, err := t.store.Get(nodeHash)
+	if err != nil {
+		return err
+	}
+	err = t.printInOrder(n.low, height)
+	if err != nil {
+		return err
+	}
 	for _, child := range n.children {
 		fmt.Printf("%s%v -> %v\n", strings.Repeat("\t", int(height-n.level)), child.key, child.value)
-		t.printInOrder(child.high, height)
+		err = t.printInOrder(child.high, height)
+		if err != nil {
+			return err
+		}
 	}
+	return nil
 }
 
-func (t *MerkleSearchTree) numNodes(n []byte) uint {
+func (t *MerkleSearchTree) numNodes(n []byte) (uint, error) {
 	if n == nil {
-		return 0
+		return 0, nil
+	}
+	node, err := t.store.Get(n)
+	if err != nil {
+		return 0, err
+	}
+	nNodes, err := t.numNodes(node.low)
+	if err != nil {
+		return 0, err
 	}
-	node := t.store.Get(n)
-	nNodes := t.numNodes(node.low)
 	for _, child := range node.children {
-		nNodes += t.numNodes(child.high)
+		nChildNodes, err := t.numNodes(child.high)
+		if err != nil {
+			return 0, err
+		}
+		nNodes += nChildNodes
 	}
-	return nNodes + 1
+	return nNodes + 1, nil
 }
 
 func (t *MerkleSearchTree) withStoreAndRoot(store NodeStore, root []byte) *MerkleSearchTree {
 	return &MerkleSearchTree{
-		root:  root,
-		base:  t.base,
-		hash:  t.hash,
-		store: store,
+		root:       root,
+		base:       t.base,
+		hash:       t.hash,
+		store:      store,
+		aggregator: t.aggregator,
 	}
 }
 
-func (t *MerkleSearchTree) Put(key Key, val Value) *MerkleSearchTree {
+func (t *MerkleSearchTree) Put(key Key, val Value) (*MerkleSearchTree, error) {
 	atLevel := t.leadingZeros(key)
-	newStore, newRoot := t.put(t.store, t.root, key, val, atLevel)
-	return t.withStoreAndRoot(newStore, newRoot)
+	newStore, newRoot, err := t.put(t.store, t.root, key, val, atLevel)
+	if err != nil {
+		return nil, err
+	}
+	return t.withStoreAndRoot(newStore, newRoot), nil
 }
 
-func (t *MerkleSearchTree) Get(key Key) Value {
-	return t.get(t.root, key)
+// Delete marks key as deleted by writing a tombstone over its current value. Deleting a key that
+// doesn't exist or is already deleted leaves the tree unchanged.
+func (t *MerkleSearchTree) Delete(key Key) (*MerkleSearchTree, error) {
+	val, err := t.get(t.root, key)
+	if err != nil {
+		return nil, err
+	}
+	if val == nil {
+		return t, nil
+	}
+	tombstone, err := tombstoneFor(val)
+	if err != nil || tombstone == nil {
+		return t, err
+	}
+	return t.Put(key, tombstone)
+}
+
+// Get returns the value for key, or nil if the key doesn't exist or has been deleted
+func (t *MerkleSearchTree) Get(key Key) (Value, error) {
+	val, err := t.get(t.root, key)
+	if err != nil {
+		return nil, err
+	}
+	return visibleValue(val)
 }
 
 func (t *MerkleSearchTree) Merge(with *MerkleSearchTree) (*MerkleSearchTree, error) {
@@ -341,22 +461,43 @@ func (t *MerkleSearchTree) Merge(with *MerkleSearchTree) (*MerkleSearchTree, err
 		return nil, fmt.Errorf("Mismatching bases. 2^%d vs 2^%d", t.base, with.base)
 	} else if t.hash != with.hash {
 		return t, fmt.Errorf("Mismatching hash functions. %s vs %s", t.hash, with.hash)
+	} else if aggregatorName(t.aggregator) != aggregatorName(with.aggregator) {
+		return nil, fmt.Errorf(
+			"Mismatching aggregators. %q vs %q",
+			aggregatorName(t.aggregator),
+			aggregatorName(with.aggregator),
+		)
+	}
+	newStore, newRoot, err := t.merge(with, t.store, t.root, with.root)
+	if err != nil {
+		return nil, err
 	}
-	newStore, newRoot := t.merge(with, t.store, t.root, with.root)
 	return t.withStoreAndRoot(newStore, newRoot), nil
 }
 
-func (t *MerkleSearchTree) PrintInOrder() {
+func (t *MerkleSearchTree) PrintInOrder() error {
 	if t.root != nil {
-		height := t.store.Get(t.root).level
-		t.printInOrder(t.root, height)
+		root, err := t.store.Get(t.root)
+		if err != nil {
+			return err
+		}
+		return t.printInOrder(t.root, root.level)
 	}
+	return nil
 }
 
 func (t *MerkleSearchTree) RootHash() []byte {
 	return t.root
 }
 
-func (t *MerkleSearchTree) NumNodes() uint {
+func (t *MerkleSearchTree) Base() Base {
+	return t.base
+}
+
+func (t *MerkleSearchTree) Hash() crypto.Hash {
+	return t.hash
+}
+
+func (t *MerkleSearchTree) NumNodes() (uint, error) {
 	return t.numNodes(t.root)
 }
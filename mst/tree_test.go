@@ -2,12 +2,47 @@ package mst
 
 import (
 	"crypto"
+	"errors"
 	"math/rand"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
+func mustPut(t *testing.T, tree *MerkleSearchTree, key Key, val Value) *MerkleSearchTree {
+	newTree, err := tree.Put(key, val)
+	require.NoError(t, err)
+	return newTree
+}
+
+func mustGet(t *testing.T, tree *MerkleSearchTree, key Key) Value {
+	val, err := tree.Get(key)
+	require.NoError(t, err)
+	return val
+}
+
+func mustDelete(t *testing.T, tree *MerkleSearchTree, key Key) *MerkleSearchTree {
+	newTree, err := tree.Delete(key)
+	require.NoError(t, err)
+	return newTree
+}
+
+func mustMerge(t *testing.T, tree *MerkleSearchTree, with *MerkleSearchTree) *MerkleSearchTree {
+	newTree, err := tree.Merge(with)
+	require.NoError(t, err)
+	return newTree
+}
+
+// Checks that the store of a tree doesn't hold any nodes that aren't reachable from its root
+func assertNoLeakedNodes(t *testing.T, tree *MerkleSearchTree) {
+	size, err := tree.store.Size()
+	require.NoError(t, err)
+	numNodes, err := tree.NumNodes()
+	require.NoError(t, err)
+	assert.Equal(t, size, numNodes)
+}
+
 func genKeyVal(keyMod int) (UInt32, UInt32) {
 	key := UInt32(rand.Uint32() % uint32(keyMod))
 	val := UInt32(rand.Uint32())
@@ -21,19 +56,19 @@ func putAndGetRunner(t *testing.T, base Base, iters, elems, keyMod int) {
 		collected := map[UInt32]Value{}
 		for j := 0; j < elems; j++ {
 			key, val := genKeyVal(keyMod)
-			index = index.Put(key, val)
+			index = mustPut(t, index, key, val)
 			if oVal, exists := collected[key]; exists {
 				collected[key] = val.Merge(oVal)
 			} else {
 				collected[key] = val
 			}
-			assert.Equal(t, index.Get(key), collected[key])
+			assert.Equal(t, mustGet(t, index, key), collected[key])
 		}
 
 		for key, val := range collected {
-			assert.Equal(t, index.Get(key), val)
+			assert.Equal(t, mustGet(t, index, key), val)
 		}
-		assert.Equal(t, index.store.Size(), index.NumNodes())
+		assertNoLeakedNodes(t, index)
 	}
 }
 
@@ -55,7 +90,7 @@ func mergeRunner(t *testing.T, base Base, iters, elems, keyMod int) {
 		mCollected := map[UInt32]Value{}
 		for j := 0; j < elems; j++ {
 			key, val := genKeyVal(keyMod)
-			lInd = lInd.Put(key, val)
+			lInd = mustPut(t, lInd, key, val)
 			if oVal, exists := mCollected[key]; exists {
 				mCollected[key] = val.Merge(oVal)
 			} else {
@@ -67,7 +102,7 @@ func mergeRunner(t *testing.T, base Base, iters, elems, keyMod int) {
 				lCollected[key] = val
 			}
 			key, val = genKeyVal(keyMod)
-			rInd = rInd.Put(key, val)
+			rInd = mustPut(t, rInd, key, val)
 			if oVal, exists := mCollected[key]; exists {
 				mCollected[key] = val.Merge(oVal)
 			} else {
@@ -84,18 +119,18 @@ func mergeRunner(t *testing.T, base Base, iters, elems, keyMod int) {
 		assert.NoError(t, err)
 
 		for key, val := range lCollected {
-			assert.Equal(t, lInd.Get(key), val)
+			assert.Equal(t, mustGet(t, lInd, key), val)
 		}
 		for key, val := range rCollected {
-			assert.Equal(t, rInd.Get(key), val)
+			assert.Equal(t, mustGet(t, rInd, key), val)
 		}
 		for key, val := range mCollected {
-			assert.Equal(t, mInd.Get(key), val)
+			assert.Equal(t, mustGet(t, mInd, key), val)
 		}
 
-		assert.Equal(t, lInd.store.Size(), lInd.NumNodes())
-		assert.Equal(t, rInd.store.Size(), rInd.NumNodes())
-		assert.Equal(t, mInd.store.Size(), mInd.NumNodes())
+		assertNoLeakedNodes(t, lInd)
+		assertNoLeakedNodes(t, rInd)
+		assertNoLeakedNodes(t, mInd)
 	}
 }
 
@@ -113,11 +148,11 @@ func TestMSTMergeAnecdotal(t *testing.T) {
 	lInd := NewLocalMST(Base16, crypto.SHA256)
 	rInd := NewLocalMST(Base16, crypto.SHA256)
 	for i := 0; i < 437; i++ {
-		lInd = lInd.Put(UInt32(i), UInt32(i+25))
-		rInd = rInd.Put(UInt32(i), UInt32(i+25))
+		lInd = mustPut(t, lInd, UInt32(i), UInt32(i+25))
+		rInd = mustPut(t, rInd, UInt32(i), UInt32(i+25))
 	}
 	for i := 437; i < 443; i++ {
-		rInd = rInd.Put(UInt32(i), UInt32(i+25))
+		rInd = mustPut(t, rInd, UInt32(i), UInt32(i+25))
 	}
 	mInd, err := lInd.Merge(rInd)
 	assert.NoError(t, err)
@@ -132,9 +167,26 @@ func TestMSTMergeAnecdotal(t *testing.T) {
 	// fmt.Printf("%d\n", mInd.store.Size())
 	// mInd.PrintInOrder()
 
-	assert.Equal(t, lInd.store.Size(), lInd.NumNodes())
-	assert.Equal(t, rInd.store.Size(), rInd.NumNodes())
-	assert.Equal(t, mInd.store.Size(), mInd.NumNodes())
+	assertNoLeakedNodes(t, lInd)
+	assertNoLeakedNodes(t, rInd)
+	assertNoLeakedNodes(t, mInd)
+}
+
+func TestMSTMergeSplitSubtree(t *testing.T) {
+	// 5 is a level above 4, so merging splits the right tree's only node at 5 and then has to copy
+	// the half above 5, which only exists in the merged store
+	lInd := NewLocalMST(Base2, crypto.SHA256)
+	lInd = mustPut(t, lInd, UInt32(4), UInt32(1))
+	lInd = mustPut(t, lInd, UInt32(5), UInt32(1))
+	rInd := NewLocalMST(Base2, crypto.SHA256)
+	rInd = mustPut(t, rInd, UInt32(8), UInt32(1))
+	rInd = mustPut(t, rInd, UInt32(3), UInt32(1))
+
+	mInd := mustMerge(t, lInd, rInd)
+	for _, key := range []UInt32{3, 4, 5, 8} {
+		assert.Equal(t, UInt32(1), mustGet(t, mInd, key))
+	}
+	assert.Equal(t, mInd.RootHash(), mustMerge(t, rInd, lInd).RootHash())
 }
 
 func TestMSTMergeConsecutive(t *testing.T) {
@@ -142,8 +194,8 @@ func TestMSTMergeConsecutive(t *testing.T) {
 	lInd := NewLocalMST(Base32, crypto.SHA256)
 	rInd := NewLocalMST(Base32, crypto.SHA256)
 	for i := 0; i < 50; i++ {
-		lInd = lInd.Put(UInt32(i), UInt32(i))
-		rInd = rInd.Put(UInt32(i+25), UInt32(i+50))
+		lInd = mustPut(t, lInd, UInt32(i), UInt32(i))
+		rInd = mustPut(t, rInd, UInt32(i+25), UInt32(i+50))
 	}
 
 	mInd, err := lInd.Merge(rInd)
@@ -151,21 +203,21 @@ func TestMSTMergeConsecutive(t *testing.T) {
 
 	// Check originals are the same
 	for i := 0; i < 50; i++ {
-		assert.Equal(t, lInd.Get(UInt32(i)), UInt32(i))
-		assert.Equal(t, rInd.Get(UInt32(i+25)), UInt32(i+50))
+		assert.Equal(t, mustGet(t, lInd, UInt32(i)), UInt32(i))
+		assert.Equal(t, mustGet(t, rInd, UInt32(i+25)), UInt32(i+50))
 	}
 
 	// Check merged
 	for i := 0; i < 25; i++ {
-		assert.Equal(t, mInd.Get(UInt32(i)), UInt32(i))
+		assert.Equal(t, mustGet(t, mInd, UInt32(i)), UInt32(i))
 	}
 	for i := 25; i < 75; i++ {
-		assert.Equal(t, mInd.Get(UInt32(i)), UInt32(i+25))
+		assert.Equal(t, mustGet(t, mInd, UInt32(i)), UInt32(i+25))
 	}
 
-	assert.Equal(t, lInd.store.Size(), lInd.NumNodes())
-	assert.Equal(t, rInd.store.Size(), rInd.NumNodes())
-	assert.Equal(t, mInd.store.Size(), mInd.NumNodes())
+	assertNoLeakedNodes(t, lInd)
+	assertNoLeakedNodes(t, rInd)
+	assertNoLeakedNodes(t, mInd)
 
 	// Redo idk why but I did it before
 	mInd, err = rInd.Merge(lInd)
@@ -173,21 +225,21 @@ func TestMSTMergeConsecutive(t *testing.T) {
 
 	// Check originals are the same
 	for i := 0; i < 50; i++ {
-		assert.Equal(t, lInd.Get(UInt32(i)), UInt32(i))
-		assert.Equal(t, rInd.Get(UInt32(i+25)), UInt32(i+50))
+		assert.Equal(t, mustGet(t, lInd, UInt32(i)), UInt32(i))
+		assert.Equal(t, mustGet(t, rInd, UInt32(i+25)), UInt32(i+50))
 	}
 
 	// Check merged
 	for i := 0; i < 25; i++ {
-		assert.Equal(t, mInd.Get(UInt32(i)), UInt32(i))
+		assert.Equal(t, mustGet(t, mInd, UInt32(i)), UInt32(i))
 	}
 	for i := 25; i < 75; i++ {
-		assert.Equal(t, mInd.Get(UInt32(i)), UInt32(i+25))
+		assert.Equal(t, mustGet(t, mInd, UInt32(i)), UInt32(i+25))
 	}
 
-	assert.Equal(t, lInd.store.Size(), lInd.NumNodes())
-	assert.Equal(t, rInd.store.Size(), rInd.NumNodes())
-	assert.Equal(t, mInd.store.Size(), mInd.NumNodes())
+	assertNoLeakedNodes(t, lInd)
+	assertNoLeakedNodes(t, rInd)
+	assertNoLeakedNodes(t, mInd)
 }
 
 func TestMSTMergeDiffBase(t *testing.T) {
@@ -205,3 +257,45 @@ func TestMSTMergeDiffHash(t *testing.T) {
 	assert.Error(t, err)
 	assert.Equal(t, "Mismatching hash functions. SHA-512 vs SHA-256", err.Error())
 }
+
+func TestMSTPutStoreError(t *testing.T) {
+	store := &failingNodeStore{NewLocalNodeStore(crypto.SHA256), false, false}
+	index := NewMST(Base16, crypto.SHA256, store)
+	index = mustPut(t, index, UInt32(1), UInt32(1))
+	index.store = &failingNodeStore{index.store.(*failingNodeStore).NodeStore, true, false}
+	_, err := index.Put(UInt32(2), UInt32(2))
+	assert.Error(t, err)
+	assert.Equal(t, "Couldn't put node", err.Error())
+}
+
+func TestMSTGetStoreError(t *testing.T) {
+	store := &failingNodeStore{NewLocalNodeStore(crypto.SHA256), false, false}
+	index := NewMST(Base16, crypto.SHA256, store)
+	index = mustPut(t, index, UInt32(1), UInt32(1))
+	index.store = &failingNodeStore{index.store.(*failingNodeStore).NodeStore, false, true}
+	_, err := index.Get(UInt32(1))
+	assert.Error(t, err)
+	assert.Equal(t, "Couldn't get node", err.Error())
+	_, err = index.NumNodes()
+	assert.Error(t, err)
+}
+
+func TestMSTMergeStoreError(t *testing.T) {
+	lInd := NewLocalMST(Base16, crypto.SHA256)
+	rInd := NewLocalMST(Base16, crypto.SHA256)
+	for i := 0; i < 50; i++ {
+		lInd = mustPut(t, lInd, UInt32(i), UInt32(i))
+		rInd = mustPut(t, rInd, UInt32(i+25), UInt32(i))
+	}
+	rInd = rInd.WithNodeStore(&failingNodeStore{rInd.store, false, true})
+	_, err := lInd.Merge(rInd)
+	assert.Error(t, err)
+}
+
+func TestMSTGetMissingNode(t *testing.T) {
+	index := NewLocalMST(Base16, crypto.SHA256)
+	index = mustPut(t, index, UInt32(1), UInt32(1))
+	index = index.WithNodeStore(NewLocalNodeStore(crypto.SHA256))
+	_, err := index.Get(UInt32(1))
+	assert.True(t, errors.Is(err, ErrNodeNotFound))
+}
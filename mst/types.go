@@ -0,0 +1,192 @@
+package mst
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Bytes is a key that sorts by its raw bytes
+type Bytes []byte
+
+func (b Bytes) Less(than Key) bool {
+	return bytes.Compare(b, than.(Bytes)) < 0
+}
+
+func (b Bytes) Write(w io.Writer) error {
+	_, err := w.Write(b)
+	return err
+}
+
+func (b Bytes) HasPrefix(prefix Key) bool {
+	return bytes.HasPrefix(b, prefix.(Bytes))
+}
+
+type BytesKeyReader struct{}
+
+func (kr BytesKeyReader) FromBytes(b []byte) (Key, error) {
+	return Bytes(append([]byte{}, b...)), nil
+}
+
+// String is a key that sorts the same way Go compares strings, i.e. by its UTF-8 bytes
+type String string
+
+func (s String) Less(than Key) bool {
+	return s < than.(String)
+}
+
+func (s String) Write(w io.Writer) error {
+	_, err := io.WriteString(w, string(s))
+	return err
+}
+
+func (s String) HasPrefix(prefix Key) bool {
+	return strings.HasPrefix(string(s), string(prefix.(String)))
+}
+
+type StringKeyReader struct{}
+
+func (kr StringKeyReader) FromBytes(b []byte) (Key, error) {
+	return String(b), nil
+}
+
+// Int64 is a signed integer key. It's written big endian with the sign bit flipped so that its
+// bytes sort in the same order as the numbers themselves.
+type Int64 int64
+
+func (i Int64) Less(than Key) bool {
+	return i < than.(Int64)
+}
+
+func (i Int64) Write(w io.Writer) error {
+	buf := make([]byte, 8)
+	binary.BigEndian.PutUint64(buf, uint64(i)^(1<<63))
+	_, err := w.Write(buf)
+	return err
+}
+
+type Int64KeyReader struct{}
+
+func (kr Int64KeyReader) FromBytes(b []byte) (Key, error) {
+	if len(b) != 8 {
+		return nil, fmt.Errorf("Expected 8 bytes for Int64, got %d", len(b))
+	}
+	return Int64(binary.BigEndian.Uint64(b) ^ (1 << 63)), nil
+}
+
+// LWWBytes is a last-writer-wins register holding opaque bytes. Merging keeps the value with the
+// later timestamp, and ties are broken by comparing the bytes so that every replica picks the
+// same value.
+type LWWBytes struct {
+	timestamp uint64
+	value     []byte
+}
+
+func NewLWWBytes(timestamp uint64, value []byte) LWWBytes {
+	return LWWBytes{timestamp, value}
+}
+
+func (v LWWBytes) Timestamp() uint64 {
+	return v.timestamp
+}
+
+func (v LWWBytes) Value() []byte {
+	return v.value
+}
+
+func (v LWWBytes) Write(w io.Writer) error {
+	buf := make([]byte, 8)
+	binary.BigEndian.PutUint64(buf, v.timestamp)
+	_, err := w.Write(buf)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(v.value)
+	return err
+}
+
+func (v LWWBytes) Merge(with Value) Value {
+	other := with.(LWWBytes)
+	if v.timestamp > other.timestamp {
+		return v
+	} else if other.timestamp > v.timestamp {
+		return other
+	} else if bytes.Compare(v.value, other.value) >= 0 {
+		return v
+	}
+	return other
+}
+
+type LWWBytesValueReader struct{}
+
+func (vr LWWBytesValueReader) FromBytes(b []byte) (Value, error) {
+	if len(b) < 8 {
+		return nil, fmt.Errorf("Expected at least 8 bytes for LWWBytes, got %d", len(b))
+	}
+	return LWWBytes{binary.BigEndian.Uint64(b), append([]byte{}, b[8:]...)}, nil
+}
+
+// keyTypeKinds and valueTypeKinds make the readers of the types registered with RegisterKeyType
+// and RegisterValueType, by kind
+var keyTypeKinds = map[string]func(arg string) (KeyReader, error){}
+var valueTypeKinds = map[string]func(arg string) (ValueReader, error){}
+
+// RegisterKeyType adds a kind of key that this package doesn't know about. KeyReaderFor returns
+// newReader(arg) for names of the form "kind:arg", or "kind" with an empty arg. It's meant to be
+// called from init functions.
+func RegisterKeyType(kind string, newReader func(arg string) (KeyReader, error)) {
+	keyTypeKinds[kind] = newReader
+}
+
+// RegisterValueType adds a kind of value that this package doesn't know about, the same way
+// RegisterKeyType adds keys
+func RegisterValueType(kind string, newReader func(arg string) (ValueReader, error)) {
+	valueTypeKinds[kind] = newReader
+}
+
+// KeyReaderFor returns the reader for one of the built in key types or a registered one by name
+func KeyReaderFor(name string) (KeyReader, error) {
+	switch name {
+	case "uint32":
+		return UInt32KeyReader{}, nil
+	case "int64":
+		return Int64KeyReader{}, nil
+	case "string":
+		return StringKeyReader{}, nil
+	case "bytes":
+		return BytesKeyReader{}, nil
+	}
+	kind, arg := splitKind(name)
+	newReader, ok := keyTypeKinds[kind]
+	if !ok {
+		return nil, fmt.Errorf("Unknown key type %s", name)
+	}
+	kr, err := newReader(arg)
+	if err != nil {
+		return nil, fmt.Errorf("Invalid key type %s: %w", name, err)
+	}
+	return kr, nil
+}
+
+// ValueReaderFor returns the reader for one of the built in value types or a registered one by
+// name
+func ValueReaderFor(name string) (ValueReader, error) {
+	switch name {
+	case "uint32":
+		return UInt32ValueReader{}, nil
+	case "lww":
+		return LWWBytesValueReader{}, nil
+	}
+	kind, arg := splitKind(name)
+	newReader, ok := valueTypeKinds[kind]
+	if !ok {
+		return nil, fmt.Errorf("Unknown value type %s", name)
+	}
+	vr, err := newReader(arg)
+	if err != nil {
+		return nil, fmt.Errorf("Invalid value type %s: %w", name, err)
+	}
+	return vr, nil
+}
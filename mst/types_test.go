@@ -0,0 +1,84 @@
+package mst
+
+import (
+	"bytes"
+	"crypto"
+	"math"
+	"math/rand"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func roundTripKey(t *testing.T, kr KeyReader, k Key) Key {
+	b, err := writableBytes(k)
+	require.NoError(t, err)
+	read, err := kr.FromBytes(b)
+	require.NoError(t, err)
+	return read
+}
+
+func TestBuiltinKeysRoundTrip(t *testing.T) {
+	assert.Equal(t, Bytes{1, 2, 3}, roundTripKey(t, BytesKeyReader{}, Bytes{1, 2, 3}))
+	assert.Equal(t, String("héllo"), roundTripKey(t, StringKeyReader{}, String("héllo")))
+	for _, i := range []Int64{math.MinInt64, -1, 0, 1, math.MaxInt64} {
+		assert.Equal(t, i, roundTripKey(t, Int64KeyReader{}, i))
+	}
+	_, err := Int64KeyReader{}.FromBytes([]byte{1, 2})
+	assert.Error(t, err)
+}
+
+func TestInt64BytesSortLikeNumbers(t *testing.T) {
+	rand.Seed(42)
+	keys := []Int64{math.MinInt64, math.MaxInt64, 0, -1}
+	for i := 0; i < 100; i++ {
+		keys = append(keys, Int64(rand.Uint64()))
+	}
+	encoded := make([][]byte, len(keys))
+	for i, k := range keys {
+		b, err := writableBytes(k)
+		require.NoError(t, err)
+		encoded[i] = b
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i].Less(keys[j]) })
+	sort.Slice(encoded, func(i, j int) bool { return bytes.Compare(encoded[i], encoded[j]) < 0 })
+	for i, k := range keys {
+		assert.Equal(t, k, roundTripKey(t, Int64KeyReader{}, Bytes(encoded[i])).(Int64))
+	}
+}
+
+func TestLWWBytesMerge(t *testing.T) {
+	older := NewLWWBytes(1, []byte("b"))
+	newer := NewLWWBytes(2, []byte("a"))
+	assert.Equal(t, newer, older.Merge(newer))
+	assert.Equal(t, newer, newer.Merge(older))
+
+	// Ties are broken the same way on both sides
+	tie := NewLWWBytes(2, []byte("b"))
+	assert.Equal(t, tie, newer.Merge(tie))
+	assert.Equal(t, tie, tie.Merge(newer))
+
+	b, err := writableBytes(tie)
+	require.NoError(t, err)
+	read, err := LWWBytesValueReader{}.FromBytes(b)
+	require.NoError(t, err)
+	assert.Equal(t, tie, read)
+}
+
+func TestLWWBytesTree(t *testing.T) {
+	lInd := NewLocalMST(Base4, crypto.SHA256)
+	rInd := NewLocalMST(Base4, crypto.SHA256)
+	lInd = mustPut(t, lInd, String("a"), NewLWWBytes(1, []byte("left")))
+	rInd = mustPut(t, rInd, String("a"), NewLWWBytes(2, []byte("right")))
+	rInd = mustPut(t, rInd, String("b"), NewLWWBytes(1, []byte("only")))
+	merged := mustMerge(t, lInd, rInd)
+	assert.Equal(t, NewLWWBytes(2, []byte("right")), mustGet(t, merged, String("a")))
+	assert.Equal(t, NewLWWBytes(1, []byte("only")), mustGet(t, merged, String("b")))
+
+	merged = mustDelete(t, merged, String("a"))
+	assert.Nil(t, mustGet(t, merged, String("a")))
+	merged = mustPut(t, merged, String("a"), NewLWWBytes(3, []byte("again")))
+	assert.Equal(t, NewLWWBytes(3, []byte("again")), mustGet(t, merged, String("a")))
+}
@@ -0,0 +1,323 @@
+package query
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/vulturedb/vulture/core"
+)
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenIdent
+	tokenNumber
+	tokenString
+	tokenSymbol
+)
+
+type token struct {
+	kind tokenKind
+	text string
+	// Byte offset of the token in the query
+	pos int
+}
+
+func (t token) String() string {
+	switch t.kind {
+	case tokenEOF:
+		return "end of query"
+	case tokenString:
+		return quote(t.text)
+	default:
+		return strconv.Quote(t.text)
+	}
+}
+
+// quote writes s as a string literal of the query language
+func quote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
+
+var keywords = map[string]bool{
+	"SELECT": true,
+	"FROM":   true,
+	"WHERE":  true,
+	"AND":    true,
+	"ORDER":  true,
+	"BY":     true,
+	"ASC":    true,
+	"DESC":   true,
+	"LIMIT":  true,
+	"TRUE":   true,
+	"FALSE":  true,
+}
+
+func isIdentStart(c rune) bool {
+	return c == '_' || unicode.IsLetter(c)
+}
+
+func isIdentPart(c rune) bool {
+	return isIdentStart(c) || unicode.IsDigit(c)
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+// lex splits a query into tokens, ending with a tokenEOF
+func lex(text string) ([]token, error) {
+	tokens := []token{}
+	for i := 0; i < len(text); {
+		c, size := utf8.DecodeRuneInString(text[i:])
+		switch {
+		case unicode.IsSpace(c):
+			i += size
+		case isIdentStart(c):
+			start := i
+			for i < len(text) && isIdentPart(c) {
+				i += size
+				c, size = utf8.DecodeRuneInString(text[i:])
+			}
+			tokens = append(tokens, token{tokenIdent, text[start:i], start})
+		case isDigit(text[i]) || (c == '-' && i+1 < len(text) && isDigit(text[i+1])):
+			start := i
+			i++
+			for i < len(text) && isNumberPart(text, i) {
+				i++
+			}
+			tokens = append(tokens, token{tokenNumber, text[start:i], start})
+		case c == '\'':
+			start := i
+			var b strings.Builder
+			closed := false
+			for i++; i < len(text); i++ {
+				if text[i] != '\'' {
+					b.WriteByte(text[i])
+				} else if i+1 < len(text) && text[i+1] == '\'' {
+					b.WriteByte('\'')
+					i++
+				} else {
+					i++
+					closed = true
+					break
+				}
+			}
+			if !closed {
+				return nil, fmt.Errorf("Unterminated string at position %d", start)
+			}
+			tokens = append(tokens, token{tokenString, b.String(), start})
+		case c == '<' || c == '>':
+			start := i
+			i++
+			if i < len(text) && text[i] == '=' {
+				i++
+			}
+			tokens = append(tokens, token{tokenSymbol, text[start:i], start})
+		case strings.ContainsRune("*,=;", c):
+			tokens = append(tokens, token{tokenSymbol, text[i : i+1], i})
+			i++
+		default:
+			return nil, fmt.Errorf("Unexpected character %q at position %d", c, i)
+		}
+	}
+	return append(tokens, token{tokenEOF, "", len(text)}), nil
+}
+
+// isNumberPart returns whether the byte at i continues a number, which can have a fraction and an
+// exponent
+func isNumberPart(text string, i int) bool {
+	c := text[i]
+	switch {
+	case isDigit(c) || c == '.' || c == 'e' || c == 'E':
+		return true
+	case c == '+' || c == '-':
+		return text[i-1] == 'e' || text[i-1] == 'E'
+	default:
+		return false
+	}
+}
+
+type parser struct {
+	tokens []token
+	at     int
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.at]
+}
+
+func (p *parser) errorf(expected string) error {
+	t := p.peek()
+	return fmt.Errorf("Expected %s at position %d, got %s", expected, t.pos, t)
+}
+
+// keyword consumes the next token if it's the given keyword
+func (p *parser) keyword(word string) bool {
+	t := p.peek()
+	if t.kind == tokenIdent && strings.EqualFold(t.text, word) {
+		p.at++
+		return true
+	}
+	return false
+}
+
+func (p *parser) expectKeyword(word string) error {
+	if !p.keyword(word) {
+		return p.errorf(word)
+	}
+	return nil
+}
+
+// symbol consumes the next token if it's the given symbol
+func (p *parser) symbol(sym string) bool {
+	t := p.peek()
+	if t.kind == tokenSymbol && t.text == sym {
+		p.at++
+		return true
+	}
+	return false
+}
+
+func (p *parser) ident(what string) (string, error) {
+	t := p.peek()
+	if t.kind != tokenIdent || keywords[strings.ToUpper(t.text)] {
+		return "", p.errorf(what)
+	}
+	p.at++
+	return t.text, nil
+}
+
+// identList parses one or more comma separated identifiers
+func (p *parser) identList(what string) ([]string, error) {
+	idents := []string{}
+	for {
+		ident, err := p.ident(what)
+		if err != nil {
+			return nil, err
+		}
+		idents = append(idents, ident)
+		if !p.symbol(",") {
+			return idents, nil
+		}
+	}
+}
+
+func (p *parser) literal() (Literal, error) {
+	t := p.peek()
+	switch {
+	case t.kind == tokenNumber:
+		p.at++
+		return Literal{literalNumber, t.text}, nil
+	case t.kind == tokenString:
+		p.at++
+		return Literal{literalString, t.text}, nil
+	case p.keyword("TRUE"):
+		return Literal{literalBool, "true"}, nil
+	case p.keyword("FALSE"):
+		return Literal{literalBool, "false"}, nil
+	default:
+		return Literal{}, p.errorf("a value")
+	}
+}
+
+var ops = map[string]core.Op{
+	"=":  core.OpEq,
+	"<":  core.OpLt,
+	"<=": core.OpLe,
+	">":  core.OpGt,
+	">=": core.OpGe,
+}
+
+func (p *parser) condition() (Condition, error) {
+	field, err := p.ident("field name")
+	if err != nil {
+		return Condition{}, err
+	}
+	t := p.peek()
+	op, ok := ops[t.text]
+	if t.kind != tokenSymbol || !ok {
+		return Condition{}, p.errorf("comparison")
+	}
+	p.at++
+	value, err := p.literal()
+	if err != nil {
+		return Condition{}, err
+	}
+	return Condition{field, op, value}, nil
+}
+
+// Parse parses a query of the form
+//
+//	SELECT * | <field>, ... FROM <table>
+//	[WHERE <field> <op> <value> [AND ...]]
+//	[ORDER BY <field>, ... [ASC | DESC]]
+//	[LIMIT <n>]
+//
+// where op is one of =, <, <=, > and >=, and values are numbers, strings in single quotes or
+// booleans. Keywords are case insensitive and a trailing semicolon is allowed.
+func Parse(text string) (Query, error) {
+	tokens, err := lex(text)
+	if err != nil {
+		return Query{}, err
+	}
+	p := &parser{tokens: tokens}
+	q := Query{}
+	if err := p.expectKeyword("SELECT"); err != nil {
+		return Query{}, err
+	}
+	if !p.symbol("*") {
+		if q.Fields, err = p.identList("field name or *"); err != nil {
+			return Query{}, err
+		}
+	}
+	if err := p.expectKeyword("FROM"); err != nil {
+		return Query{}, err
+	}
+	if q.Table, err = p.ident("table name"); err != nil {
+		return Query{}, err
+	}
+	if p.keyword("WHERE") {
+		for {
+			cond, err := p.condition()
+			if err != nil {
+				return Query{}, err
+			}
+			q.Where = append(q.Where, cond)
+			if !p.keyword("AND") {
+				break
+			}
+		}
+	}
+	if p.keyword("ORDER") {
+		if err := p.expectKeyword("BY"); err != nil {
+			return Query{}, err
+		}
+		if q.OrderBy, err = p.identList("field name"); err != nil {
+			return Query{}, err
+		}
+		if p.keyword("DESC") {
+			q.Desc = true
+		} else {
+			p.keyword("ASC")
+		}
+	}
+	if p.keyword("LIMIT") {
+		t := p.peek()
+		limit, err := strconv.ParseUint(t.text, 10, 32)
+		if t.kind != tokenNumber || err != nil {
+			return Query{}, p.errorf("a limit")
+		}
+		p.at++
+		q.Limit = uint32(limit)
+	}
+	p.symbol(";")
+	if p.peek().kind != tokenEOF {
+		return Query{}, p.errorf("end of query")
+	}
+	return q, nil
+}
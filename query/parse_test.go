@@ -0,0 +1,79 @@
+package query
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/vulturedb/vulture/core"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		text  string
+		query Query
+	}{
+		{"SELECT * FROM users", Query{Table: "users"}},
+		{
+			"select id, name from users where age >= 18 and name < 'b''c' " +
+				"order by id desc limit 5;",
+			Query{
+				Table:  "users",
+				Fields: []string{"id", "name"},
+				Where: []Condition{
+					{"age", core.OpGe, Literal{literalNumber, "18"}},
+					{"name", core.OpLt, Literal{literalString, "b'c"}},
+				},
+				OrderBy: []string{"id"},
+				Desc:    true,
+				Limit:   5,
+			},
+		},
+		{
+			"SELECT x FROM t WHERE a=-1.5e3 AND b<=TRUE AND c>'' ORDER BY k, l ASC",
+			Query{
+				Table:  "t",
+				Fields: []string{"x"},
+				Where: []Condition{
+					{"a", core.OpEq, Literal{literalNumber, "-1.5e3"}},
+					{"b", core.OpLe, Literal{literalBool, "true"}},
+					{"c", core.OpGt, Literal{literalString, ""}},
+				},
+				OrderBy: []string{"k", "l"},
+			},
+		},
+		{"SELECT * FROM città WHERE año = 1", Query{
+			Table: "città",
+			Where: []Condition{{"año", core.OpEq, Literal{literalNumber, "1"}}},
+		}},
+	}
+	for _, test := range tests {
+		q, err := Parse(test.text)
+		require.NoError(t, err, test.text)
+		assert.Equal(t, test.query, q, test.text)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		text string
+		err  string
+	}{
+		{"", "Expected SELECT at position 0, got end of query"},
+		{"SELECT FROM users", `Expected field name or * at position 7, got "FROM"`},
+		{"SELECT * users", `Expected FROM at position 9, got "users"`},
+		{"SELECT * FROM users WHERE", "Expected field name at position 25, got end of query"},
+		{"SELECT * FROM users WHERE a != 1", `Unexpected character '!' at position 28`},
+		{"SELECT * FROM users WHERE a = b", `Expected a value at position 30, got "b"`},
+		{"SELECT * FROM users WHERE a = 'b", "Unterminated string at position 30"},
+		{"SELECT * FROM users ORDER id", `Expected BY at position 26, got "id"`},
+		{"SELECT * FROM users LIMIT -1", `Expected a limit at position 26, got "-1"`},
+		{"SELECT * FROM users LIMIT 1 2", `Expected end of query at position 28, got "2"`},
+	}
+	for _, test := range tests {
+		_, err := Parse(test.text)
+		require.Error(t, err, test.text)
+		assert.Equal(t, test.err, err.Error(), test.text)
+	}
+}
@@ -0,0 +1,296 @@
+// Package query implements a small SQL-like query language over tables
+package query
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/vulturedb/vulture/core"
+	"github.com/vulturedb/vulture/table"
+)
+
+type literalKind int
+
+const (
+	literalNumber literalKind = iota
+	literalString
+	literalBool
+)
+
+// Literal is a value written in a query. Its type is only known once it's compared to a field, so
+// it's kept as text until then.
+type Literal struct {
+	kind literalKind
+	text string
+}
+
+func (l Literal) String() string {
+	if l.kind == literalString {
+		return quote(l.text)
+	}
+	return l.text
+}
+
+// valueFor converts the literal into a value of the type of a field
+func (l Literal) valueFor(fieldName string, spec core.FieldSpec) (interface{}, error) {
+	var v interface{}
+	var err error
+	switch {
+	case l.kind == literalNumber && spec.Type == "int":
+		var n int64
+		n, err = strconv.ParseInt(l.text, 10, 32)
+		v = int32(n)
+	case l.kind == literalNumber && spec.Type == "long":
+		v, err = strconv.ParseInt(l.text, 10, 64)
+	case l.kind == literalNumber && spec.Type == "float":
+		var f float64
+		f, err = strconv.ParseFloat(l.text, 32)
+		v = float32(f)
+	case l.kind == literalNumber && spec.Type == "double":
+		v, err = strconv.ParseFloat(l.text, 64)
+	case l.kind == literalString && (spec.Type == "string" || spec.Type == "enum"):
+		v = l.text
+	case l.kind == literalString && spec.Type == "bytes":
+		v = []byte(l.text)
+	case l.kind == literalString && spec.Type == "timestamp":
+		v, err = time.Parse(time.RFC3339Nano, l.text)
+	case l.kind == literalString && spec.Type == "uuid":
+		v, err = uuid.Parse(l.text)
+	case l.kind == literalBool && spec.Type == "boolean":
+		v = l.text == "true"
+	default:
+		return nil, fmt.Errorf(
+			"Field %s of type %s can't be compared to %s",
+			fieldName,
+			spec.Type,
+			l,
+		)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("Invalid %s value %s for field %s", spec.Type, l, fieldName)
+	}
+	return v, nil
+}
+
+// Condition is a predicate of a query's WHERE clause
+type Condition struct {
+	Field string
+	Op    core.Op
+	Value Literal
+}
+
+// Query is a parsed query, see Parse
+type Query struct {
+	Table string
+	// The fields to return, or nil for every field
+	Fields []string
+	Where  []Condition
+	// A leading run of the primary key to order rows by, or nil to return them in whatever order
+	// is fastest
+	OrderBy []string
+	Desc    bool
+	// A limit of 0 returns every row
+	Limit uint32
+}
+
+// Rows iterates over the result of a query
+type Rows struct {
+	columns []string
+	plan    string
+	// Rows are read from it, or from sorted if they had to be sorted first
+	it       *table.RowIterator
+	sorted   []core.Row
+	limit    uint32
+	returned uint32
+	row      core.Row
+}
+
+// Columns returns the names of the fields of each row, in the order they were selected in
+func (r *Rows) Columns() []string {
+	return r.columns
+}
+
+// Plan returns how the rows are found, see table.Table.Plan
+func (r *Rows) Plan() string {
+	return r.plan
+}
+
+// Next advances to the next row and returns whether there is one
+func (r *Rows) Next() bool {
+	if r.limit > 0 && r.returned >= r.limit {
+		return false
+	}
+	var row core.Row
+	if r.it != nil {
+		if !r.it.Next() {
+			return false
+		}
+		row = r.it.Row()
+	} else {
+		if len(r.sorted) == 0 {
+			return false
+		}
+		row, r.sorted = r.sorted[0], r.sorted[1:]
+	}
+	r.row = core.Row{Data: make(map[string]interface{}, len(r.columns))}
+	for _, column := range r.columns {
+		if v, ok := row.Data[column]; ok {
+			r.row.Data[column] = v
+		}
+	}
+	r.returned++
+	return true
+}
+
+// Row returns the selected fields of the current row. Null fields are left out.
+func (r *Rows) Row() core.Row {
+	return r.row
+}
+
+// Err returns the error that stopped the iteration, if any
+func (r *Rows) Err() error {
+	if r.it == nil {
+		return nil
+	}
+	return r.it.Err()
+}
+
+// columns returns the fields a query selects. Every field is the primary key followed by the rest
+// of the fields by name.
+func columns(schema core.Schema, fields []string) ([]string, error) {
+	if fields == nil {
+		isKey := make(map[string]bool, len(schema.PrimaryKey))
+		for _, fieldName := range schema.PrimaryKey {
+			isKey[fieldName] = true
+		}
+		rest := []string{}
+		for fieldName := range schema.Fields {
+			if !isKey[fieldName] {
+				rest = append(rest, fieldName)
+			}
+		}
+		sort.Strings(rest)
+		return append(append([]string{}, schema.PrimaryKey...), rest...), nil
+	}
+	seen := make(map[string]bool, len(fields))
+	for _, fieldName := range fields {
+		if _, ok := schema.Fields[fieldName]; !ok {
+			return nil, fmt.Errorf("Unknown field %s", fieldName)
+		} else if seen[fieldName] {
+			return nil, fmt.Errorf("Field %s is selected twice", fieldName)
+		}
+		seen[fieldName] = true
+	}
+	return fields, nil
+}
+
+func predicates(schema core.Schema, where []Condition) ([]core.Predicate, error) {
+	preds := make([]core.Predicate, len(where))
+	for i, cond := range where {
+		spec, ok := schema.Fields[cond.Field]
+		if !ok {
+			return nil, fmt.Errorf("Unknown field %s", cond.Field)
+		}
+		v, err := cond.Value.valueFor(cond.Field, spec)
+		if err != nil {
+			return nil, err
+		}
+		preds[i] = core.Predicate{Field: cond.Field, Op: cond.Op, Value: v}
+	}
+	return preds, nil
+}
+
+// Run runs q against t, which has to be the table q selects from. Predicates on the primary key or
+// on indexes narrow down the rows that are read as described in table.Table.Select, and the rest
+// are checked against each row.
+//
+// Queries ordered by the primary key are read in that order when no index narrows them down more
+// than the primary key does. Otherwise the matching rows are found through the index and sorted
+// in memory.
+func Run(t *table.Table, q Query) (*Rows, error) {
+	if q.Table != t.Name() {
+		return nil, fmt.Errorf("Query selects from %s, not %s", q.Table, t.Name())
+	}
+	schema := t.Schema()
+	cols, err := columns(schema, q.Fields)
+	if err != nil {
+		return nil, err
+	}
+	preds, err := predicates(schema, q.Where)
+	if err != nil {
+		return nil, err
+	}
+	if len(q.OrderBy) > len(schema.PrimaryKey) {
+		return nil, fmt.Errorf("Can only order by the primary key %v", schema.PrimaryKey)
+	}
+	for i, fieldName := range q.OrderBy {
+		if fieldName != schema.PrimaryKey[i] {
+			return nil, fmt.Errorf("Can only order by the primary key %v", schema.PrimaryKey)
+		}
+	}
+	plan, err := t.Plan(preds)
+	if err != nil {
+		return nil, err
+	}
+	rows := &Rows{columns: cols, plan: plan, limit: q.Limit}
+	if q.OrderBy == nil {
+		rows.it, err = t.Select(preds)
+		return rows, err
+	}
+	if plan == table.PrimaryKeyPlan {
+		rows.it, err = t.SelectByPrimaryKey(preds, q.Desc)
+		return rows, err
+	}
+	rows.sorted, err = selectSorted(t, preds, q.Desc)
+	return rows, err
+}
+
+// selectSorted returns every row matching preds in primary key order
+func selectSorted(t *table.Table, preds []core.Predicate, desc bool) ([]core.Row, error) {
+	it, err := t.Select(preds)
+	if err != nil {
+		return nil, err
+	}
+	rows := []core.Row{}
+	keys := [][]byte{}
+	for it.Next() {
+		key, err := t.Schema().PrimaryKeyOf(it.Row())
+		if err != nil {
+			return nil, err
+		}
+		rows = append(rows, it.Row())
+		keys = append(keys, key.Bytes())
+	}
+	if err := it.Err(); err != nil {
+		return nil, err
+	}
+	sort.Sort(byKey{rows, keys, desc})
+	return rows, nil
+}
+
+type byKey struct {
+	rows []core.Row
+	keys [][]byte
+	desc bool
+}
+
+func (b byKey) Len() int {
+	return len(b.rows)
+}
+
+func (b byKey) Less(i, j int) bool {
+	if b.desc {
+		i, j = j, i
+	}
+	return bytes.Compare(b.keys[i], b.keys[j]) < 0
+}
+
+func (b byKey) Swap(i, j int) {
+	b.rows[i], b.rows[j] = b.rows[j], b.rows[i]
+	b.keys[i], b.keys[j] = b.keys[j], b.keys[i]
+}
@@ -0,0 +1,131 @@
+package query
+
+import (
+	"crypto"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/vulturedb/vulture/core"
+	"github.com/vulturedb/vulture/mst"
+	"github.com/vulturedb/vulture/table"
+)
+
+func newEvents(t *testing.T) *table.Table {
+	schema := core.Schema{
+		Fields: map[string]core.FieldSpec{
+			"tenant": {Type: "string"},
+			"seq":    {Type: "int"},
+			"kind":   {Type: "enum", Symbols: []string{"click", "view"}},
+			"note":   {Type: "string", Nullable: true},
+		},
+		PrimaryKey: []string{"tenant", "seq"},
+		Indexes:    map[string][]string{"by_kind": {"kind"}},
+	}
+	events, err := table.NewTable("events", schema, mst.NewLocalMST(mst.Base4, crypto.SHA256))
+	require.NoError(t, err)
+	for _, tenant := range []string{"a", "b"} {
+		for seq := int32(0); seq < 4; seq++ {
+			kind := "view"
+			if seq%2 == 1 {
+				kind = "click"
+			}
+			row := core.Row{Data: map[string]interface{}{
+				"tenant": tenant,
+				"seq":    seq,
+				"kind":   kind,
+			}}
+			if seq == 0 {
+				row.Data["note"] = "first"
+			}
+			events, err = events.Insert(row, core.HLC{Wall: 1, Replica: "a"})
+			require.NoError(t, err)
+		}
+	}
+	return events
+}
+
+func mustRun(t *testing.T, events *table.Table, text string) ([]core.Row, *Rows) {
+	q, err := Parse(text)
+	require.NoError(t, err)
+	rows, err := Run(events, q)
+	require.NoError(t, err)
+	result := []core.Row{}
+	for rows.Next() {
+		result = append(result, rows.Row())
+	}
+	require.NoError(t, rows.Err())
+	return result, rows
+}
+
+func event(tenant string, seq int32) core.Row {
+	return core.Row{Data: map[string]interface{}{"tenant": tenant, "seq": seq}}
+}
+
+func TestRun(t *testing.T) {
+	events := newEvents(t)
+	tests := []struct {
+		text string
+		plan string
+		rows []core.Row
+	}{
+		{
+			"SELECT tenant, seq FROM events WHERE tenant = 'a' AND seq > 1",
+			table.PrimaryKeyPlan,
+			[]core.Row{event("a", 2), event("a", 3)},
+		},
+		{
+			"SELECT tenant, seq FROM events WHERE tenant = 'b' ORDER BY tenant, seq DESC LIMIT 3",
+			table.PrimaryKeyPlan,
+			[]core.Row{event("b", 3), event("b", 2), event("b", 1)},
+		},
+		{
+			"SELECT tenant, seq FROM events WHERE kind = 'click' ORDER BY tenant DESC LIMIT 3",
+			"by_kind",
+			[]core.Row{event("b", 3), event("b", 1), event("a", 3)},
+		},
+		{
+			"SELECT tenant, seq FROM events WHERE seq = 2",
+			table.PrimaryKeyPlan,
+			[]core.Row{event("a", 2), event("b", 2)},
+		},
+		{
+			"SELECT note FROM events WHERE tenant = 'a' AND seq <= 1",
+			table.PrimaryKeyPlan,
+			[]core.Row{
+				{Data: map[string]interface{}{"note": "first"}},
+				{Data: map[string]interface{}{}},
+			},
+		},
+	}
+	for _, test := range tests {
+		result, rows := mustRun(t, events, test.text)
+		assert.Equal(t, test.plan, rows.Plan(), test.text)
+		assert.Equal(t, test.rows, result, test.text)
+	}
+
+	_, rows := mustRun(t, events, "SELECT * FROM events LIMIT 1")
+	assert.Equal(t, []string{"tenant", "seq", "kind", "note"}, rows.Columns())
+}
+
+func TestRunErrors(t *testing.T) {
+	events := newEvents(t)
+	for _, text := range []string{
+		"SELECT * FROM users",
+		"SELECT missing FROM events",
+		"SELECT seq, seq FROM events",
+		"SELECT * FROM events WHERE missing = 1",
+		"SELECT * FROM events WHERE seq = 'a'",
+		"SELECT * FROM events WHERE seq = 1.5",
+		"SELECT * FROM events WHERE seq = 3000000000",
+		"SELECT * FROM events WHERE kind = 'other'",
+		"SELECT * FROM events ORDER BY seq",
+		"SELECT * FROM events ORDER BY tenant, seq, kind",
+	} {
+		q, err := Parse(text)
+		require.NoError(t, err, text)
+		_, err = Run(events, q)
+		assert.Error(t, err, text)
+	}
+}
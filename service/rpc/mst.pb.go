@@ -1,6 +1,6 @@
 // Code generated by protoc-gen-go. DO NOT EDIT.
 // versions:
-// 	protoc-gen-go v1.25.0
+// 	protoc-gen-go v1.27.1
 // 	protoc        v3.13.0
 // source: mst.proto
 
@@ -8,8 +8,8 @@ package rpc
 
 import (
 	context "context"
-	proto "github.com/golang/protobuf/proto"
 	empty "github.com/golang/protobuf/ptypes/empty"
+	timestamp "github.com/golang/protobuf/ptypes/timestamp"
 	grpc "google.golang.org/grpc"
 	codes "google.golang.org/grpc/codes"
 	status "google.golang.org/grpc/status"
@@ -26,10 +26,6 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
-// This is a compile-time assertion that a sufficiently up-to-date version
-// of the legacy proto package is being used.
-const _ = proto.ProtoPackageIsVersion4
-
 type MSTChild struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -38,6 +34,10 @@ type MSTChild struct {
 	Key   []byte `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
 	Value []byte `protobuf:"bytes,2,opt,name=value,proto3" json:"value,omitempty"`
 	High  []byte `protobuf:"bytes,3,opt,name=high,proto3" json:"high,omitempty"`
+	// Set when the child holds a tombstone, in which case value holds the tombstone's value and
+	// deleted holds the value that was visible when the key was deleted.
+	Tombstone bool   `protobuf:"varint,4,opt,name=tombstone,proto3" json:"tombstone,omitempty"`
+	Deleted   []byte `protobuf:"bytes,5,opt,name=deleted,proto3" json:"deleted,omitempty"`
 }
 
 func (x *MSTChild) Reset() {
@@ -93,6 +93,101 @@ func (x *MSTChild) GetHigh() []byte {
 	return nil
 }
 
+func (x *MSTChild) GetTombstone() bool {
+	if x != nil {
+		return x.Tombstone
+	}
+	return false
+}
+
+func (x *MSTChild) GetDeleted() []byte {
+	if x != nil {
+		return x.Deleted
+	}
+	return nil
+}
+
+// MSTSummary aggregates the values of a range of keys. sum, min and max are over the measured
+// values and min and max are 0 if none were measured.
+type MSTSummary struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Count    uint64  `protobuf:"varint,1,opt,name=count,proto3" json:"count,omitempty"`
+	Measured uint64  `protobuf:"varint,2,opt,name=measured,proto3" json:"measured,omitempty"`
+	Sum      float64 `protobuf:"fixed64,3,opt,name=sum,proto3" json:"sum,omitempty"`
+	Min      float64 `protobuf:"fixed64,4,opt,name=min,proto3" json:"min,omitempty"`
+	Max      float64 `protobuf:"fixed64,5,opt,name=max,proto3" json:"max,omitempty"`
+}
+
+func (x *MSTSummary) Reset() {
+	*x = MSTSummary{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_mst_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *MSTSummary) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MSTSummary) ProtoMessage() {}
+
+func (x *MSTSummary) ProtoReflect() protoreflect.Message {
+	mi := &file_mst_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MSTSummary.ProtoReflect.Descriptor instead.
+func (*MSTSummary) Descriptor() ([]byte, []int) {
+	return file_mst_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *MSTSummary) GetCount() uint64 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+func (x *MSTSummary) GetMeasured() uint64 {
+	if x != nil {
+		return x.Measured
+	}
+	return 0
+}
+
+func (x *MSTSummary) GetSum() float64 {
+	if x != nil {
+		return x.Sum
+	}
+	return 0
+}
+
+func (x *MSTSummary) GetMin() float64 {
+	if x != nil {
+		return x.Min
+	}
+	return 0
+}
+
+func (x *MSTSummary) GetMax() float64 {
+	if x != nil {
+		return x.Max
+	}
+	return 0
+}
+
 type MSTNode struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -101,12 +196,15 @@ type MSTNode struct {
 	Level    uint32      `protobuf:"varint,1,opt,name=level,proto3" json:"level,omitempty"`
 	Low      []byte      `protobuf:"bytes,2,opt,name=low,proto3" json:"low,omitempty"`
 	Children []*MSTChild `protobuf:"bytes,3,rep,name=children,proto3" json:"children,omitempty"`
+	// The summaries of the subtrees at low and at the high of each child, if the tree keeps
+	// aggregates.
+	Summaries []*MSTSummary `protobuf:"bytes,4,rep,name=summaries,proto3" json:"summaries,omitempty"`
 }
 
 func (x *MSTNode) Reset() {
 	*x = MSTNode{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_mst_proto_msgTypes[1]
+		mi := &file_mst_proto_msgTypes[2]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -119,7 +217,7 @@ func (x *MSTNode) String() string {
 func (*MSTNode) ProtoMessage() {}
 
 func (x *MSTNode) ProtoReflect() protoreflect.Message {
-	mi := &file_mst_proto_msgTypes[1]
+	mi := &file_mst_proto_msgTypes[2]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -132,7 +230,7 @@ func (x *MSTNode) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use MSTNode.ProtoReflect.Descriptor instead.
 func (*MSTNode) Descriptor() ([]byte, []int) {
-	return file_mst_proto_rawDescGZIP(), []int{1}
+	return file_mst_proto_rawDescGZIP(), []int{2}
 }
 
 func (x *MSTNode) GetLevel() uint32 {
@@ -156,19 +254,27 @@ func (x *MSTNode) GetChildren() []*MSTChild {
 	return nil
 }
 
+func (x *MSTNode) GetSummaries() []*MSTSummary {
+	if x != nil {
+		return x.Summaries
+	}
+	return nil
+}
+
 type MSTPutRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Key   uint32 `protobuf:"varint,1,opt,name=key,proto3" json:"key,omitempty"`
-	Value uint32 `protobuf:"varint,2,opt,name=value,proto3" json:"value,omitempty"`
+	Key      []byte `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	Value    []byte `protobuf:"bytes,2,opt,name=value,proto3" json:"value,omitempty"`
+	Keyspace string `protobuf:"bytes,3,opt,name=keyspace,proto3" json:"keyspace,omitempty"`
 }
 
 func (x *MSTPutRequest) Reset() {
 	*x = MSTPutRequest{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_mst_proto_msgTypes[2]
+		mi := &file_mst_proto_msgTypes[3]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -181,7 +287,7 @@ func (x *MSTPutRequest) String() string {
 func (*MSTPutRequest) ProtoMessage() {}
 
 func (x *MSTPutRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_mst_proto_msgTypes[2]
+	mi := &file_mst_proto_msgTypes[3]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -194,21 +300,28 @@ func (x *MSTPutRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use MSTPutRequest.ProtoReflect.Descriptor instead.
 func (*MSTPutRequest) Descriptor() ([]byte, []int) {
-	return file_mst_proto_rawDescGZIP(), []int{2}
+	return file_mst_proto_rawDescGZIP(), []int{3}
 }
 
-func (x *MSTPutRequest) GetKey() uint32 {
+func (x *MSTPutRequest) GetKey() []byte {
 	if x != nil {
 		return x.Key
 	}
-	return 0
+	return nil
 }
 
-func (x *MSTPutRequest) GetValue() uint32 {
+func (x *MSTPutRequest) GetValue() []byte {
 	if x != nil {
 		return x.Value
 	}
-	return 0
+	return nil
+}
+
+func (x *MSTPutRequest) GetKeyspace() string {
+	if x != nil {
+		return x.Keyspace
+	}
+	return ""
 }
 
 type MSTGetRequest struct {
@@ -216,13 +329,18 @@ type MSTGetRequest struct {
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Key uint32 `protobuf:"varint,1,opt,name=key,proto3" json:"key,omitempty"`
+	Key []byte `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	// Reads the tree at a past root or a snapshot instead of the current tree if either is set. The
+	// root has to be in the root history or be the root of a snapshot.
+	RootHash []byte `protobuf:"bytes,2,opt,name=root_hash,json=rootHash,proto3" json:"root_hash,omitempty"`
+	Snapshot string `protobuf:"bytes,3,opt,name=snapshot,proto3" json:"snapshot,omitempty"`
+	Keyspace string `protobuf:"bytes,4,opt,name=keyspace,proto3" json:"keyspace,omitempty"`
 }
 
 func (x *MSTGetRequest) Reset() {
 	*x = MSTGetRequest{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_mst_proto_msgTypes[3]
+		mi := &file_mst_proto_msgTypes[4]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -235,7 +353,7 @@ func (x *MSTGetRequest) String() string {
 func (*MSTGetRequest) ProtoMessage() {}
 
 func (x *MSTGetRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_mst_proto_msgTypes[3]
+	mi := &file_mst_proto_msgTypes[4]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -248,14 +366,35 @@ func (x *MSTGetRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use MSTGetRequest.ProtoReflect.Descriptor instead.
 func (*MSTGetRequest) Descriptor() ([]byte, []int) {
-	return file_mst_proto_rawDescGZIP(), []int{3}
+	return file_mst_proto_rawDescGZIP(), []int{4}
 }
 
-func (x *MSTGetRequest) GetKey() uint32 {
+func (x *MSTGetRequest) GetKey() []byte {
 	if x != nil {
 		return x.Key
 	}
-	return 0
+	return nil
+}
+
+func (x *MSTGetRequest) GetRootHash() []byte {
+	if x != nil {
+		return x.RootHash
+	}
+	return nil
+}
+
+func (x *MSTGetRequest) GetSnapshot() string {
+	if x != nil {
+		return x.Snapshot
+	}
+	return ""
+}
+
+func (x *MSTGetRequest) GetKeyspace() string {
+	if x != nil {
+		return x.Keyspace
+	}
+	return ""
 }
 
 type MSTGetResponse struct {
@@ -263,13 +402,14 @@ type MSTGetResponse struct {
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Value uint32 `protobuf:"varint,1,opt,name=value,proto3" json:"value,omitempty"`
+	Value []byte `protobuf:"bytes,1,opt,name=value,proto3" json:"value,omitempty"`
+	Found bool   `protobuf:"varint,2,opt,name=found,proto3" json:"found,omitempty"`
 }
 
 func (x *MSTGetResponse) Reset() {
 	*x = MSTGetResponse{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_mst_proto_msgTypes[4]
+		mi := &file_mst_proto_msgTypes[5]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -282,7 +422,7 @@ func (x *MSTGetResponse) String() string {
 func (*MSTGetResponse) ProtoMessage() {}
 
 func (x *MSTGetResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_mst_proto_msgTypes[4]
+	mi := &file_mst_proto_msgTypes[5]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -295,42 +435,49 @@ func (x *MSTGetResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use MSTGetResponse.ProtoReflect.Descriptor instead.
 func (*MSTGetResponse) Descriptor() ([]byte, []int) {
-	return file_mst_proto_rawDescGZIP(), []int{4}
+	return file_mst_proto_rawDescGZIP(), []int{5}
 }
 
-func (x *MSTGetResponse) GetValue() uint32 {
+func (x *MSTGetResponse) GetValue() []byte {
 	if x != nil {
 		return x.Value
 	}
-	return 0
+	return nil
 }
 
-type MSTRoundStartRequest struct {
+func (x *MSTGetResponse) GetFound() bool {
+	if x != nil {
+		return x.Found
+	}
+	return false
+}
+
+type MSTGetWithProofRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	RoundUuid []byte `protobuf:"bytes,1,opt,name=round_uuid,json=roundUuid,proto3" json:"round_uuid,omitempty"`
-	RootHash  []byte `protobuf:"bytes,2,opt,name=root_hash,json=rootHash,proto3" json:"root_hash,omitempty"`
+	Key      []byte `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	Keyspace string `protobuf:"bytes,2,opt,name=keyspace,proto3" json:"keyspace,omitempty"`
 }
 
-func (x *MSTRoundStartRequest) Reset() {
-	*x = MSTRoundStartRequest{}
+func (x *MSTGetWithProofRequest) Reset() {
+	*x = MSTGetWithProofRequest{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_mst_proto_msgTypes[5]
+		mi := &file_mst_proto_msgTypes[6]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *MSTRoundStartRequest) String() string {
+func (x *MSTGetWithProofRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*MSTRoundStartRequest) ProtoMessage() {}
+func (*MSTGetWithProofRequest) ProtoMessage() {}
 
-func (x *MSTRoundStartRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_mst_proto_msgTypes[5]
+func (x *MSTGetWithProofRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_mst_proto_msgTypes[6]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -341,51 +488,55 @@ func (x *MSTRoundStartRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use MSTRoundStartRequest.ProtoReflect.Descriptor instead.
-func (*MSTRoundStartRequest) Descriptor() ([]byte, []int) {
-	return file_mst_proto_rawDescGZIP(), []int{5}
+// Deprecated: Use MSTGetWithProofRequest.ProtoReflect.Descriptor instead.
+func (*MSTGetWithProofRequest) Descriptor() ([]byte, []int) {
+	return file_mst_proto_rawDescGZIP(), []int{6}
 }
 
-func (x *MSTRoundStartRequest) GetRoundUuid() []byte {
+func (x *MSTGetWithProofRequest) GetKey() []byte {
 	if x != nil {
-		return x.RoundUuid
+		return x.Key
 	}
 	return nil
 }
 
-func (x *MSTRoundStartRequest) GetRootHash() []byte {
+func (x *MSTGetWithProofRequest) GetKeyspace() string {
 	if x != nil {
-		return x.RootHash
+		return x.Keyspace
 	}
-	return nil
+	return ""
 }
 
-type MSTRoundStepRequest struct {
+type MSTGetWithProofResponse struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	RoundUuid []byte     `protobuf:"bytes,1,opt,name=round_uuid,json=roundUuid,proto3" json:"round_uuid,omitempty"`
-	Nodes     []*MSTNode `protobuf:"bytes,2,rep,name=nodes,proto3" json:"nodes,omitempty"`
+	Value []byte `protobuf:"bytes,1,opt,name=value,proto3" json:"value,omitempty"`
+	// found is false when the proof shows that the key isn't in the tree.
+	Found    bool   `protobuf:"varint,2,opt,name=found,proto3" json:"found,omitempty"`
+	RootHash []byte `protobuf:"bytes,3,opt,name=root_hash,json=rootHash,proto3" json:"root_hash,omitempty"`
+	// The nodes from the root down to the key, or to where the key would be.
+	Proof []*MSTNode `protobuf:"bytes,4,rep,name=proof,proto3" json:"proof,omitempty"`
 }
 
-func (x *MSTRoundStepRequest) Reset() {
-	*x = MSTRoundStepRequest{}
+func (x *MSTGetWithProofResponse) Reset() {
+	*x = MSTGetWithProofResponse{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_mst_proto_msgTypes[6]
+		mi := &file_mst_proto_msgTypes[7]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *MSTRoundStepRequest) String() string {
+func (x *MSTGetWithProofResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*MSTRoundStepRequest) ProtoMessage() {}
+func (*MSTGetWithProofResponse) ProtoMessage() {}
 
-func (x *MSTRoundStepRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_mst_proto_msgTypes[6]
+func (x *MSTGetWithProofResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_mst_proto_msgTypes[7]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -396,50 +547,65 @@ func (x *MSTRoundStepRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use MSTRoundStepRequest.ProtoReflect.Descriptor instead.
-func (*MSTRoundStepRequest) Descriptor() ([]byte, []int) {
-	return file_mst_proto_rawDescGZIP(), []int{6}
+// Deprecated: Use MSTGetWithProofResponse.ProtoReflect.Descriptor instead.
+func (*MSTGetWithProofResponse) Descriptor() ([]byte, []int) {
+	return file_mst_proto_rawDescGZIP(), []int{7}
 }
 
-func (x *MSTRoundStepRequest) GetRoundUuid() []byte {
+func (x *MSTGetWithProofResponse) GetValue() []byte {
 	if x != nil {
-		return x.RoundUuid
+		return x.Value
 	}
 	return nil
 }
 
-func (x *MSTRoundStepRequest) GetNodes() []*MSTNode {
+func (x *MSTGetWithProofResponse) GetFound() bool {
 	if x != nil {
-		return x.Nodes
+		return x.Found
+	}
+	return false
+}
+
+func (x *MSTGetWithProofResponse) GetRootHash() []byte {
+	if x != nil {
+		return x.RootHash
 	}
 	return nil
 }
 
-type MSTRoundStepResponse struct {
+func (x *MSTGetWithProofResponse) GetProof() []*MSTNode {
+	if x != nil {
+		return x.Proof
+	}
+	return nil
+}
+
+type MSTDeleteRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Hashes [][]byte `protobuf:"bytes,1,rep,name=hashes,proto3" json:"hashes,omitempty"`
+	Key      []byte `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	Keyspace string `protobuf:"bytes,2,opt,name=keyspace,proto3" json:"keyspace,omitempty"`
 }
 
-func (x *MSTRoundStepResponse) Reset() {
-	*x = MSTRoundStepResponse{}
+func (x *MSTDeleteRequest) Reset() {
+	*x = MSTDeleteRequest{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_mst_proto_msgTypes[7]
+		mi := &file_mst_proto_msgTypes[8]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *MSTRoundStepResponse) String() string {
+func (x *MSTDeleteRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*MSTRoundStepResponse) ProtoMessage() {}
+func (*MSTDeleteRequest) ProtoMessage() {}
 
-func (x *MSTRoundStepResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_mst_proto_msgTypes[7]
+func (x *MSTDeleteRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_mst_proto_msgTypes[8]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -450,364 +616,3558 @@ func (x *MSTRoundStepResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use MSTRoundStepResponse.ProtoReflect.Descriptor instead.
-func (*MSTRoundStepResponse) Descriptor() ([]byte, []int) {
-	return file_mst_proto_rawDescGZIP(), []int{7}
+// Deprecated: Use MSTDeleteRequest.ProtoReflect.Descriptor instead.
+func (*MSTDeleteRequest) Descriptor() ([]byte, []int) {
+	return file_mst_proto_rawDescGZIP(), []int{8}
 }
 
-func (x *MSTRoundStepResponse) GetHashes() [][]byte {
+func (x *MSTDeleteRequest) GetKey() []byte {
 	if x != nil {
-		return x.Hashes
+		return x.Key
 	}
 	return nil
 }
 
-var File_mst_proto protoreflect.FileDescriptor
-
-var file_mst_proto_rawDesc = []byte{
-	0x0a, 0x09, 0x6d, 0x73, 0x74, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x13, 0x76, 0x75, 0x6c,
-	0x74, 0x75, 0x72, 0x65, 0x2e, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x72, 0x70, 0x63,
-	0x1a, 0x1b, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75,
-	0x66, 0x2f, 0x65, 0x6d, 0x70, 0x74, 0x79, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0x46, 0x0a,
-	0x08, 0x4d, 0x53, 0x54, 0x43, 0x68, 0x69, 0x6c, 0x64, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79,
-	0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x76,
-	0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75,
-	0x65, 0x12, 0x12, 0x0a, 0x04, 0x68, 0x69, 0x67, 0x68, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0c, 0x52,
-	0x04, 0x68, 0x69, 0x67, 0x68, 0x22, 0x6c, 0x0a, 0x07, 0x4d, 0x53, 0x54, 0x4e, 0x6f, 0x64, 0x65,
-	0x12, 0x14, 0x0a, 0x05, 0x6c, 0x65, 0x76, 0x65, 0x6c, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0d, 0x52,
-	0x05, 0x6c, 0x65, 0x76, 0x65, 0x6c, 0x12, 0x10, 0x0a, 0x03, 0x6c, 0x6f, 0x77, 0x18, 0x02, 0x20,
-	0x01, 0x28, 0x0c, 0x52, 0x03, 0x6c, 0x6f, 0x77, 0x12, 0x39, 0x0a, 0x08, 0x63, 0x68, 0x69, 0x6c,
-	0x64, 0x72, 0x65, 0x6e, 0x18, 0x03, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1d, 0x2e, 0x76, 0x75, 0x6c,
-	0x74, 0x75, 0x72, 0x65, 0x2e, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x72, 0x70, 0x63,
-	0x2e, 0x4d, 0x53, 0x54, 0x43, 0x68, 0x69, 0x6c, 0x64, 0x52, 0x08, 0x63, 0x68, 0x69, 0x6c, 0x64,
-	0x72, 0x65, 0x6e, 0x22, 0x37, 0x0a, 0x0d, 0x4d, 0x53, 0x54, 0x50, 0x75, 0x74, 0x52, 0x65, 0x71,
-	0x75, 0x65, 0x73, 0x74, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28,
-	0x0d, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18,
-	0x02, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x22, 0x21, 0x0a, 0x0d,
-	0x4d, 0x53, 0x54, 0x47, 0x65, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x10, 0x0a,
-	0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x22,
-	0x26, 0x0a, 0x0e, 0x4d, 0x53, 0x54, 0x47, 0x65, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
-	0x65, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0d,
-	0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x22, 0x52, 0x0a, 0x14, 0x4d, 0x53, 0x54, 0x52, 0x6f,
-	0x75, 0x6e, 0x64, 0x53, 0x74, 0x61, 0x72, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12,
-	0x1d, 0x0a, 0x0a, 0x72, 0x6f, 0x75, 0x6e, 0x64, 0x5f, 0x75, 0x75, 0x69, 0x64, 0x18, 0x01, 0x20,
-	0x01, 0x28, 0x0c, 0x52, 0x09, 0x72, 0x6f, 0x75, 0x6e, 0x64, 0x55, 0x75, 0x69, 0x64, 0x12, 0x1b,
-	0x0a, 0x09, 0x72, 0x6f, 0x6f, 0x74, 0x5f, 0x68, 0x61, 0x73, 0x68, 0x18, 0x02, 0x20, 0x01, 0x28,
-	0x0c, 0x52, 0x08, 0x72, 0x6f, 0x6f, 0x74, 0x48, 0x61, 0x73, 0x68, 0x22, 0x68, 0x0a, 0x13, 0x4d,
-	0x53, 0x54, 0x52, 0x6f, 0x75, 0x6e, 0x64, 0x53, 0x74, 0x65, 0x70, 0x52, 0x65, 0x71, 0x75, 0x65,
-	0x73, 0x74, 0x12, 0x1d, 0x0a, 0x0a, 0x72, 0x6f, 0x75, 0x6e, 0x64, 0x5f, 0x75, 0x75, 0x69, 0x64,
-	0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x09, 0x72, 0x6f, 0x75, 0x6e, 0x64, 0x55, 0x75, 0x69,
-	0x64, 0x12, 0x32, 0x0a, 0x05, 0x6e, 0x6f, 0x64, 0x65, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b,
-	0x32, 0x1c, 0x2e, 0x76, 0x75, 0x6c, 0x74, 0x75, 0x72, 0x65, 0x2e, 0x73, 0x65, 0x72, 0x76, 0x69,
-	0x63, 0x65, 0x2e, 0x72, 0x70, 0x63, 0x2e, 0x4d, 0x53, 0x54, 0x4e, 0x6f, 0x64, 0x65, 0x52, 0x05,
-	0x6e, 0x6f, 0x64, 0x65, 0x73, 0x22, 0x2e, 0x0a, 0x14, 0x4d, 0x53, 0x54, 0x52, 0x6f, 0x75, 0x6e,
-	0x64, 0x53, 0x74, 0x65, 0x70, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x16, 0x0a,
-	0x06, 0x68, 0x61, 0x73, 0x68, 0x65, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0c, 0x52, 0x06, 0x68,
-	0x61, 0x73, 0x68, 0x65, 0x73, 0x32, 0xa3, 0x01, 0x0a, 0x0a, 0x4d, 0x53, 0x54, 0x53, 0x65, 0x72,
-	0x76, 0x69, 0x63, 0x65, 0x12, 0x43, 0x0a, 0x03, 0x50, 0x75, 0x74, 0x12, 0x22, 0x2e, 0x76, 0x75,
-	0x6c, 0x74, 0x75, 0x72, 0x65, 0x2e, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x72, 0x70,
-	0x63, 0x2e, 0x4d, 0x53, 0x54, 0x50, 0x75, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a,
-	0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75,
-	0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x22, 0x00, 0x12, 0x50, 0x0a, 0x03, 0x47, 0x65, 0x74,
-	0x12, 0x22, 0x2e, 0x76, 0x75, 0x6c, 0x74, 0x75, 0x72, 0x65, 0x2e, 0x73, 0x65, 0x72, 0x76, 0x69,
-	0x63, 0x65, 0x2e, 0x72, 0x70, 0x63, 0x2e, 0x4d, 0x53, 0x54, 0x47, 0x65, 0x74, 0x52, 0x65, 0x71,
-	0x75, 0x65, 0x73, 0x74, 0x1a, 0x23, 0x2e, 0x76, 0x75, 0x6c, 0x74, 0x75, 0x72, 0x65, 0x2e, 0x73,
-	0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x72, 0x70, 0x63, 0x2e, 0x4d, 0x53, 0x54, 0x47, 0x65,
-	0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x32, 0xdd, 0x01, 0x0a, 0x11,
-	0x4d, 0x53, 0x54, 0x4d, 0x61, 0x6e, 0x61, 0x67, 0x65, 0x72, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63,
-	0x65, 0x12, 0x64, 0x0a, 0x0a, 0x52, 0x6f, 0x75, 0x6e, 0x64, 0x53, 0x74, 0x61, 0x72, 0x74, 0x12,
-	0x29, 0x2e, 0x76, 0x75, 0x6c, 0x74, 0x75, 0x72, 0x65, 0x2e, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63,
-	0x65, 0x2e, 0x72, 0x70, 0x63, 0x2e, 0x4d, 0x53, 0x54, 0x52, 0x6f, 0x75, 0x6e, 0x64, 0x53, 0x74,
-	0x61, 0x72, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x29, 0x2e, 0x76, 0x75, 0x6c,
-	0x74, 0x75, 0x72, 0x65, 0x2e, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x72, 0x70, 0x63,
-	0x2e, 0x4d, 0x53, 0x54, 0x52, 0x6f, 0x75, 0x6e, 0x64, 0x53, 0x74, 0x65, 0x70, 0x52, 0x65, 0x73,
-	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x62, 0x0a, 0x09, 0x52, 0x6f, 0x75, 0x6e, 0x64,
-	0x53, 0x74, 0x65, 0x70, 0x12, 0x28, 0x2e, 0x76, 0x75, 0x6c, 0x74, 0x75, 0x72, 0x65, 0x2e, 0x73,
-	0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x72, 0x70, 0x63, 0x2e, 0x4d, 0x53, 0x54, 0x52, 0x6f,
-	0x75, 0x6e, 0x64, 0x53, 0x74, 0x65, 0x70, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x29,
-	0x2e, 0x76, 0x75, 0x6c, 0x74, 0x75, 0x72, 0x65, 0x2e, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65,
-	0x2e, 0x72, 0x70, 0x63, 0x2e, 0x4d, 0x53, 0x54, 0x52, 0x6f, 0x75, 0x6e, 0x64, 0x53, 0x74, 0x65,
-	0x70, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x42, 0x2a, 0x5a, 0x28, 0x67,
-	0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x76, 0x75, 0x6c, 0x74, 0x75, 0x72,
-	0x65, 0x64, 0x62, 0x2f, 0x76, 0x75, 0x6c, 0x74, 0x75, 0x72, 0x65, 0x2f, 0x73, 0x65, 0x72, 0x76,
-	0x69, 0x63, 0x65, 0x2f, 0x72, 0x70, 0x63, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+func (x *MSTDeleteRequest) GetKeyspace() string {
+	if x != nil {
+		return x.Keyspace
+	}
+	return ""
 }
 
-var (
-	file_mst_proto_rawDescOnce sync.Once
-	file_mst_proto_rawDescData = file_mst_proto_rawDesc
-)
+type MSTWrite struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
 
-func file_mst_proto_rawDescGZIP() []byte {
-	file_mst_proto_rawDescOnce.Do(func() {
-		file_mst_proto_rawDescData = protoimpl.X.CompressGZIP(file_mst_proto_rawDescData)
-	})
-	return file_mst_proto_rawDescData
+	Key []byte `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	// Ignored when delete is set.
+	Value  []byte `protobuf:"bytes,2,opt,name=value,proto3" json:"value,omitempty"`
+	Delete bool   `protobuf:"varint,3,opt,name=delete,proto3" json:"delete,omitempty"`
 }
 
-var file_mst_proto_msgTypes = make([]protoimpl.MessageInfo, 8)
-var file_mst_proto_goTypes = []interface{}{
-	(*MSTChild)(nil),             // 0: vulture.service.rpc.MSTChild
-	(*MSTNode)(nil),              // 1: vulture.service.rpc.MSTNode
-	(*MSTPutRequest)(nil),        // 2: vulture.service.rpc.MSTPutRequest
-	(*MSTGetRequest)(nil),        // 3: vulture.service.rpc.MSTGetRequest
-	(*MSTGetResponse)(nil),       // 4: vulture.service.rpc.MSTGetResponse
-	(*MSTRoundStartRequest)(nil), // 5: vulture.service.rpc.MSTRoundStartRequest
-	(*MSTRoundStepRequest)(nil),  // 6: vulture.service.rpc.MSTRoundStepRequest
-	(*MSTRoundStepResponse)(nil), // 7: vulture.service.rpc.MSTRoundStepResponse
-	(*empty.Empty)(nil),          // 8: google.protobuf.Empty
+func (x *MSTWrite) Reset() {
+	*x = MSTWrite{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_mst_proto_msgTypes[9]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
 }
-var file_mst_proto_depIdxs = []int32{
-	0, // 0: vulture.service.rpc.MSTNode.children:type_name -> vulture.service.rpc.MSTChild
-	1, // 1: vulture.service.rpc.MSTRoundStepRequest.nodes:type_name -> vulture.service.rpc.MSTNode
-	2, // 2: vulture.service.rpc.MSTService.Put:input_type -> vulture.service.rpc.MSTPutRequest
-	3, // 3: vulture.service.rpc.MSTService.Get:input_type -> vulture.service.rpc.MSTGetRequest
-	5, // 4: vulture.service.rpc.MSTManagerService.RoundStart:input_type -> vulture.service.rpc.MSTRoundStartRequest
-	6, // 5: vulture.service.rpc.MSTManagerService.RoundStep:input_type -> vulture.service.rpc.MSTRoundStepRequest
-	8, // 6: vulture.service.rpc.MSTService.Put:output_type -> google.protobuf.Empty
-	4, // 7: vulture.service.rpc.MSTService.Get:output_type -> vulture.service.rpc.MSTGetResponse
-	7, // 8: vulture.service.rpc.MSTManagerService.RoundStart:output_type -> vulture.service.rpc.MSTRoundStepResponse
-	7, // 9: vulture.service.rpc.MSTManagerService.RoundStep:output_type -> vulture.service.rpc.MSTRoundStepResponse
-	6, // [6:10] is the sub-list for method output_type
-	2, // [2:6] is the sub-list for method input_type
-	2, // [2:2] is the sub-list for extension type_name
-	2, // [2:2] is the sub-list for extension extendee
-	0, // [0:2] is the sub-list for field type_name
+
+func (x *MSTWrite) String() string {
+	return protoimpl.X.MessageStringOf(x)
 }
 
-func init() { file_mst_proto_init() }
-func file_mst_proto_init() {
-	if File_mst_proto != nil {
-		return
+func (*MSTWrite) ProtoMessage() {}
+
+func (x *MSTWrite) ProtoReflect() protoreflect.Message {
+	mi := &file_mst_proto_msgTypes[9]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
 	}
-	if !protoimpl.UnsafeEnabled {
-		file_mst_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*MSTChild); i {
-			case 0:
-				return &v.state
-			case 1:
-				return &v.sizeCache
-			case 2:
-				return &v.unknownFields
-			default:
-				return nil
-			}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MSTWrite.ProtoReflect.Descriptor instead.
+func (*MSTWrite) Descriptor() ([]byte, []int) {
+	return file_mst_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *MSTWrite) GetKey() []byte {
+	if x != nil {
+		return x.Key
+	}
+	return nil
+}
+
+func (x *MSTWrite) GetValue() []byte {
+	if x != nil {
+		return x.Value
+	}
+	return nil
+}
+
+func (x *MSTWrite) GetDelete() bool {
+	if x != nil {
+		return x.Delete
+	}
+	return false
+}
+
+// MSTWriteBatchRequest applies its writes in order as a single change to the tree, so either all
+// of them are visible or none are.
+type MSTWriteBatchRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Writes   []*MSTWrite `protobuf:"bytes,1,rep,name=writes,proto3" json:"writes,omitempty"`
+	Keyspace string      `protobuf:"bytes,2,opt,name=keyspace,proto3" json:"keyspace,omitempty"`
+}
+
+func (x *MSTWriteBatchRequest) Reset() {
+	*x = MSTWriteBatchRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_mst_proto_msgTypes[10]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *MSTWriteBatchRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MSTWriteBatchRequest) ProtoMessage() {}
+
+func (x *MSTWriteBatchRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_mst_proto_msgTypes[10]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
 		}
-		file_mst_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*MSTNode); i {
-			case 0:
-				return &v.state
-			case 1:
-				return &v.sizeCache
-			case 2:
-				return &v.unknownFields
-			default:
-				return nil
-			}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MSTWriteBatchRequest.ProtoReflect.Descriptor instead.
+func (*MSTWriteBatchRequest) Descriptor() ([]byte, []int) {
+	return file_mst_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *MSTWriteBatchRequest) GetWrites() []*MSTWrite {
+	if x != nil {
+		return x.Writes
+	}
+	return nil
+}
+
+func (x *MSTWriteBatchRequest) GetKeyspace() string {
+	if x != nil {
+		return x.Keyspace
+	}
+	return ""
+}
+
+// MSTImportRequest is one key/value pair of an import. Keys have to be sent in sorted order.
+type MSTImportRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Key   []byte `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	Value []byte `protobuf:"bytes,2,opt,name=value,proto3" json:"value,omitempty"`
+	// Only read from the first request of an import.
+	Keyspace string `protobuf:"bytes,3,opt,name=keyspace,proto3" json:"keyspace,omitempty"`
+}
+
+func (x *MSTImportRequest) Reset() {
+	*x = MSTImportRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_mst_proto_msgTypes[11]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *MSTImportRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MSTImportRequest) ProtoMessage() {}
+
+func (x *MSTImportRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_mst_proto_msgTypes[11]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
 		}
-		file_mst_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*MSTPutRequest); i {
-			case 0:
-				return &v.state
-			case 1:
-				return &v.sizeCache
-			case 2:
-				return &v.unknownFields
-			default:
-				return nil
-			}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MSTImportRequest.ProtoReflect.Descriptor instead.
+func (*MSTImportRequest) Descriptor() ([]byte, []int) {
+	return file_mst_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *MSTImportRequest) GetKey() []byte {
+	if x != nil {
+		return x.Key
+	}
+	return nil
+}
+
+func (x *MSTImportRequest) GetValue() []byte {
+	if x != nil {
+		return x.Value
+	}
+	return nil
+}
+
+func (x *MSTImportRequest) GetKeyspace() string {
+	if x != nil {
+		return x.Keyspace
+	}
+	return ""
+}
+
+type MSTImportResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The number of pairs that were imported.
+	Count    uint64 `protobuf:"varint,1,opt,name=count,proto3" json:"count,omitempty"`
+	RootHash []byte `protobuf:"bytes,2,opt,name=root_hash,json=rootHash,proto3" json:"root_hash,omitempty"`
+}
+
+func (x *MSTImportResponse) Reset() {
+	*x = MSTImportResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_mst_proto_msgTypes[12]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *MSTImportResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MSTImportResponse) ProtoMessage() {}
+
+func (x *MSTImportResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_mst_proto_msgTypes[12]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
 		}
-		file_mst_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*MSTGetRequest); i {
-			case 0:
-				return &v.state
-			case 1:
-				return &v.sizeCache
-			case 2:
-				return &v.unknownFields
-			default:
-				return nil
-			}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MSTImportResponse.ProtoReflect.Descriptor instead.
+func (*MSTImportResponse) Descriptor() ([]byte, []int) {
+	return file_mst_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *MSTImportResponse) GetCount() uint64 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+func (x *MSTImportResponse) GetRootHash() []byte {
+	if x != nil {
+		return x.RootHash
+	}
+	return nil
+}
+
+type MSTSnapshotRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Keyspace string `protobuf:"bytes,1,opt,name=keyspace,proto3" json:"keyspace,omitempty"`
+	// Archives a snapshot instead of the current tree if set.
+	Snapshot string `protobuf:"bytes,2,opt,name=snapshot,proto3" json:"snapshot,omitempty"`
+}
+
+func (x *MSTSnapshotRequest) Reset() {
+	*x = MSTSnapshotRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_mst_proto_msgTypes[13]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *MSTSnapshotRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MSTSnapshotRequest) ProtoMessage() {}
+
+func (x *MSTSnapshotRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_mst_proto_msgTypes[13]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
 		}
-		file_mst_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*MSTGetResponse); i {
-			case 0:
-				return &v.state
-			case 1:
-				return &v.sizeCache
-			case 2:
-				return &v.unknownFields
-			default:
-				return nil
-			}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MSTSnapshotRequest.ProtoReflect.Descriptor instead.
+func (*MSTSnapshotRequest) Descriptor() ([]byte, []int) {
+	return file_mst_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *MSTSnapshotRequest) GetKeyspace() string {
+	if x != nil {
+		return x.Keyspace
+	}
+	return ""
+}
+
+func (x *MSTSnapshotRequest) GetSnapshot() string {
+	if x != nil {
+		return x.Snapshot
+	}
+	return ""
+}
+
+// MSTArchiveChunk holds the next part of an archive of the tree. The archive starts with a header
+// naming the root hash, base and hash function of the tree, followed by every node of the tree.
+type MSTArchiveChunk struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Data []byte `protobuf:"bytes,1,opt,name=data,proto3" json:"data,omitempty"`
+	// Only read from the first chunk of a restore.
+	Keyspace string `protobuf:"bytes,2,opt,name=keyspace,proto3" json:"keyspace,omitempty"`
+}
+
+func (x *MSTArchiveChunk) Reset() {
+	*x = MSTArchiveChunk{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_mst_proto_msgTypes[14]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *MSTArchiveChunk) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MSTArchiveChunk) ProtoMessage() {}
+
+func (x *MSTArchiveChunk) ProtoReflect() protoreflect.Message {
+	mi := &file_mst_proto_msgTypes[14]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
 		}
-		file_mst_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*MSTRoundStartRequest); i {
-			case 0:
-				return &v.state
-			case 1:
-				return &v.sizeCache
-			case 2:
-				return &v.unknownFields
-			default:
-				return nil
-			}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MSTArchiveChunk.ProtoReflect.Descriptor instead.
+func (*MSTArchiveChunk) Descriptor() ([]byte, []int) {
+	return file_mst_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *MSTArchiveChunk) GetData() []byte {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+func (x *MSTArchiveChunk) GetKeyspace() string {
+	if x != nil {
+		return x.Keyspace
+	}
+	return ""
+}
+
+type MSTRestoreResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	RootHash []byte `protobuf:"bytes,1,opt,name=root_hash,json=rootHash,proto3" json:"root_hash,omitempty"`
+}
+
+func (x *MSTRestoreResponse) Reset() {
+	*x = MSTRestoreResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_mst_proto_msgTypes[15]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *MSTRestoreResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MSTRestoreResponse) ProtoMessage() {}
+
+func (x *MSTRestoreResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_mst_proto_msgTypes[15]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
 		}
-		file_mst_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*MSTRoundStepRequest); i {
-			case 0:
-				return &v.state
-			case 1:
-				return &v.sizeCache
-			case 2:
-				return &v.unknownFields
-			default:
-				return nil
-			}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MSTRestoreResponse.ProtoReflect.Descriptor instead.
+func (*MSTRestoreResponse) Descriptor() ([]byte, []int) {
+	return file_mst_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *MSTRestoreResponse) GetRootHash() []byte {
+	if x != nil {
+		return x.RootHash
+	}
+	return nil
+}
+
+type MSTScanRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// start is inclusive and end is exclusive. Leaving either empty leaves that side unbounded.
+	Start   []byte `protobuf:"bytes,1,opt,name=start,proto3" json:"start,omitempty"`
+	End     []byte `protobuf:"bytes,2,opt,name=end,proto3" json:"end,omitempty"`
+	Reverse bool   `protobuf:"varint,3,opt,name=reverse,proto3" json:"reverse,omitempty"`
+	// A limit of 0 returns every key in the range.
+	Limit uint32 `protobuf:"varint,4,opt,name=limit,proto3" json:"limit,omitempty"`
+	// Only keys with this prefix are returned if it's set, which the key type has to support.
+	Prefix []byte `protobuf:"bytes,5,opt,name=prefix,proto3" json:"prefix,omitempty"`
+	// Scans the tree at a past root or a snapshot like MSTGetRequest.
+	RootHash []byte `protobuf:"bytes,6,opt,name=root_hash,json=rootHash,proto3" json:"root_hash,omitempty"`
+	Snapshot string `protobuf:"bytes,7,opt,name=snapshot,proto3" json:"snapshot,omitempty"`
+	Keyspace string `protobuf:"bytes,8,opt,name=keyspace,proto3" json:"keyspace,omitempty"`
+}
+
+func (x *MSTScanRequest) Reset() {
+	*x = MSTScanRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_mst_proto_msgTypes[16]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *MSTScanRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MSTScanRequest) ProtoMessage() {}
+
+func (x *MSTScanRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_mst_proto_msgTypes[16]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
 		}
-		file_mst_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*MSTRoundStepResponse); i {
-			case 0:
-				return &v.state
-			case 1:
-				return &v.sizeCache
-			case 2:
-				return &v.unknownFields
-			default:
-				return nil
-			}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MSTScanRequest.ProtoReflect.Descriptor instead.
+func (*MSTScanRequest) Descriptor() ([]byte, []int) {
+	return file_mst_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *MSTScanRequest) GetStart() []byte {
+	if x != nil {
+		return x.Start
+	}
+	return nil
+}
+
+func (x *MSTScanRequest) GetEnd() []byte {
+	if x != nil {
+		return x.End
+	}
+	return nil
+}
+
+func (x *MSTScanRequest) GetReverse() bool {
+	if x != nil {
+		return x.Reverse
+	}
+	return false
+}
+
+func (x *MSTScanRequest) GetLimit() uint32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *MSTScanRequest) GetPrefix() []byte {
+	if x != nil {
+		return x.Prefix
+	}
+	return nil
+}
+
+func (x *MSTScanRequest) GetRootHash() []byte {
+	if x != nil {
+		return x.RootHash
+	}
+	return nil
+}
+
+func (x *MSTScanRequest) GetSnapshot() string {
+	if x != nil {
+		return x.Snapshot
+	}
+	return ""
+}
+
+func (x *MSTScanRequest) GetKeyspace() string {
+	if x != nil {
+		return x.Keyspace
+	}
+	return ""
+}
+
+type MSTAggregateRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// start is inclusive and end is exclusive. Leaving either empty leaves that side unbounded.
+	Start []byte `protobuf:"bytes,1,opt,name=start,proto3" json:"start,omitempty"`
+	End   []byte `protobuf:"bytes,2,opt,name=end,proto3" json:"end,omitempty"`
+	// Aggregates the tree at a past root or a snapshot like MSTGetRequest.
+	RootHash []byte `protobuf:"bytes,3,opt,name=root_hash,json=rootHash,proto3" json:"root_hash,omitempty"`
+	Snapshot string `protobuf:"bytes,4,opt,name=snapshot,proto3" json:"snapshot,omitempty"`
+	Keyspace string `protobuf:"bytes,5,opt,name=keyspace,proto3" json:"keyspace,omitempty"`
+}
+
+func (x *MSTAggregateRequest) Reset() {
+	*x = MSTAggregateRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_mst_proto_msgTypes[17]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *MSTAggregateRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MSTAggregateRequest) ProtoMessage() {}
+
+func (x *MSTAggregateRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_mst_proto_msgTypes[17]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MSTAggregateRequest.ProtoReflect.Descriptor instead.
+func (*MSTAggregateRequest) Descriptor() ([]byte, []int) {
+	return file_mst_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *MSTAggregateRequest) GetStart() []byte {
+	if x != nil {
+		return x.Start
+	}
+	return nil
+}
+
+func (x *MSTAggregateRequest) GetEnd() []byte {
+	if x != nil {
+		return x.End
+	}
+	return nil
+}
+
+func (x *MSTAggregateRequest) GetRootHash() []byte {
+	if x != nil {
+		return x.RootHash
+	}
+	return nil
+}
+
+func (x *MSTAggregateRequest) GetSnapshot() string {
+	if x != nil {
+		return x.Snapshot
+	}
+	return ""
+}
+
+func (x *MSTAggregateRequest) GetKeyspace() string {
+	if x != nil {
+		return x.Keyspace
+	}
+	return ""
+}
+
+type MSTScanResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Key   []byte `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	Value []byte `protobuf:"bytes,2,opt,name=value,proto3" json:"value,omitempty"`
+}
+
+func (x *MSTScanResponse) Reset() {
+	*x = MSTScanResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_mst_proto_msgTypes[18]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *MSTScanResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MSTScanResponse) ProtoMessage() {}
+
+func (x *MSTScanResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_mst_proto_msgTypes[18]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MSTScanResponse.ProtoReflect.Descriptor instead.
+func (*MSTScanResponse) Descriptor() ([]byte, []int) {
+	return file_mst_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *MSTScanResponse) GetKey() []byte {
+	if x != nil {
+		return x.Key
+	}
+	return nil
+}
+
+func (x *MSTScanResponse) GetValue() []byte {
+	if x != nil {
+		return x.Value
+	}
+	return nil
+}
+
+type MSTSnapshot struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Name     string               `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	RootHash []byte               `protobuf:"bytes,2,opt,name=root_hash,json=rootHash,proto3" json:"root_hash,omitempty"`
+	Created  *timestamp.Timestamp `protobuf:"bytes,3,opt,name=created,proto3" json:"created,omitempty"`
+}
+
+func (x *MSTSnapshot) Reset() {
+	*x = MSTSnapshot{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_mst_proto_msgTypes[19]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *MSTSnapshot) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MSTSnapshot) ProtoMessage() {}
+
+func (x *MSTSnapshot) ProtoReflect() protoreflect.Message {
+	mi := &file_mst_proto_msgTypes[19]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
 		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MSTSnapshot.ProtoReflect.Descriptor instead.
+func (*MSTSnapshot) Descriptor() ([]byte, []int) {
+	return file_mst_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *MSTSnapshot) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *MSTSnapshot) GetRootHash() []byte {
+	if x != nil {
+		return x.RootHash
+	}
+	return nil
+}
+
+func (x *MSTSnapshot) GetCreated() *timestamp.Timestamp {
+	if x != nil {
+		return x.Created
+	}
+	return nil
+}
+
+type MSTCreateSnapshotRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	// The root to name, which has to be in the root history. Defaults to the current root.
+	RootHash []byte `protobuf:"bytes,2,opt,name=root_hash,json=rootHash,proto3" json:"root_hash,omitempty"`
+	Keyspace string `protobuf:"bytes,3,opt,name=keyspace,proto3" json:"keyspace,omitempty"`
+}
+
+func (x *MSTCreateSnapshotRequest) Reset() {
+	*x = MSTCreateSnapshotRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_mst_proto_msgTypes[20]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *MSTCreateSnapshotRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MSTCreateSnapshotRequest) ProtoMessage() {}
+
+func (x *MSTCreateSnapshotRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_mst_proto_msgTypes[20]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MSTCreateSnapshotRequest.ProtoReflect.Descriptor instead.
+func (*MSTCreateSnapshotRequest) Descriptor() ([]byte, []int) {
+	return file_mst_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *MSTCreateSnapshotRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *MSTCreateSnapshotRequest) GetRootHash() []byte {
+	if x != nil {
+		return x.RootHash
+	}
+	return nil
+}
+
+func (x *MSTCreateSnapshotRequest) GetKeyspace() string {
+	if x != nil {
+		return x.Keyspace
+	}
+	return ""
+}
+
+type MSTDeleteSnapshotRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Name     string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Keyspace string `protobuf:"bytes,2,opt,name=keyspace,proto3" json:"keyspace,omitempty"`
+}
+
+func (x *MSTDeleteSnapshotRequest) Reset() {
+	*x = MSTDeleteSnapshotRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_mst_proto_msgTypes[21]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *MSTDeleteSnapshotRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MSTDeleteSnapshotRequest) ProtoMessage() {}
+
+func (x *MSTDeleteSnapshotRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_mst_proto_msgTypes[21]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MSTDeleteSnapshotRequest.ProtoReflect.Descriptor instead.
+func (*MSTDeleteSnapshotRequest) Descriptor() ([]byte, []int) {
+	return file_mst_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *MSTDeleteSnapshotRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *MSTDeleteSnapshotRequest) GetKeyspace() string {
+	if x != nil {
+		return x.Keyspace
+	}
+	return ""
+}
+
+type MSTListSnapshotsRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Keyspace string `protobuf:"bytes,1,opt,name=keyspace,proto3" json:"keyspace,omitempty"`
+}
+
+func (x *MSTListSnapshotsRequest) Reset() {
+	*x = MSTListSnapshotsRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_mst_proto_msgTypes[22]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *MSTListSnapshotsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MSTListSnapshotsRequest) ProtoMessage() {}
+
+func (x *MSTListSnapshotsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_mst_proto_msgTypes[22]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MSTListSnapshotsRequest.ProtoReflect.Descriptor instead.
+func (*MSTListSnapshotsRequest) Descriptor() ([]byte, []int) {
+	return file_mst_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *MSTListSnapshotsRequest) GetKeyspace() string {
+	if x != nil {
+		return x.Keyspace
+	}
+	return ""
+}
+
+type MSTListSnapshotsResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Snapshots []*MSTSnapshot `protobuf:"bytes,1,rep,name=snapshots,proto3" json:"snapshots,omitempty"`
+}
+
+func (x *MSTListSnapshotsResponse) Reset() {
+	*x = MSTListSnapshotsResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_mst_proto_msgTypes[23]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *MSTListSnapshotsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MSTListSnapshotsResponse) ProtoMessage() {}
+
+func (x *MSTListSnapshotsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_mst_proto_msgTypes[23]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MSTListSnapshotsResponse.ProtoReflect.Descriptor instead.
+func (*MSTListSnapshotsResponse) Descriptor() ([]byte, []int) {
+	return file_mst_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *MSTListSnapshotsResponse) GetSnapshots() []*MSTSnapshot {
+	if x != nil {
+		return x.Snapshots
+	}
+	return nil
+}
+
+type MSTRootHistoryRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// A limit of 0 returns every root in the history.
+	Limit    uint32 `protobuf:"varint,1,opt,name=limit,proto3" json:"limit,omitempty"`
+	Keyspace string `protobuf:"bytes,2,opt,name=keyspace,proto3" json:"keyspace,omitempty"`
+}
+
+func (x *MSTRootHistoryRequest) Reset() {
+	*x = MSTRootHistoryRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_mst_proto_msgTypes[24]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *MSTRootHistoryRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MSTRootHistoryRequest) ProtoMessage() {}
+
+func (x *MSTRootHistoryRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_mst_proto_msgTypes[24]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MSTRootHistoryRequest.ProtoReflect.Descriptor instead.
+func (*MSTRootHistoryRequest) Descriptor() ([]byte, []int) {
+	return file_mst_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *MSTRootHistoryRequest) GetLimit() uint32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *MSTRootHistoryRequest) GetKeyspace() string {
+	if x != nil {
+		return x.Keyspace
+	}
+	return ""
+}
+
+type MSTRootHistoryEntry struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	RootHash []byte `protobuf:"bytes,1,opt,name=root_hash,json=rootHash,proto3" json:"root_hash,omitempty"`
+	// When the tree changed to this root.
+	Time *timestamp.Timestamp `protobuf:"bytes,2,opt,name=time,proto3" json:"time,omitempty"`
+}
+
+func (x *MSTRootHistoryEntry) Reset() {
+	*x = MSTRootHistoryEntry{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_mst_proto_msgTypes[25]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *MSTRootHistoryEntry) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MSTRootHistoryEntry) ProtoMessage() {}
+
+func (x *MSTRootHistoryEntry) ProtoReflect() protoreflect.Message {
+	mi := &file_mst_proto_msgTypes[25]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MSTRootHistoryEntry.ProtoReflect.Descriptor instead.
+func (*MSTRootHistoryEntry) Descriptor() ([]byte, []int) {
+	return file_mst_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *MSTRootHistoryEntry) GetRootHash() []byte {
+	if x != nil {
+		return x.RootHash
+	}
+	return nil
+}
+
+func (x *MSTRootHistoryEntry) GetTime() *timestamp.Timestamp {
+	if x != nil {
+		return x.Time
+	}
+	return nil
+}
+
+type MSTRootHistoryResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The newest root comes first and is the current root.
+	Entries []*MSTRootHistoryEntry `protobuf:"bytes,1,rep,name=entries,proto3" json:"entries,omitempty"`
+}
+
+func (x *MSTRootHistoryResponse) Reset() {
+	*x = MSTRootHistoryResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_mst_proto_msgTypes[26]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *MSTRootHistoryResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MSTRootHistoryResponse) ProtoMessage() {}
+
+func (x *MSTRootHistoryResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_mst_proto_msgTypes[26]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MSTRootHistoryResponse.ProtoReflect.Descriptor instead.
+func (*MSTRootHistoryResponse) Descriptor() ([]byte, []int) {
+	return file_mst_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *MSTRootHistoryResponse) GetEntries() []*MSTRootHistoryEntry {
+	if x != nil {
+		return x.Entries
+	}
+	return nil
+}
+
+type MSTRoundStartRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	RoundUuid []byte `protobuf:"bytes,1,opt,name=round_uuid,json=roundUuid,proto3" json:"round_uuid,omitempty"`
+	RootHash  []byte `protobuf:"bytes,2,opt,name=root_hash,json=rootHash,proto3" json:"root_hash,omitempty"`
+	Keyspace  string `protobuf:"bytes,3,opt,name=keyspace,proto3" json:"keyspace,omitempty"`
+}
+
+func (x *MSTRoundStartRequest) Reset() {
+	*x = MSTRoundStartRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_mst_proto_msgTypes[27]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *MSTRoundStartRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MSTRoundStartRequest) ProtoMessage() {}
+
+func (x *MSTRoundStartRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_mst_proto_msgTypes[27]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MSTRoundStartRequest.ProtoReflect.Descriptor instead.
+func (*MSTRoundStartRequest) Descriptor() ([]byte, []int) {
+	return file_mst_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *MSTRoundStartRequest) GetRoundUuid() []byte {
+	if x != nil {
+		return x.RoundUuid
+	}
+	return nil
+}
+
+func (x *MSTRoundStartRequest) GetRootHash() []byte {
+	if x != nil {
+		return x.RootHash
+	}
+	return nil
+}
+
+func (x *MSTRoundStartRequest) GetKeyspace() string {
+	if x != nil {
+		return x.Keyspace
+	}
+	return ""
+}
+
+type MSTRoundStepRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	RoundUuid []byte     `protobuf:"bytes,1,opt,name=round_uuid,json=roundUuid,proto3" json:"round_uuid,omitempty"`
+	Nodes     []*MSTNode `protobuf:"bytes,2,rep,name=nodes,proto3" json:"nodes,omitempty"`
+}
+
+func (x *MSTRoundStepRequest) Reset() {
+	*x = MSTRoundStepRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_mst_proto_msgTypes[28]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *MSTRoundStepRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MSTRoundStepRequest) ProtoMessage() {}
+
+func (x *MSTRoundStepRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_mst_proto_msgTypes[28]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MSTRoundStepRequest.ProtoReflect.Descriptor instead.
+func (*MSTRoundStepRequest) Descriptor() ([]byte, []int) {
+	return file_mst_proto_rawDescGZIP(), []int{28}
+}
+
+func (x *MSTRoundStepRequest) GetRoundUuid() []byte {
+	if x != nil {
+		return x.RoundUuid
+	}
+	return nil
+}
+
+func (x *MSTRoundStepRequest) GetNodes() []*MSTNode {
+	if x != nil {
+		return x.Nodes
+	}
+	return nil
+}
+
+type MSTRoundStepResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Hashes [][]byte `protobuf:"bytes,1,rep,name=hashes,proto3" json:"hashes,omitempty"`
+}
+
+func (x *MSTRoundStepResponse) Reset() {
+	*x = MSTRoundStepResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_mst_proto_msgTypes[29]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *MSTRoundStepResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MSTRoundStepResponse) ProtoMessage() {}
+
+func (x *MSTRoundStepResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_mst_proto_msgTypes[29]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MSTRoundStepResponse.ProtoReflect.Descriptor instead.
+func (*MSTRoundStepResponse) Descriptor() ([]byte, []int) {
+	return file_mst_proto_rawDescGZIP(), []int{29}
+}
+
+func (x *MSTRoundStepResponse) GetHashes() [][]byte {
+	if x != nil {
+		return x.Hashes
+	}
+	return nil
+}
+
+// MSTKeyspace describes a keyspace. base is the log2 of the tree's base, from 1 to 5, and hash is
+// the number Go's crypto package gives the hash function. Key types, value types and aggregators
+// are named like the server's flags name them, and an empty aggregator keeps no aggregates.
+type MSTKeyspace struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Name       string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Base       uint32 `protobuf:"varint,2,opt,name=base,proto3" json:"base,omitempty"`
+	Hash       uint32 `protobuf:"varint,3,opt,name=hash,proto3" json:"hash,omitempty"`
+	KeyType    string `protobuf:"bytes,4,opt,name=key_type,json=keyType,proto3" json:"key_type,omitempty"`
+	ValueType  string `protobuf:"bytes,5,opt,name=value_type,json=valueType,proto3" json:"value_type,omitempty"`
+	Aggregator string `protobuf:"bytes,6,opt,name=aggregator,proto3" json:"aggregator,omitempty"`
+}
+
+func (x *MSTKeyspace) Reset() {
+	*x = MSTKeyspace{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_mst_proto_msgTypes[30]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *MSTKeyspace) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MSTKeyspace) ProtoMessage() {}
+
+func (x *MSTKeyspace) ProtoReflect() protoreflect.Message {
+	mi := &file_mst_proto_msgTypes[30]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MSTKeyspace.ProtoReflect.Descriptor instead.
+func (*MSTKeyspace) Descriptor() ([]byte, []int) {
+	return file_mst_proto_rawDescGZIP(), []int{30}
+}
+
+func (x *MSTKeyspace) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *MSTKeyspace) GetBase() uint32 {
+	if x != nil {
+		return x.Base
+	}
+	return 0
+}
+
+func (x *MSTKeyspace) GetHash() uint32 {
+	if x != nil {
+		return x.Hash
+	}
+	return 0
+}
+
+func (x *MSTKeyspace) GetKeyType() string {
+	if x != nil {
+		return x.KeyType
+	}
+	return ""
+}
+
+func (x *MSTKeyspace) GetValueType() string {
+	if x != nil {
+		return x.ValueType
+	}
+	return ""
+}
+
+func (x *MSTKeyspace) GetAggregator() string {
+	if x != nil {
+		return x.Aggregator
+	}
+	return ""
+}
+
+type MSTDropKeyspaceRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+}
+
+func (x *MSTDropKeyspaceRequest) Reset() {
+	*x = MSTDropKeyspaceRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_mst_proto_msgTypes[31]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *MSTDropKeyspaceRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MSTDropKeyspaceRequest) ProtoMessage() {}
+
+func (x *MSTDropKeyspaceRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_mst_proto_msgTypes[31]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MSTDropKeyspaceRequest.ProtoReflect.Descriptor instead.
+func (*MSTDropKeyspaceRequest) Descriptor() ([]byte, []int) {
+	return file_mst_proto_rawDescGZIP(), []int{31}
+}
+
+func (x *MSTDropKeyspaceRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+type MSTListKeyspacesResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Keyspaces []*MSTKeyspace `protobuf:"bytes,1,rep,name=keyspaces,proto3" json:"keyspaces,omitempty"`
+}
+
+func (x *MSTListKeyspacesResponse) Reset() {
+	*x = MSTListKeyspacesResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_mst_proto_msgTypes[32]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *MSTListKeyspacesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MSTListKeyspacesResponse) ProtoMessage() {}
+
+func (x *MSTListKeyspacesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_mst_proto_msgTypes[32]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MSTListKeyspacesResponse.ProtoReflect.Descriptor instead.
+func (*MSTListKeyspacesResponse) Descriptor() ([]byte, []int) {
+	return file_mst_proto_rawDescGZIP(), []int{32}
+}
+
+func (x *MSTListKeyspacesResponse) GetKeyspaces() []*MSTKeyspace {
+	if x != nil {
+		return x.Keyspaces
+	}
+	return nil
+}
+
+// MSTCatalogEntry is the entry of a keyspace in the catalog, a tree that commits to the current
+// root of every keyspace of a server under a single root. schema_hash identifies the description
+// of the keyspace.
+type MSTCatalogEntry struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Keyspace   string `protobuf:"bytes,1,opt,name=keyspace,proto3" json:"keyspace,omitempty"`
+	RootHash   []byte `protobuf:"bytes,2,opt,name=root_hash,json=rootHash,proto3" json:"root_hash,omitempty"`
+	SchemaHash []byte `protobuf:"bytes,3,opt,name=schema_hash,json=schemaHash,proto3" json:"schema_hash,omitempty"`
+}
+
+func (x *MSTCatalogEntry) Reset() {
+	*x = MSTCatalogEntry{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_mst_proto_msgTypes[33]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *MSTCatalogEntry) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MSTCatalogEntry) ProtoMessage() {}
+
+func (x *MSTCatalogEntry) ProtoReflect() protoreflect.Message {
+	mi := &file_mst_proto_msgTypes[33]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MSTCatalogEntry.ProtoReflect.Descriptor instead.
+func (*MSTCatalogEntry) Descriptor() ([]byte, []int) {
+	return file_mst_proto_rawDescGZIP(), []int{33}
+}
+
+func (x *MSTCatalogEntry) GetKeyspace() string {
+	if x != nil {
+		return x.Keyspace
+	}
+	return ""
+}
+
+func (x *MSTCatalogEntry) GetRootHash() []byte {
+	if x != nil {
+		return x.RootHash
+	}
+	return nil
+}
+
+func (x *MSTCatalogEntry) GetSchemaHash() []byte {
+	if x != nil {
+		return x.SchemaHash
+	}
+	return nil
+}
+
+type MSTCatalogRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Entries are left out if the root of the catalog is known_root_hash, so checking whether
+	// anything differs only takes one hash.
+	KnownRootHash []byte `protobuf:"bytes,1,opt,name=known_root_hash,json=knownRootHash,proto3" json:"known_root_hash,omitempty"`
+}
+
+func (x *MSTCatalogRequest) Reset() {
+	*x = MSTCatalogRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_mst_proto_msgTypes[34]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *MSTCatalogRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MSTCatalogRequest) ProtoMessage() {}
+
+func (x *MSTCatalogRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_mst_proto_msgTypes[34]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MSTCatalogRequest.ProtoReflect.Descriptor instead.
+func (*MSTCatalogRequest) Descriptor() ([]byte, []int) {
+	return file_mst_proto_rawDescGZIP(), []int{34}
+}
+
+func (x *MSTCatalogRequest) GetKnownRootHash() []byte {
+	if x != nil {
+		return x.KnownRootHash
+	}
+	return nil
+}
+
+type MSTCatalog struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	RootHash []byte             `protobuf:"bytes,1,opt,name=root_hash,json=rootHash,proto3" json:"root_hash,omitempty"`
+	Entries  []*MSTCatalogEntry `protobuf:"bytes,2,rep,name=entries,proto3" json:"entries,omitempty"`
+}
+
+func (x *MSTCatalog) Reset() {
+	*x = MSTCatalog{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_mst_proto_msgTypes[35]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *MSTCatalog) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MSTCatalog) ProtoMessage() {}
+
+func (x *MSTCatalog) ProtoReflect() protoreflect.Message {
+	mi := &file_mst_proto_msgTypes[35]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MSTCatalog.ProtoReflect.Descriptor instead.
+func (*MSTCatalog) Descriptor() ([]byte, []int) {
+	return file_mst_proto_rawDescGZIP(), []int{35}
+}
+
+func (x *MSTCatalog) GetRootHash() []byte {
+	if x != nil {
+		return x.RootHash
+	}
+	return nil
+}
+
+func (x *MSTCatalog) GetEntries() []*MSTCatalogEntry {
+	if x != nil {
+		return x.Entries
+	}
+	return nil
+}
+
+type MSTDatabaseSnapshotRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+}
+
+func (x *MSTDatabaseSnapshotRequest) Reset() {
+	*x = MSTDatabaseSnapshotRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_mst_proto_msgTypes[36]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *MSTDatabaseSnapshotRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MSTDatabaseSnapshotRequest) ProtoMessage() {}
+
+func (x *MSTDatabaseSnapshotRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_mst_proto_msgTypes[36]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MSTDatabaseSnapshotRequest.ProtoReflect.Descriptor instead.
+func (*MSTDatabaseSnapshotRequest) Descriptor() ([]byte, []int) {
+	return file_mst_proto_rawDescGZIP(), []int{36}
+}
+
+func (x *MSTDatabaseSnapshotRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+var File_mst_proto protoreflect.FileDescriptor
+
+var file_mst_proto_rawDesc = []byte{
+	0x0a, 0x09, 0x6d, 0x73, 0x74, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x13, 0x76, 0x75, 0x6c,
+	0x74, 0x75, 0x72, 0x65, 0x2e, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x72, 0x70, 0x63,
+	0x1a, 0x1b, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75,
+	0x66, 0x2f, 0x65, 0x6d, 0x70, 0x74, 0x79, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x1f, 0x67,
+	0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x74,
+	0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0x7e,
+	0x0a, 0x08, 0x4d, 0x53, 0x54, 0x43, 0x68, 0x69, 0x6c, 0x64, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65,
+	0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a, 0x05,
+	0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x05, 0x76, 0x61, 0x6c,
+	0x75, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x68, 0x69, 0x67, 0x68, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0c,
+	0x52, 0x04, 0x68, 0x69, 0x67, 0x68, 0x12, 0x1c, 0x0a, 0x09, 0x74, 0x6f, 0x6d, 0x62, 0x73, 0x74,
+	0x6f, 0x6e, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x08, 0x52, 0x09, 0x74, 0x6f, 0x6d, 0x62, 0x73,
+	0x74, 0x6f, 0x6e, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x64, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x64, 0x18,
+	0x05, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x07, 0x64, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x64, 0x22, 0x74,
+	0x0a, 0x0a, 0x4d, 0x53, 0x54, 0x53, 0x75, 0x6d, 0x6d, 0x61, 0x72, 0x79, 0x12, 0x14, 0x0a, 0x05,
+	0x63, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x05, 0x63, 0x6f, 0x75,
+	0x6e, 0x74, 0x12, 0x1a, 0x0a, 0x08, 0x6d, 0x65, 0x61, 0x73, 0x75, 0x72, 0x65, 0x64, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x04, 0x52, 0x08, 0x6d, 0x65, 0x61, 0x73, 0x75, 0x72, 0x65, 0x64, 0x12, 0x10,
+	0x0a, 0x03, 0x73, 0x75, 0x6d, 0x18, 0x03, 0x20, 0x01, 0x28, 0x01, 0x52, 0x03, 0x73, 0x75, 0x6d,
+	0x12, 0x10, 0x0a, 0x03, 0x6d, 0x69, 0x6e, 0x18, 0x04, 0x20, 0x01, 0x28, 0x01, 0x52, 0x03, 0x6d,
+	0x69, 0x6e, 0x12, 0x10, 0x0a, 0x03, 0x6d, 0x61, 0x78, 0x18, 0x05, 0x20, 0x01, 0x28, 0x01, 0x52,
+	0x03, 0x6d, 0x61, 0x78, 0x22, 0xab, 0x01, 0x0a, 0x07, 0x4d, 0x53, 0x54, 0x4e, 0x6f, 0x64, 0x65,
+	0x12, 0x14, 0x0a, 0x05, 0x6c, 0x65, 0x76, 0x65, 0x6c, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0d, 0x52,
+	0x05, 0x6c, 0x65, 0x76, 0x65, 0x6c, 0x12, 0x10, 0x0a, 0x03, 0x6c, 0x6f, 0x77, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x0c, 0x52, 0x03, 0x6c, 0x6f, 0x77, 0x12, 0x39, 0x0a, 0x08, 0x63, 0x68, 0x69, 0x6c,
+	0x64, 0x72, 0x65, 0x6e, 0x18, 0x03, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1d, 0x2e, 0x76, 0x75, 0x6c,
+	0x74, 0x75, 0x72, 0x65, 0x2e, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x72, 0x70, 0x63,
+	0x2e, 0x4d, 0x53, 0x54, 0x43, 0x68, 0x69, 0x6c, 0x64, 0x52, 0x08, 0x63, 0x68, 0x69, 0x6c, 0x64,
+	0x72, 0x65, 0x6e, 0x12, 0x3d, 0x0a, 0x09, 0x73, 0x75, 0x6d, 0x6d, 0x61, 0x72, 0x69, 0x65, 0x73,
+	0x18, 0x04, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1f, 0x2e, 0x76, 0x75, 0x6c, 0x74, 0x75, 0x72, 0x65,
+	0x2e, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x72, 0x70, 0x63, 0x2e, 0x4d, 0x53, 0x54,
+	0x53, 0x75, 0x6d, 0x6d, 0x61, 0x72, 0x79, 0x52, 0x09, 0x73, 0x75, 0x6d, 0x6d, 0x61, 0x72, 0x69,
+	0x65, 0x73, 0x22, 0x53, 0x0a, 0x0d, 0x4d, 0x53, 0x54, 0x50, 0x75, 0x74, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c,
+	0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x0c, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x12, 0x1a, 0x0a, 0x08, 0x6b,
+	0x65, 0x79, 0x73, 0x70, 0x61, 0x63, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x6b,
+	0x65, 0x79, 0x73, 0x70, 0x61, 0x63, 0x65, 0x22, 0x76, 0x0a, 0x0d, 0x4d, 0x53, 0x54, 0x47, 0x65,
+	0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x1b, 0x0a, 0x09, 0x72, 0x6f,
+	0x6f, 0x74, 0x5f, 0x68, 0x61, 0x73, 0x68, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x08, 0x72,
+	0x6f, 0x6f, 0x74, 0x48, 0x61, 0x73, 0x68, 0x12, 0x1a, 0x0a, 0x08, 0x73, 0x6e, 0x61, 0x70, 0x73,
+	0x68, 0x6f, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x73, 0x6e, 0x61, 0x70, 0x73,
+	0x68, 0x6f, 0x74, 0x12, 0x1a, 0x0a, 0x08, 0x6b, 0x65, 0x79, 0x73, 0x70, 0x61, 0x63, 0x65, 0x18,
+	0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x6b, 0x65, 0x79, 0x73, 0x70, 0x61, 0x63, 0x65, 0x22,
+	0x3c, 0x0a, 0x0e, 0x4d, 0x53, 0x54, 0x47, 0x65, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
+	0x65, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c,
+	0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x66, 0x6f, 0x75, 0x6e, 0x64,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x08, 0x52, 0x05, 0x66, 0x6f, 0x75, 0x6e, 0x64, 0x22, 0x46, 0x0a,
+	0x16, 0x4d, 0x53, 0x54, 0x47, 0x65, 0x74, 0x57, 0x69, 0x74, 0x68, 0x50, 0x72, 0x6f, 0x6f, 0x66,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x0c, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x1a, 0x0a, 0x08, 0x6b, 0x65, 0x79,
+	0x73, 0x70, 0x61, 0x63, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x6b, 0x65, 0x79,
+	0x73, 0x70, 0x61, 0x63, 0x65, 0x22, 0x96, 0x01, 0x0a, 0x17, 0x4d, 0x53, 0x54, 0x47, 0x65, 0x74,
+	0x57, 0x69, 0x74, 0x68, 0x50, 0x72, 0x6f, 0x6f, 0x66, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
+	0x65, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c,
+	0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x66, 0x6f, 0x75, 0x6e, 0x64,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x08, 0x52, 0x05, 0x66, 0x6f, 0x75, 0x6e, 0x64, 0x12, 0x1b, 0x0a,
+	0x09, 0x72, 0x6f, 0x6f, 0x74, 0x5f, 0x68, 0x61, 0x73, 0x68, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0c,
+	0x52, 0x08, 0x72, 0x6f, 0x6f, 0x74, 0x48, 0x61, 0x73, 0x68, 0x12, 0x32, 0x0a, 0x05, 0x70, 0x72,
+	0x6f, 0x6f, 0x66, 0x18, 0x04, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1c, 0x2e, 0x76, 0x75, 0x6c, 0x74,
+	0x75, 0x72, 0x65, 0x2e, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x72, 0x70, 0x63, 0x2e,
+	0x4d, 0x53, 0x54, 0x4e, 0x6f, 0x64, 0x65, 0x52, 0x05, 0x70, 0x72, 0x6f, 0x6f, 0x66, 0x22, 0x40,
+	0x0a, 0x10, 0x4d, 0x53, 0x54, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52,
+	0x03, 0x6b, 0x65, 0x79, 0x12, 0x1a, 0x0a, 0x08, 0x6b, 0x65, 0x79, 0x73, 0x70, 0x61, 0x63, 0x65,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x6b, 0x65, 0x79, 0x73, 0x70, 0x61, 0x63, 0x65,
+	0x22, 0x4a, 0x0a, 0x08, 0x4d, 0x53, 0x54, 0x57, 0x72, 0x69, 0x74, 0x65, 0x12, 0x10, 0x0a, 0x03,
+	0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x14,
+	0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x05, 0x76,
+	0x61, 0x6c, 0x75, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x64, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x18, 0x03,
+	0x20, 0x01, 0x28, 0x08, 0x52, 0x06, 0x64, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x22, 0x69, 0x0a, 0x14,
+	0x4d, 0x53, 0x54, 0x57, 0x72, 0x69, 0x74, 0x65, 0x42, 0x61, 0x74, 0x63, 0x68, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x12, 0x35, 0x0a, 0x06, 0x77, 0x72, 0x69, 0x74, 0x65, 0x73, 0x18, 0x01,
+	0x20, 0x03, 0x28, 0x0b, 0x32, 0x1d, 0x2e, 0x76, 0x75, 0x6c, 0x74, 0x75, 0x72, 0x65, 0x2e, 0x73,
+	0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x72, 0x70, 0x63, 0x2e, 0x4d, 0x53, 0x54, 0x57, 0x72,
+	0x69, 0x74, 0x65, 0x52, 0x06, 0x77, 0x72, 0x69, 0x74, 0x65, 0x73, 0x12, 0x1a, 0x0a, 0x08, 0x6b,
+	0x65, 0x79, 0x73, 0x70, 0x61, 0x63, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x6b,
+	0x65, 0x79, 0x73, 0x70, 0x61, 0x63, 0x65, 0x22, 0x56, 0x0a, 0x10, 0x4d, 0x53, 0x54, 0x49, 0x6d,
+	0x70, 0x6f, 0x72, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x10, 0x0a, 0x03, 0x6b,
+	0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a,
+	0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x05, 0x76, 0x61,
+	0x6c, 0x75, 0x65, 0x12, 0x1a, 0x0a, 0x08, 0x6b, 0x65, 0x79, 0x73, 0x70, 0x61, 0x63, 0x65, 0x18,
+	0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x6b, 0x65, 0x79, 0x73, 0x70, 0x61, 0x63, 0x65, 0x22,
+	0x46, 0x0a, 0x11, 0x4d, 0x53, 0x54, 0x49, 0x6d, 0x70, 0x6f, 0x72, 0x74, 0x52, 0x65, 0x73, 0x70,
+	0x6f, 0x6e, 0x73, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x04, 0x52, 0x05, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x1b, 0x0a, 0x09, 0x72, 0x6f,
+	0x6f, 0x74, 0x5f, 0x68, 0x61, 0x73, 0x68, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x08, 0x72,
+	0x6f, 0x6f, 0x74, 0x48, 0x61, 0x73, 0x68, 0x22, 0x4c, 0x0a, 0x12, 0x4d, 0x53, 0x54, 0x53, 0x6e,
+	0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1a, 0x0a,
+	0x08, 0x6b, 0x65, 0x79, 0x73, 0x70, 0x61, 0x63, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x08, 0x6b, 0x65, 0x79, 0x73, 0x70, 0x61, 0x63, 0x65, 0x12, 0x1a, 0x0a, 0x08, 0x73, 0x6e, 0x61,
+	0x70, 0x73, 0x68, 0x6f, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x73, 0x6e, 0x61,
+	0x70, 0x73, 0x68, 0x6f, 0x74, 0x22, 0x41, 0x0a, 0x0f, 0x4d, 0x53, 0x54, 0x41, 0x72, 0x63, 0x68,
+	0x69, 0x76, 0x65, 0x43, 0x68, 0x75, 0x6e, 0x6b, 0x12, 0x12, 0x0a, 0x04, 0x64, 0x61, 0x74, 0x61,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x04, 0x64, 0x61, 0x74, 0x61, 0x12, 0x1a, 0x0a, 0x08,
+	0x6b, 0x65, 0x79, 0x73, 0x70, 0x61, 0x63, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08,
+	0x6b, 0x65, 0x79, 0x73, 0x70, 0x61, 0x63, 0x65, 0x22, 0x31, 0x0a, 0x12, 0x4d, 0x53, 0x54, 0x52,
+	0x65, 0x73, 0x74, 0x6f, 0x72, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x1b,
+	0x0a, 0x09, 0x72, 0x6f, 0x6f, 0x74, 0x5f, 0x68, 0x61, 0x73, 0x68, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x0c, 0x52, 0x08, 0x72, 0x6f, 0x6f, 0x74, 0x48, 0x61, 0x73, 0x68, 0x22, 0xd5, 0x01, 0x0a, 0x0e,
+	0x4d, 0x53, 0x54, 0x53, 0x63, 0x61, 0x6e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x14,
+	0x0a, 0x05, 0x73, 0x74, 0x61, 0x72, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x05, 0x73,
+	0x74, 0x61, 0x72, 0x74, 0x12, 0x10, 0x0a, 0x03, 0x65, 0x6e, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x0c, 0x52, 0x03, 0x65, 0x6e, 0x64, 0x12, 0x18, 0x0a, 0x07, 0x72, 0x65, 0x76, 0x65, 0x72, 0x73,
+	0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x08, 0x52, 0x07, 0x72, 0x65, 0x76, 0x65, 0x72, 0x73, 0x65,
+	0x12, 0x14, 0x0a, 0x05, 0x6c, 0x69, 0x6d, 0x69, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0d, 0x52,
+	0x05, 0x6c, 0x69, 0x6d, 0x69, 0x74, 0x12, 0x16, 0x0a, 0x06, 0x70, 0x72, 0x65, 0x66, 0x69, 0x78,
+	0x18, 0x05, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x06, 0x70, 0x72, 0x65, 0x66, 0x69, 0x78, 0x12, 0x1b,
+	0x0a, 0x09, 0x72, 0x6f, 0x6f, 0x74, 0x5f, 0x68, 0x61, 0x73, 0x68, 0x18, 0x06, 0x20, 0x01, 0x28,
+	0x0c, 0x52, 0x08, 0x72, 0x6f, 0x6f, 0x74, 0x48, 0x61, 0x73, 0x68, 0x12, 0x1a, 0x0a, 0x08, 0x73,
+	0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x18, 0x07, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x73,
+	0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x12, 0x1a, 0x0a, 0x08, 0x6b, 0x65, 0x79, 0x73, 0x70,
+	0x61, 0x63, 0x65, 0x18, 0x08, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x6b, 0x65, 0x79, 0x73, 0x70,
+	0x61, 0x63, 0x65, 0x22, 0x92, 0x01, 0x0a, 0x13, 0x4d, 0x53, 0x54, 0x41, 0x67, 0x67, 0x72, 0x65,
+	0x67, 0x61, 0x74, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x73,
+	0x74, 0x61, 0x72, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x05, 0x73, 0x74, 0x61, 0x72,
+	0x74, 0x12, 0x10, 0x0a, 0x03, 0x65, 0x6e, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x03,
+	0x65, 0x6e, 0x64, 0x12, 0x1b, 0x0a, 0x09, 0x72, 0x6f, 0x6f, 0x74, 0x5f, 0x68, 0x61, 0x73, 0x68,
+	0x18, 0x03, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x08, 0x72, 0x6f, 0x6f, 0x74, 0x48, 0x61, 0x73, 0x68,
+	0x12, 0x1a, 0x0a, 0x08, 0x73, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x18, 0x04, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x08, 0x73, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x12, 0x1a, 0x0a, 0x08,
+	0x6b, 0x65, 0x79, 0x73, 0x70, 0x61, 0x63, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08,
+	0x6b, 0x65, 0x79, 0x73, 0x70, 0x61, 0x63, 0x65, 0x22, 0x39, 0x0a, 0x0f, 0x4d, 0x53, 0x54, 0x53,
+	0x63, 0x61, 0x6e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x10, 0x0a, 0x03, 0x6b,
+	0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a,
+	0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x05, 0x76, 0x61,
+	0x6c, 0x75, 0x65, 0x22, 0x74, 0x0a, 0x0b, 0x4d, 0x53, 0x54, 0x53, 0x6e, 0x61, 0x70, 0x73, 0x68,
+	0x6f, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x1b, 0x0a, 0x09, 0x72, 0x6f, 0x6f, 0x74, 0x5f, 0x68,
+	0x61, 0x73, 0x68, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x08, 0x72, 0x6f, 0x6f, 0x74, 0x48,
+	0x61, 0x73, 0x68, 0x12, 0x34, 0x0a, 0x07, 0x63, 0x72, 0x65, 0x61, 0x74, 0x65, 0x64, 0x18, 0x03,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70,
+	0x52, 0x07, 0x63, 0x72, 0x65, 0x61, 0x74, 0x65, 0x64, 0x22, 0x67, 0x0a, 0x18, 0x4d, 0x53, 0x54,
+	0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x53, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x1b, 0x0a, 0x09, 0x72, 0x6f, 0x6f,
+	0x74, 0x5f, 0x68, 0x61, 0x73, 0x68, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x08, 0x72, 0x6f,
+	0x6f, 0x74, 0x48, 0x61, 0x73, 0x68, 0x12, 0x1a, 0x0a, 0x08, 0x6b, 0x65, 0x79, 0x73, 0x70, 0x61,
+	0x63, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x6b, 0x65, 0x79, 0x73, 0x70, 0x61,
+	0x63, 0x65, 0x22, 0x4a, 0x0a, 0x18, 0x4d, 0x53, 0x54, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x53,
+	0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x12,
+	0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61,
+	0x6d, 0x65, 0x12, 0x1a, 0x0a, 0x08, 0x6b, 0x65, 0x79, 0x73, 0x70, 0x61, 0x63, 0x65, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x6b, 0x65, 0x79, 0x73, 0x70, 0x61, 0x63, 0x65, 0x22, 0x35,
+	0x0a, 0x17, 0x4d, 0x53, 0x54, 0x4c, 0x69, 0x73, 0x74, 0x53, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f,
+	0x74, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1a, 0x0a, 0x08, 0x6b, 0x65, 0x79,
+	0x73, 0x70, 0x61, 0x63, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x6b, 0x65, 0x79,
+	0x73, 0x70, 0x61, 0x63, 0x65, 0x22, 0x5a, 0x0a, 0x18, 0x4d, 0x53, 0x54, 0x4c, 0x69, 0x73, 0x74,
+	0x53, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
+	0x65, 0x12, 0x3e, 0x0a, 0x09, 0x73, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x73, 0x18, 0x01,
+	0x20, 0x03, 0x28, 0x0b, 0x32, 0x20, 0x2e, 0x76, 0x75, 0x6c, 0x74, 0x75, 0x72, 0x65, 0x2e, 0x73,
+	0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x72, 0x70, 0x63, 0x2e, 0x4d, 0x53, 0x54, 0x53, 0x6e,
+	0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x52, 0x09, 0x73, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74,
+	0x73, 0x22, 0x49, 0x0a, 0x15, 0x4d, 0x53, 0x54, 0x52, 0x6f, 0x6f, 0x74, 0x48, 0x69, 0x73, 0x74,
+	0x6f, 0x72, 0x79, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x6c, 0x69,
+	0x6d, 0x69, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x05, 0x6c, 0x69, 0x6d, 0x69, 0x74,
+	0x12, 0x1a, 0x0a, 0x08, 0x6b, 0x65, 0x79, 0x73, 0x70, 0x61, 0x63, 0x65, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x08, 0x6b, 0x65, 0x79, 0x73, 0x70, 0x61, 0x63, 0x65, 0x22, 0x62, 0x0a, 0x13,
+	0x4d, 0x53, 0x54, 0x52, 0x6f, 0x6f, 0x74, 0x48, 0x69, 0x73, 0x74, 0x6f, 0x72, 0x79, 0x45, 0x6e,
+	0x74, 0x72, 0x79, 0x12, 0x1b, 0x0a, 0x09, 0x72, 0x6f, 0x6f, 0x74, 0x5f, 0x68, 0x61, 0x73, 0x68,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x08, 0x72, 0x6f, 0x6f, 0x74, 0x48, 0x61, 0x73, 0x68,
+	0x12, 0x2e, 0x0a, 0x04, 0x74, 0x69, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a,
+	0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66,
+	0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x04, 0x74, 0x69, 0x6d, 0x65,
+	0x22, 0x5c, 0x0a, 0x16, 0x4d, 0x53, 0x54, 0x52, 0x6f, 0x6f, 0x74, 0x48, 0x69, 0x73, 0x74, 0x6f,
+	0x72, 0x79, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x42, 0x0a, 0x07, 0x65, 0x6e,
+	0x74, 0x72, 0x69, 0x65, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x28, 0x2e, 0x76, 0x75,
+	0x6c, 0x74, 0x75, 0x72, 0x65, 0x2e, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x72, 0x70,
+	0x63, 0x2e, 0x4d, 0x53, 0x54, 0x52, 0x6f, 0x6f, 0x74, 0x48, 0x69, 0x73, 0x74, 0x6f, 0x72, 0x79,
+	0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x07, 0x65, 0x6e, 0x74, 0x72, 0x69, 0x65, 0x73, 0x22, 0x6e,
+	0x0a, 0x14, 0x4d, 0x53, 0x54, 0x52, 0x6f, 0x75, 0x6e, 0x64, 0x53, 0x74, 0x61, 0x72, 0x74, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1d, 0x0a, 0x0a, 0x72, 0x6f, 0x75, 0x6e, 0x64, 0x5f,
+	0x75, 0x75, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x09, 0x72, 0x6f, 0x75, 0x6e,
+	0x64, 0x55, 0x75, 0x69, 0x64, 0x12, 0x1b, 0x0a, 0x09, 0x72, 0x6f, 0x6f, 0x74, 0x5f, 0x68, 0x61,
+	0x73, 0x68, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x08, 0x72, 0x6f, 0x6f, 0x74, 0x48, 0x61,
+	0x73, 0x68, 0x12, 0x1a, 0x0a, 0x08, 0x6b, 0x65, 0x79, 0x73, 0x70, 0x61, 0x63, 0x65, 0x18, 0x03,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x6b, 0x65, 0x79, 0x73, 0x70, 0x61, 0x63, 0x65, 0x22, 0x68,
+	0x0a, 0x13, 0x4d, 0x53, 0x54, 0x52, 0x6f, 0x75, 0x6e, 0x64, 0x53, 0x74, 0x65, 0x70, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1d, 0x0a, 0x0a, 0x72, 0x6f, 0x75, 0x6e, 0x64, 0x5f, 0x75,
+	0x75, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x09, 0x72, 0x6f, 0x75, 0x6e, 0x64,
+	0x55, 0x75, 0x69, 0x64, 0x12, 0x32, 0x0a, 0x05, 0x6e, 0x6f, 0x64, 0x65, 0x73, 0x18, 0x02, 0x20,
+	0x03, 0x28, 0x0b, 0x32, 0x1c, 0x2e, 0x76, 0x75, 0x6c, 0x74, 0x75, 0x72, 0x65, 0x2e, 0x73, 0x65,
+	0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x72, 0x70, 0x63, 0x2e, 0x4d, 0x53, 0x54, 0x4e, 0x6f, 0x64,
+	0x65, 0x52, 0x05, 0x6e, 0x6f, 0x64, 0x65, 0x73, 0x22, 0x2e, 0x0a, 0x14, 0x4d, 0x53, 0x54, 0x52,
+	0x6f, 0x75, 0x6e, 0x64, 0x53, 0x74, 0x65, 0x70, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
+	0x12, 0x16, 0x0a, 0x06, 0x68, 0x61, 0x73, 0x68, 0x65, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0c,
+	0x52, 0x06, 0x68, 0x61, 0x73, 0x68, 0x65, 0x73, 0x22, 0xa3, 0x01, 0x0a, 0x0b, 0x4d, 0x53, 0x54,
+	0x4b, 0x65, 0x79, 0x73, 0x70, 0x61, 0x63, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x12, 0x0a, 0x04,
+	0x62, 0x61, 0x73, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x04, 0x62, 0x61, 0x73, 0x65,
+	0x12, 0x12, 0x0a, 0x04, 0x68, 0x61, 0x73, 0x68, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x04,
+	0x68, 0x61, 0x73, 0x68, 0x12, 0x19, 0x0a, 0x08, 0x6b, 0x65, 0x79, 0x5f, 0x74, 0x79, 0x70, 0x65,
+	0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x6b, 0x65, 0x79, 0x54, 0x79, 0x70, 0x65, 0x12,
+	0x1d, 0x0a, 0x0a, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x5f, 0x74, 0x79, 0x70, 0x65, 0x18, 0x05, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x09, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x54, 0x79, 0x70, 0x65, 0x12, 0x1e,
+	0x0a, 0x0a, 0x61, 0x67, 0x67, 0x72, 0x65, 0x67, 0x61, 0x74, 0x6f, 0x72, 0x18, 0x06, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x0a, 0x61, 0x67, 0x67, 0x72, 0x65, 0x67, 0x61, 0x74, 0x6f, 0x72, 0x22, 0x2c,
+	0x0a, 0x16, 0x4d, 0x53, 0x54, 0x44, 0x72, 0x6f, 0x70, 0x4b, 0x65, 0x79, 0x73, 0x70, 0x61, 0x63,
+	0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x22, 0x5a, 0x0a, 0x18,
+	0x4d, 0x53, 0x54, 0x4c, 0x69, 0x73, 0x74, 0x4b, 0x65, 0x79, 0x73, 0x70, 0x61, 0x63, 0x65, 0x73,
+	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x3e, 0x0a, 0x09, 0x6b, 0x65, 0x79, 0x73,
+	0x70, 0x61, 0x63, 0x65, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x20, 0x2e, 0x76, 0x75,
+	0x6c, 0x74, 0x75, 0x72, 0x65, 0x2e, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x72, 0x70,
+	0x63, 0x2e, 0x4d, 0x53, 0x54, 0x4b, 0x65, 0x79, 0x73, 0x70, 0x61, 0x63, 0x65, 0x52, 0x09, 0x6b,
+	0x65, 0x79, 0x73, 0x70, 0x61, 0x63, 0x65, 0x73, 0x22, 0x6b, 0x0a, 0x0f, 0x4d, 0x53, 0x54, 0x43,
+	0x61, 0x74, 0x61, 0x6c, 0x6f, 0x67, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x1a, 0x0a, 0x08, 0x6b,
+	0x65, 0x79, 0x73, 0x70, 0x61, 0x63, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x6b,
+	0x65, 0x79, 0x73, 0x70, 0x61, 0x63, 0x65, 0x12, 0x1b, 0x0a, 0x09, 0x72, 0x6f, 0x6f, 0x74, 0x5f,
+	0x68, 0x61, 0x73, 0x68, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x08, 0x72, 0x6f, 0x6f, 0x74,
+	0x48, 0x61, 0x73, 0x68, 0x12, 0x1f, 0x0a, 0x0b, 0x73, 0x63, 0x68, 0x65, 0x6d, 0x61, 0x5f, 0x68,
+	0x61, 0x73, 0x68, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x0a, 0x73, 0x63, 0x68, 0x65, 0x6d,
+	0x61, 0x48, 0x61, 0x73, 0x68, 0x22, 0x3b, 0x0a, 0x11, 0x4d, 0x53, 0x54, 0x43, 0x61, 0x74, 0x61,
+	0x6c, 0x6f, 0x67, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x26, 0x0a, 0x0f, 0x6b, 0x6e,
+	0x6f, 0x77, 0x6e, 0x5f, 0x72, 0x6f, 0x6f, 0x74, 0x5f, 0x68, 0x61, 0x73, 0x68, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x0c, 0x52, 0x0d, 0x6b, 0x6e, 0x6f, 0x77, 0x6e, 0x52, 0x6f, 0x6f, 0x74, 0x48, 0x61,
+	0x73, 0x68, 0x22, 0x69, 0x0a, 0x0a, 0x4d, 0x53, 0x54, 0x43, 0x61, 0x74, 0x61, 0x6c, 0x6f, 0x67,
+	0x12, 0x1b, 0x0a, 0x09, 0x72, 0x6f, 0x6f, 0x74, 0x5f, 0x68, 0x61, 0x73, 0x68, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x0c, 0x52, 0x08, 0x72, 0x6f, 0x6f, 0x74, 0x48, 0x61, 0x73, 0x68, 0x12, 0x3e, 0x0a,
+	0x07, 0x65, 0x6e, 0x74, 0x72, 0x69, 0x65, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x24,
+	0x2e, 0x76, 0x75, 0x6c, 0x74, 0x75, 0x72, 0x65, 0x2e, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65,
+	0x2e, 0x72, 0x70, 0x63, 0x2e, 0x4d, 0x53, 0x54, 0x43, 0x61, 0x74, 0x61, 0x6c, 0x6f, 0x67, 0x45,
+	0x6e, 0x74, 0x72, 0x79, 0x52, 0x07, 0x65, 0x6e, 0x74, 0x72, 0x69, 0x65, 0x73, 0x22, 0x30, 0x0a,
+	0x1a, 0x4d, 0x53, 0x54, 0x44, 0x61, 0x74, 0x61, 0x62, 0x61, 0x73, 0x65, 0x53, 0x6e, 0x61, 0x70,
+	0x73, 0x68, 0x6f, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x6e,
+	0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x32,
+	0x93, 0x0a, 0x0a, 0x0a, 0x4d, 0x53, 0x54, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x12, 0x43,
+	0x0a, 0x03, 0x50, 0x75, 0x74, 0x12, 0x22, 0x2e, 0x76, 0x75, 0x6c, 0x74, 0x75, 0x72, 0x65, 0x2e,
+	0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x72, 0x70, 0x63, 0x2e, 0x4d, 0x53, 0x54, 0x50,
+	0x75, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67,
+	0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74,
+	0x79, 0x22, 0x00, 0x12, 0x50, 0x0a, 0x03, 0x47, 0x65, 0x74, 0x12, 0x22, 0x2e, 0x76, 0x75, 0x6c,
+	0x74, 0x75, 0x72, 0x65, 0x2e, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x72, 0x70, 0x63,
+	0x2e, 0x4d, 0x53, 0x54, 0x47, 0x65, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x23,
+	0x2e, 0x76, 0x75, 0x6c, 0x74, 0x75, 0x72, 0x65, 0x2e, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65,
+	0x2e, 0x72, 0x70, 0x63, 0x2e, 0x4d, 0x53, 0x54, 0x47, 0x65, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f,
+	0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x6b, 0x0a, 0x0c, 0x47, 0x65, 0x74, 0x57, 0x69, 0x74, 0x68,
+	0x50, 0x72, 0x6f, 0x6f, 0x66, 0x12, 0x2b, 0x2e, 0x76, 0x75, 0x6c, 0x74, 0x75, 0x72, 0x65, 0x2e,
+	0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x72, 0x70, 0x63, 0x2e, 0x4d, 0x53, 0x54, 0x47,
+	0x65, 0x74, 0x57, 0x69, 0x74, 0x68, 0x50, 0x72, 0x6f, 0x6f, 0x66, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x1a, 0x2c, 0x2e, 0x76, 0x75, 0x6c, 0x74, 0x75, 0x72, 0x65, 0x2e, 0x73, 0x65, 0x72,
+	0x76, 0x69, 0x63, 0x65, 0x2e, 0x72, 0x70, 0x63, 0x2e, 0x4d, 0x53, 0x54, 0x47, 0x65, 0x74, 0x57,
+	0x69, 0x74, 0x68, 0x50, 0x72, 0x6f, 0x6f, 0x66, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
+	0x22, 0x00, 0x12, 0x49, 0x0a, 0x06, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x12, 0x25, 0x2e, 0x76,
+	0x75, 0x6c, 0x74, 0x75, 0x72, 0x65, 0x2e, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x72,
+	0x70, 0x63, 0x2e, 0x4d, 0x53, 0x54, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x1a, 0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x22, 0x00, 0x12, 0x51, 0x0a,
+	0x0a, 0x57, 0x72, 0x69, 0x74, 0x65, 0x42, 0x61, 0x74, 0x63, 0x68, 0x12, 0x29, 0x2e, 0x76, 0x75,
+	0x6c, 0x74, 0x75, 0x72, 0x65, 0x2e, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x72, 0x70,
+	0x63, 0x2e, 0x4d, 0x53, 0x54, 0x57, 0x72, 0x69, 0x74, 0x65, 0x42, 0x61, 0x74, 0x63, 0x68, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x22, 0x00,
+	0x12, 0x5b, 0x0a, 0x06, 0x49, 0x6d, 0x70, 0x6f, 0x72, 0x74, 0x12, 0x25, 0x2e, 0x76, 0x75, 0x6c,
+	0x74, 0x75, 0x72, 0x65, 0x2e, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x72, 0x70, 0x63,
+	0x2e, 0x4d, 0x53, 0x54, 0x49, 0x6d, 0x70, 0x6f, 0x72, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x1a, 0x26, 0x2e, 0x76, 0x75, 0x6c, 0x74, 0x75, 0x72, 0x65, 0x2e, 0x73, 0x65, 0x72, 0x76,
+	0x69, 0x63, 0x65, 0x2e, 0x72, 0x70, 0x63, 0x2e, 0x4d, 0x53, 0x54, 0x49, 0x6d, 0x70, 0x6f, 0x72,
+	0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x28, 0x01, 0x12, 0x5d, 0x0a,
+	0x08, 0x53, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x12, 0x27, 0x2e, 0x76, 0x75, 0x6c, 0x74,
+	0x75, 0x72, 0x65, 0x2e, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x72, 0x70, 0x63, 0x2e,
+	0x4d, 0x53, 0x54, 0x53, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x1a, 0x24, 0x2e, 0x76, 0x75, 0x6c, 0x74, 0x75, 0x72, 0x65, 0x2e, 0x73, 0x65, 0x72,
+	0x76, 0x69, 0x63, 0x65, 0x2e, 0x72, 0x70, 0x63, 0x2e, 0x4d, 0x53, 0x54, 0x41, 0x72, 0x63, 0x68,
+	0x69, 0x76, 0x65, 0x43, 0x68, 0x75, 0x6e, 0x6b, 0x22, 0x00, 0x30, 0x01, 0x12, 0x5c, 0x0a, 0x07,
+	0x52, 0x65, 0x73, 0x74, 0x6f, 0x72, 0x65, 0x12, 0x24, 0x2e, 0x76, 0x75, 0x6c, 0x74, 0x75, 0x72,
+	0x65, 0x2e, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x72, 0x70, 0x63, 0x2e, 0x4d, 0x53,
+	0x54, 0x41, 0x72, 0x63, 0x68, 0x69, 0x76, 0x65, 0x43, 0x68, 0x75, 0x6e, 0x6b, 0x1a, 0x27, 0x2e,
+	0x76, 0x75, 0x6c, 0x74, 0x75, 0x72, 0x65, 0x2e, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e,
+	0x72, 0x70, 0x63, 0x2e, 0x4d, 0x53, 0x54, 0x52, 0x65, 0x73, 0x74, 0x6f, 0x72, 0x65, 0x52, 0x65,
+	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x28, 0x01, 0x12, 0x63, 0x0a, 0x0e, 0x43, 0x72,
+	0x65, 0x61, 0x74, 0x65, 0x53, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x12, 0x2d, 0x2e, 0x76,
+	0x75, 0x6c, 0x74, 0x75, 0x72, 0x65, 0x2e, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x72,
+	0x70, 0x63, 0x2e, 0x4d, 0x53, 0x54, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x53, 0x6e, 0x61, 0x70,
+	0x73, 0x68, 0x6f, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x20, 0x2e, 0x76, 0x75,
+	0x6c, 0x74, 0x75, 0x72, 0x65, 0x2e, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x72, 0x70,
+	0x63, 0x2e, 0x4d, 0x53, 0x54, 0x53, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x22, 0x00, 0x12,
+	0x59, 0x0a, 0x0e, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x53, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f,
+	0x74, 0x12, 0x2d, 0x2e, 0x76, 0x75, 0x6c, 0x74, 0x75, 0x72, 0x65, 0x2e, 0x73, 0x65, 0x72, 0x76,
+	0x69, 0x63, 0x65, 0x2e, 0x72, 0x70, 0x63, 0x2e, 0x4d, 0x53, 0x54, 0x44, 0x65, 0x6c, 0x65, 0x74,
+	0x65, 0x53, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x1a, 0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62,
+	0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x22, 0x00, 0x12, 0x6e, 0x0a, 0x0d, 0x4c, 0x69,
+	0x73, 0x74, 0x53, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x73, 0x12, 0x2c, 0x2e, 0x76, 0x75,
+	0x6c, 0x74, 0x75, 0x72, 0x65, 0x2e, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x72, 0x70,
+	0x63, 0x2e, 0x4d, 0x53, 0x54, 0x4c, 0x69, 0x73, 0x74, 0x53, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f,
+	0x74, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x2d, 0x2e, 0x76, 0x75, 0x6c, 0x74,
+	0x75, 0x72, 0x65, 0x2e, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x72, 0x70, 0x63, 0x2e,
+	0x4d, 0x53, 0x54, 0x4c, 0x69, 0x73, 0x74, 0x53, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x73,
+	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x68, 0x0a, 0x0b, 0x52, 0x6f,
+	0x6f, 0x74, 0x48, 0x69, 0x73, 0x74, 0x6f, 0x72, 0x79, 0x12, 0x2a, 0x2e, 0x76, 0x75, 0x6c, 0x74,
+	0x75, 0x72, 0x65, 0x2e, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x72, 0x70, 0x63, 0x2e,
+	0x4d, 0x53, 0x54, 0x52, 0x6f, 0x6f, 0x74, 0x48, 0x69, 0x73, 0x74, 0x6f, 0x72, 0x79, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x2b, 0x2e, 0x76, 0x75, 0x6c, 0x74, 0x75, 0x72, 0x65, 0x2e,
+	0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x72, 0x70, 0x63, 0x2e, 0x4d, 0x53, 0x54, 0x52,
+	0x6f, 0x6f, 0x74, 0x48, 0x69, 0x73, 0x74, 0x6f, 0x72, 0x79, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
+	0x73, 0x65, 0x22, 0x00, 0x12, 0x55, 0x0a, 0x04, 0x53, 0x63, 0x61, 0x6e, 0x12, 0x23, 0x2e, 0x76,
+	0x75, 0x6c, 0x74, 0x75, 0x72, 0x65, 0x2e, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x72,
+	0x70, 0x63, 0x2e, 0x4d, 0x53, 0x54, 0x53, 0x63, 0x61, 0x6e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x1a, 0x24, 0x2e, 0x76, 0x75, 0x6c, 0x74, 0x75, 0x72, 0x65, 0x2e, 0x73, 0x65, 0x72, 0x76,
+	0x69, 0x63, 0x65, 0x2e, 0x72, 0x70, 0x63, 0x2e, 0x4d, 0x53, 0x54, 0x53, 0x63, 0x61, 0x6e, 0x52,
+	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x30, 0x01, 0x12, 0x58, 0x0a, 0x09, 0x41,
+	0x67, 0x67, 0x72, 0x65, 0x67, 0x61, 0x74, 0x65, 0x12, 0x28, 0x2e, 0x76, 0x75, 0x6c, 0x74, 0x75,
+	0x72, 0x65, 0x2e, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x72, 0x70, 0x63, 0x2e, 0x4d,
+	0x53, 0x54, 0x41, 0x67, 0x67, 0x72, 0x65, 0x67, 0x61, 0x74, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x1a, 0x1f, 0x2e, 0x76, 0x75, 0x6c, 0x74, 0x75, 0x72, 0x65, 0x2e, 0x73, 0x65, 0x72,
+	0x76, 0x69, 0x63, 0x65, 0x2e, 0x72, 0x70, 0x63, 0x2e, 0x4d, 0x53, 0x54, 0x53, 0x75, 0x6d, 0x6d,
+	0x61, 0x72, 0x79, 0x22, 0x00, 0x32, 0xc3, 0x04, 0x0a, 0x0f, 0x4d, 0x53, 0x54, 0x41, 0x64, 0x6d,
+	0x69, 0x6e, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x12, 0x56, 0x0a, 0x0e, 0x43, 0x72, 0x65,
+	0x61, 0x74, 0x65, 0x4b, 0x65, 0x79, 0x73, 0x70, 0x61, 0x63, 0x65, 0x12, 0x20, 0x2e, 0x76, 0x75,
+	0x6c, 0x74, 0x75, 0x72, 0x65, 0x2e, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x72, 0x70,
+	0x63, 0x2e, 0x4d, 0x53, 0x54, 0x4b, 0x65, 0x79, 0x73, 0x70, 0x61, 0x63, 0x65, 0x1a, 0x20, 0x2e,
+	0x76, 0x75, 0x6c, 0x74, 0x75, 0x72, 0x65, 0x2e, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e,
+	0x72, 0x70, 0x63, 0x2e, 0x4d, 0x53, 0x54, 0x4b, 0x65, 0x79, 0x73, 0x70, 0x61, 0x63, 0x65, 0x22,
+	0x00, 0x12, 0x55, 0x0a, 0x0c, 0x44, 0x72, 0x6f, 0x70, 0x4b, 0x65, 0x79, 0x73, 0x70, 0x61, 0x63,
+	0x65, 0x12, 0x2b, 0x2e, 0x76, 0x75, 0x6c, 0x74, 0x75, 0x72, 0x65, 0x2e, 0x73, 0x65, 0x72, 0x76,
+	0x69, 0x63, 0x65, 0x2e, 0x72, 0x70, 0x63, 0x2e, 0x4d, 0x53, 0x54, 0x44, 0x72, 0x6f, 0x70, 0x4b,
+	0x65, 0x79, 0x73, 0x70, 0x61, 0x63, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x16,
+	0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66,
+	0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x22, 0x00, 0x12, 0x58, 0x0a, 0x0d, 0x4c, 0x69, 0x73, 0x74,
+	0x4b, 0x65, 0x79, 0x73, 0x70, 0x61, 0x63, 0x65, 0x73, 0x12, 0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67,
+	0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74,
+	0x79, 0x1a, 0x2d, 0x2e, 0x76, 0x75, 0x6c, 0x74, 0x75, 0x72, 0x65, 0x2e, 0x73, 0x65, 0x72, 0x76,
+	0x69, 0x63, 0x65, 0x2e, 0x72, 0x70, 0x63, 0x2e, 0x4d, 0x53, 0x54, 0x4c, 0x69, 0x73, 0x74, 0x4b,
+	0x65, 0x79, 0x73, 0x70, 0x61, 0x63, 0x65, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
+	0x22, 0x00, 0x12, 0x54, 0x0a, 0x07, 0x43, 0x61, 0x74, 0x61, 0x6c, 0x6f, 0x67, 0x12, 0x26, 0x2e,
+	0x76, 0x75, 0x6c, 0x74, 0x75, 0x72, 0x65, 0x2e, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e,
+	0x72, 0x70, 0x63, 0x2e, 0x4d, 0x53, 0x54, 0x43, 0x61, 0x74, 0x61, 0x6c, 0x6f, 0x67, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1f, 0x2e, 0x76, 0x75, 0x6c, 0x74, 0x75, 0x72, 0x65, 0x2e,
+	0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x72, 0x70, 0x63, 0x2e, 0x4d, 0x53, 0x54, 0x43,
+	0x61, 0x74, 0x61, 0x6c, 0x6f, 0x67, 0x22, 0x00, 0x12, 0x6c, 0x0a, 0x16, 0x43, 0x72, 0x65, 0x61,
+	0x74, 0x65, 0x44, 0x61, 0x74, 0x61, 0x62, 0x61, 0x73, 0x65, 0x53, 0x6e, 0x61, 0x70, 0x73, 0x68,
+	0x6f, 0x74, 0x12, 0x2f, 0x2e, 0x76, 0x75, 0x6c, 0x74, 0x75, 0x72, 0x65, 0x2e, 0x73, 0x65, 0x72,
+	0x76, 0x69, 0x63, 0x65, 0x2e, 0x72, 0x70, 0x63, 0x2e, 0x4d, 0x53, 0x54, 0x44, 0x61, 0x74, 0x61,
+	0x62, 0x61, 0x73, 0x65, 0x53, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x1a, 0x1f, 0x2e, 0x76, 0x75, 0x6c, 0x74, 0x75, 0x72, 0x65, 0x2e, 0x73, 0x65,
+	0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x72, 0x70, 0x63, 0x2e, 0x4d, 0x53, 0x54, 0x43, 0x61, 0x74,
+	0x61, 0x6c, 0x6f, 0x67, 0x22, 0x00, 0x12, 0x63, 0x0a, 0x16, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65,
+	0x44, 0x61, 0x74, 0x61, 0x62, 0x61, 0x73, 0x65, 0x53, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74,
+	0x12, 0x2f, 0x2e, 0x76, 0x75, 0x6c, 0x74, 0x75, 0x72, 0x65, 0x2e, 0x73, 0x65, 0x72, 0x76, 0x69,
+	0x63, 0x65, 0x2e, 0x72, 0x70, 0x63, 0x2e, 0x4d, 0x53, 0x54, 0x44, 0x61, 0x74, 0x61, 0x62, 0x61,
+	0x73, 0x65, 0x53, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x1a, 0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x62, 0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x22, 0x00, 0x32, 0xdd, 0x01, 0x0a, 0x11,
+	0x4d, 0x53, 0x54, 0x4d, 0x61, 0x6e, 0x61, 0x67, 0x65, 0x72, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63,
+	0x65, 0x12, 0x64, 0x0a, 0x0a, 0x52, 0x6f, 0x75, 0x6e, 0x64, 0x53, 0x74, 0x61, 0x72, 0x74, 0x12,
+	0x29, 0x2e, 0x76, 0x75, 0x6c, 0x74, 0x75, 0x72, 0x65, 0x2e, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63,
+	0x65, 0x2e, 0x72, 0x70, 0x63, 0x2e, 0x4d, 0x53, 0x54, 0x52, 0x6f, 0x75, 0x6e, 0x64, 0x53, 0x74,
+	0x61, 0x72, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x29, 0x2e, 0x76, 0x75, 0x6c,
+	0x74, 0x75, 0x72, 0x65, 0x2e, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x72, 0x70, 0x63,
+	0x2e, 0x4d, 0x53, 0x54, 0x52, 0x6f, 0x75, 0x6e, 0x64, 0x53, 0x74, 0x65, 0x70, 0x52, 0x65, 0x73,
+	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x62, 0x0a, 0x09, 0x52, 0x6f, 0x75, 0x6e, 0x64,
+	0x53, 0x74, 0x65, 0x70, 0x12, 0x28, 0x2e, 0x76, 0x75, 0x6c, 0x74, 0x75, 0x72, 0x65, 0x2e, 0x73,
+	0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x72, 0x70, 0x63, 0x2e, 0x4d, 0x53, 0x54, 0x52, 0x6f,
+	0x75, 0x6e, 0x64, 0x53, 0x74, 0x65, 0x70, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x29,
+	0x2e, 0x76, 0x75, 0x6c, 0x74, 0x75, 0x72, 0x65, 0x2e, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65,
+	0x2e, 0x72, 0x70, 0x63, 0x2e, 0x4d, 0x53, 0x54, 0x52, 0x6f, 0x75, 0x6e, 0x64, 0x53, 0x74, 0x65,
+	0x70, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x42, 0x2a, 0x5a, 0x28, 0x67,
+	0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x76, 0x75, 0x6c, 0x74, 0x75, 0x72,
+	0x65, 0x64, 0x62, 0x2f, 0x76, 0x75, 0x6c, 0x74, 0x75, 0x72, 0x65, 0x2f, 0x73, 0x65, 0x72, 0x76,
+	0x69, 0x63, 0x65, 0x2f, 0x72, 0x70, 0x63, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_mst_proto_rawDescOnce sync.Once
+	file_mst_proto_rawDescData = file_mst_proto_rawDesc
+)
+
+func file_mst_proto_rawDescGZIP() []byte {
+	file_mst_proto_rawDescOnce.Do(func() {
+		file_mst_proto_rawDescData = protoimpl.X.CompressGZIP(file_mst_proto_rawDescData)
+	})
+	return file_mst_proto_rawDescData
+}
+
+var file_mst_proto_msgTypes = make([]protoimpl.MessageInfo, 37)
+var file_mst_proto_goTypes = []interface{}{
+	(*MSTChild)(nil),                   // 0: vulture.service.rpc.MSTChild
+	(*MSTSummary)(nil),                 // 1: vulture.service.rpc.MSTSummary
+	(*MSTNode)(nil),                    // 2: vulture.service.rpc.MSTNode
+	(*MSTPutRequest)(nil),              // 3: vulture.service.rpc.MSTPutRequest
+	(*MSTGetRequest)(nil),              // 4: vulture.service.rpc.MSTGetRequest
+	(*MSTGetResponse)(nil),             // 5: vulture.service.rpc.MSTGetResponse
+	(*MSTGetWithProofRequest)(nil),     // 6: vulture.service.rpc.MSTGetWithProofRequest
+	(*MSTGetWithProofResponse)(nil),    // 7: vulture.service.rpc.MSTGetWithProofResponse
+	(*MSTDeleteRequest)(nil),           // 8: vulture.service.rpc.MSTDeleteRequest
+	(*MSTWrite)(nil),                   // 9: vulture.service.rpc.MSTWrite
+	(*MSTWriteBatchRequest)(nil),       // 10: vulture.service.rpc.MSTWriteBatchRequest
+	(*MSTImportRequest)(nil),           // 11: vulture.service.rpc.MSTImportRequest
+	(*MSTImportResponse)(nil),          // 12: vulture.service.rpc.MSTImportResponse
+	(*MSTSnapshotRequest)(nil),         // 13: vulture.service.rpc.MSTSnapshotRequest
+	(*MSTArchiveChunk)(nil),            // 14: vulture.service.rpc.MSTArchiveChunk
+	(*MSTRestoreResponse)(nil),         // 15: vulture.service.rpc.MSTRestoreResponse
+	(*MSTScanRequest)(nil),             // 16: vulture.service.rpc.MSTScanRequest
+	(*MSTAggregateRequest)(nil),        // 17: vulture.service.rpc.MSTAggregateRequest
+	(*MSTScanResponse)(nil),            // 18: vulture.service.rpc.MSTScanResponse
+	(*MSTSnapshot)(nil),                // 19: vulture.service.rpc.MSTSnapshot
+	(*MSTCreateSnapshotRequest)(nil),   // 20: vulture.service.rpc.MSTCreateSnapshotRequest
+	(*MSTDeleteSnapshotRequest)(nil),   // 21: vulture.service.rpc.MSTDeleteSnapshotRequest
+	(*MSTListSnapshotsRequest)(nil),    // 22: vulture.service.rpc.MSTListSnapshotsRequest
+	(*MSTListSnapshotsResponse)(nil),   // 23: vulture.service.rpc.MSTListSnapshotsResponse
+	(*MSTRootHistoryRequest)(nil),      // 24: vulture.service.rpc.MSTRootHistoryRequest
+	(*MSTRootHistoryEntry)(nil),        // 25: vulture.service.rpc.MSTRootHistoryEntry
+	(*MSTRootHistoryResponse)(nil),     // 26: vulture.service.rpc.MSTRootHistoryResponse
+	(*MSTRoundStartRequest)(nil),       // 27: vulture.service.rpc.MSTRoundStartRequest
+	(*MSTRoundStepRequest)(nil),        // 28: vulture.service.rpc.MSTRoundStepRequest
+	(*MSTRoundStepResponse)(nil),       // 29: vulture.service.rpc.MSTRoundStepResponse
+	(*MSTKeyspace)(nil),                // 30: vulture.service.rpc.MSTKeyspace
+	(*MSTDropKeyspaceRequest)(nil),     // 31: vulture.service.rpc.MSTDropKeyspaceRequest
+	(*MSTListKeyspacesResponse)(nil),   // 32: vulture.service.rpc.MSTListKeyspacesResponse
+	(*MSTCatalogEntry)(nil),            // 33: vulture.service.rpc.MSTCatalogEntry
+	(*MSTCatalogRequest)(nil),          // 34: vulture.service.rpc.MSTCatalogRequest
+	(*MSTCatalog)(nil),                 // 35: vulture.service.rpc.MSTCatalog
+	(*MSTDatabaseSnapshotRequest)(nil), // 36: vulture.service.rpc.MSTDatabaseSnapshotRequest
+	(*timestamp.Timestamp)(nil),        // 37: google.protobuf.Timestamp
+	(*empty.Empty)(nil),                // 38: google.protobuf.Empty
+}
+var file_mst_proto_depIdxs = []int32{
+	0,  // 0: vulture.service.rpc.MSTNode.children:type_name -> vulture.service.rpc.MSTChild
+	1,  // 1: vulture.service.rpc.MSTNode.summaries:type_name -> vulture.service.rpc.MSTSummary
+	2,  // 2: vulture.service.rpc.MSTGetWithProofResponse.proof:type_name -> vulture.service.rpc.MSTNode
+	9,  // 3: vulture.service.rpc.MSTWriteBatchRequest.writes:type_name -> vulture.service.rpc.MSTWrite
+	37, // 4: vulture.service.rpc.MSTSnapshot.created:type_name -> google.protobuf.Timestamp
+	19, // 5: vulture.service.rpc.MSTListSnapshotsResponse.snapshots:type_name -> vulture.service.rpc.MSTSnapshot
+	37, // 6: vulture.service.rpc.MSTRootHistoryEntry.time:type_name -> google.protobuf.Timestamp
+	25, // 7: vulture.service.rpc.MSTRootHistoryResponse.entries:type_name -> vulture.service.rpc.MSTRootHistoryEntry
+	2,  // 8: vulture.service.rpc.MSTRoundStepRequest.nodes:type_name -> vulture.service.rpc.MSTNode
+	30, // 9: vulture.service.rpc.MSTListKeyspacesResponse.keyspaces:type_name -> vulture.service.rpc.MSTKeyspace
+	33, // 10: vulture.service.rpc.MSTCatalog.entries:type_name -> vulture.service.rpc.MSTCatalogEntry
+	3,  // 11: vulture.service.rpc.MSTService.Put:input_type -> vulture.service.rpc.MSTPutRequest
+	4,  // 12: vulture.service.rpc.MSTService.Get:input_type -> vulture.service.rpc.MSTGetRequest
+	6,  // 13: vulture.service.rpc.MSTService.GetWithProof:input_type -> vulture.service.rpc.MSTGetWithProofRequest
+	8,  // 14: vulture.service.rpc.MSTService.Delete:input_type -> vulture.service.rpc.MSTDeleteRequest
+	10, // 15: vulture.service.rpc.MSTService.WriteBatch:input_type -> vulture.service.rpc.MSTWriteBatchRequest
+	11, // 16: vulture.service.rpc.MSTService.Import:input_type -> vulture.service.rpc.MSTImportRequest
+	13, // 17: vulture.service.rpc.MSTService.Snapshot:input_type -> vulture.service.rpc.MSTSnapshotRequest
+	14, // 18: vulture.service.rpc.MSTService.Restore:input_type -> vulture.service.rpc.MSTArchiveChunk
+	20, // 19: vulture.service.rpc.MSTService.CreateSnapshot:input_type -> vulture.service.rpc.MSTCreateSnapshotRequest
+	21, // 20: vulture.service.rpc.MSTService.DeleteSnapshot:input_type -> vulture.service.rpc.MSTDeleteSnapshotRequest
+	22, // 21: vulture.service.rpc.MSTService.ListSnapshots:input_type -> vulture.service.rpc.MSTListSnapshotsRequest
+	24, // 22: vulture.service.rpc.MSTService.RootHistory:input_type -> vulture.service.rpc.MSTRootHistoryRequest
+	16, // 23: vulture.service.rpc.MSTService.Scan:input_type -> vulture.service.rpc.MSTScanRequest
+	17, // 24: vulture.service.rpc.MSTService.Aggregate:input_type -> vulture.service.rpc.MSTAggregateRequest
+	30, // 25: vulture.service.rpc.MSTAdminService.CreateKeyspace:input_type -> vulture.service.rpc.MSTKeyspace
+	31, // 26: vulture.service.rpc.MSTAdminService.DropKeyspace:input_type -> vulture.service.rpc.MSTDropKeyspaceRequest
+	38, // 27: vulture.service.rpc.MSTAdminService.ListKeyspaces:input_type -> google.protobuf.Empty
+	34, // 28: vulture.service.rpc.MSTAdminService.Catalog:input_type -> vulture.service.rpc.MSTCatalogRequest
+	36, // 29: vulture.service.rpc.MSTAdminService.CreateDatabaseSnapshot:input_type -> vulture.service.rpc.MSTDatabaseSnapshotRequest
+	36, // 30: vulture.service.rpc.MSTAdminService.DeleteDatabaseSnapshot:input_type -> vulture.service.rpc.MSTDatabaseSnapshotRequest
+	27, // 31: vulture.service.rpc.MSTManagerService.RoundStart:input_type -> vulture.service.rpc.MSTRoundStartRequest
+	28, // 32: vulture.service.rpc.MSTManagerService.RoundStep:input_type -> vulture.service.rpc.MSTRoundStepRequest
+	38, // 33: vulture.service.rpc.MSTService.Put:output_type -> google.protobuf.Empty
+	5,  // 34: vulture.service.rpc.MSTService.Get:output_type -> vulture.service.rpc.MSTGetResponse
+	7,  // 35: vulture.service.rpc.MSTService.GetWithProof:output_type -> vulture.service.rpc.MSTGetWithProofResponse
+	38, // 36: vulture.service.rpc.MSTService.Delete:output_type -> google.protobuf.Empty
+	38, // 37: vulture.service.rpc.MSTService.WriteBatch:output_type -> google.protobuf.Empty
+	12, // 38: vulture.service.rpc.MSTService.Import:output_type -> vulture.service.rpc.MSTImportResponse
+	14, // 39: vulture.service.rpc.MSTService.Snapshot:output_type -> vulture.service.rpc.MSTArchiveChunk
+	15, // 40: vulture.service.rpc.MSTService.Restore:output_type -> vulture.service.rpc.MSTRestoreResponse
+	19, // 41: vulture.service.rpc.MSTService.CreateSnapshot:output_type -> vulture.service.rpc.MSTSnapshot
+	38, // 42: vulture.service.rpc.MSTService.DeleteSnapshot:output_type -> google.protobuf.Empty
+	23, // 43: vulture.service.rpc.MSTService.ListSnapshots:output_type -> vulture.service.rpc.MSTListSnapshotsResponse
+	26, // 44: vulture.service.rpc.MSTService.RootHistory:output_type -> vulture.service.rpc.MSTRootHistoryResponse
+	18, // 45: vulture.service.rpc.MSTService.Scan:output_type -> vulture.service.rpc.MSTScanResponse
+	1,  // 46: vulture.service.rpc.MSTService.Aggregate:output_type -> vulture.service.rpc.MSTSummary
+	30, // 47: vulture.service.rpc.MSTAdminService.CreateKeyspace:output_type -> vulture.service.rpc.MSTKeyspace
+	38, // 48: vulture.service.rpc.MSTAdminService.DropKeyspace:output_type -> google.protobuf.Empty
+	32, // 49: vulture.service.rpc.MSTAdminService.ListKeyspaces:output_type -> vulture.service.rpc.MSTListKeyspacesResponse
+	35, // 50: vulture.service.rpc.MSTAdminService.Catalog:output_type -> vulture.service.rpc.MSTCatalog
+	35, // 51: vulture.service.rpc.MSTAdminService.CreateDatabaseSnapshot:output_type -> vulture.service.rpc.MSTCatalog
+	38, // 52: vulture.service.rpc.MSTAdminService.DeleteDatabaseSnapshot:output_type -> google.protobuf.Empty
+	29, // 53: vulture.service.rpc.MSTManagerService.RoundStart:output_type -> vulture.service.rpc.MSTRoundStepResponse
+	29, // 54: vulture.service.rpc.MSTManagerService.RoundStep:output_type -> vulture.service.rpc.MSTRoundStepResponse
+	33, // [33:55] is the sub-list for method output_type
+	11, // [11:33] is the sub-list for method input_type
+	11, // [11:11] is the sub-list for extension type_name
+	11, // [11:11] is the sub-list for extension extendee
+	0,  // [0:11] is the sub-list for field type_name
+}
+
+func init() { file_mst_proto_init() }
+func file_mst_proto_init() {
+	if File_mst_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_mst_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*MSTChild); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_mst_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*MSTSummary); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_mst_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*MSTNode); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_mst_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*MSTPutRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_mst_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*MSTGetRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_mst_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*MSTGetResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_mst_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*MSTGetWithProofRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_mst_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*MSTGetWithProofResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_mst_proto_msgTypes[8].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*MSTDeleteRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_mst_proto_msgTypes[9].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*MSTWrite); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_mst_proto_msgTypes[10].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*MSTWriteBatchRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_mst_proto_msgTypes[11].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*MSTImportRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_mst_proto_msgTypes[12].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*MSTImportResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_mst_proto_msgTypes[13].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*MSTSnapshotRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_mst_proto_msgTypes[14].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*MSTArchiveChunk); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_mst_proto_msgTypes[15].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*MSTRestoreResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_mst_proto_msgTypes[16].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*MSTScanRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_mst_proto_msgTypes[17].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*MSTAggregateRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_mst_proto_msgTypes[18].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*MSTScanResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_mst_proto_msgTypes[19].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*MSTSnapshot); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_mst_proto_msgTypes[20].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*MSTCreateSnapshotRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_mst_proto_msgTypes[21].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*MSTDeleteSnapshotRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_mst_proto_msgTypes[22].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*MSTListSnapshotsRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_mst_proto_msgTypes[23].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*MSTListSnapshotsResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_mst_proto_msgTypes[24].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*MSTRootHistoryRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_mst_proto_msgTypes[25].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*MSTRootHistoryEntry); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_mst_proto_msgTypes[26].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*MSTRootHistoryResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_mst_proto_msgTypes[27].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*MSTRoundStartRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_mst_proto_msgTypes[28].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*MSTRoundStepRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_mst_proto_msgTypes[29].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*MSTRoundStepResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_mst_proto_msgTypes[30].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*MSTKeyspace); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_mst_proto_msgTypes[31].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*MSTDropKeyspaceRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_mst_proto_msgTypes[32].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*MSTListKeyspacesResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_mst_proto_msgTypes[33].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*MSTCatalogEntry); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_mst_proto_msgTypes[34].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*MSTCatalogRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_mst_proto_msgTypes[35].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*MSTCatalog); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_mst_proto_msgTypes[36].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*MSTDatabaseSnapshotRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_mst_proto_rawDesc,
+			NumEnums:      0,
+			NumMessages:   37,
+			NumExtensions: 0,
+			NumServices:   3,
+		},
+		GoTypes:           file_mst_proto_goTypes,
+		DependencyIndexes: file_mst_proto_depIdxs,
+		MessageInfos:      file_mst_proto_msgTypes,
+	}.Build()
+	File_mst_proto = out.File
+	file_mst_proto_rawDesc = nil
+	file_mst_proto_goTypes = nil
+	file_mst_proto_depIdxs = nil
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConnInterface
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion6
+
+// MSTServiceClient is the client API for MSTService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type MSTServiceClient interface {
+	Put(ctx context.Context, in *MSTPutRequest, opts ...grpc.CallOption) (*empty.Empty, error)
+	Get(ctx context.Context, in *MSTGetRequest, opts ...grpc.CallOption) (*MSTGetResponse, error)
+	GetWithProof(ctx context.Context, in *MSTGetWithProofRequest, opts ...grpc.CallOption) (*MSTGetWithProofResponse, error)
+	Delete(ctx context.Context, in *MSTDeleteRequest, opts ...grpc.CallOption) (*empty.Empty, error)
+	WriteBatch(ctx context.Context, in *MSTWriteBatchRequest, opts ...grpc.CallOption) (*empty.Empty, error)
+	Import(ctx context.Context, opts ...grpc.CallOption) (MSTService_ImportClient, error)
+	Snapshot(ctx context.Context, in *MSTSnapshotRequest, opts ...grpc.CallOption) (MSTService_SnapshotClient, error)
+	Restore(ctx context.Context, opts ...grpc.CallOption) (MSTService_RestoreClient, error)
+	CreateSnapshot(ctx context.Context, in *MSTCreateSnapshotRequest, opts ...grpc.CallOption) (*MSTSnapshot, error)
+	DeleteSnapshot(ctx context.Context, in *MSTDeleteSnapshotRequest, opts ...grpc.CallOption) (*empty.Empty, error)
+	ListSnapshots(ctx context.Context, in *MSTListSnapshotsRequest, opts ...grpc.CallOption) (*MSTListSnapshotsResponse, error)
+	RootHistory(ctx context.Context, in *MSTRootHistoryRequest, opts ...grpc.CallOption) (*MSTRootHistoryResponse, error)
+	Scan(ctx context.Context, in *MSTScanRequest, opts ...grpc.CallOption) (MSTService_ScanClient, error)
+	// Aggregate summarizes a range of keys. Trees that keep aggregates answer it by reading only the
+	// nodes on the paths to both ends of the range.
+	Aggregate(ctx context.Context, in *MSTAggregateRequest, opts ...grpc.CallOption) (*MSTSummary, error)
+}
+
+type mSTServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewMSTServiceClient(cc grpc.ClientConnInterface) MSTServiceClient {
+	return &mSTServiceClient{cc}
+}
+
+func (c *mSTServiceClient) Put(ctx context.Context, in *MSTPutRequest, opts ...grpc.CallOption) (*empty.Empty, error) {
+	out := new(empty.Empty)
+	err := c.cc.Invoke(ctx, "/vulture.service.rpc.MSTService/Put", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mSTServiceClient) Get(ctx context.Context, in *MSTGetRequest, opts ...grpc.CallOption) (*MSTGetResponse, error) {
+	out := new(MSTGetResponse)
+	err := c.cc.Invoke(ctx, "/vulture.service.rpc.MSTService/Get", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mSTServiceClient) GetWithProof(ctx context.Context, in *MSTGetWithProofRequest, opts ...grpc.CallOption) (*MSTGetWithProofResponse, error) {
+	out := new(MSTGetWithProofResponse)
+	err := c.cc.Invoke(ctx, "/vulture.service.rpc.MSTService/GetWithProof", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mSTServiceClient) Delete(ctx context.Context, in *MSTDeleteRequest, opts ...grpc.CallOption) (*empty.Empty, error) {
+	out := new(empty.Empty)
+	err := c.cc.Invoke(ctx, "/vulture.service.rpc.MSTService/Delete", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mSTServiceClient) WriteBatch(ctx context.Context, in *MSTWriteBatchRequest, opts ...grpc.CallOption) (*empty.Empty, error) {
+	out := new(empty.Empty)
+	err := c.cc.Invoke(ctx, "/vulture.service.rpc.MSTService/WriteBatch", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mSTServiceClient) Import(ctx context.Context, opts ...grpc.CallOption) (MSTService_ImportClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_MSTService_serviceDesc.Streams[0], "/vulture.service.rpc.MSTService/Import", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &mSTServiceImportClient{stream}
+	return x, nil
+}
+
+type MSTService_ImportClient interface {
+	Send(*MSTImportRequest) error
+	CloseAndRecv() (*MSTImportResponse, error)
+	grpc.ClientStream
+}
+
+type mSTServiceImportClient struct {
+	grpc.ClientStream
+}
+
+func (x *mSTServiceImportClient) Send(m *MSTImportRequest) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *mSTServiceImportClient) CloseAndRecv() (*MSTImportResponse, error) {
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	m := new(MSTImportResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *mSTServiceClient) Snapshot(ctx context.Context, in *MSTSnapshotRequest, opts ...grpc.CallOption) (MSTService_SnapshotClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_MSTService_serviceDesc.Streams[1], "/vulture.service.rpc.MSTService/Snapshot", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &mSTServiceSnapshotClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type MSTService_SnapshotClient interface {
+	Recv() (*MSTArchiveChunk, error)
+	grpc.ClientStream
+}
+
+type mSTServiceSnapshotClient struct {
+	grpc.ClientStream
+}
+
+func (x *mSTServiceSnapshotClient) Recv() (*MSTArchiveChunk, error) {
+	m := new(MSTArchiveChunk)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *mSTServiceClient) Restore(ctx context.Context, opts ...grpc.CallOption) (MSTService_RestoreClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_MSTService_serviceDesc.Streams[2], "/vulture.service.rpc.MSTService/Restore", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &mSTServiceRestoreClient{stream}
+	return x, nil
+}
+
+type MSTService_RestoreClient interface {
+	Send(*MSTArchiveChunk) error
+	CloseAndRecv() (*MSTRestoreResponse, error)
+	grpc.ClientStream
+}
+
+type mSTServiceRestoreClient struct {
+	grpc.ClientStream
+}
+
+func (x *mSTServiceRestoreClient) Send(m *MSTArchiveChunk) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *mSTServiceRestoreClient) CloseAndRecv() (*MSTRestoreResponse, error) {
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	m := new(MSTRestoreResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *mSTServiceClient) CreateSnapshot(ctx context.Context, in *MSTCreateSnapshotRequest, opts ...grpc.CallOption) (*MSTSnapshot, error) {
+	out := new(MSTSnapshot)
+	err := c.cc.Invoke(ctx, "/vulture.service.rpc.MSTService/CreateSnapshot", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mSTServiceClient) DeleteSnapshot(ctx context.Context, in *MSTDeleteSnapshotRequest, opts ...grpc.CallOption) (*empty.Empty, error) {
+	out := new(empty.Empty)
+	err := c.cc.Invoke(ctx, "/vulture.service.rpc.MSTService/DeleteSnapshot", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mSTServiceClient) ListSnapshots(ctx context.Context, in *MSTListSnapshotsRequest, opts ...grpc.CallOption) (*MSTListSnapshotsResponse, error) {
+	out := new(MSTListSnapshotsResponse)
+	err := c.cc.Invoke(ctx, "/vulture.service.rpc.MSTService/ListSnapshots", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mSTServiceClient) RootHistory(ctx context.Context, in *MSTRootHistoryRequest, opts ...grpc.CallOption) (*MSTRootHistoryResponse, error) {
+	out := new(MSTRootHistoryResponse)
+	err := c.cc.Invoke(ctx, "/vulture.service.rpc.MSTService/RootHistory", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mSTServiceClient) Scan(ctx context.Context, in *MSTScanRequest, opts ...grpc.CallOption) (MSTService_ScanClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_MSTService_serviceDesc.Streams[3], "/vulture.service.rpc.MSTService/Scan", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &mSTServiceScanClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type MSTService_ScanClient interface {
+	Recv() (*MSTScanResponse, error)
+	grpc.ClientStream
+}
+
+type mSTServiceScanClient struct {
+	grpc.ClientStream
+}
+
+func (x *mSTServiceScanClient) Recv() (*MSTScanResponse, error) {
+	m := new(MSTScanResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *mSTServiceClient) Aggregate(ctx context.Context, in *MSTAggregateRequest, opts ...grpc.CallOption) (*MSTSummary, error) {
+	out := new(MSTSummary)
+	err := c.cc.Invoke(ctx, "/vulture.service.rpc.MSTService/Aggregate", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// MSTServiceServer is the server API for MSTService service.
+type MSTServiceServer interface {
+	Put(context.Context, *MSTPutRequest) (*empty.Empty, error)
+	Get(context.Context, *MSTGetRequest) (*MSTGetResponse, error)
+	GetWithProof(context.Context, *MSTGetWithProofRequest) (*MSTGetWithProofResponse, error)
+	Delete(context.Context, *MSTDeleteRequest) (*empty.Empty, error)
+	WriteBatch(context.Context, *MSTWriteBatchRequest) (*empty.Empty, error)
+	Import(MSTService_ImportServer) error
+	Snapshot(*MSTSnapshotRequest, MSTService_SnapshotServer) error
+	Restore(MSTService_RestoreServer) error
+	CreateSnapshot(context.Context, *MSTCreateSnapshotRequest) (*MSTSnapshot, error)
+	DeleteSnapshot(context.Context, *MSTDeleteSnapshotRequest) (*empty.Empty, error)
+	ListSnapshots(context.Context, *MSTListSnapshotsRequest) (*MSTListSnapshotsResponse, error)
+	RootHistory(context.Context, *MSTRootHistoryRequest) (*MSTRootHistoryResponse, error)
+	Scan(*MSTScanRequest, MSTService_ScanServer) error
+	// Aggregate summarizes a range of keys. Trees that keep aggregates answer it by reading only the
+	// nodes on the paths to both ends of the range.
+	Aggregate(context.Context, *MSTAggregateRequest) (*MSTSummary, error)
+}
+
+// UnimplementedMSTServiceServer can be embedded to have forward compatible implementations.
+type UnimplementedMSTServiceServer struct {
+}
+
+func (*UnimplementedMSTServiceServer) Put(context.Context, *MSTPutRequest) (*empty.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Put not implemented")
+}
+func (*UnimplementedMSTServiceServer) Get(context.Context, *MSTGetRequest) (*MSTGetResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Get not implemented")
+}
+func (*UnimplementedMSTServiceServer) GetWithProof(context.Context, *MSTGetWithProofRequest) (*MSTGetWithProofResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetWithProof not implemented")
+}
+func (*UnimplementedMSTServiceServer) Delete(context.Context, *MSTDeleteRequest) (*empty.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Delete not implemented")
+}
+func (*UnimplementedMSTServiceServer) WriteBatch(context.Context, *MSTWriteBatchRequest) (*empty.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method WriteBatch not implemented")
+}
+func (*UnimplementedMSTServiceServer) Import(MSTService_ImportServer) error {
+	return status.Errorf(codes.Unimplemented, "method Import not implemented")
+}
+func (*UnimplementedMSTServiceServer) Snapshot(*MSTSnapshotRequest, MSTService_SnapshotServer) error {
+	return status.Errorf(codes.Unimplemented, "method Snapshot not implemented")
+}
+func (*UnimplementedMSTServiceServer) Restore(MSTService_RestoreServer) error {
+	return status.Errorf(codes.Unimplemented, "method Restore not implemented")
+}
+func (*UnimplementedMSTServiceServer) CreateSnapshot(context.Context, *MSTCreateSnapshotRequest) (*MSTSnapshot, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateSnapshot not implemented")
+}
+func (*UnimplementedMSTServiceServer) DeleteSnapshot(context.Context, *MSTDeleteSnapshotRequest) (*empty.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteSnapshot not implemented")
+}
+func (*UnimplementedMSTServiceServer) ListSnapshots(context.Context, *MSTListSnapshotsRequest) (*MSTListSnapshotsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListSnapshots not implemented")
+}
+func (*UnimplementedMSTServiceServer) RootHistory(context.Context, *MSTRootHistoryRequest) (*MSTRootHistoryResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RootHistory not implemented")
+}
+func (*UnimplementedMSTServiceServer) Scan(*MSTScanRequest, MSTService_ScanServer) error {
+	return status.Errorf(codes.Unimplemented, "method Scan not implemented")
+}
+func (*UnimplementedMSTServiceServer) Aggregate(context.Context, *MSTAggregateRequest) (*MSTSummary, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Aggregate not implemented")
+}
+
+func RegisterMSTServiceServer(s *grpc.Server, srv MSTServiceServer) {
+	s.RegisterService(&_MSTService_serviceDesc, srv)
+}
+
+func _MSTService_Put_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MSTPutRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MSTServiceServer).Put(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/vulture.service.rpc.MSTService/Put",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MSTServiceServer).Put(ctx, req.(*MSTPutRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MSTService_Get_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MSTGetRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MSTServiceServer).Get(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/vulture.service.rpc.MSTService/Get",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MSTServiceServer).Get(ctx, req.(*MSTGetRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MSTService_GetWithProof_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MSTGetWithProofRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MSTServiceServer).GetWithProof(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/vulture.service.rpc.MSTService/GetWithProof",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MSTServiceServer).GetWithProof(ctx, req.(*MSTGetWithProofRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MSTService_Delete_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MSTDeleteRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MSTServiceServer).Delete(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/vulture.service.rpc.MSTService/Delete",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MSTServiceServer).Delete(ctx, req.(*MSTDeleteRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MSTService_WriteBatch_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MSTWriteBatchRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MSTServiceServer).WriteBatch(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/vulture.service.rpc.MSTService/WriteBatch",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MSTServiceServer).WriteBatch(ctx, req.(*MSTWriteBatchRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MSTService_Import_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(MSTServiceServer).Import(&mSTServiceImportServer{stream})
+}
+
+type MSTService_ImportServer interface {
+	SendAndClose(*MSTImportResponse) error
+	Recv() (*MSTImportRequest, error)
+	grpc.ServerStream
+}
+
+type mSTServiceImportServer struct {
+	grpc.ServerStream
+}
+
+func (x *mSTServiceImportServer) SendAndClose(m *MSTImportResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *mSTServiceImportServer) Recv() (*MSTImportRequest, error) {
+	m := new(MSTImportRequest)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func _MSTService_Snapshot_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(MSTSnapshotRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(MSTServiceServer).Snapshot(m, &mSTServiceSnapshotServer{stream})
+}
+
+type MSTService_SnapshotServer interface {
+	Send(*MSTArchiveChunk) error
+	grpc.ServerStream
+}
+
+type mSTServiceSnapshotServer struct {
+	grpc.ServerStream
+}
+
+func (x *mSTServiceSnapshotServer) Send(m *MSTArchiveChunk) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _MSTService_Restore_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(MSTServiceServer).Restore(&mSTServiceRestoreServer{stream})
+}
+
+type MSTService_RestoreServer interface {
+	SendAndClose(*MSTRestoreResponse) error
+	Recv() (*MSTArchiveChunk, error)
+	grpc.ServerStream
+}
+
+type mSTServiceRestoreServer struct {
+	grpc.ServerStream
+}
+
+func (x *mSTServiceRestoreServer) SendAndClose(m *MSTRestoreResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *mSTServiceRestoreServer) Recv() (*MSTArchiveChunk, error) {
+	m := new(MSTArchiveChunk)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func _MSTService_CreateSnapshot_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MSTCreateSnapshotRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MSTServiceServer).CreateSnapshot(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/vulture.service.rpc.MSTService/CreateSnapshot",
 	}
-	type x struct{}
-	out := protoimpl.TypeBuilder{
-		File: protoimpl.DescBuilder{
-			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
-			RawDescriptor: file_mst_proto_rawDesc,
-			NumEnums:      0,
-			NumMessages:   8,
-			NumExtensions: 0,
-			NumServices:   2,
-		},
-		GoTypes:           file_mst_proto_goTypes,
-		DependencyIndexes: file_mst_proto_depIdxs,
-		MessageInfos:      file_mst_proto_msgTypes,
-	}.Build()
-	File_mst_proto = out.File
-	file_mst_proto_rawDesc = nil
-	file_mst_proto_goTypes = nil
-	file_mst_proto_depIdxs = nil
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MSTServiceServer).CreateSnapshot(ctx, req.(*MSTCreateSnapshotRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-// Reference imports to suppress errors if they are not otherwise used.
-var _ context.Context
-var _ grpc.ClientConnInterface
+func _MSTService_DeleteSnapshot_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MSTDeleteSnapshotRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MSTServiceServer).DeleteSnapshot(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/vulture.service.rpc.MSTService/DeleteSnapshot",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MSTServiceServer).DeleteSnapshot(ctx, req.(*MSTDeleteSnapshotRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
 
-// This is a compile-time assertion to ensure that this generated file
-// is compatible with the grpc package it is being compiled against.
-const _ = grpc.SupportPackageIsVersion6
+func _MSTService_ListSnapshots_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MSTListSnapshotsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MSTServiceServer).ListSnapshots(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/vulture.service.rpc.MSTService/ListSnapshots",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MSTServiceServer).ListSnapshots(ctx, req.(*MSTListSnapshotsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
 
-// MSTServiceClient is the client API for MSTService service.
+func _MSTService_RootHistory_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MSTRootHistoryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MSTServiceServer).RootHistory(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/vulture.service.rpc.MSTService/RootHistory",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MSTServiceServer).RootHistory(ctx, req.(*MSTRootHistoryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MSTService_Scan_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(MSTScanRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(MSTServiceServer).Scan(m, &mSTServiceScanServer{stream})
+}
+
+type MSTService_ScanServer interface {
+	Send(*MSTScanResponse) error
+	grpc.ServerStream
+}
+
+type mSTServiceScanServer struct {
+	grpc.ServerStream
+}
+
+func (x *mSTServiceScanServer) Send(m *MSTScanResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _MSTService_Aggregate_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MSTAggregateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MSTServiceServer).Aggregate(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/vulture.service.rpc.MSTService/Aggregate",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MSTServiceServer).Aggregate(ctx, req.(*MSTAggregateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var _MSTService_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "vulture.service.rpc.MSTService",
+	HandlerType: (*MSTServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Put",
+			Handler:    _MSTService_Put_Handler,
+		},
+		{
+			MethodName: "Get",
+			Handler:    _MSTService_Get_Handler,
+		},
+		{
+			MethodName: "GetWithProof",
+			Handler:    _MSTService_GetWithProof_Handler,
+		},
+		{
+			MethodName: "Delete",
+			Handler:    _MSTService_Delete_Handler,
+		},
+		{
+			MethodName: "WriteBatch",
+			Handler:    _MSTService_WriteBatch_Handler,
+		},
+		{
+			MethodName: "CreateSnapshot",
+			Handler:    _MSTService_CreateSnapshot_Handler,
+		},
+		{
+			MethodName: "DeleteSnapshot",
+			Handler:    _MSTService_DeleteSnapshot_Handler,
+		},
+		{
+			MethodName: "ListSnapshots",
+			Handler:    _MSTService_ListSnapshots_Handler,
+		},
+		{
+			MethodName: "RootHistory",
+			Handler:    _MSTService_RootHistory_Handler,
+		},
+		{
+			MethodName: "Aggregate",
+			Handler:    _MSTService_Aggregate_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "Import",
+			Handler:       _MSTService_Import_Handler,
+			ClientStreams: true,
+		},
+		{
+			StreamName:    "Snapshot",
+			Handler:       _MSTService_Snapshot_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "Restore",
+			Handler:       _MSTService_Restore_Handler,
+			ClientStreams: true,
+		},
+		{
+			StreamName:    "Scan",
+			Handler:       _MSTService_Scan_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "mst.proto",
+}
+
+// MSTAdminServiceClient is the client API for MSTAdminService service.
 //
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
-type MSTServiceClient interface {
-	Put(ctx context.Context, in *MSTPutRequest, opts ...grpc.CallOption) (*empty.Empty, error)
-	Get(ctx context.Context, in *MSTGetRequest, opts ...grpc.CallOption) (*MSTGetResponse, error)
+type MSTAdminServiceClient interface {
+	CreateKeyspace(ctx context.Context, in *MSTKeyspace, opts ...grpc.CallOption) (*MSTKeyspace, error)
+	// Dropping a keyspace deletes all of its data. The default keyspace can't be dropped.
+	DropKeyspace(ctx context.Context, in *MSTDropKeyspaceRequest, opts ...grpc.CallOption) (*empty.Empty, error)
+	ListKeyspaces(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (*MSTListKeyspacesResponse, error)
+	Catalog(ctx context.Context, in *MSTCatalogRequest, opts ...grpc.CallOption) (*MSTCatalog, error)
+	// CreateDatabaseSnapshot creates a snapshot of the same name in every keyspace, all at the roots
+	// of a single catalog, and returns that catalog.
+	CreateDatabaseSnapshot(ctx context.Context, in *MSTDatabaseSnapshotRequest, opts ...grpc.CallOption) (*MSTCatalog, error)
+	// DeleteDatabaseSnapshot deletes the snapshot of a name from every keyspace that has it.
+	DeleteDatabaseSnapshot(ctx context.Context, in *MSTDatabaseSnapshotRequest, opts ...grpc.CallOption) (*empty.Empty, error)
 }
 
-type mSTServiceClient struct {
+type mSTAdminServiceClient struct {
 	cc grpc.ClientConnInterface
 }
 
-func NewMSTServiceClient(cc grpc.ClientConnInterface) MSTServiceClient {
-	return &mSTServiceClient{cc}
+func NewMSTAdminServiceClient(cc grpc.ClientConnInterface) MSTAdminServiceClient {
+	return &mSTAdminServiceClient{cc}
 }
 
-func (c *mSTServiceClient) Put(ctx context.Context, in *MSTPutRequest, opts ...grpc.CallOption) (*empty.Empty, error) {
+func (c *mSTAdminServiceClient) CreateKeyspace(ctx context.Context, in *MSTKeyspace, opts ...grpc.CallOption) (*MSTKeyspace, error) {
+	out := new(MSTKeyspace)
+	err := c.cc.Invoke(ctx, "/vulture.service.rpc.MSTAdminService/CreateKeyspace", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mSTAdminServiceClient) DropKeyspace(ctx context.Context, in *MSTDropKeyspaceRequest, opts ...grpc.CallOption) (*empty.Empty, error) {
 	out := new(empty.Empty)
-	err := c.cc.Invoke(ctx, "/vulture.service.rpc.MSTService/Put", in, out, opts...)
+	err := c.cc.Invoke(ctx, "/vulture.service.rpc.MSTAdminService/DropKeyspace", in, out, opts...)
 	if err != nil {
 		return nil, err
 	}
 	return out, nil
 }
 
-func (c *mSTServiceClient) Get(ctx context.Context, in *MSTGetRequest, opts ...grpc.CallOption) (*MSTGetResponse, error) {
-	out := new(MSTGetResponse)
-	err := c.cc.Invoke(ctx, "/vulture.service.rpc.MSTService/Get", in, out, opts...)
+func (c *mSTAdminServiceClient) ListKeyspaces(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (*MSTListKeyspacesResponse, error) {
+	out := new(MSTListKeyspacesResponse)
+	err := c.cc.Invoke(ctx, "/vulture.service.rpc.MSTAdminService/ListKeyspaces", in, out, opts...)
 	if err != nil {
 		return nil, err
 	}
 	return out, nil
 }
 
-// MSTServiceServer is the server API for MSTService service.
-type MSTServiceServer interface {
-	Put(context.Context, *MSTPutRequest) (*empty.Empty, error)
-	Get(context.Context, *MSTGetRequest) (*MSTGetResponse, error)
+func (c *mSTAdminServiceClient) Catalog(ctx context.Context, in *MSTCatalogRequest, opts ...grpc.CallOption) (*MSTCatalog, error) {
+	out := new(MSTCatalog)
+	err := c.cc.Invoke(ctx, "/vulture.service.rpc.MSTAdminService/Catalog", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
 }
 
-// UnimplementedMSTServiceServer can be embedded to have forward compatible implementations.
-type UnimplementedMSTServiceServer struct {
+func (c *mSTAdminServiceClient) CreateDatabaseSnapshot(ctx context.Context, in *MSTDatabaseSnapshotRequest, opts ...grpc.CallOption) (*MSTCatalog, error) {
+	out := new(MSTCatalog)
+	err := c.cc.Invoke(ctx, "/vulture.service.rpc.MSTAdminService/CreateDatabaseSnapshot", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
 }
 
-func (*UnimplementedMSTServiceServer) Put(context.Context, *MSTPutRequest) (*empty.Empty, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method Put not implemented")
+func (c *mSTAdminServiceClient) DeleteDatabaseSnapshot(ctx context.Context, in *MSTDatabaseSnapshotRequest, opts ...grpc.CallOption) (*empty.Empty, error) {
+	out := new(empty.Empty)
+	err := c.cc.Invoke(ctx, "/vulture.service.rpc.MSTAdminService/DeleteDatabaseSnapshot", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
 }
-func (*UnimplementedMSTServiceServer) Get(context.Context, *MSTGetRequest) (*MSTGetResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method Get not implemented")
+
+// MSTAdminServiceServer is the server API for MSTAdminService service.
+type MSTAdminServiceServer interface {
+	CreateKeyspace(context.Context, *MSTKeyspace) (*MSTKeyspace, error)
+	// Dropping a keyspace deletes all of its data. The default keyspace can't be dropped.
+	DropKeyspace(context.Context, *MSTDropKeyspaceRequest) (*empty.Empty, error)
+	ListKeyspaces(context.Context, *empty.Empty) (*MSTListKeyspacesResponse, error)
+	Catalog(context.Context, *MSTCatalogRequest) (*MSTCatalog, error)
+	// CreateDatabaseSnapshot creates a snapshot of the same name in every keyspace, all at the roots
+	// of a single catalog, and returns that catalog.
+	CreateDatabaseSnapshot(context.Context, *MSTDatabaseSnapshotRequest) (*MSTCatalog, error)
+	// DeleteDatabaseSnapshot deletes the snapshot of a name from every keyspace that has it.
+	DeleteDatabaseSnapshot(context.Context, *MSTDatabaseSnapshotRequest) (*empty.Empty, error)
 }
 
-func RegisterMSTServiceServer(s *grpc.Server, srv MSTServiceServer) {
-	s.RegisterService(&_MSTService_serviceDesc, srv)
+// UnimplementedMSTAdminServiceServer can be embedded to have forward compatible implementations.
+type UnimplementedMSTAdminServiceServer struct {
 }
 
-func _MSTService_Put_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(MSTPutRequest)
+func (*UnimplementedMSTAdminServiceServer) CreateKeyspace(context.Context, *MSTKeyspace) (*MSTKeyspace, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateKeyspace not implemented")
+}
+func (*UnimplementedMSTAdminServiceServer) DropKeyspace(context.Context, *MSTDropKeyspaceRequest) (*empty.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DropKeyspace not implemented")
+}
+func (*UnimplementedMSTAdminServiceServer) ListKeyspaces(context.Context, *empty.Empty) (*MSTListKeyspacesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListKeyspaces not implemented")
+}
+func (*UnimplementedMSTAdminServiceServer) Catalog(context.Context, *MSTCatalogRequest) (*MSTCatalog, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Catalog not implemented")
+}
+func (*UnimplementedMSTAdminServiceServer) CreateDatabaseSnapshot(context.Context, *MSTDatabaseSnapshotRequest) (*MSTCatalog, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateDatabaseSnapshot not implemented")
+}
+func (*UnimplementedMSTAdminServiceServer) DeleteDatabaseSnapshot(context.Context, *MSTDatabaseSnapshotRequest) (*empty.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteDatabaseSnapshot not implemented")
+}
+
+func RegisterMSTAdminServiceServer(s *grpc.Server, srv MSTAdminServiceServer) {
+	s.RegisterService(&_MSTAdminService_serviceDesc, srv)
+}
+
+func _MSTAdminService_CreateKeyspace_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MSTKeyspace)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(MSTServiceServer).Put(ctx, in)
+		return srv.(MSTAdminServiceServer).CreateKeyspace(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/vulture.service.rpc.MSTService/Put",
+		FullMethod: "/vulture.service.rpc.MSTAdminService/CreateKeyspace",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(MSTServiceServer).Put(ctx, req.(*MSTPutRequest))
+		return srv.(MSTAdminServiceServer).CreateKeyspace(ctx, req.(*MSTKeyspace))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _MSTService_Get_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(MSTGetRequest)
+func _MSTAdminService_DropKeyspace_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MSTDropKeyspaceRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(MSTServiceServer).Get(ctx, in)
+		return srv.(MSTAdminServiceServer).DropKeyspace(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/vulture.service.rpc.MSTService/Get",
+		FullMethod: "/vulture.service.rpc.MSTAdminService/DropKeyspace",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(MSTServiceServer).Get(ctx, req.(*MSTGetRequest))
+		return srv.(MSTAdminServiceServer).DropKeyspace(ctx, req.(*MSTDropKeyspaceRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-var _MSTService_serviceDesc = grpc.ServiceDesc{
-	ServiceName: "vulture.service.rpc.MSTService",
-	HandlerType: (*MSTServiceServer)(nil),
+func _MSTAdminService_ListKeyspaces_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(empty.Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MSTAdminServiceServer).ListKeyspaces(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/vulture.service.rpc.MSTAdminService/ListKeyspaces",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MSTAdminServiceServer).ListKeyspaces(ctx, req.(*empty.Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MSTAdminService_Catalog_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MSTCatalogRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MSTAdminServiceServer).Catalog(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/vulture.service.rpc.MSTAdminService/Catalog",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MSTAdminServiceServer).Catalog(ctx, req.(*MSTCatalogRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MSTAdminService_CreateDatabaseSnapshot_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MSTDatabaseSnapshotRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MSTAdminServiceServer).CreateDatabaseSnapshot(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/vulture.service.rpc.MSTAdminService/CreateDatabaseSnapshot",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MSTAdminServiceServer).CreateDatabaseSnapshot(ctx, req.(*MSTDatabaseSnapshotRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MSTAdminService_DeleteDatabaseSnapshot_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MSTDatabaseSnapshotRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MSTAdminServiceServer).DeleteDatabaseSnapshot(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/vulture.service.rpc.MSTAdminService/DeleteDatabaseSnapshot",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MSTAdminServiceServer).DeleteDatabaseSnapshot(ctx, req.(*MSTDatabaseSnapshotRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var _MSTAdminService_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "vulture.service.rpc.MSTAdminService",
+	HandlerType: (*MSTAdminServiceServer)(nil),
 	Methods: []grpc.MethodDesc{
 		{
-			MethodName: "Put",
-			Handler:    _MSTService_Put_Handler,
+			MethodName: "CreateKeyspace",
+			Handler:    _MSTAdminService_CreateKeyspace_Handler,
 		},
 		{
-			MethodName: "Get",
-			Handler:    _MSTService_Get_Handler,
+			MethodName: "DropKeyspace",
+			Handler:    _MSTAdminService_DropKeyspace_Handler,
+		},
+		{
+			MethodName: "ListKeyspaces",
+			Handler:    _MSTAdminService_ListKeyspaces_Handler,
+		},
+		{
+			MethodName: "Catalog",
+			Handler:    _MSTAdminService_Catalog_Handler,
+		},
+		{
+			MethodName: "CreateDatabaseSnapshot",
+			Handler:    _MSTAdminService_CreateDatabaseSnapshot_Handler,
+		},
+		{
+			MethodName: "DeleteDatabaseSnapshot",
+			Handler:    _MSTAdminService_DeleteDatabaseSnapshot_Handler,
 		},
 	},
 	Streams:  []grpc.StreamDesc{},
@@ -24,18 +24,19 @@ type AntiEntropyRound struct {
 	roundUUID uuid.UUID
 	ctx       context.Context
 	peer      Peer
+	keyspace  string
 	tree      *mst.MerkleSearchTree
 	cancelFn  context.CancelFunc
 }
 
-// NewAntiEntropyRound creates new AntiEntropyRound
-func NewAntiEntropyRound(peer Peer, tree *mst.MerkleSearchTree) AntiEntropyRound {
+// NewAntiEntropyRound creates new AntiEntropyRound that reconciles the tree of a keyspace
+func NewAntiEntropyRound(peer Peer, keyspace string, tree *mst.MerkleSearchTree) AntiEntropyRound {
 	ctx, cancelFn := context.WithCancel(context.Background())
 	roundUUID, err := uuid.NewRandom()
 	if err != nil {
 		panic(err)
 	}
-	return AntiEntropyRound{roundUUID, ctx, peer, tree, cancelFn}
+	return AntiEntropyRound{roundUUID, ctx, peer, keyspace, tree, cancelFn}
 }
 
 func (r AntiEntropyRound) runRound(endRoundFunc EndRoundFunc) {
@@ -60,9 +61,10 @@ func (r AntiEntropyRound) runRound(endRoundFunc EndRoundFunc) {
 	res, err := client.RoundStart(r.ctx, &rpc.MSTRoundStartRequest{
 		RootHash:  rootHash,
 		RoundUuid: roundUUIDBytes,
+		Keyspace:  r.keyspace,
 	})
 	if err != nil {
-		log.Printf("Error starting round to %s: %s", address, err)
+		log.Printf("Error starting round of %s to %s: %s", r.keyspace, address, err)
 		endRoundFunc()
 		return
 	}
@@ -73,9 +75,16 @@ func (r AntiEntropyRound) runRound(endRoundFunc EndRoundFunc) {
 		rpcNodes := make([]*rpc.MSTNode, 0, len(hashes))
 		hashStrs := make([]string, 0, len(hashes))
 		for _, hash := range hashes {
-			node := store.Get(hash)
-			if node == nil {
-				panic(fmt.Sprintf("Missing node for hash %s", hex.EncodeToString(hash)))
+			node, err := store.Get(hash)
+			if err != nil {
+				log.Printf(
+					"Error getting node %s for round to %s: %s",
+					hex.EncodeToString(hash),
+					address,
+					err,
+				)
+				endRoundFunc()
+				return
 			}
 			rpcNodes = append(rpcNodes, nodeToRPC(node))
 			hashStrs = append(hashStrs, hex.EncodeToString(hash))
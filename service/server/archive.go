@@ -0,0 +1,128 @@
+package server
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"log"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/vulturedb/vulture/mst"
+	"github.com/vulturedb/vulture/service/rpc"
+)
+
+// archiveChunkSize is the most archive data sent in a single message
+const archiveChunkSize = 64 * 1024
+
+// chunkWriter sends everything written to it as archive chunks
+type chunkWriter struct {
+	send func(*rpc.MSTArchiveChunk) error
+}
+
+func (w *chunkWriter) Write(p []byte) (int, error) {
+	data := make([]byte, len(p))
+	copy(data, p)
+	err := w.send(&rpc.MSTArchiveChunk{Data: data})
+	if err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// chunkReader reads the data of the archive chunks it receives
+type chunkReader struct {
+	recv func() (*rpc.MSTArchiveChunk, error)
+	buf  []byte
+}
+
+func (r *chunkReader) Read(p []byte) (int, error) {
+	for len(r.buf) == 0 {
+		chunk, err := r.recv()
+		if err != nil {
+			return 0, err
+		}
+		r.buf = chunk.GetData()
+	}
+	n := copy(p, r.buf)
+	r.buf = r.buf[n:]
+	return n, nil
+}
+
+// Snapshot streams an archive of the current tree or of a snapshot
+func (s *MSTServer) Snapshot(
+	in *rpc.MSTSnapshotRequest,
+	stream rpc.MSTService_SnapshotServer,
+) error {
+	ks, err := s.keyspace(in.GetKeyspace())
+	if err != nil {
+		return err
+	}
+	tree, unpin, err := ks.pinTreeAt(nil, in.GetSnapshot())
+	if err != nil {
+		return err
+	}
+	defer unpin()
+	w := bufio.NewWriterSize(&chunkWriter{stream.Send}, archiveChunkSize)
+	err = tree.Export(w)
+	if err == nil {
+		err = w.Flush()
+	}
+	if err != nil {
+		log.Printf("Error writing snapshot of %x: %s", tree.RootHash(), err)
+		return status.Errorf(codes.Internal, "Couldn't write snapshot: %s", err)
+	}
+	log.Printf("Wrote snapshot of %x (%s)", tree.RootHash(), describeTree(tree))
+	return nil
+}
+
+// Restore reads an archive of a tree and merges it into the current tree, which makes the
+// current tree the archived one if it was empty. Other writes wait until the restore is done.
+func (s *MSTServer) Restore(stream rpc.MSTService_RestoreServer) error {
+	// The keyspace is named by the first chunk
+	first, err := stream.Recv()
+	if err == io.EOF {
+		return status.Errorf(codes.InvalidArgument, "Couldn't restore archive: it's empty")
+	} else if err != nil {
+		return err
+	}
+	ks, err := s.keyspace(first.GetKeyspace())
+	if err != nil {
+		return err
+	}
+	ks.treeLock.Lock()
+	defer ks.treeLock.Unlock()
+	r := &chunkReader{recv: stream.Recv, buf: first.GetData()}
+	restored, err := mst.Import(r, ks.tree.NodeStore(), ks.kr, ks.vr)
+	if err == nil && (restored.Base() != ks.tree.Base() || restored.Hash() != ks.tree.Hash()) {
+		err = fmt.Errorf(
+			"Archive uses base %d and hash %s, but the tree uses base %d and hash %s",
+			restored.Base(),
+			restored.Hash(),
+			ks.tree.Base(),
+			ks.tree.Hash(),
+		)
+	}
+	if err != nil {
+		return status.Errorf(codes.InvalidArgument, "Couldn't restore archive: %s", err)
+	}
+	// Archives of trees that keep aggregates hold the summaries, but not the aggregator
+	restored = restored.WithAggregator(ks.tree.Aggregator())
+	newTree, err := ks.mergeOnto(restored)
+	if err == nil {
+		err = ks.setTree(newTree)
+	}
+	if err != nil {
+		log.Printf("Error restoring %x: %s", restored.RootHash(), err)
+		return status.Errorf(codes.Internal, "Couldn't restore archive: %s", err)
+	}
+	log.Printf(
+		"Restored %x into %s (%s)",
+		restored.RootHash(),
+		ks.spec.Name,
+		describeTree(newTree),
+	)
+	go ks.runAntiEntropy()
+	return stream.SendAndClose(&rpc.MSTRestoreResponse{RootHash: newTree.RootHash()})
+}
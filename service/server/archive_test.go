@@ -0,0 +1,118 @@
+package server
+
+import (
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+
+	"github.com/vulturedb/vulture/mst"
+	"github.com/vulturedb/vulture/service/rpc"
+)
+
+func mustSnapshot(t *testing.T, s *testServer, req *rpc.MSTSnapshotRequest) [][]byte {
+	stream, err := s.client.Snapshot(context.Background(), req)
+	require.NoError(t, err)
+	chunks := [][]byte{}
+	for {
+		chunk, err := stream.Recv()
+		if err == io.EOF {
+			return chunks
+		}
+		require.NoError(t, err)
+		chunks = append(chunks, chunk.GetData())
+	}
+}
+
+func restore(
+	t *testing.T,
+	s *testServer,
+	keyspace string,
+	chunks [][]byte,
+) (*rpc.MSTRestoreResponse, error) {
+	stream, err := s.client.Restore(context.Background())
+	require.NoError(t, err)
+	for i, data := range chunks {
+		chunk := &rpc.MSTArchiveChunk{Data: data}
+		if i == 0 {
+			chunk.Keyspace = keyspace
+		}
+		require.NoError(t, stream.Send(chunk))
+	}
+	return stream.CloseAndRecv()
+}
+
+func TestBackupAndRestore(t *testing.T) {
+	a := newTestServer(t, "a", time.Hour)
+	b := newTestServer(t, "b", time.Hour)
+	for i := uint32(0); i < 1000; i++ {
+		mustPut(t, a, "", uint32Bytes(i), uint32Bytes(i))
+	}
+	_, err := a.client.CreateSnapshot(
+		context.Background(),
+		&rpc.MSTCreateSnapshotRequest{Name: "s"},
+	)
+	require.NoError(t, err)
+	root := currentRoot(t, a, "")
+	mustPut(t, a, "", uint32Bytes(1000), uint32Bytes(1000))
+	chunks := mustSnapshot(t, a, &rpc.MSTSnapshotRequest{Snapshot: "s"})
+	require.NotEmpty(t, chunks)
+
+	// Restoring into an empty tree makes it the archived one
+	resp, err := restore(t, b, "", chunks)
+	require.NoError(t, err)
+	assert.Equal(t, root, resp.GetRootHash())
+	assert.Equal(t, root, currentRoot(t, b, ""))
+	for i := uint32(0); i < 1000; i++ {
+		assert.Equal(t, uint32Bytes(i), mustGet(t, b, &rpc.MSTGetRequest{Key: uint32Bytes(i)}))
+	}
+	summary, err := b.client.Aggregate(context.Background(), &rpc.MSTAggregateRequest{})
+	require.NoError(t, err)
+	assert.Equal(t, uint64(1000), summary.GetCount())
+
+	// Restoring into a tree that has pairs merges the archived ones into it
+	mustPut(t, b, "", uint32Bytes(2000), uint32Bytes(2000))
+	resp, err = restore(t, b, "", mustSnapshot(t, a, &rpc.MSTSnapshotRequest{}))
+	require.NoError(t, err)
+	assert.Equal(t, resp.GetRootHash(), currentRoot(t, b, ""))
+	for _, i := range []uint32{0, 1000, 2000} {
+		assert.Equal(t, uint32Bytes(i), mustGet(t, b, &rpc.MSTGetRequest{Key: uint32Bytes(i)}))
+	}
+}
+
+func TestBackupAndRestoreErrors(t *testing.T) {
+	s := newTestServer(t, "a", time.Hour)
+	ctx := context.Background()
+	mustPut(t, s, "", uint32Bytes(1), uint32Bytes(1))
+	stream, err := s.client.Snapshot(ctx, &rpc.MSTSnapshotRequest{Snapshot: "missing"})
+	require.NoError(t, err)
+	_, err = stream.Recv()
+	assertCode(t, codes.NotFound, err)
+	stream, err = s.client.Snapshot(ctx, &rpc.MSTSnapshotRequest{Keyspace: "missing"})
+	require.NoError(t, err)
+	_, err = stream.Recv()
+	assertCode(t, codes.NotFound, err)
+
+	chunks := mustSnapshot(t, s, &rpc.MSTSnapshotRequest{})
+	_, err = restore(t, s, "", nil)
+	assertCode(t, codes.InvalidArgument, err)
+	_, err = restore(t, s, "", [][]byte{{1, 2, 3}})
+	assertCode(t, codes.InvalidArgument, err)
+	_, err = restore(t, s, "missing", chunks)
+	assertCode(t, codes.NotFound, err)
+
+	// Archives only restore into keyspaces of the same base and hash function
+	_, err = s.admin.CreateKeyspace(ctx, &rpc.MSTKeyspace{
+		Name:      "other",
+		Base:      uint32(mst.Base16),
+		KeyType:   "uint32",
+		ValueType: "uint32",
+	})
+	require.NoError(t, err)
+	_, err = restore(t, s, "other", chunks)
+	assertCode(t, codes.InvalidArgument, err)
+}
@@ -0,0 +1,359 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"fmt"
+	"log"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/golang/protobuf/ptypes/empty"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/vulturedb/vulture/mst"
+	"github.com/vulturedb/vulture/service/rpc"
+)
+
+// catalog keeps the catalog of the current tree of every keyspace, so that the whole database is
+// named by a single root. It's updated with the keyspace's treeLock held whenever a keyspace's
+// tree changes, so the catalog is always a consistent cut across keyspaces.
+type catalog struct {
+	base    mst.Base
+	hash    crypto.Hash
+	tree    *mst.MerkleSearchTree
+	entries map[string]mst.CatalogEntry
+	// The trees the entries are of, which are pinned to take snapshots of the whole database
+	trees map[string]*mst.MerkleSearchTree
+	// The schemas of keyspaces whose entries don't use the hash of their spec, which are the
+	// keyspaces of the rows of tables with the ID of the latest version of the table's schema
+	schemas map[string][]byte
+	lock    sync.Mutex
+}
+
+func newCatalog(base mst.Base, hash crypto.Hash) *catalog {
+	return &catalog{
+		base:    base,
+		hash:    hash,
+		tree:    mst.NewLocalMST(base, hash),
+		entries: make(map[string]mst.CatalogEntry),
+		trees:   make(map[string]*mst.MerkleSearchTree),
+		schemas: make(map[string][]byte),
+	}
+}
+
+// rebuild replaces the catalog with one of entries. Must be called with lock held.
+func (c *catalog) rebuild(entries map[string]mst.CatalogEntry) error {
+	tree, err := mst.BuildCatalog(c.base, c.hash, entries)
+	if err != nil {
+		return fmt.Errorf("Couldn't build catalog: %w", err)
+	}
+	c.tree = tree
+	c.entries = entries
+	return nil
+}
+
+// put puts the entry of a keyspace into the catalog unless it's already there. Must be called with
+// lock held.
+func (c *catalog) put(name string, entry mst.CatalogEntry) error {
+	if current, exists := c.entries[name]; exists && catalogEntriesEqual(current, entry) {
+		return nil
+	}
+	updated, err := c.tree.Put(mst.String(name), entry)
+	if err != nil {
+		return fmt.Errorf("Couldn't update catalog: %w", err)
+	}
+	c.tree = updated
+	c.entries[name] = entry
+	return nil
+}
+
+// set records the current tree of a keyspace. Must be called with the keyspace's treeLock held.
+func (c *catalog) set(spec mst.Keyspace, tree *mst.MerkleSearchTree) error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	entry := mst.CatalogEntryFor(spec, tree.RootHash(), c.hash)
+	if schema, exists := c.schemas[spec.Name]; exists {
+		entry.Schema = schema
+	}
+	err := c.put(spec.Name, entry)
+	if err != nil {
+		return err
+	}
+	// The tree's store can change without its root changing
+	c.trees[spec.Name] = tree
+	return nil
+}
+
+// setSchema sets the schema of a keyspace's entry to schema instead of the hash of its spec, both
+// for the entry that's in the catalog and for the ones that are set later. The keyspace doesn't
+// have to exist yet. If schema is nil, the hash of its spec is used again once it's set.
+func (c *catalog) setSchema(name string, schema []byte) error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if schema == nil {
+		delete(c.schemas, name)
+		return nil
+	}
+	c.schemas[name] = schema
+	entry, exists := c.entries[name]
+	if !exists {
+		return nil
+	}
+	entry.Schema = schema
+	return c.put(name, entry)
+}
+
+// remove drops a keyspace from the catalog, which is rebuilt without it. Must be called with the
+// keyspace's treeLock held.
+func (c *catalog) remove(name string) error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	entries := make(map[string]mst.CatalogEntry, len(c.entries))
+	for existing, entry := range c.entries {
+		if existing != name {
+			entries[existing] = entry
+		}
+	}
+	err := c.rebuild(entries)
+	if err != nil {
+		return err
+	}
+	delete(c.trees, name)
+	delete(c.schemas, name)
+	return nil
+}
+
+// current returns the root and a copy of the entries of the catalog
+func (c *catalog) current() ([]byte, map[string]mst.CatalogEntry) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	return c.tree.RootHash(), c.copyEntries()
+}
+
+// copyEntries returns a copy of the entries that isn't changed by later writes. Must be called with
+// lock held.
+func (c *catalog) copyEntries() map[string]mst.CatalogEntry {
+	entries := make(map[string]mst.CatalogEntry, len(c.entries))
+	for name, entry := range c.entries {
+		entries[name] = entry
+	}
+	return entries
+}
+
+func catalogEntriesEqual(e1 mst.CatalogEntry, e2 mst.CatalogEntry) bool {
+	return bytes.Equal(e1.Root, e2.Root) && bytes.Equal(e1.Schema, e2.Schema)
+}
+
+func catalogToRPC(root []byte, entries map[string]mst.CatalogEntry) *rpc.MSTCatalog {
+	names := make([]string, 0, len(entries))
+	for name := range entries {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	resp := &rpc.MSTCatalog{RootHash: root, Entries: make([]*rpc.MSTCatalogEntry, len(names))}
+	for i, name := range names {
+		resp.Entries[i] = &rpc.MSTCatalogEntry{
+			Keyspace:   name,
+			RootHash:   entries[name].Root,
+			SchemaHash: entries[name].Schema,
+		}
+	}
+	return resp
+}
+
+// Catalog returns the catalog of the current root of every keyspace
+func (s *MSTServer) Catalog(
+	ctx context.Context,
+	in *rpc.MSTCatalogRequest,
+) (*rpc.MSTCatalog, error) {
+	root, entries := s.catalog.current()
+	if len(in.GetKnownRootHash()) > 0 && bytes.Equal(root, in.GetKnownRootHash()) {
+		return &rpc.MSTCatalog{RootHash: root}, nil
+	}
+	return catalogToRPC(root, entries), nil
+}
+
+// pinCatalog returns the current catalog along with the tree of each of its keyspaces, and keeps
+// those trees from being garbage collected until the returned function is called
+func (s *MSTServer) pinCatalog() (
+	[]byte,
+	map[string]mst.CatalogEntry,
+	map[string]*mst.MerkleSearchTree,
+	func(),
+) {
+	s.keyspacesLock.RLock()
+	defer s.keyspacesLock.RUnlock()
+	s.catalog.lock.Lock()
+	defer s.catalog.lock.Unlock()
+	trees := make(map[string]*mst.MerkleSearchTree, len(s.catalog.trees))
+	unpins := make([]func(), 0, len(s.catalog.trees))
+	for name, tree := range s.catalog.trees {
+		// Trees only change in the catalog with the keyspace's treeLock held, so garbage
+		// collection can't run for a tree that's still in the catalog before it's pinned
+		trees[name] = tree
+		unpins = append(unpins, s.keyspaces[name].pin(tree))
+	}
+	unpin := func() {
+		for _, unpin := range unpins {
+			unpin()
+		}
+	}
+	return s.catalog.tree.RootHash(), s.catalog.copyEntries(), trees, unpin
+}
+
+// CreateDatabaseSnapshot creates a snapshot of the same name in every keyspace, all at the roots
+// of one catalog. If creating any of them fails, the ones that were created are deleted again.
+func (s *MSTServer) CreateDatabaseSnapshot(
+	ctx context.Context,
+	in *rpc.MSTDatabaseSnapshotRequest,
+) (*rpc.MSTCatalog, error) {
+	name := in.GetName()
+	if name == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "Snapshot name can't be empty")
+	}
+	root, entries, trees, unpin := s.pinCatalog()
+	defer unpin()
+	created := time.Now()
+	snapshotted := make([]*keyspace, 0, len(trees))
+	var err error
+	for ksName, tree := range trees {
+		var ks *keyspace
+		ks, err = s.keyspace(ksName)
+		if err != nil {
+			break
+		}
+		snapshot := mst.Snapshot{Name: name, Root: tree.RootHash(), Created: created}
+		ks.treeLock.Lock()
+		if _, exists := ks.snapshots[name]; exists {
+			err = status.Errorf(
+				codes.AlreadyExists,
+				"Snapshot %s already exists in keyspace %s",
+				name,
+				ksName,
+			)
+		} else {
+			err = ks.setSnapshots(ks.snapshotsWith(name, &snapshotEntry{snapshot, tree}))
+		}
+		ks.treeLock.Unlock()
+		if err != nil {
+			break
+		}
+		snapshotted = append(snapshotted, ks)
+	}
+	if err != nil {
+		for _, ks := range snapshotted {
+			ks.treeLock.Lock()
+			if deleteErr := ks.setSnapshots(ks.snapshotsWith(name, nil)); deleteErr != nil {
+				log.Printf("Error deleting snapshot %s of %s: %s", name, ks.spec.Name, deleteErr)
+			}
+			ks.treeLock.Unlock()
+		}
+		if _, isStatus := status.FromError(err); isStatus {
+			return nil, err
+		}
+		log.Printf("Error creating database snapshot %s: %s", name, err)
+		return nil, status.Errorf(codes.Internal, "Couldn't create snapshot: %s", err)
+	}
+	log.Printf("Created database snapshot %s of catalog %x", name, root)
+	return catalogToRPC(root, entries), nil
+}
+
+// DeleteDatabaseSnapshot deletes the snapshot of a name from every keyspace that has it
+func (s *MSTServer) DeleteDatabaseSnapshot(
+	ctx context.Context,
+	in *rpc.MSTDatabaseSnapshotRequest,
+) (*empty.Empty, error) {
+	name := in.GetName()
+	deleted := 0
+	for _, ks := range s.allKeyspaces() {
+		ks.treeLock.Lock()
+		var err error
+		if _, exists := ks.snapshots[name]; exists {
+			err = ks.setSnapshots(ks.snapshotsWith(name, nil))
+			deleted++
+		}
+		ks.treeLock.Unlock()
+		if err != nil {
+			log.Printf("Error deleting snapshot %s of %s: %s", name, ks.spec.Name, err)
+			return nil, status.Errorf(codes.Internal, "Couldn't delete snapshot: %s", err)
+		}
+	}
+	if deleted == 0 {
+		return nil, status.Errorf(codes.NotFound, "No snapshot named %s", name)
+	}
+	log.Printf("Deleted database snapshot %s from %d keyspaces", name, deleted)
+	return &empty.Empty{}, nil
+}
+
+// syncWith compares the catalog with the catalog of a peer, and starts a round of anti-entropy
+// with the peer for every keyspace whose root differs. Nothing else is sent if both catalogs have
+// the same root.
+func (s *MSTServer) syncWith(peer Peer) error {
+	address := fmt.Sprintf("%s:%d", peer.Hostname, peer.Port)
+	conn, err := grpc.Dial(address, grpc.WithInsecure())
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	root, entries := s.catalog.current()
+	resp, err := rpc.NewMSTAdminServiceClient(conn).Catalog(
+		context.Background(),
+		&rpc.MSTCatalogRequest{KnownRootHash: root},
+	)
+	if err != nil {
+		return err
+	}
+	if bytes.Equal(resp.GetRootHash(), root) {
+		return nil
+	}
+	differing := 0
+	for _, theirs := range resp.GetEntries() {
+		ours, exists := entries[theirs.GetKeyspace()]
+		if !exists || bytes.Equal(ours.Root, theirs.GetRootHash()) {
+			continue
+		} else if !bytes.Equal(ours.Schema, theirs.GetSchemaHash()) {
+			log.Printf(
+				"Not reconciling keyspace %s with %s, which has a different schema",
+				theirs.GetKeyspace(),
+				address,
+			)
+			continue
+		}
+		ks, err := s.keyspace(theirs.GetKeyspace())
+		if err != nil {
+			// The keyspace was dropped since
+			continue
+		}
+		ks.runAntiEntropyWith([]Peer{peer})
+		differing++
+	}
+	log.Printf(
+		"Catalog %x differs from %x of %s in %d keyspaces",
+		root,
+		resp.GetRootHash(),
+		address,
+		differing,
+	)
+	return nil
+}
+
+// RunAntiEntropy compares the catalog with the catalogs of peers every interval until the process
+// exits, and reconciles the keyspaces that differ. Keyspaces are also reconciled whenever they
+// change, so this catches up with peers that missed a change.
+func (s *MSTServer) RunAntiEntropy(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for range ticker.C {
+		for _, peer := range s.peers.Select() {
+			err := s.syncWith(peer)
+			if err != nil {
+				log.Printf("Error comparing catalogs with %s:%d: %s", peer.Hostname, peer.Port, err)
+			}
+		}
+	}
+}
@@ -0,0 +1,137 @@
+package server
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+
+	"github.com/vulturedb/vulture/mst"
+	"github.com/vulturedb/vulture/service/rpc"
+)
+
+func catalogRoots(catalog *rpc.MSTCatalog) map[string][]byte {
+	roots := make(map[string][]byte, len(catalog.GetEntries()))
+	for _, entry := range catalog.GetEntries() {
+		roots[entry.GetKeyspace()] = entry.GetRootHash()
+	}
+	return roots
+}
+
+func mustCatalog(t *testing.T, s *testServer) *rpc.MSTCatalog {
+	catalog, err := s.admin.Catalog(context.Background(), &rpc.MSTCatalogRequest{})
+	require.NoError(t, err)
+	return catalog
+}
+
+func TestCatalog(t *testing.T) {
+	s := newTestServer(t, "a", time.Hour)
+	ctx := context.Background()
+	catalog := mustCatalog(t, s)
+	assert.Equal(
+		t,
+		map[string][]byte{mst.DefaultKeyspace: currentRoot(t, s, "")},
+		catalogRoots(catalog),
+	)
+
+	// Entries are left out when the root is already known
+	known, err := s.admin.Catalog(ctx, &rpc.MSTCatalogRequest{KnownRootHash: catalog.RootHash})
+	require.NoError(t, err)
+	assert.Equal(t, catalog.GetRootHash(), known.GetRootHash())
+	assert.Empty(t, known.GetEntries())
+
+	mustPut(t, s, "", uint32Bytes(1), uint32Bytes(1))
+	updated := mustCatalog(t, s)
+	assert.NotEqual(t, catalog.GetRootHash(), updated.GetRootHash())
+	assert.Equal(t, currentRoot(t, s, ""), catalogRoots(updated)[mst.DefaultKeyspace])
+	known, err = s.admin.Catalog(ctx, &rpc.MSTCatalogRequest{KnownRootHash: catalog.RootHash})
+	require.NoError(t, err)
+	assert.Len(t, known.GetEntries(), 1)
+
+	_, err = s.admin.CreateKeyspace(
+		ctx,
+		&rpc.MSTKeyspace{Name: "other", KeyType: "uint32", ValueType: "uint32"},
+	)
+	require.NoError(t, err)
+	mustPut(t, s, "other", uint32Bytes(2), uint32Bytes(2))
+	assert.Equal(
+		t,
+		map[string][]byte{
+			mst.DefaultKeyspace: currentRoot(t, s, ""),
+			"other":             currentRoot(t, s, "other"),
+		},
+		catalogRoots(mustCatalog(t, s)),
+	)
+
+	_, err = s.admin.DropKeyspace(ctx, &rpc.MSTDropKeyspaceRequest{Name: "other"})
+	require.NoError(t, err)
+	dropped := mustCatalog(t, s)
+	assert.Equal(
+		t,
+		map[string][]byte{mst.DefaultKeyspace: currentRoot(t, s, "")},
+		catalogRoots(dropped),
+	)
+	// The catalog of the same roots has the same root
+	assert.Equal(t, updated.GetRootHash(), dropped.GetRootHash())
+}
+
+func TestDatabaseSnapshot(t *testing.T) {
+	s := newTestServer(t, "a", time.Hour)
+	ctx := context.Background()
+	_, err := s.admin.CreateKeyspace(
+		ctx,
+		&rpc.MSTKeyspace{Name: "other", KeyType: "uint32", ValueType: "uint32"},
+	)
+	require.NoError(t, err)
+	mustPut(t, s, "", uint32Bytes(1), uint32Bytes(1))
+	mustPut(t, s, "other", uint32Bytes(1), uint32Bytes(10))
+
+	snapshot, err := s.admin.CreateDatabaseSnapshot(
+		ctx,
+		&rpc.MSTDatabaseSnapshotRequest{Name: "db"},
+	)
+	require.NoError(t, err)
+	current := mustCatalog(t, s)
+	assert.Equal(t, current.GetRootHash(), snapshot.GetRootHash())
+	assert.Equal(t, catalogRoots(current), catalogRoots(snapshot))
+	_, err = s.admin.CreateDatabaseSnapshot(ctx, &rpc.MSTDatabaseSnapshotRequest{Name: "db"})
+	assertCode(t, codes.AlreadyExists, err)
+	_, err = s.admin.CreateDatabaseSnapshot(ctx, &rpc.MSTDatabaseSnapshotRequest{})
+	assertCode(t, codes.InvalidArgument, err)
+
+	// Every keyspace has the snapshot at the root in the catalog
+	mustPut(t, s, "", uint32Bytes(1), uint32Bytes(2))
+	mustPut(t, s, "other", uint32Bytes(1), uint32Bytes(20))
+	roots := catalogRoots(snapshot)
+	for keyspace, val := range map[string]uint32{"": 1, "other": 10} {
+		listed, err := s.client.ListSnapshots(
+			ctx,
+			&rpc.MSTListSnapshotsRequest{Keyspace: keyspace},
+		)
+		require.NoError(t, err)
+		require.Len(t, listed.GetSnapshots(), 1)
+		assert.Equal(t, "db", listed.GetSnapshots()[0].GetName())
+		name := keyspace
+		if name == "" {
+			name = mst.DefaultKeyspace
+		}
+		assert.Equal(t, roots[name], listed.GetSnapshots()[0].GetRootHash())
+		req := &rpc.MSTGetRequest{Key: uint32Bytes(1), Snapshot: "db", Keyspace: keyspace}
+		assert.Equal(t, uint32Bytes(val), mustGet(t, s, req))
+	}
+
+	_, err = s.admin.DeleteDatabaseSnapshot(ctx, &rpc.MSTDatabaseSnapshotRequest{Name: "db"})
+	require.NoError(t, err)
+	for _, keyspace := range []string{"", "other"} {
+		_, err = s.client.Get(
+			ctx,
+			&rpc.MSTGetRequest{Key: uint32Bytes(1), Snapshot: "db", Keyspace: keyspace},
+		)
+		assertCode(t, codes.NotFound, err)
+	}
+	_, err = s.admin.DeleteDatabaseSnapshot(ctx, &rpc.MSTDatabaseSnapshotRequest{Name: "db"})
+	assertCode(t, codes.NotFound, err)
+}
@@ -2,29 +2,76 @@ package server
 
 import (
 	"bytes"
+	"crypto"
+	"errors"
 	"fmt"
+	"time"
 
+	"github.com/golang/protobuf/ptypes"
+	"github.com/golang/protobuf/ptypes/wrappers"
+	"github.com/google/uuid"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/vulturedb/vulture/core"
 	"github.com/vulturedb/vulture/mst"
 	"github.com/vulturedb/vulture/service/rpc"
 )
 
-// TODO: Write tests for this
+func writableToBytes(w mst.Writable, what string) []byte {
+	buf := new(bytes.Buffer)
+	err := w.Write(buf)
+	if err != nil {
+		panic(fmt.Errorf("Couldn't write %s to byte buffer: %s", what, err))
+	}
+	return buf.Bytes()
+}
 
 func childToRPC(child mst.Child) *rpc.MSTChild {
-	keyBuf := new(bytes.Buffer)
-	valBuf := new(bytes.Buffer)
-	err := child.Key().Write(keyBuf)
-	if err != nil {
-		panic(fmt.Errorf("Couldn't write key to byte buffer: %s", err))
+	rpcChild := &rpc.MSTChild{
+		Key:  writableToBytes(child.Key(), "key"),
+		High: child.High(),
 	}
-	err = child.Value().Write(valBuf)
-	if err != nil {
-		panic(fmt.Errorf("Couldn't write value to byte buffer: %s", err))
+	if tombstone, isTombstone := child.Value().(mst.Tombstone); isTombstone {
+		rpcChild.Value = writableToBytes(tombstone.Value(), "value")
+		rpcChild.Tombstone = true
+		rpcChild.Deleted = writableToBytes(tombstone.Deleted(), "deleted value")
+	} else {
+		rpcChild.Value = writableToBytes(child.Value(), "value")
+	}
+	return rpcChild
+}
+
+func summaryToRPC(summary mst.Summary) *rpc.MSTSummary {
+	return &rpc.MSTSummary{
+		Count:    summary.Count,
+		Measured: summary.Measured,
+		Sum:      summary.Sum,
+		Min:      summary.Min,
+		Max:      summary.Max,
 	}
-	return &rpc.MSTChild{
-		Key:   keyBuf.Bytes(),
-		Value: valBuf.Bytes(),
-		High:  child.High(),
+}
+
+func keyspaceToRPC(ks mst.Keyspace) *rpc.MSTKeyspace {
+	return &rpc.MSTKeyspace{
+		Name:       ks.Name,
+		Base:       uint32(ks.Base),
+		Hash:       uint32(ks.Hash),
+		KeyType:    ks.KeyType,
+		ValueType:  ks.ValueType,
+		Aggregator: ks.Aggregator,
+	}
+}
+
+func keyspaceFromRPC(ks *rpc.MSTKeyspace) mst.Keyspace {
+	return mst.Keyspace{
+		Name:       ks.GetName(),
+		Base:       mst.Base(ks.GetBase()),
+		Hash:       crypto.Hash(ks.GetHash()),
+		KeyType:    ks.GetKeyType(),
+		ValueType:  ks.GetValueType(),
+		Aggregator: ks.GetAggregator(),
 	}
 }
 
@@ -35,10 +82,15 @@ func nodeToRPC(node *mst.Node) *rpc.MSTNode {
 	for _, child := range children {
 		rpcChildren = append(rpcChildren, childToRPC(child))
 	}
+	var rpcSummaries []*rpc.MSTSummary
+	for _, summary := range node.Summaries() {
+		rpcSummaries = append(rpcSummaries, summaryToRPC(summary))
+	}
 	return &rpc.MSTNode{
-		Level:    node.Level(),
-		Low:      node.Low(),
-		Children: rpcChildren,
+		Level:     node.Level(),
+		Low:       node.Low(),
+		Children:  rpcChildren,
+		Summaries: rpcSummaries,
 	}
 }
 
@@ -51,11 +103,18 @@ func childFromRPC(child *rpc.MSTChild, kr mst.KeyReader, vr mst.ValueReader) (ms
 	if err != nil {
 		return mst.Child{}, err
 	}
+	if child.GetTombstone() {
+		deleted, err := vr.FromBytes(child.GetDeleted())
+		if err != nil {
+			return mst.Child{}, err
+		}
+		v = mst.NewTombstone(v, deleted)
+	}
 	return mst.NewChild(k, v, child.GetHigh()), nil
 }
 
-// nodeFromRPC creates a native mst.Node type from the transport layer
-func nodeFromRPC(node *rpc.MSTNode, kr mst.KeyReader, vr mst.ValueReader) (*mst.Node, error) {
+// NodeFromRPC creates a native mst.Node type from the transport layer
+func NodeFromRPC(node *rpc.MSTNode, kr mst.KeyReader, vr mst.ValueReader) (*mst.Node, error) {
 	children := node.GetChildren()
 	mstChildren := make([]mst.Child, 0, len(children))
 	for _, child := range children {
@@ -65,5 +124,328 @@ func nodeFromRPC(node *rpc.MSTNode, kr mst.KeyReader, vr mst.ValueReader) (*mst.
 		}
 		mstChildren = append(mstChildren, mstChild)
 	}
-	return mst.NewNode(node.GetLevel(), node.GetLow(), mstChildren), nil
+	n := mst.NewNode(node.GetLevel(), node.GetLow(), mstChildren)
+	if len(node.GetSummaries()) == 0 {
+		return n, nil
+	}
+	if len(node.GetSummaries()) != len(mstChildren)+1 {
+		return nil, fmt.Errorf(
+			"Expected %d summaries, got %d",
+			len(mstChildren)+1,
+			len(node.GetSummaries()),
+		)
+	}
+	summaries := make([]mst.Summary, 0, len(node.GetSummaries()))
+	for _, summary := range node.GetSummaries() {
+		summaries = append(summaries, mst.Summary{
+			Count:    summary.GetCount(),
+			Measured: summary.GetMeasured(),
+			Sum:      summary.GetSum(),
+			Min:      summary.GetMin(),
+			Max:      summary.GetMax(),
+		})
+	}
+	return n.WithSummaries(summaries), nil
+}
+
+func fieldToRPC(v interface{}) (*rpc.TableValue, error) {
+	switch val := v.(type) {
+	case string:
+		return &rpc.TableValue{Kind: &rpc.TableValue_StringValue{StringValue: val}}, nil
+	case int32:
+		return &rpc.TableValue{Kind: &rpc.TableValue_IntValue{IntValue: val}}, nil
+	case int64:
+		return &rpc.TableValue{Kind: &rpc.TableValue_LongValue{LongValue: val}}, nil
+	case float32:
+		return &rpc.TableValue{Kind: &rpc.TableValue_FloatValue{FloatValue: val}}, nil
+	case float64:
+		return &rpc.TableValue{Kind: &rpc.TableValue_DoubleValue{DoubleValue: val}}, nil
+	case bool:
+		return &rpc.TableValue{Kind: &rpc.TableValue_BooleanValue{BooleanValue: val}}, nil
+	case []byte:
+		return &rpc.TableValue{Kind: &rpc.TableValue_BytesValue{BytesValue: val}}, nil
+	case time.Time:
+		ts, err := ptypes.TimestampProto(val)
+		if err != nil {
+			return nil, err
+		}
+		return &rpc.TableValue{Kind: &rpc.TableValue_TimestampValue{TimestampValue: ts}}, nil
+	case uuid.UUID:
+		return &rpc.TableValue{Kind: &rpc.TableValue_UuidValue{UuidValue: val[:]}}, nil
+	case core.Decimal:
+		return &rpc.TableValue{Kind: &rpc.TableValue_DecimalValue{DecimalValue: val.String()}}, nil
+	case core.Row:
+		record, err := rowToRPC(val)
+		if err != nil {
+			return nil, err
+		}
+		return &rpc.TableValue{Kind: &rpc.TableValue_RecordValue{RecordValue: record}}, nil
+	case []interface{}:
+		items := make([]*rpc.TableValue, len(val))
+		for i, item := range val {
+			var err error
+			items[i], err = fieldToRPC(item)
+			if err != nil {
+				return nil, fmt.Errorf("Couldn't convert item %d: %w", i, err)
+			}
+		}
+		array := &rpc.TableArray{Items: items}
+		return &rpc.TableValue{Kind: &rpc.TableValue_ArrayValue{ArrayValue: array}}, nil
+	default:
+		return nil, fmt.Errorf("Unsupported field value %v of type %T", v, v)
+	}
+}
+
+func fieldFromRPC(v *rpc.TableValue) (interface{}, error) {
+	switch kind := v.GetKind().(type) {
+	case *rpc.TableValue_StringValue:
+		return kind.StringValue, nil
+	case *rpc.TableValue_IntValue:
+		return kind.IntValue, nil
+	case *rpc.TableValue_LongValue:
+		return kind.LongValue, nil
+	case *rpc.TableValue_FloatValue:
+		return kind.FloatValue, nil
+	case *rpc.TableValue_DoubleValue:
+		return kind.DoubleValue, nil
+	case *rpc.TableValue_BooleanValue:
+		return kind.BooleanValue, nil
+	case *rpc.TableValue_BytesValue:
+		return kind.BytesValue, nil
+	case *rpc.TableValue_TimestampValue:
+		ts, err := ptypes.Timestamp(kind.TimestampValue)
+		if err != nil {
+			return nil, err
+		}
+		return ts.UTC(), nil
+	case *rpc.TableValue_UuidValue:
+		return uuid.FromBytes(kind.UuidValue)
+	case *rpc.TableValue_DecimalValue:
+		return core.ParseDecimal(kind.DecimalValue)
+	case *rpc.TableValue_RecordValue:
+		return rowFromRPC(kind.RecordValue)
+	case *rpc.TableValue_ArrayValue:
+		rpcItems := kind.ArrayValue.GetItems()
+		items := make([]interface{}, len(rpcItems))
+		for i, rpcItem := range rpcItems {
+			var err error
+			items[i], err = fieldFromRPC(rpcItem)
+			if err != nil {
+				return nil, fmt.Errorf("Invalid item %d: %w", i, err)
+			}
+		}
+		return items, nil
+	default:
+		return nil, fmt.Errorf("Field value isn't set")
+	}
+}
+
+// rowToRPC converts a native core.Row type into the transport layer
+func rowToRPC(row core.Row) (*rpc.TableRow, error) {
+	fields := make(map[string]*rpc.TableValue, len(row.Data))
+	for name, v := range row.Data {
+		field, err := fieldToRPC(v)
+		if err != nil {
+			return nil, fmt.Errorf("Couldn't convert field %s: %w", name, err)
+		}
+		fields[name] = field
+	}
+	return &rpc.TableRow{Fields: fields}, nil
+}
+
+// rowFromRPC creates a native core.Row type from the transport layer
+func rowFromRPC(row *rpc.TableRow) (core.Row, error) {
+	data := make(map[string]interface{}, len(row.GetFields()))
+	for name, field := range row.GetFields() {
+		v, err := fieldFromRPC(field)
+		if err != nil {
+			return core.Row{}, fmt.Errorf("Invalid field %s: %w", name, err)
+		}
+		data[name] = v
+	}
+	return core.Row{Data: data}, nil
+}
+
+// schemaFromRPC creates a native core.Schema type from the transport layer
+func schemaFromRPC(schema *rpc.TableSchema) (core.Schema, error) {
+	fields, err := fieldSpecsFromRPC(schema.GetFields())
+	if err != nil {
+		return core.Schema{}, err
+	}
+	var indexes map[string][]string
+	if len(schema.GetIndexes()) > 0 {
+		indexes = make(map[string][]string, len(schema.GetIndexes()))
+		for name, index := range schema.GetIndexes() {
+			indexes[name] = index.GetFields()
+		}
+	}
+	return core.Schema{
+		Fields:     fields,
+		PrimaryKey: schema.GetPrimaryKey(),
+		Indexes:    indexes,
+	}, nil
+}
+
+// schemaToRPC converts a native core.Schema type into the transport layer. The version and parent
+// are left out since they're set when a schema is stored.
+func schemaToRPC(schema core.Schema) (*rpc.TableSchema, error) {
+	fields, err := fieldSpecsToRPC(schema.Fields)
+	if err != nil {
+		return nil, err
+	}
+	var indexes map[string]*rpc.TableIndex
+	if len(schema.Indexes) > 0 {
+		indexes = make(map[string]*rpc.TableIndex, len(schema.Indexes))
+		for name, index := range schema.Indexes {
+			indexes[name] = &rpc.TableIndex{Fields: index}
+		}
+	}
+	return &rpc.TableSchema{
+		Fields:     fields,
+		PrimaryKey: schema.PrimaryKey,
+		Indexes:    indexes,
+	}, nil
+}
+
+// predicatesFromRPC creates native core.Predicate types from the transport layer
+func predicatesFromRPC(preds []*rpc.TablePredicate) ([]core.Predicate, error) {
+	converted := make([]core.Predicate, len(preds))
+	for i, pred := range preds {
+		v, err := fieldFromRPC(pred.GetValue())
+		if err != nil {
+			return nil, fmt.Errorf("Invalid value for field %s: %w", pred.GetField(), err)
+		}
+		converted[i] = core.Predicate{Field: pred.GetField(), Op: core.Op(pred.GetOp()), Value: v}
+	}
+	return converted, nil
+}
+
+func fieldSpecsFromRPC(specs map[string]*rpc.TableFieldSpec) (map[string]core.FieldSpec, error) {
+	if len(specs) == 0 {
+		return nil, nil
+	}
+	fields := make(map[string]core.FieldSpec, len(specs))
+	for name, spec := range specs {
+		fieldSpec, err := fieldSpecFromRPC(spec)
+		if err != nil {
+			return nil, fmt.Errorf("Invalid field %s: %w", name, err)
+		}
+		fields[name] = fieldSpec
+	}
+	return fields, nil
+}
+
+func fieldSpecFromRPC(spec *rpc.TableFieldSpec) (core.FieldSpec, error) {
+	fields, err := fieldSpecsFromRPC(spec.GetFields())
+	if err != nil {
+		return core.FieldSpec{}, err
+	}
+	fieldSpec := core.FieldSpec{
+		Type:     spec.GetType(),
+		Nullable: spec.GetNullable(),
+		Symbols:  spec.GetSymbols(),
+		Fields:   fields,
+		Pattern:  spec.GetPattern(),
+		Merge:    spec.GetMerge(),
+	}
+	if spec.GetItems() != nil {
+		items, err := fieldSpecFromRPC(spec.GetItems())
+		if err != nil {
+			return core.FieldSpec{}, err
+		}
+		fieldSpec.Items = &items
+	}
+	if spec.GetDefaultValue() != nil {
+		fieldSpec.Default, err = fieldFromRPC(spec.GetDefaultValue())
+		if err != nil {
+			return core.FieldSpec{}, fmt.Errorf("Invalid default: %w", err)
+		}
+	}
+	if spec.GetMin() != nil {
+		min := spec.GetMin().GetValue()
+		fieldSpec.Min = &min
+	}
+	if spec.GetMax() != nil {
+		max := spec.GetMax().GetValue()
+		fieldSpec.Max = &max
+	}
+	if spec.GetMaxLength() != nil {
+		maxLength := spec.GetMaxLength().GetValue()
+		fieldSpec.MaxLength = &maxLength
+	}
+	return fieldSpec, nil
+}
+
+func fieldSpecsToRPC(specs map[string]core.FieldSpec) (map[string]*rpc.TableFieldSpec, error) {
+	if len(specs) == 0 {
+		return nil, nil
+	}
+	fields := make(map[string]*rpc.TableFieldSpec, len(specs))
+	for name, spec := range specs {
+		fieldSpec, err := fieldSpecToRPC(spec)
+		if err != nil {
+			return nil, fmt.Errorf("Couldn't convert field %s: %w", name, err)
+		}
+		fields[name] = fieldSpec
+	}
+	return fields, nil
+}
+
+func fieldSpecToRPC(spec core.FieldSpec) (*rpc.TableFieldSpec, error) {
+	fields, err := fieldSpecsToRPC(spec.Fields)
+	if err != nil {
+		return nil, err
+	}
+	fieldSpec := &rpc.TableFieldSpec{
+		Type:     spec.Type,
+		Nullable: spec.Nullable,
+		Symbols:  spec.Symbols,
+		Fields:   fields,
+		Pattern:  spec.Pattern,
+		Merge:    spec.Merge,
+	}
+	if spec.Items != nil {
+		fieldSpec.Items, err = fieldSpecToRPC(*spec.Items)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if spec.Default != nil {
+		fieldSpec.DefaultValue, err = fieldToRPC(spec.Default)
+		if err != nil {
+			return nil, fmt.Errorf("Couldn't convert default: %w", err)
+		}
+	}
+	if spec.Min != nil {
+		fieldSpec.Min = &wrappers.DoubleValue{Value: *spec.Min}
+	}
+	if spec.Max != nil {
+		fieldSpec.Max = &wrappers.DoubleValue{Value: *spec.Max}
+	}
+	if spec.MaxLength != nil {
+		fieldSpec.MaxLength = &wrappers.UInt32Value{Value: *spec.MaxLength}
+	}
+	return fieldSpec, nil
+}
+
+// validationStatus converts an error from validating a row into an InvalidArgument status. If the
+// row failed validation, the status lists every failing field as a BadRequest detail.
+func validationStatus(msg string, err error) error {
+	st := status.Newf(codes.InvalidArgument, "%s: %s", msg, err)
+	validationErr := &core.ValidationError{}
+	if !errors.As(err, &validationErr) {
+		return st.Err()
+	}
+	violations := make([]*errdetails.BadRequest_FieldViolation, len(validationErr.Errors))
+	for i, fieldErr := range validationErr.Errors {
+		violations[i] = &errdetails.BadRequest_FieldViolation{
+			Field:       fieldErr.Field,
+			Description: fieldErr.Message,
+		}
+	}
+	detailed, detailErr := st.WithDetails(&errdetails.BadRequest{FieldViolations: violations})
+	if detailErr != nil {
+		return st.Err()
+	}
+	return detailed.Err()
 }
@@ -0,0 +1,97 @@
+package server
+
+import (
+	"crypto"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/vulturedb/vulture/mst"
+	"github.com/vulturedb/vulture/service/rpc"
+)
+
+func nodeHash(n *mst.Node) []byte {
+	hashable := mst.HashableNode(*n)
+	return mst.HashWritable(&hashable, crypto.SHA256)
+}
+
+func TestNodeRoundTrip(t *testing.T) {
+	tree := mst.NewLocalMST(mst.Base4, crypto.SHA256).WithAggregator(mst.UInt32Aggregator{})
+	var err error
+	for i := uint32(0); i < 100; i++ {
+		tree, err = tree.Put(mst.UInt32(i), mst.UInt32(i))
+		require.NoError(t, err)
+	}
+	for i := uint32(0); i < 100; i += 3 {
+		tree, err = tree.Delete(mst.UInt32(i))
+		require.NoError(t, err)
+	}
+	hashes, err := tree.NodeStore().(mst.CollectableNodeStore).Hashes()
+	require.NoError(t, err)
+	require.NotEmpty(t, hashes)
+	tombstones := 0
+	for _, hash := range hashes {
+		n, err := tree.NodeStore().Get(hash)
+		require.NoError(t, err)
+		rpcNode := nodeToRPC(n)
+		require.Len(t, rpcNode.GetSummaries(), len(n.Children())+1)
+		for _, child := range rpcNode.GetChildren() {
+			if child.GetTombstone() {
+				tombstones++
+			}
+		}
+		converted, err := NodeFromRPC(rpcNode, mst.UInt32KeyReader{}, mst.UInt32ValueReader{})
+		require.NoError(t, err)
+		// Nodes are only the same if their hashes are, which covers every child and tombstone
+		assert.Equal(t, hash, nodeHash(converted))
+		assert.Equal(t, n.Summaries(), converted.Summaries())
+	}
+	assert.Equal(t, 34, tombstones)
+}
+
+func TestChildToRPC(t *testing.T) {
+	high := []byte{1, 2, 3}
+	child := childToRPC(mst.NewChild(mst.UInt32(1), mst.UInt32(2), high))
+	assert.Equal(t, &rpc.MSTChild{
+		Key:   uint32Bytes(1),
+		Value: uint32Bytes(2),
+		High:  high,
+	}, child)
+
+	tombstone := mst.NewTombstone(mst.UInt32(3), mst.UInt32(2))
+	child = childToRPC(mst.NewChild(mst.UInt32(1), tombstone, nil))
+	assert.Equal(t, &rpc.MSTChild{
+		Key:       uint32Bytes(1),
+		Value:     uint32Bytes(3),
+		Tombstone: true,
+		Deleted:   uint32Bytes(2),
+	}, child)
+	converted, err := childFromRPC(child, mst.UInt32KeyReader{}, mst.UInt32ValueReader{})
+	require.NoError(t, err)
+	assert.Equal(t, mst.UInt32(1), converted.Key())
+	assert.Equal(t, tombstone, converted.Value())
+	assert.Nil(t, converted.High())
+}
+
+func TestNodeFromRPCErrors(t *testing.T) {
+	kr := mst.UInt32KeyReader{}
+	vr := mst.UInt32ValueReader{}
+	child := &rpc.MSTChild{Key: uint32Bytes(1), Value: uint32Bytes(2)}
+	_, err := NodeFromRPC(&rpc.MSTNode{Children: []*rpc.MSTChild{child}}, kr, vr)
+	assert.NoError(t, err)
+
+	_, err = NodeFromRPC(&rpc.MSTNode{
+		Children:  []*rpc.MSTChild{child},
+		Summaries: []*rpc.MSTSummary{{Count: 1}},
+	}, kr, vr)
+	assert.Error(t, err)
+	_, err = NodeFromRPC(&rpc.MSTNode{
+		Children: []*rpc.MSTChild{{Key: []byte{1}, Value: uint32Bytes(2)}},
+	}, kr, vr)
+	assert.Error(t, err)
+	_, err = NodeFromRPC(&rpc.MSTNode{
+		Children: []*rpc.MSTChild{{Key: uint32Bytes(1), Value: uint32Bytes(2), Tombstone: true}},
+	}, kr, vr)
+	assert.Error(t, err)
+}
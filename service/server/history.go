@@ -0,0 +1,272 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"log"
+	"sort"
+	"time"
+
+	"github.com/golang/protobuf/ptypes"
+	"github.com/golang/protobuf/ptypes/empty"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/vulturedb/vulture/mst"
+	"github.com/vulturedb/vulture/service/rpc"
+)
+
+// rootEntry records when the tree changed. Each entry keeps its own tree since the node stores of
+// later trees don't have to hold the nodes of earlier ones.
+type rootEntry struct {
+	tree *mst.MerkleSearchTree
+	time time.Time
+}
+
+// snapshotEntry is a snapshot along with the tree at its root
+type snapshotEntry struct {
+	mst.Snapshot
+	tree *mst.MerkleSearchTree
+}
+
+// liveHistory returns the entries of the root history whose roots the tree still had within the
+// retention period, oldest first. The last entry is always the current root. Must be called with
+// treeLock held.
+func (k *keyspace) liveHistory(now time.Time) []rootEntry {
+	i := 0
+	// A root was replaced when the next one was set
+	for i < len(k.history)-1 && now.Sub(k.history[i+1].time) > k.historyRetention {
+		i++
+	}
+	return k.history[i:]
+}
+
+// retainedTrees drops roots that are past the retention period from the root history, and returns
+// the trees of the remaining roots and every snapshot so that they aren't garbage collected. Must
+// be called with treeLock held for writing.
+func (k *keyspace) retainedTrees(now time.Time) []*mst.MerkleSearchTree {
+	k.history = k.liveHistory(now)
+	trees := make([]*mst.MerkleSearchTree, 0, len(k.history)+len(k.snapshots))
+	for _, entry := range k.history {
+		trees = append(trees, entry.tree)
+	}
+	for _, snapshot := range k.snapshots {
+		trees = append(trees, snapshot.tree)
+	}
+	return trees
+}
+
+// treeAt returns the tree at a root in the root history or of a snapshot, or nil if there isn't
+// one. Must be called with treeLock held.
+func (k *keyspace) treeAt(root []byte) *mst.MerkleSearchTree {
+	for _, entry := range k.liveHistory(time.Now()) {
+		if bytes.Equal(entry.tree.RootHash(), root) {
+			return entry.tree
+		}
+	}
+	for _, snapshot := range k.snapshots {
+		if bytes.Equal(snapshot.Root, root) {
+			return snapshot.tree
+		}
+	}
+	return nil
+}
+
+// pinTreeAt pins the tree at a root in the history or at a snapshot, or the current tree if
+// neither is given
+func (k *keyspace) pinTreeAt(
+	root []byte,
+	snapshot string,
+) (*mst.MerkleSearchTree, func(), error) {
+	if len(root) == 0 && snapshot == "" {
+		tree, unpin := k.pinTree()
+		return tree, unpin, nil
+	} else if len(root) > 0 && snapshot != "" {
+		return nil, nil, status.Errorf(
+			codes.InvalidArgument,
+			"Only one of a root hash and a snapshot can be given",
+		)
+	}
+	k.treeLock.RLock()
+	defer k.treeLock.RUnlock()
+	var tree *mst.MerkleSearchTree
+	if snapshot != "" {
+		named, exists := k.snapshots[snapshot]
+		if !exists {
+			return nil, nil, status.Errorf(codes.NotFound, "No snapshot named %s", snapshot)
+		}
+		tree = named.tree
+	} else if tree = k.treeAt(root); tree == nil {
+		return nil, nil, status.Errorf(
+			codes.NotFound,
+			"Root %x isn't in the root history or a snapshot",
+			root,
+		)
+	}
+	return tree, k.pin(tree), nil
+}
+
+// sortedSnapshots returns every snapshot sorted by name. Must be called with treeLock held.
+func (k *keyspace) sortedSnapshots() []mst.Snapshot {
+	snapshots := make([]mst.Snapshot, 0, len(k.snapshots))
+	for _, snapshot := range k.snapshots {
+		snapshots = append(snapshots, snapshot.Snapshot)
+	}
+	sort.Slice(snapshots, func(i, j int) bool { return snapshots[i].Name < snapshots[j].Name })
+	return snapshots
+}
+
+// setSnapshots replaces the snapshots, saving them first if there's a snapshot store. Must be
+// called with treeLock held for writing.
+func (k *keyspace) setSnapshots(snapshots map[string]snapshotEntry) error {
+	previous := k.snapshots
+	k.snapshots = snapshots
+	if k.snapshotStore != nil {
+		err := k.snapshotStore.SetSnapshots(k.sortedSnapshots())
+		if err != nil {
+			k.snapshots = previous
+			return err
+		}
+	}
+	return nil
+}
+
+func (k *keyspace) snapshotsWith(name string, snapshot *snapshotEntry) map[string]snapshotEntry {
+	snapshots := make(map[string]snapshotEntry, len(k.snapshots)+1)
+	for existing, snapshot := range k.snapshots {
+		snapshots[existing] = snapshot
+	}
+	if snapshot == nil {
+		delete(snapshots, name)
+	} else {
+		snapshots[name] = *snapshot
+	}
+	return snapshots
+}
+
+func snapshotToRPC(snapshot mst.Snapshot) (*rpc.MSTSnapshot, error) {
+	created, err := ptypes.TimestampProto(snapshot.Created)
+	if err != nil {
+		return nil, err
+	}
+	return &rpc.MSTSnapshot{Name: snapshot.Name, RootHash: snapshot.Root, Created: created}, nil
+}
+
+// CreateSnapshot names the current root, or a root in the history, so that it can be read and is
+// kept from garbage collection until the snapshot is deleted
+func (s *MSTServer) CreateSnapshot(
+	ctx context.Context,
+	in *rpc.MSTCreateSnapshotRequest,
+) (*rpc.MSTSnapshot, error) {
+	ks, err := s.keyspace(in.GetKeyspace())
+	if err != nil {
+		return nil, err
+	}
+	name := in.GetName()
+	if name == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "Snapshot name can't be empty")
+	}
+	ks.treeLock.Lock()
+	defer ks.treeLock.Unlock()
+	if _, exists := ks.snapshots[name]; exists {
+		return nil, status.Errorf(codes.AlreadyExists, "Snapshot %s already exists", name)
+	}
+	tree := ks.tree
+	if len(in.GetRootHash()) > 0 {
+		if tree = ks.treeAt(in.GetRootHash()); tree == nil {
+			return nil, status.Errorf(
+				codes.NotFound,
+				"Root %x isn't in the root history or a snapshot",
+				in.GetRootHash(),
+			)
+		}
+	}
+	root := tree.RootHash()
+	snapshot := mst.Snapshot{Name: name, Root: root, Created: time.Now()}
+	err = ks.setSnapshots(ks.snapshotsWith(name, &snapshotEntry{snapshot, tree}))
+	if err != nil {
+		log.Printf("Error creating snapshot %s: %s", name, err)
+		return nil, status.Errorf(codes.Internal, "Couldn't create snapshot: %s", err)
+	}
+	log.Printf("Created snapshot %s of %x", name, root)
+	resp, err := snapshotToRPC(snapshot)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "Couldn't convert snapshot: %s", err)
+	}
+	return resp, nil
+}
+
+// DeleteSnapshot deletes a snapshot, after which its nodes can be garbage collected
+func (s *MSTServer) DeleteSnapshot(
+	ctx context.Context,
+	in *rpc.MSTDeleteSnapshotRequest,
+) (*empty.Empty, error) {
+	ks, err := s.keyspace(in.GetKeyspace())
+	if err != nil {
+		return nil, err
+	}
+	name := in.GetName()
+	ks.treeLock.Lock()
+	defer ks.treeLock.Unlock()
+	if _, exists := ks.snapshots[name]; !exists {
+		return nil, status.Errorf(codes.NotFound, "No snapshot named %s", name)
+	}
+	err = ks.setSnapshots(ks.snapshotsWith(name, nil))
+	if err != nil {
+		log.Printf("Error deleting snapshot %s: %s", name, err)
+		return nil, status.Errorf(codes.Internal, "Couldn't delete snapshot: %s", err)
+	}
+	log.Printf("Deleted snapshot %s", name)
+	return &empty.Empty{}, nil
+}
+
+// ListSnapshots returns every snapshot sorted by name
+func (s *MSTServer) ListSnapshots(
+	ctx context.Context,
+	in *rpc.MSTListSnapshotsRequest,
+) (*rpc.MSTListSnapshotsResponse, error) {
+	ks, err := s.keyspace(in.GetKeyspace())
+	if err != nil {
+		return nil, err
+	}
+	ks.treeLock.RLock()
+	snapshots := ks.sortedSnapshots()
+	ks.treeLock.RUnlock()
+	resp := &rpc.MSTListSnapshotsResponse{Snapshots: make([]*rpc.MSTSnapshot, len(snapshots))}
+	for i, snapshot := range snapshots {
+		resp.Snapshots[i], err = snapshotToRPC(snapshot)
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "Couldn't convert snapshot: %s", err)
+		}
+	}
+	return resp, nil
+}
+
+// RootHistory returns the roots that can still be read by their hash, newest first
+func (s *MSTServer) RootHistory(
+	ctx context.Context,
+	in *rpc.MSTRootHistoryRequest,
+) (*rpc.MSTRootHistoryResponse, error) {
+	ks, err := s.keyspace(in.GetKeyspace())
+	if err != nil {
+		return nil, err
+	}
+	ks.treeLock.RLock()
+	history := append([]rootEntry{}, ks.liveHistory(time.Now())...)
+	ks.treeLock.RUnlock()
+	resp := &rpc.MSTRootHistoryResponse{}
+	for i := len(history) - 1; i >= 0; i-- {
+		if in.GetLimit() > 0 && uint32(len(resp.Entries)) >= in.GetLimit() {
+			break
+		}
+		ts, err := ptypes.TimestampProto(history[i].time)
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "Couldn't convert time: %s", err)
+		}
+		resp.Entries = append(resp.Entries, &rpc.MSTRootHistoryEntry{
+			RootHash: history[i].tree.RootHash(),
+			Time:     ts,
+		})
+	}
+	return resp, nil
+}
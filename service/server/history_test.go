@@ -0,0 +1,144 @@
+package server
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+
+	"github.com/vulturedb/vulture/disk"
+	"github.com/vulturedb/vulture/mst"
+	"github.com/vulturedb/vulture/service/rpc"
+)
+
+func currentRoot(t *testing.T, s *testServer, keyspace string) []byte {
+	resp, err := s.client.RootHistory(
+		context.Background(),
+		&rpc.MSTRootHistoryRequest{Limit: 1, Keyspace: keyspace},
+	)
+	require.NoError(t, err)
+	require.Len(t, resp.GetEntries(), 1)
+	return resp.GetEntries()[0].GetRootHash()
+}
+
+func TestRootHistory(t *testing.T) {
+	s := newTestServer(t, "a", time.Hour)
+	roots := [][]byte{}
+	for i := uint32(0); i < 3; i++ {
+		mustPut(t, s, "", uint32Bytes(i), uint32Bytes(i))
+		roots = append(roots, currentRoot(t, s, ""))
+	}
+	resp, err := s.client.RootHistory(context.Background(), &rpc.MSTRootHistoryRequest{})
+	require.NoError(t, err)
+	require.True(t, len(resp.GetEntries()) >= 3)
+	for i, root := range roots {
+		assert.Equal(t, root, resp.GetEntries()[len(roots)-1-i].GetRootHash())
+	}
+	resp, err = s.client.RootHistory(context.Background(), &rpc.MSTRootHistoryRequest{Limit: 2})
+	require.NoError(t, err)
+	assert.Len(t, resp.GetEntries(), 2)
+
+	// Past roots can still be read
+	assert.Equal(
+		t,
+		uint32Bytes(0),
+		mustGet(t, s, &rpc.MSTGetRequest{Key: uint32Bytes(0), RootHash: roots[0]}),
+	)
+	assert.Nil(t, mustGet(t, s, &rpc.MSTGetRequest{Key: uint32Bytes(1), RootHash: roots[0]}))
+	summary, err := s.client.Aggregate(
+		context.Background(),
+		&rpc.MSTAggregateRequest{RootHash: roots[1]},
+	)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(2), summary.GetCount())
+}
+
+func TestRootHistoryRetention(t *testing.T) {
+	s := newTestServer(t, "a", 50*time.Millisecond)
+	ctx := context.Background()
+	mustPut(t, s, "", uint32Bytes(1), uint32Bytes(1))
+	root := currentRoot(t, s, "")
+	mustPut(t, s, "", uint32Bytes(2), uint32Bytes(2))
+	assert.Equal(
+		t,
+		uint32Bytes(1),
+		mustGet(t, s, &rpc.MSTGetRequest{Key: uint32Bytes(1), RootHash: root}),
+	)
+
+	time.Sleep(100 * time.Millisecond)
+	_, err := s.client.Get(ctx, &rpc.MSTGetRequest{Key: uint32Bytes(1), RootHash: root})
+	assertCode(t, codes.NotFound, err)
+	_, err = s.client.CreateSnapshot(ctx, &rpc.MSTCreateSnapshotRequest{Name: "s", RootHash: root})
+	assertCode(t, codes.NotFound, err)
+	// The current root is always kept
+	resp, err := s.client.RootHistory(ctx, &rpc.MSTRootHistoryRequest{})
+	require.NoError(t, err)
+	require.Len(t, resp.GetEntries(), 1)
+	assert.Equal(
+		t,
+		uint32Bytes(2),
+		mustGet(t, s, &rpc.MSTGetRequest{Key: uint32Bytes(2), RootHash: currentRoot(t, s, "")}),
+	)
+}
+
+func TestSnapshotRetention(t *testing.T) {
+	dir, err := ioutil.TempDir("", "vulture-server-test")
+	require.NoError(t, err)
+	t.Cleanup(func() { os.RemoveAll(dir) })
+	s := newTestServerWithStorage(t, "a", disk.NewStorage(dir), 0)
+	ctx := context.Background()
+	for i := uint32(0); i < 50; i++ {
+		mustPut(t, s, "", uint32Bytes(i), uint32Bytes(i))
+	}
+	snapshot, err := s.client.CreateSnapshot(ctx, &rpc.MSTCreateSnapshotRequest{Name: "s"})
+	require.NoError(t, err)
+	assert.Equal(t, currentRoot(t, s, ""), snapshot.GetRootHash())
+	_, err = s.client.CreateSnapshot(ctx, &rpc.MSTCreateSnapshotRequest{Name: "s"})
+	assertCode(t, codes.AlreadyExists, err)
+	_, err = s.client.CreateSnapshot(ctx, &rpc.MSTCreateSnapshotRequest{})
+	assertCode(t, codes.InvalidArgument, err)
+	for i := uint32(0); i < 100; i++ {
+		mustPut(t, s, "", uint32Bytes(i), uint32Bytes(i+1))
+	}
+
+	// The snapshot keeps its nodes from being collected
+	_, err = s.manager.collectGarbage()
+	require.NoError(t, err)
+	for i := uint32(0); i < 50; i++ {
+		req := &rpc.MSTGetRequest{Key: uint32Bytes(i), Snapshot: "s"}
+		assert.Equal(t, uint32Bytes(i), mustGet(t, s, req))
+		req = &rpc.MSTGetRequest{Key: uint32Bytes(i), RootHash: snapshot.GetRootHash()}
+		assert.Equal(t, uint32Bytes(i), mustGet(t, s, req))
+	}
+	assert.Nil(t, mustGet(t, s, &rpc.MSTGetRequest{Key: uint32Bytes(50), Snapshot: "s"}))
+	listed, err := s.client.ListSnapshots(ctx, &rpc.MSTListSnapshotsRequest{})
+	require.NoError(t, err)
+	require.Len(t, listed.GetSnapshots(), 1)
+	assert.Equal(t, "s", listed.GetSnapshots()[0].GetName())
+	assert.Equal(t, snapshot.GetRootHash(), listed.GetSnapshots()[0].GetRootHash())
+
+	// Once it's deleted they are
+	_, err = s.client.DeleteSnapshot(ctx, &rpc.MSTDeleteSnapshotRequest{Name: "s"})
+	require.NoError(t, err)
+	collected, err := s.manager.collectGarbage()
+	require.NoError(t, err)
+	assert.NotZero(t, collected[mst.DefaultKeyspace])
+	_, err = s.client.Get(ctx, &rpc.MSTGetRequest{Key: uint32Bytes(1), Snapshot: "s"})
+	assertCode(t, codes.NotFound, err)
+	_, err = s.client.Get(
+		ctx,
+		&rpc.MSTGetRequest{Key: uint32Bytes(1), RootHash: snapshot.GetRootHash()},
+	)
+	assertCode(t, codes.NotFound, err)
+	_, err = s.client.DeleteSnapshot(ctx, &rpc.MSTDeleteSnapshotRequest{Name: "s"})
+	assertCode(t, codes.NotFound, err)
+	for i := uint32(0); i < 100; i++ {
+		req := &rpc.MSTGetRequest{Key: uint32Bytes(i)}
+		assert.Equal(t, uint32Bytes(i+1), mustGet(t, s, req))
+	}
+}
@@ -0,0 +1,241 @@
+package server
+
+import (
+	"context"
+	"crypto"
+	"log"
+	"sort"
+
+	"github.com/golang/protobuf/ptypes/empty"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/vulturedb/vulture/mst"
+	"github.com/vulturedb/vulture/service/rpc"
+)
+
+// KeyspaceStorage opens the stores each keyspace keeps its data in. The root and snapshot stores
+// can be nil to keep the root and snapshots in memory only.
+type KeyspaceStorage interface {
+	Open(
+		ks mst.Keyspace,
+		kr mst.KeyReader,
+		vr mst.ValueReader,
+	) (mst.NodeStore, mst.RootStore, mst.SnapshotStore, error)
+	// Remove deletes all of the data of a keyspace
+	Remove(ks mst.Keyspace) error
+}
+
+// MemoryStorage keeps every keyspace in memory
+type MemoryStorage struct{}
+
+func (MemoryStorage) Open(
+	ks mst.Keyspace,
+	kr mst.KeyReader,
+	vr mst.ValueReader,
+) (mst.NodeStore, mst.RootStore, mst.SnapshotStore, error) {
+	return mst.NewLocalNodeStore(ks.Hash), nil, nil, nil
+}
+
+func (MemoryStorage) Remove(ks mst.Keyspace) error {
+	return nil
+}
+
+// MemoryKeyspaceStore keeps the keyspaces of a server in memory
+type MemoryKeyspaceStore struct {
+	keyspaces []mst.Keyspace
+}
+
+func (s *MemoryKeyspaceStore) Keyspaces() ([]mst.Keyspace, error) {
+	return append([]mst.Keyspace{}, s.keyspaces...), nil
+}
+
+func (s *MemoryKeyspaceStore) SetKeyspaces(keyspaces []mst.Keyspace) error {
+	s.keyspaces = append([]mst.Keyspace{}, keyspaces...)
+	return nil
+}
+
+// saveKeyspaces saves every keyspace but the default one, which comes from the server's flags.
+// Must be called with keyspacesLock held.
+func (s *MSTServer) saveKeyspaces(keyspaces map[string]*keyspace) error {
+	specs := make([]mst.Keyspace, 0, len(keyspaces))
+	for name, ks := range keyspaces {
+		if name != mst.DefaultKeyspace {
+			specs = append(specs, ks.spec)
+		}
+	}
+	sort.Slice(specs, func(i, j int) bool { return specs[i].Name < specs[j].Name })
+	return s.keyspaceStore.SetKeyspaces(specs)
+}
+
+// addKeyspaces creates empty keyspaces of specs, which have to be valid. Either all of them are
+// created or none are.
+func (s *MSTServer) addKeyspaces(specs []mst.Keyspace) error {
+	s.keyspacesLock.Lock()
+	defer s.keyspacesLock.Unlock()
+	for _, spec := range specs {
+		if _, exists := s.keyspaces[spec.Name]; exists {
+			return status.Errorf(codes.AlreadyExists, "Keyspace %s already exists", spec.Name)
+		}
+	}
+	keyspaces := make(map[string]*keyspace, len(s.keyspaces)+len(specs))
+	for name, existing := range s.keyspaces {
+		keyspaces[name] = existing
+	}
+	added := make([]*keyspace, 0, len(specs))
+	var err error
+	for _, spec := range specs {
+		var ks *keyspace
+		ks, err = newKeyspace(spec, s.storage, s.historyRetention, s.peers, s.catalog)
+		if err != nil {
+			break
+		}
+		added = append(added, ks)
+		err = ks.addToCatalog()
+		if err != nil {
+			break
+		}
+		keyspaces[spec.Name] = ks
+	}
+	if err == nil {
+		err = s.saveKeyspaces(keyspaces)
+	}
+	if err == nil {
+		s.keyspaces = keyspaces
+		return nil
+	}
+	for _, ks := range added {
+		ks.treeLock.Lock()
+		if removeErr := s.catalog.remove(ks.spec.Name); removeErr != nil {
+			log.Printf("Error removing keyspace %s from the catalog: %s", ks.spec.Name, removeErr)
+		}
+		ks.treeLock.Unlock()
+	}
+	for _, spec := range specs {
+		// Whatever was created for the keyspace would otherwise be picked up by a keyspace
+		// with the same name later
+		if removeErr := s.storage.Remove(spec); removeErr != nil {
+			log.Printf("Error cleaning up keyspace %s: %s", spec.Name, removeErr)
+		}
+	}
+	return err
+}
+
+// dropKeyspaces deletes keyspaces and all of their data
+func (s *MSTServer) dropKeyspaces(names []string) error {
+	s.keyspacesLock.Lock()
+	defer s.keyspacesLock.Unlock()
+	dropped := make(map[string]*keyspace, len(names))
+	for _, name := range names {
+		ks, exists := s.keyspaces[name]
+		if !exists {
+			return status.Errorf(codes.NotFound, "No keyspace named %s", name)
+		}
+		dropped[name] = ks
+	}
+	keyspaces := make(map[string]*keyspace, len(s.keyspaces))
+	for existing, other := range s.keyspaces {
+		if _, isDropped := dropped[existing]; !isDropped {
+			keyspaces[existing] = other
+		}
+	}
+	err := s.saveKeyspaces(keyspaces)
+	if err != nil {
+		return err
+	}
+	s.keyspaces = keyspaces
+	for name, ks := range dropped {
+		// Requests that already have the keyspace can finish, but nothing can write to it after
+		// this
+		ks.treeLock.Lock()
+		ks.dropped = true
+		err = s.catalog.remove(name)
+		if err != nil {
+			log.Printf("Error removing keyspace %s from the catalog: %s", name, err)
+		}
+		ks.treeLock.Unlock()
+		err = s.storage.Remove(ks.spec)
+		if err != nil {
+			// The keyspace is already gone, so this only leaves its data behind
+			log.Printf("Error removing data of keyspace %s: %s", name, err)
+		}
+	}
+	return nil
+}
+
+// CreateKeyspace creates an empty keyspace. The base and hash function default to the default
+// keyspace's if they're 0. Keyspaces of tables can't be created this way.
+func (s *MSTServer) CreateKeyspace(
+	ctx context.Context,
+	in *rpc.MSTKeyspace,
+) (*rpc.MSTKeyspace, error) {
+	spec := keyspaceFromRPC(in)
+	if isTableKeyspace(spec.Name) {
+		return nil, status.Errorf(
+			codes.InvalidArgument,
+			"Keyspaces starting with %s are kept for tables",
+			tableKeyspacePrefix,
+		)
+	}
+	defaultKeyspace, err := s.keyspace(mst.DefaultKeyspace)
+	if err != nil {
+		return nil, err
+	}
+	if spec.Base == 0 {
+		spec.Base = defaultKeyspace.spec.Base
+	}
+	if spec.Hash == crypto.Hash(0) {
+		spec.Hash = defaultKeyspace.spec.Hash
+	}
+	err = spec.Validate()
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "Invalid keyspace: %s", err)
+	}
+	err = s.addKeyspaces([]mst.Keyspace{spec})
+	if err != nil {
+		if _, isStatus := status.FromError(err); isStatus {
+			return nil, err
+		}
+		log.Printf("Error creating keyspace %s: %s", spec.Name, err)
+		return nil, status.Errorf(codes.Internal, "Couldn't create keyspace: %s", err)
+	}
+	log.Printf("Created keyspace %s", spec.Name)
+	return keyspaceToRPC(spec), nil
+}
+
+// DropKeyspace deletes a keyspace and all of its data. Keyspaces of tables can't be dropped this
+// way.
+func (s *MSTServer) DropKeyspace(
+	ctx context.Context,
+	in *rpc.MSTDropKeyspaceRequest,
+) (*empty.Empty, error) {
+	name := in.GetName()
+	if name == mst.DefaultKeyspace {
+		return nil, status.Errorf(codes.InvalidArgument, "The default keyspace can't be dropped")
+	} else if isTableKeyspace(name) {
+		return nil, status.Errorf(codes.InvalidArgument, "Keyspace %s belongs to a table", name)
+	}
+	err := s.dropKeyspaces([]string{name})
+	if err != nil {
+		if _, isStatus := status.FromError(err); isStatus {
+			return nil, err
+		}
+		log.Printf("Error dropping keyspace %s: %s", name, err)
+		return nil, status.Errorf(codes.Internal, "Couldn't drop keyspace: %s", err)
+	}
+	log.Printf("Dropped keyspace %s", name)
+	return &empty.Empty{}, nil
+}
+
+// ListKeyspaces returns every keyspace sorted by name, including the default one
+func (s *MSTServer) ListKeyspaces(
+	ctx context.Context,
+	in *empty.Empty,
+) (*rpc.MSTListKeyspacesResponse, error) {
+	all := s.allKeyspaces()
+	keyspaces := make([]*rpc.MSTKeyspace, 0, len(all))
+	for _, ks := range all {
+		keyspaces = append(keyspaces, keyspaceToRPC(ks.spec))
+	}
+	return &rpc.MSTListKeyspacesResponse{Keyspaces: keyspaces}, nil
+}
@@ -0,0 +1,102 @@
+package server
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/ptypes/empty"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+
+	"github.com/vulturedb/vulture/mst"
+	"github.com/vulturedb/vulture/service/rpc"
+)
+
+func keyspaceNames(t *testing.T, s *testServer) []string {
+	resp, err := s.admin.ListKeyspaces(context.Background(), &empty.Empty{})
+	require.NoError(t, err)
+	names := make([]string, len(resp.GetKeyspaces()))
+	for i, ks := range resp.GetKeyspaces() {
+		names[i] = ks.GetName()
+	}
+	return names
+}
+
+func TestCreateKeyspace(t *testing.T) {
+	s := newTestServer(t, "a", time.Hour)
+	ctx := context.Background()
+	created, err := s.admin.CreateKeyspace(
+		ctx,
+		&rpc.MSTKeyspace{Name: "other", KeyType: "uint32", ValueType: "uint32"},
+	)
+	require.NoError(t, err)
+	// The base and hash function default to the default keyspace's
+	assert.Equal(t, uint32(mst.Base4), created.GetBase())
+	assert.NotZero(t, created.GetHash())
+	assert.Equal(t, []string{mst.DefaultKeyspace, "other"}, keyspaceNames(t, s))
+
+	// Keyspaces don't share keys
+	mustPut(t, s, "other", uint32Bytes(1), uint32Bytes(10))
+	assert.Equal(
+		t,
+		uint32Bytes(10),
+		mustGet(t, s, &rpc.MSTGetRequest{Key: uint32Bytes(1), Keyspace: "other"}),
+	)
+	assert.Nil(t, mustGet(t, s, &rpc.MSTGetRequest{Key: uint32Bytes(1)}))
+
+	_, err = s.admin.CreateKeyspace(
+		ctx,
+		&rpc.MSTKeyspace{Name: "other", KeyType: "string", ValueType: "uint32"},
+	)
+	assertCode(t, codes.AlreadyExists, err)
+	_, err = s.admin.CreateKeyspace(
+		ctx,
+		&rpc.MSTKeyspace{Name: "table-other", KeyType: "uint32", ValueType: "uint32"},
+	)
+	assertCode(t, codes.InvalidArgument, err)
+	_, err = s.admin.CreateKeyspace(
+		ctx,
+		&rpc.MSTKeyspace{Name: "invalid", KeyType: "float", ValueType: "uint32"},
+	)
+	assertCode(t, codes.InvalidArgument, err)
+	assert.Equal(t, []string{mst.DefaultKeyspace, "other"}, keyspaceNames(t, s))
+}
+
+func TestDropKeyspace(t *testing.T) {
+	s := newTestServer(t, "a", time.Hour)
+	ctx := context.Background()
+	_, err := s.admin.CreateKeyspace(
+		ctx,
+		&rpc.MSTKeyspace{Name: "other", KeyType: "uint32", ValueType: "uint32"},
+	)
+	require.NoError(t, err)
+	mustPut(t, s, "other", uint32Bytes(1), uint32Bytes(10))
+
+	_, err = s.admin.DropKeyspace(ctx, &rpc.MSTDropKeyspaceRequest{Name: "other"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{mst.DefaultKeyspace}, keyspaceNames(t, s))
+	_, err = s.client.Get(ctx, &rpc.MSTGetRequest{Key: uint32Bytes(1), Keyspace: "other"})
+	assertCode(t, codes.NotFound, err)
+	_, err = s.client.Put(
+		ctx,
+		&rpc.MSTPutRequest{Key: uint32Bytes(1), Value: uint32Bytes(1), Keyspace: "other"},
+	)
+	assertCode(t, codes.NotFound, err)
+
+	_, err = s.admin.DropKeyspace(ctx, &rpc.MSTDropKeyspaceRequest{Name: "other"})
+	assertCode(t, codes.NotFound, err)
+	_, err = s.admin.DropKeyspace(ctx, &rpc.MSTDropKeyspaceRequest{Name: mst.DefaultKeyspace})
+	assertCode(t, codes.InvalidArgument, err)
+	_, err = s.admin.DropKeyspace(ctx, &rpc.MSTDropKeyspaceRequest{Name: "table-other"})
+	assertCode(t, codes.InvalidArgument, err)
+
+	// A keyspace of the same name starts out empty
+	_, err = s.admin.CreateKeyspace(
+		ctx,
+		&rpc.MSTKeyspace{Name: "other", KeyType: "uint32", ValueType: "uint32"},
+	)
+	require.NoError(t, err)
+	assert.Nil(t, mustGet(t, s, &rpc.MSTGetRequest{Key: uint32Bytes(1), Keyspace: "other"}))
+}
@@ -4,159 +4,791 @@ import (
 	"bytes"
 	"context"
 	"encoding/hex"
+	"fmt"
+	"io"
 	"log"
+	"sort"
 	"sync"
+	"time"
 
 	"github.com/golang/protobuf/ptypes/empty"
 	"github.com/google/uuid"
-	"github.com/pkg/errors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 
 	"github.com/vulturedb/vulture/mst"
 	"github.com/vulturedb/vulture/service/rpc"
 )
 
-// MSTServer stores all local data required for running the Vulture server
-type MSTServer struct {
+// keyspace is a named tree and everything the server keeps for it
+type keyspace struct {
+	spec                  mst.Keyspace
 	tree                  *mst.MerkleSearchTree
+	roots                 mst.RootStore
+	kr                    mst.KeyReader
+	vr                    mst.ValueReader
 	peers                 *Peers
 	antiEntropyRounds     map[Peer]AntiEntropyRound
+	pinned                map[string]*pinnedTree
+	snapshotStore         mst.SnapshotStore
+	snapshots             map[string]snapshotEntry // guarded by treeLock
+	history               []rootEntry              // guarded by treeLock
+	historyRetention      time.Duration
+	catalog               *catalog
+	dropped               bool // guarded by treeLock
+	loading               int  // guarded by treeLock
 	treeLock              sync.RWMutex
 	antiEntropyRoundsLock sync.RWMutex
+	pinnedLock            sync.Mutex
 }
 
-// NewMSTServer creates a new Vulture server
-func NewMSTServer(tree *mst.MerkleSearchTree, peers *Peers) *MSTServer {
-	return &MSTServer{tree: tree, peers: peers, antiEntropyRounds: make(map[Peer]AntiEntropyRound)}
+// pinnedTree is a tree that a request is still reading from, so garbage collection has to keep
+// its nodes around even if it's no longer the current tree
+type pinnedTree struct {
+	tree  *mst.MerkleSearchTree
+	count int
 }
 
-func (s *MSTServer) getTree() *mst.MerkleSearchTree {
-	s.treeLock.RLock()
-	defer s.treeLock.RUnlock()
-	return s.tree
+// newKeyspace opens the stores of a keyspace in storage and loads its tree and snapshots. If the
+// root store isn't nil, the root of the tree is saved to it every time the tree changes, and if
+// the snapshot store isn't nil, named snapshots are loaded from it and saved to it. The keyspace
+// keeps its entry in the catalog up to date once it's added with addToCatalog.
+func newKeyspace(
+	spec mst.Keyspace,
+	storage KeyspaceStorage,
+	historyRetention time.Duration,
+	peers *Peers,
+	catalog *catalog,
+) (*keyspace, error) {
+	kr, vr, _, err := spec.Codecs()
+	if err != nil {
+		return nil, err
+	}
+	nodes, roots, snapshots, err := storage.Open(spec, kr, vr)
+	if err != nil {
+		return nil, fmt.Errorf("Couldn't open keyspace %s: %w", spec.Name, err)
+	}
+	var root []byte
+	if roots != nil {
+		root, err = roots.Root()
+		if err != nil {
+			return nil, fmt.Errorf("Couldn't read root of keyspace %s: %w", spec.Name, err)
+		}
+	}
+	tree, err := spec.Tree(root, nodes)
+	if err != nil {
+		return nil, err
+	}
+	k := &keyspace{
+		spec:              spec,
+		tree:              tree,
+		roots:             roots,
+		kr:                kr,
+		vr:                vr,
+		peers:             peers,
+		antiEntropyRounds: make(map[Peer]AntiEntropyRound),
+		pinned:            make(map[string]*pinnedTree),
+		snapshotStore:     snapshots,
+		snapshots:         make(map[string]snapshotEntry),
+		history:           []rootEntry{{tree, time.Now()}},
+		historyRetention:  historyRetention,
+		catalog:           catalog,
+	}
+	if snapshots != nil {
+		stored, err := snapshots.Snapshots()
+		if err != nil {
+			return nil, fmt.Errorf("Couldn't load snapshots of keyspace %s: %w", spec.Name, err)
+		}
+		for _, snapshot := range stored {
+			k.snapshots[snapshot.Name] = snapshotEntry{snapshot, tree.WithRoot(snapshot.Root)}
+		}
+	}
+	log.Printf("Opened keyspace %s at root %x", spec.Name, root)
+	return k, nil
 }
 
-func (s *MSTServer) mergeTree(tree *mst.MerkleSearchTree) {
-	s.treeLock.Lock()
-	defer s.treeLock.Unlock()
-	newTree, err := s.tree.Merge(tree)
+func (k *keyspace) addToCatalog() error {
+	k.treeLock.Lock()
+	defer k.treeLock.Unlock()
+	return k.catalog.set(k.spec, k.tree)
+}
+
+// MSTServer stores all local data required for running the Vulture server
+type MSTServer struct {
+	keyspaces        map[string]*keyspace // guarded by keyspacesLock
+	catalog          *catalog
+	storage          KeyspaceStorage
+	keyspaceStore    mst.KeyspaceStore
+	historyRetention time.Duration
+	peers            *Peers
+	// Merges the trees anti-entropy receives for keyspaces of tables. Set by NewTableServer
+	// before the server serves anything.
+	tables        *TableServer
+	keyspacesLock sync.RWMutex
+}
+
+// NewMSTServer creates a new Vulture server with a default keyspace that requests without a
+// keyspace use, and every keyspace in keyspaces. The stores of each keyspace are opened in
+// storage. Every root a keyspace had within the last historyRetention can still be read. The
+// catalog of every keyspace uses the base and hash function of the default keyspace.
+func NewMSTServer(
+	defaultKeyspace mst.Keyspace,
+	storage KeyspaceStorage,
+	keyspaces mst.KeyspaceStore,
+	historyRetention time.Duration,
+	peers *Peers,
+) (*MSTServer, error) {
+	s := &MSTServer{
+		keyspaces:        make(map[string]*keyspace),
+		catalog:          newCatalog(defaultKeyspace.Base, defaultKeyspace.Hash),
+		storage:          storage,
+		keyspaceStore:    keyspaces,
+		historyRetention: historyRetention,
+		peers:            peers,
+	}
+	defaultKeyspace.Name = mst.DefaultKeyspace
+	specs := []mst.Keyspace{defaultKeyspace}
+	stored, err := keyspaces.Keyspaces()
 	if err != nil {
-		panic(err)
+		return nil, fmt.Errorf("Couldn't load keyspaces: %w", err)
+	}
+	specs = append(specs, stored...)
+	for _, spec := range specs {
+		err := spec.Validate()
+		if err != nil {
+			return nil, err
+		}
+		ks, err := newKeyspace(spec, storage, historyRetention, peers, s.catalog)
+		if err != nil {
+			return nil, err
+		}
+		err = ks.addToCatalog()
+		if err != nil {
+			return nil, err
+		}
+		s.keyspaces[spec.Name] = ks
+	}
+	root, _ := s.catalog.current()
+	log.Printf("Opened %d keyspaces at catalog root %x", len(s.keyspaces), root)
+	return s, nil
+}
+
+// keyspace returns the keyspace with the given name, or the default keyspace if it's empty
+func (s *MSTServer) keyspace(name string) (*keyspace, error) {
+	if name == "" {
+		name = mst.DefaultKeyspace
+	}
+	s.keyspacesLock.RLock()
+	defer s.keyspacesLock.RUnlock()
+	ks, exists := s.keyspaces[name]
+	if !exists {
+		return nil, status.Errorf(codes.NotFound, "No keyspace named %s", name)
+	}
+	return ks, nil
+}
+
+// allKeyspaces returns every keyspace sorted by name
+func (s *MSTServer) allKeyspaces() []*keyspace {
+	s.keyspacesLock.RLock()
+	defer s.keyspacesLock.RUnlock()
+	all := make([]*keyspace, 0, len(s.keyspaces))
+	for _, ks := range s.keyspaces {
+		all = append(all, ks)
+	}
+	sort.Slice(all, func(i, j int) bool { return all[i].spec.Name < all[j].spec.Name })
+	return all
+}
+
+func (k *keyspace) getTree() *mst.MerkleSearchTree {
+	k.treeLock.RLock()
+	defer k.treeLock.RUnlock()
+	return k.tree
+}
+
+// pin keeps tree from being garbage collected until the returned function is called. Must be
+// called with treeLock held so that garbage collection can't run before the tree is pinned.
+func (k *keyspace) pin(tree *mst.MerkleSearchTree) func() {
+	root := string(tree.RootHash())
+	k.pinnedLock.Lock()
+	defer k.pinnedLock.Unlock()
+	pin, exists := k.pinned[root]
+	if !exists {
+		pin = &pinnedTree{tree: tree}
+		k.pinned[root] = pin
+	}
+	pin.count++
+	return func() {
+		k.pinnedLock.Lock()
+		defer k.pinnedLock.Unlock()
+		pin.count--
+		if pin.count == 0 {
+			delete(k.pinned, root)
+		}
+	}
+}
+
+// pinTree returns the current tree and keeps it from being garbage collected until the returned
+// function is called
+func (k *keyspace) pinTree() (*mst.MerkleSearchTree, func()) {
+	k.treeLock.RLock()
+	defer k.treeLock.RUnlock()
+	return k.tree, k.pin(k.tree)
+}
+
+func (k *keyspace) pinnedTrees() []*mst.MerkleSearchTree {
+	k.pinnedLock.Lock()
+	defer k.pinnedLock.Unlock()
+	trees := make([]*mst.MerkleSearchTree, 0, len(k.pinned))
+	for _, pin := range k.pinned {
+		trees = append(trees, pin.tree)
+	}
+	return trees
+}
+
+// describeTree summarizes the size of a tree and its store for logging. Not every store can
+// report its size, so errors are described rather than returned.
+func describeTree(tree *mst.MerkleSearchTree) string {
+	numNodes, err := tree.NumNodes()
+	if err != nil {
+		return fmt.Sprintf("tree: %s", err)
+	}
+	storeSize, err := tree.NodeStore().Size()
+	if err != nil {
+		return fmt.Sprintf("tree: %d, store: %s", numNodes, err)
+	}
+	return fmt.Sprintf("tree: %d, store: %d", numNodes, storeSize)
+}
+
+// setTree replaces the current tree, saving its root first so that a crash never leaves the saved
+// root behind what clients have seen, and updates the keyspace's entry in the catalog. Must be
+// called with treeLock held.
+func (k *keyspace) setTree(tree *mst.MerkleSearchTree) error {
+	if k.dropped {
+		return fmt.Errorf("Keyspace %s was dropped", k.spec.Name)
+	}
+	if k.roots != nil && !bytes.Equal(tree.RootHash(), k.tree.RootHash()) {
+		err := k.roots.SetRoot(tree.RootHash())
+		if err != nil {
+			return fmt.Errorf("Couldn't save root: %w", err)
+		}
+	}
+	err := k.catalog.set(k.spec, tree)
+	if err != nil {
+		return err
+	}
+	if !bytes.Equal(tree.RootHash(), k.tree.RootHash()) {
+		now := time.Now()
+		k.history = append(k.liveHistory(now), rootEntry{tree, now})
+	}
+	k.tree = tree
+	return nil
+}
+
+func (k *keyspace) mergeTree(tree *mst.MerkleSearchTree) error {
+	k.treeLock.Lock()
+	defer k.treeLock.Unlock()
+	newTree, err := k.tree.Merge(tree)
+	if err != nil {
+		return err
 	}
 	log.Printf(
-		"Merging tree from (tree: %d, store: %d) and (tree: %d, store: %d) to (tree: %d, store: %d)",
-		s.tree.NumNodes(),
-		s.tree.NodeStore().Size(),
-		tree.NumNodes(),
-		tree.NodeStore().Size(),
-		newTree.NumNodes(),
-		newTree.NodeStore().Size(),
+		"Merging tree from (%s) and (%s) to (%s)",
+		describeTree(k.tree),
+		describeTree(tree),
+		describeTree(newTree),
 	)
-	s.tree = newTree
+	return k.setTree(newTree)
+}
+
+// mergeRound merges the tree an anti-entropy round received into its keyspace. Trees of tables
+// are merged through their table, see TableServer.mergeKeyspace.
+func (s *MSTServer) mergeRound(ks *keyspace, tree *mst.MerkleSearchTree) error {
+	if s.tables != nil && isTableKeyspace(ks.spec.Name) {
+		return s.tables.mergeKeyspace(ks, tree)
+	}
+	return ks.mergeTree(tree)
+}
+
+// mergeOnto merges a tree whose store was built on top of the current tree's store into the
+// current tree. Must be called with treeLock held.
+func (k *keyspace) mergeOnto(tree *mst.MerkleSearchTree) (*mst.MerkleSearchTree, error) {
+	if k.tree.RootHash() == nil {
+		return tree, nil
+	}
+	// The other tree's store holds the nodes of both trees
+	return k.tree.WithNodeStore(tree.NodeStore()).Merge(tree)
+}
+
+// startLoading returns the current tree for a bulk load to build on without holding treeLock.
+// Garbage collection is held off until the returned function is called, since the nodes the load
+// writes aren't reachable from any tree until it's merged in.
+func (k *keyspace) startLoading() (*mst.MerkleSearchTree, func()) {
+	k.treeLock.Lock()
+	defer k.treeLock.Unlock()
+	k.loading++
+	return k.tree, func() {
+		k.treeLock.Lock()
+		defer k.treeLock.Unlock()
+		k.loading--
+	}
+}
+
+// mergeLoaded merges a tree that was loaded on top of snapshot, the tree that was current when
+// the load started, into the current tree and makes the result current. If the tree hasn't
+// changed since, the loaded tree's store already holds the nodes of both, otherwise the merge
+// copies the loaded nodes into the current store.
+func (k *keyspace) mergeLoaded(
+	snapshot *mst.MerkleSearchTree,
+	loaded *mst.MerkleSearchTree,
+) (*mst.MerkleSearchTree, error) {
+	k.treeLock.Lock()
+	defer k.treeLock.Unlock()
+	var newTree *mst.MerkleSearchTree
+	var err error
+	if k.tree == snapshot {
+		newTree, err = k.mergeOnto(loaded)
+	} else {
+		newTree, err = k.tree.Merge(loaded)
+	}
+	if err != nil {
+		return nil, err
+	}
+	err = k.setTree(newTree)
+	if err != nil {
+		return nil, err
+	}
+	return newTree, nil
 }
 
-func (s *MSTServer) createEndRoundFunc(peer Peer) EndRoundFunc {
+func (k *keyspace) createEndRoundFunc(peer Peer) EndRoundFunc {
 	return func() {
-		s.antiEntropyRoundsLock.Lock()
-		defer s.antiEntropyRoundsLock.Unlock()
-		_, hasRound := s.antiEntropyRounds[peer]
+		k.antiEntropyRoundsLock.Lock()
+		defer k.antiEntropyRoundsLock.Unlock()
+		_, hasRound := k.antiEntropyRounds[peer]
 		if hasRound {
-			delete(s.antiEntropyRounds, peer)
+			delete(k.antiEntropyRounds, peer)
 		}
 	}
 }
 
+func (k *keyspace) readKey(raw []byte) (mst.Key, error) {
+	key, err := k.kr.FromBytes(raw)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "Invalid key: %s", err)
+	}
+	return key, nil
+}
+
+func (k *keyspace) readValue(raw []byte) (mst.Value, error) {
+	val, err := k.vr.FromBytes(raw)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "Invalid value: %s", err)
+	}
+	return val, nil
+}
+
 // Get returns the value for a given key
 func (s *MSTServer) Get(ctx context.Context, in *rpc.MSTGetRequest) (*rpc.MSTGetResponse, error) {
-	key := in.GetKey()
-	tree := s.getTree()
-	val := tree.Get(mst.UInt32(key))
-	var primVal uint32
+	ks, err := s.keyspace(in.GetKeyspace())
+	if err != nil {
+		return nil, err
+	}
+	key, err := ks.readKey(in.GetKey())
+	if err != nil {
+		return nil, err
+	}
+	tree, unpin, err := ks.pinTreeAt(in.GetRootHash(), in.GetSnapshot())
+	if err != nil {
+		return nil, err
+	}
+	defer unpin()
+	val, err := tree.Get(key)
+	if err != nil {
+		log.Printf("Error getting %v: %s", key, err)
+		return nil, status.Errorf(codes.Internal, "Couldn't get key %v: %s", key, err)
+	}
+	log.Printf("Get %v: %v", key, val)
 	if val == nil {
-		primVal = 0
-	} else {
-		primVal = uint32(val.(mst.UInt32))
+		return &rpc.MSTGetResponse{}, nil
+	}
+	return &rpc.MSTGetResponse{Value: writableToBytes(val, "value"), Found: true}, nil
+}
+
+// GetWithProof returns the value for a given key along with a proof that checks it against the
+// current root hash
+func (s *MSTServer) GetWithProof(
+	ctx context.Context,
+	in *rpc.MSTGetWithProofRequest,
+) (*rpc.MSTGetWithProofResponse, error) {
+	ks, err := s.keyspace(in.GetKeyspace())
+	if err != nil {
+		return nil, err
+	}
+	key, err := ks.readKey(in.GetKey())
+	if err != nil {
+		return nil, err
+	}
+	tree, unpin := ks.pinTree()
+	defer unpin()
+	proof, err := tree.Prove(key)
+	if err != nil {
+		log.Printf("Error proving %v: %s", key, err)
+		return nil, status.Errorf(codes.Internal, "Couldn't prove key %v: %s", key, err)
+	}
+	val, err := tree.Get(key)
+	if err != nil {
+		log.Printf("Error getting %v: %s", key, err)
+		return nil, status.Errorf(codes.Internal, "Couldn't get key %v: %s", key, err)
+	}
+	rpcProof := make([]*rpc.MSTNode, 0, len(proof))
+	for _, node := range proof {
+		rpcProof = append(rpcProof, nodeToRPC(node))
+	}
+	resp := &rpc.MSTGetWithProofResponse{RootHash: tree.RootHash(), Proof: rpcProof}
+	if val != nil {
+		resp.Value = writableToBytes(val, "value")
+		resp.Found = true
+	}
+	log.Printf("Get with proof %v: %v (%d nodes)", key, val, len(rpcProof))
+	return resp, nil
+}
+
+// Scan streams the key/value pairs in a range of keys in order
+func (s *MSTServer) Scan(in *rpc.MSTScanRequest, stream rpc.MSTService_ScanServer) error {
+	ks, err := s.keyspace(in.GetKeyspace())
+	if err != nil {
+		return err
+	}
+	opts := mst.ScanOptions{Reverse: in.GetReverse()}
+	if len(in.GetStart()) > 0 {
+		opts.Start, err = ks.readKey(in.GetStart())
+		if err != nil {
+			return err
+		}
+	}
+	if len(in.GetEnd()) > 0 {
+		opts.End, err = ks.readKey(in.GetEnd())
+		if err != nil {
+			return err
+		}
+	}
+	if len(in.GetPrefix()) > 0 {
+		opts.Prefix, err = ks.readKey(in.GetPrefix())
+		if err != nil {
+			return err
+		}
+	}
+	tree, unpin, err := ks.pinTreeAt(in.GetRootHash(), in.GetSnapshot())
+	if err != nil {
+		return err
+	}
+	defer unpin()
+	it := tree.Scan(opts)
+	sent := uint32(0)
+	for (in.GetLimit() == 0 || sent < in.GetLimit()) && it.Next() {
+		err := stream.Send(&rpc.MSTScanResponse{
+			Key:   writableToBytes(it.Key(), "key"),
+			Value: writableToBytes(it.Value(), "value"),
+		})
+		if err != nil {
+			return err
+		}
+		sent++
+	}
+	if err := it.Err(); err != nil {
+		log.Printf("Error scanning from %v to %v: %s", opts.Start, opts.End, err)
+		return status.Errorf(codes.Internal, "Couldn't scan: %s", err)
 	}
-	log.Printf("Get %d: %d", key, primVal)
-	return &rpc.MSTGetResponse{Value: primVal}, nil
+	log.Printf("Scan %v to %v: %d keys", opts.Start, opts.End, sent)
+	return nil
 }
 
-func (s *MSTServer) runAntiEntropy() {
-	peers := s.peers.Select()
+// Aggregate summarizes the keys in a range
+func (s *MSTServer) Aggregate(
+	ctx context.Context,
+	in *rpc.MSTAggregateRequest,
+) (*rpc.MSTSummary, error) {
+	ks, err := s.keyspace(in.GetKeyspace())
+	if err != nil {
+		return nil, err
+	}
+	var start, end mst.Key
+	if len(in.GetStart()) > 0 {
+		start, err = ks.readKey(in.GetStart())
+		if err != nil {
+			return nil, err
+		}
+	}
+	if len(in.GetEnd()) > 0 {
+		end, err = ks.readKey(in.GetEnd())
+		if err != nil {
+			return nil, err
+		}
+	}
+	tree, unpin, err := ks.pinTreeAt(in.GetRootHash(), in.GetSnapshot())
+	if err != nil {
+		return nil, err
+	}
+	defer unpin()
+	summary, err := tree.Aggregate(start, end)
+	if err != nil {
+		log.Printf("Error aggregating from %v to %v: %s", start, end, err)
+		return nil, status.Errorf(codes.Internal, "Couldn't aggregate: %s", err)
+	}
+	log.Printf("Aggregate %v to %v: %d keys", start, end, summary.Count)
+	return summaryToRPC(summary), nil
+}
+
+func (k *keyspace) runAntiEntropy() {
+	k.runAntiEntropyWith(k.peers.Select())
+}
+
+// runAntiEntropyWith starts a round of anti-entropy with every peer that doesn't have one of the
+// keyspace going already
+func (k *keyspace) runAntiEntropyWith(peers []Peer) {
 	rounds := make([]AntiEntropyRound, 0)
-	s.antiEntropyRoundsLock.Lock()
+	k.antiEntropyRoundsLock.Lock()
 	for _, peer := range peers {
-		_, hasRound := s.antiEntropyRounds[peer]
+		_, hasRound := k.antiEntropyRounds[peer]
 		if !hasRound {
-			round := NewAntiEntropyRound(peer, s.getTree())
-			s.antiEntropyRounds[peer] = round
+			round := NewAntiEntropyRound(peer, k.spec.Name, k.getTree())
+			k.antiEntropyRounds[peer] = round
 			rounds = append(rounds, round)
 		}
 	}
-	s.antiEntropyRoundsLock.Unlock()
+	k.antiEntropyRoundsLock.Unlock()
 	for _, round := range rounds {
-		go round.runRound(s.createEndRoundFunc(round.peer))
+		go round.runRound(k.createEndRoundFunc(round.peer))
 	}
 }
 
 // Put inserts the given value for the given key
 func (s *MSTServer) Put(ctx context.Context, in *rpc.MSTPutRequest) (*empty.Empty, error) {
-	key := in.GetKey()
-	val := in.GetValue()
-	initialRootHash := s.tree.RootHash()
-	s.treeLock.Lock()
-	s.tree = s.tree.Put(mst.UInt32(key), mst.UInt32(val))
-	s.treeLock.Unlock()
-	log.Printf("Put %d: %d", key, val)
-	newRootHash := s.tree.RootHash()
+	ks, err := s.keyspace(in.GetKeyspace())
+	if err != nil {
+		return nil, err
+	}
+	key, err := ks.readKey(in.GetKey())
+	if err != nil {
+		return nil, err
+	}
+	val, err := ks.readValue(in.GetValue())
+	if err != nil {
+		return nil, err
+	}
+	ks.treeLock.Lock()
+	initialRootHash := ks.tree.RootHash()
+	newTree, err := ks.tree.Put(key, val)
+	if err == nil {
+		err = ks.setTree(newTree)
+	}
+	ks.treeLock.Unlock()
+	if err != nil {
+		log.Printf("Error putting %v: %s", key, err)
+		return nil, status.Errorf(codes.Internal, "Couldn't put key %v: %s", key, err)
+	}
+	log.Printf("Put %v: %v", key, val)
+	newRootHash := newTree.RootHash()
 	if bytes.Compare(newRootHash, initialRootHash) != 0 {
-		go s.runAntiEntropy()
+		go ks.runAntiEntropy()
 	}
 	return &empty.Empty{}, nil
 }
 
+// Delete deletes the given key
+func (s *MSTServer) Delete(ctx context.Context, in *rpc.MSTDeleteRequest) (*empty.Empty, error) {
+	ks, err := s.keyspace(in.GetKeyspace())
+	if err != nil {
+		return nil, err
+	}
+	key, err := ks.readKey(in.GetKey())
+	if err != nil {
+		return nil, err
+	}
+	ks.treeLock.Lock()
+	initialRootHash := ks.tree.RootHash()
+	newTree, err := ks.tree.Delete(key)
+	if err == nil {
+		err = ks.setTree(newTree)
+	}
+	ks.treeLock.Unlock()
+	if err != nil {
+		log.Printf("Error deleting %v: %s", key, err)
+		return nil, status.Errorf(codes.Internal, "Couldn't delete key %v: %s", key, err)
+	}
+	log.Printf("Delete %v", key)
+	newRootHash := newTree.RootHash()
+	if bytes.Compare(newRootHash, initialRootHash) != 0 {
+		go ks.runAntiEntropy()
+	}
+	return &empty.Empty{}, nil
+}
+
+// WriteBatch applies a batch of puts and deletes as one change to the tree
+func (s *MSTServer) WriteBatch(
+	ctx context.Context,
+	in *rpc.MSTWriteBatchRequest,
+) (*empty.Empty, error) {
+	ks, err := s.keyspace(in.GetKeyspace())
+	if err != nil {
+		return nil, err
+	}
+	batch := mst.NewBatch()
+	for i, write := range in.GetWrites() {
+		key, err := ks.kr.FromBytes(write.GetKey())
+		if err != nil {
+			return nil, status.Errorf(
+				codes.InvalidArgument,
+				"Invalid key in write %d: %s",
+				i,
+				err,
+			)
+		}
+		if write.GetDelete() {
+			batch.Delete(key)
+			continue
+		}
+		val, err := ks.vr.FromBytes(write.GetValue())
+		if err != nil {
+			return nil, status.Errorf(
+				codes.InvalidArgument,
+				"Invalid value in write %d: %s",
+				i,
+				err,
+			)
+		}
+		batch.Put(key, val)
+	}
+	ks.treeLock.Lock()
+	initialRootHash := ks.tree.RootHash()
+	newTree, err := ks.tree.Apply(batch)
+	if err == nil {
+		err = ks.setTree(newTree)
+	}
+	ks.treeLock.Unlock()
+	if err != nil {
+		log.Printf("Error writing batch of %d writes: %s", batch.Len(), err)
+		return nil, status.Errorf(codes.Internal, "Couldn't write batch: %s", err)
+	}
+	log.Printf("Wrote batch of %d writes", batch.Len())
+	newRootHash := newTree.RootHash()
+	if bytes.Compare(newRootHash, initialRootHash) != 0 {
+		go ks.runAntiEntropy()
+	}
+	return &empty.Empty{}, nil
+}
+
+// Import bulk loads a stream of key/value pairs sorted by key, which is much faster than putting
+// them one by one. The pairs are merged into the tree if it isn't empty. Other writes aren't
+// blocked while the pairs are streamed in, they're merged with the imported pairs at the end.
+func (s *MSTServer) Import(stream rpc.MSTService_ImportServer) error {
+	// The keyspace is named by the first pair
+	in, err := stream.Recv()
+	if err == io.EOF {
+		ks, err := s.keyspace("")
+		if err != nil {
+			return err
+		}
+		return stream.SendAndClose(&rpc.MSTImportResponse{RootHash: ks.getTree().RootHash()})
+	} else if err != nil {
+		return err
+	}
+	ks, err := s.keyspace(in.GetKeyspace())
+	if err != nil {
+		return err
+	}
+	// Build the tree without blocking writes, and only merge it in once the stream is done
+	snapshot, done := ks.startLoading()
+	defer done()
+	loader := mst.NewBulkLoader(snapshot.Base(), snapshot.Hash(), snapshot.NodeStore())
+	loader.SetAggregator(snapshot.Aggregator())
+	count := uint64(0)
+	for ; err != io.EOF; in, err = stream.Recv() {
+		if err != nil {
+			return err
+		}
+		key, err := ks.readKey(in.GetKey())
+		if err != nil {
+			return err
+		}
+		val, err := ks.readValue(in.GetValue())
+		if err != nil {
+			return err
+		}
+		err = loader.Add(key, val)
+		if err != nil {
+			return status.Errorf(codes.InvalidArgument, "Couldn't import pair %d: %s", count, err)
+		}
+		count++
+	}
+	loaded, err := loader.Tree()
+	var newTree *mst.MerkleSearchTree
+	if err == nil {
+		newTree, err = ks.mergeLoaded(snapshot, loaded)
+	}
+	if err != nil {
+		log.Printf("Error importing %d pairs into %s: %s", count, ks.spec.Name, err)
+		return status.Errorf(codes.Internal, "Couldn't import: %s", err)
+	}
+	log.Printf("Imported %d pairs into %s (%s)", count, ks.spec.Name, describeTree(newTree))
+	if count > 0 {
+		go ks.runAntiEntropy()
+	}
+	return stream.SendAndClose(&rpc.MSTImportResponse{Count: count, RootHash: newTree.RootHash()})
+}
+
 type antiEntropyDestRound struct {
+	keyspace *keyspace
 	tree     *mst.MerkleSearchTree
 	rootHash []byte
+	lastStep time.Time
 }
 
+// destRoundTimeout is how long a destination round can go without a step before garbage
+// collection assumes the source gave up on it
+const destRoundTimeout = time.Minute
+
 // MSTManagerServer stores data required for managing the Vulture server
-// TODO: right now we don't do any cleanup of destination rounds, so there may
-// be a memory leak if we have stray rounds. We could include a garbage
-// collection round at some point here.
 type MSTManagerServer struct {
 	server                    *MSTServer
-	kr                        mst.KeyReader
-	vr                        mst.ValueReader
 	antiEntropyDestRounds     map[uuid.UUID]antiEntropyDestRound
 	antiEntropyDestRoundsLock sync.RWMutex
 }
 
 // NewMSTManagerServer creates a new Vulture management server
-func NewMSTManagerServer(
-	server *MSTServer,
-	kr mst.KeyReader,
-	vr mst.ValueReader,
-) *MSTManagerServer {
+func NewMSTManagerServer(server *MSTServer) *MSTManagerServer {
 	return &MSTManagerServer{
 		server:                server,
-		kr:                    kr,
-		vr:                    vr,
 		antiEntropyDestRounds: make(map[uuid.UUID]antiEntropyDestRound),
 	}
 }
 
+func (s *MSTManagerServer) endRound(roundUUID uuid.UUID) {
+	s.antiEntropyDestRoundsLock.Lock()
+	delete(s.antiEntropyDestRounds, roundUUID)
+	s.antiEntropyDestRoundsLock.Unlock()
+}
+
 func (s *MSTManagerServer) getMissingHashes(
 	roundUUID uuid.UUID,
 	round antiEntropyDestRound,
-) *rpc.MSTRoundStepResponse {
-	hashes := mst.FindMissingNodes(round.tree.NodeStore(), round.rootHash)
+) (*rpc.MSTRoundStepResponse, error) {
+	hashes, err := mst.FindMissingNodes(round.tree.NodeStore(), round.rootHash)
+	if err != nil {
+		s.endRound(roundUUID)
+		log.Printf("Error finding missing nodes for roundUUID %s: %s", roundUUID.String(), err)
+		return nil, status.Errorf(codes.Internal, "Couldn't find missing nodes: %s", err)
+	}
 	if len(hashes) == 0 {
-		s.antiEntropyDestRoundsLock.Lock()
-		delete(s.antiEntropyDestRounds, roundUUID)
-		s.antiEntropyDestRoundsLock.Unlock()
-		s.server.mergeTree(round.tree)
+		// The round is only ended after merging so that its nodes aren't garbage collected first
+		err = s.server.mergeRound(round.keyspace, round.tree)
+		s.endRound(roundUUID)
+		if err != nil {
+			log.Printf("Error merging tree for roundUUID %s: %s", roundUUID.String(), err)
+			return nil, status.Errorf(codes.Internal, "Couldn't merge tree: %s", err)
+		}
 	}
-	return &rpc.MSTRoundStepResponse{Hashes: hashes}
+	return &rpc.MSTRoundStepResponse{Hashes: hashes}, nil
 }
 
 // RoundStart starts a round of anti entropy
@@ -166,23 +798,28 @@ func (s *MSTManagerServer) RoundStart(
 ) (*rpc.MSTRoundStepResponse, error) {
 	rootHash := in.GetRootHash()
 	roundUUID, err := uuid.FromBytes(in.GetRoundUuid())
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "Invalid round uuid: %s", err)
+	}
+	ks, err := s.server.keyspace(in.GetKeyspace())
 	if err != nil {
 		return nil, err
 	}
 
 	// Create the round on the destination side
-	tree := s.server.getTree().WithRoot(rootHash)
-	round := antiEntropyDestRound{tree, rootHash}
+	tree := ks.getTree().WithRoot(rootHash)
+	round := antiEntropyDestRound{ks, tree, rootHash, time.Now()}
 	s.antiEntropyDestRoundsLock.Lock()
 	s.antiEntropyDestRounds[roundUUID] = round
 	s.antiEntropyDestRoundsLock.Unlock()
 
 	log.Printf(
-		"Starting round for %s with roundUUID %s",
+		"Starting round of %s for %s with roundUUID %s",
+		ks.spec.Name,
 		hex.EncodeToString(rootHash),
 		roundUUID.String(),
 	)
-	return s.getMissingHashes(roundUUID, round), nil
+	return s.getMissingHashes(roundUUID, round)
 }
 
 func (s *MSTManagerServer) updateNodes(
@@ -194,7 +831,11 @@ func (s *MSTManagerServer) updateNodes(
 	s.antiEntropyDestRoundsLock.Lock()
 	defer s.antiEntropyDestRoundsLock.Unlock()
 	if _, exists := s.antiEntropyDestRounds[roundUUID]; !exists {
-		return antiEntropyDestRound{}, errors.Errorf("Missing round %s", roundUUID.String())
+		return antiEntropyDestRound{}, status.Errorf(
+			codes.NotFound,
+			"Missing round %s",
+			roundUUID.String(),
+		)
 	}
 	round := s.antiEntropyDestRounds[roundUUID]
 	tree := round.tree
@@ -202,11 +843,17 @@ func (s *MSTManagerServer) updateNodes(
 	hashStrs := make([]string, 0, len(nodes))
 	for _, node := range nodes {
 		var hash []byte
-		store, hash = store.Put(node)
+		var err error
+		store, hash, err = store.Put(node)
+		if err != nil {
+			delete(s.antiEntropyDestRounds, roundUUID)
+			log.Printf("Error storing node for roundUUID %s: %s", roundUUID.String(), err)
+			return antiEntropyDestRound{}, status.Errorf(codes.Internal, "Couldn't store node: %s", err)
+		}
 		hashStrs = append(hashStrs, hex.EncodeToString(hash))
 	}
 	tree = tree.WithNodeStore(store)
-	round = antiEntropyDestRound{tree, round.rootHash}
+	round = antiEntropyDestRound{round.keyspace, tree, round.rootHash, time.Now()}
 	s.antiEntropyDestRounds[roundUUID] = round
 	return round, nil
 }
@@ -218,15 +865,23 @@ func (s *MSTManagerServer) RoundStep(
 ) (*rpc.MSTRoundStepResponse, error) {
 	roundUUID, err := uuid.FromBytes(in.GetRoundUuid())
 	if err != nil {
-		return nil, err
+		return nil, status.Errorf(codes.InvalidArgument, "Invalid round uuid: %s", err)
+	}
+
+	// Nodes are read with the codecs of the round's keyspace
+	s.antiEntropyDestRoundsLock.RLock()
+	started, exists := s.antiEntropyDestRounds[roundUUID]
+	s.antiEntropyDestRoundsLock.RUnlock()
+	if !exists {
+		return nil, status.Errorf(codes.NotFound, "Missing round %s", roundUUID.String())
 	}
 
 	rpcNodes := in.GetNodes()
 	mstNodes := make([]*mst.Node, 0, len(rpcNodes))
 	for _, rpcNode := range rpcNodes {
-		mstNode, err := nodeFromRPC(rpcNode, s.kr, s.vr)
+		mstNode, err := NodeFromRPC(rpcNode, started.keyspace.kr, started.keyspace.vr)
 		if err != nil {
-			return nil, err
+			return nil, status.Errorf(codes.InvalidArgument, "Invalid node: %s", err)
 		}
 		mstNodes = append(mstNodes, mstNode)
 	}
@@ -242,5 +897,125 @@ func (s *MSTManagerServer) RoundStep(
 		roundUUID.String(),
 	)
 
-	return s.getMissingHashes(roundUUID, round), nil
+	return s.getMissingHashes(roundUUID, round)
+}
+
+// liveTrees returns every tree of a keyspace that might still be read: the current tree, those of
+// its anti-entropy rounds and pinned trees, and the roots it still retains. Destination rounds
+// that have gone stale are dropped first so that their nodes can be collected too. Must be called
+// with the locks that lockTrees takes held.
+func (s *MSTManagerServer) liveTrees(ks *keyspace) []*mst.MerkleSearchTree {
+	live := []*mst.MerkleSearchTree{ks.tree}
+	for _, round := range ks.antiEntropyRounds {
+		live = append(live, round.tree)
+	}
+	now := time.Now()
+	for roundUUID, round := range s.antiEntropyDestRounds {
+		if round.keyspace != ks {
+			continue
+		}
+		if now.Sub(round.lastStep) > destRoundTimeout {
+			log.Printf("Expiring stale round with roundUUID %s", roundUUID.String())
+			delete(s.antiEntropyDestRounds, roundUUID)
+			continue
+		}
+		live = append(live, round.tree)
+	}
+	live = append(live, ks.pinnedTrees()...)
+	return append(live, ks.retainedTrees(now)...)
+}
+
+// lockTrees takes every lock that guards a tree of a keyspace, so that nothing writes nodes while
+// they're held, and returns a function that releases them. This follows the order
+// runAntiEntropy takes them in.
+func (s *MSTManagerServer) lockTrees(ks *keyspace) func() {
+	ks.antiEntropyRoundsLock.RLock()
+	ks.treeLock.Lock()
+	s.antiEntropyDestRoundsLock.Lock()
+	return func() {
+		s.antiEntropyDestRoundsLock.Unlock()
+		ks.treeLock.Unlock()
+		ks.antiEntropyRoundsLock.RUnlock()
+	}
+}
+
+// collectKeyspaceGarbage deletes every node of a keyspace that isn't reachable from one of its
+// live trees, and returns how many were deleted. Writes are only blocked while listing the nodes
+// and live trees and while sweeping. Nodes are marked from the live trees in between, and then
+// again from the trees that are live when sweeping, which only reads the nodes written since, so
+// a node is only deleted if it stayed unreachable the whole time. Nodes that bulk loads write
+// aren't reachable from any tree until the load is done, so nothing is collected while one runs.
+func (s *MSTManagerServer) collectKeyspaceGarbage(ks *keyspace) (uint, error) {
+	unlock := s.lockTrees(ks)
+	if ks.dropped || ks.loading > 0 {
+		unlock()
+		return 0, nil
+	}
+	live := s.liveTrees(ks)
+	collector, err := mst.NewGarbageCollector(ks.tree.NodeStore())
+	unlock()
+	if err != nil {
+		return 0, err
+	}
+
+	err = collector.Mark(live)
+	if err != nil {
+		return 0, err
+	}
+
+	defer s.lockTrees(ks)()
+	if ks.dropped || ks.loading > 0 {
+		return 0, nil
+	}
+	err = collector.Mark(s.liveTrees(ks))
+	if err != nil {
+		return 0, err
+	}
+	store, collected, err := collector.Sweep(ks.tree.NodeStore())
+	if err != nil {
+		return 0, err
+	}
+	ks.tree = ks.tree.WithNodeStore(store)
+	err = ks.catalog.set(ks.spec, ks.tree)
+	if err != nil {
+		return 0, err
+	}
+	return collected, nil
+}
+
+// collectGarbage collects the garbage of every keyspace, and returns how many nodes were deleted
+// from each
+func (s *MSTManagerServer) collectGarbage() (map[string]uint, error) {
+	collected := make(map[string]uint)
+	for _, ks := range s.server.allKeyspaces() {
+		n, err := s.collectKeyspaceGarbage(ks)
+		if err != nil {
+			return nil, fmt.Errorf("Couldn't collect garbage of keyspace %s: %w", ks.spec.Name, err)
+		}
+		collected[ks.spec.Name] = n
+	}
+	return collected, nil
+}
+
+// RunGarbageCollection collects garbage every interval until the process exits
+func (s *MSTManagerServer) RunGarbageCollection(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for range ticker.C {
+		collected, err := s.collectGarbage()
+		if err != nil {
+			log.Printf("Error collecting garbage: %s", err)
+			continue
+		}
+		for _, ks := range s.server.allKeyspaces() {
+			if n, exists := collected[ks.spec.Name]; exists {
+				log.Printf(
+					"Collected %d unreachable nodes of %s (%s)",
+					n,
+					ks.spec.Name,
+					describeTree(ks.getTree()),
+				)
+			}
+		}
+	}
 }
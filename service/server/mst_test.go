@@ -0,0 +1,352 @@
+package server
+
+import (
+	"context"
+	"crypto"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/ptypes/empty"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+
+	"github.com/vulturedb/vulture/core"
+	"github.com/vulturedb/vulture/disk"
+	"github.com/vulturedb/vulture/mst"
+	"github.com/vulturedb/vulture/service/rpc"
+)
+
+// memorySchemaStore keeps schemas in memory by the hash of their JSON, so that servers that store
+// the same versions give them the same IDs like the IPFS schema store does
+type memorySchemaStore struct {
+	schemas map[string]core.Schema
+	lock    sync.Mutex
+}
+
+func newMemorySchemaStore() *memorySchemaStore {
+	return &memorySchemaStore{schemas: make(map[string]core.Schema)}
+}
+
+func (s *memorySchemaStore) put(schema core.Schema) ([]byte, error) {
+	raw, err := json.Marshal(schema)
+	if err != nil {
+		return nil, err
+	}
+	id := sha256.Sum256(raw)
+	s.schemas[string(id[:])] = schema
+	return id[:], nil
+}
+
+func (s *memorySchemaStore) PutSchema(schema core.Schema) ([]byte, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	schema.Version = 0
+	schema.Parent = nil
+	return s.put(schema)
+}
+
+func (s *memorySchemaStore) EvolveSchema(parent []byte, next core.Schema) ([]byte, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	prev, exists := s.schemas[string(parent)]
+	if !exists {
+		return nil, fmt.Errorf("Missing schema %x", parent)
+	}
+	if err := prev.CheckEvolution(next); err != nil {
+		return nil, err
+	}
+	next.Version = prev.Version + 1
+	next.Parent = parent
+	return s.put(next)
+}
+
+func (s *memorySchemaStore) SchemaHistory(head []byte) (core.SchemaHistory, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	versions := []core.Schema{}
+	for id := head; id != nil; {
+		schema, exists := s.schemas[string(id)]
+		if !exists {
+			return core.SchemaHistory{}, fmt.Errorf("Missing schema %x", id)
+		}
+		versions = append([]core.Schema{schema}, versions...)
+		id = schema.Parent
+	}
+	return core.NewSchemaHistoryFrom(versions)
+}
+
+// testServer is a server without peers whose services are served over an in-memory connection
+type testServer struct {
+	server      *MSTServer
+	manager     *MSTManagerServer
+	tables      *TableServer
+	client      rpc.MSTServiceClient
+	admin       rpc.MSTAdminServiceClient
+	tableClient rpc.TableServiceClient
+}
+
+func newTestServer(t *testing.T, replica string, historyRetention time.Duration) *testServer {
+	return newTestServerWithStorage(t, replica, MemoryStorage{}, historyRetention)
+}
+
+// newTestServerWithStorage creates a test server that keeps its nodes in storage, since only
+// stores that share nodes between versions leave garbage to collect
+func newTestServerWithStorage(
+	t *testing.T,
+	replica string,
+	storage KeyspaceStorage,
+	historyRetention time.Duration,
+) *testServer {
+	defaultKeyspace := mst.Keyspace{
+		Name:       mst.DefaultKeyspace,
+		Base:       mst.Base4,
+		Hash:       crypto.SHA256,
+		KeyType:    "uint32",
+		ValueType:  "uint32",
+		Aggregator: "uint32",
+	}
+	mstServer, err := NewMSTServer(
+		defaultKeyspace,
+		storage,
+		&MemoryKeyspaceStore{},
+		historyRetention,
+		NewPeers(&SelectAll{}),
+	)
+	require.NoError(t, err)
+	managerServer := NewMSTManagerServer(mstServer)
+	tableServer, err := NewTableServer(
+		mstServer,
+		replica,
+		newMemorySchemaStore(),
+		&MemoryTableStore{},
+	)
+	require.NoError(t, err)
+
+	lis := bufconn.Listen(1 << 20)
+	grpcServer := grpc.NewServer()
+	rpc.RegisterMSTServiceServer(grpcServer, mstServer)
+	rpc.RegisterMSTAdminServiceServer(grpcServer, mstServer)
+	rpc.RegisterMSTManagerServiceServer(grpcServer, managerServer)
+	rpc.RegisterTableServiceServer(grpcServer, tableServer)
+	go grpcServer.Serve(lis)
+	t.Cleanup(grpcServer.Stop)
+	conn, err := grpc.DialContext(
+		context.Background(),
+		"bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.Dial()
+		}),
+		grpc.WithInsecure(),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+	return &testServer{
+		server:      mstServer,
+		manager:     managerServer,
+		tables:      tableServer,
+		client:      rpc.NewMSTServiceClient(conn),
+		admin:       rpc.NewMSTAdminServiceClient(conn),
+		tableClient: rpc.NewTableServiceClient(conn),
+	}
+}
+
+func uint32Bytes(n uint32) []byte {
+	return writableToBytes(mst.UInt32(n), "uint32")
+}
+
+func mustPut(t *testing.T, s *testServer, keyspace string, key []byte, val []byte) {
+	_, err := s.client.Put(
+		context.Background(),
+		&rpc.MSTPutRequest{Key: key, Value: val, Keyspace: keyspace},
+	)
+	require.NoError(t, err)
+}
+
+func mustGet(t *testing.T, s *testServer, req *rpc.MSTGetRequest) []byte {
+	resp, err := s.client.Get(context.Background(), req)
+	require.NoError(t, err)
+	if !resp.GetFound() {
+		return nil
+	}
+	return resp.GetValue()
+}
+
+func assertCode(t *testing.T, code codes.Code, err error) {
+	require.Error(t, err)
+	assert.Equal(t, code, status.Code(err), "%s", err)
+}
+
+// syncKeyspace plays the source side of a round of anti-entropy of a keyspace from one server to
+// another, sending the nodes of its current tree that the other server asks for
+func syncKeyspace(t *testing.T, from *testServer, to *testServer, name string) {
+	ks, err := from.server.keyspace(name)
+	require.NoError(t, err)
+	tree := ks.getTree()
+	roundUUID, err := uuid.New().MarshalBinary()
+	require.NoError(t, err)
+	resp, err := to.manager.RoundStart(context.Background(), &rpc.MSTRoundStartRequest{
+		RoundUuid: roundUUID,
+		RootHash:  tree.RootHash(),
+		Keyspace:  name,
+	})
+	require.NoError(t, err)
+	for len(resp.GetHashes()) > 0 {
+		nodes := make([]*rpc.MSTNode, 0, len(resp.GetHashes()))
+		for _, hash := range resp.GetHashes() {
+			n, err := tree.NodeStore().Get(hash)
+			require.NoError(t, err)
+			nodes = append(nodes, nodeToRPC(n))
+		}
+		resp, err = to.manager.RoundStep(
+			context.Background(),
+			&rpc.MSTRoundStepRequest{RoundUuid: roundUUID, Nodes: nodes},
+		)
+		require.NoError(t, err)
+	}
+}
+
+func TestMSTServerWrites(t *testing.T) {
+	s := newTestServer(t, "a", time.Hour)
+	for i := uint32(0); i < 10; i++ {
+		mustPut(t, s, "", uint32Bytes(i), uint32Bytes(i))
+	}
+	_, err := s.client.Delete(context.Background(), &rpc.MSTDeleteRequest{Key: uint32Bytes(3)})
+	require.NoError(t, err)
+	_, err = s.client.WriteBatch(context.Background(), &rpc.MSTWriteBatchRequest{
+		Writes: []*rpc.MSTWrite{
+			{Key: uint32Bytes(4), Delete: true},
+			{Key: uint32Bytes(20), Value: uint32Bytes(20)},
+		},
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, uint32Bytes(2), mustGet(t, s, &rpc.MSTGetRequest{Key: uint32Bytes(2)}))
+	assert.Nil(t, mustGet(t, s, &rpc.MSTGetRequest{Key: uint32Bytes(3)}))
+	assert.Nil(t, mustGet(t, s, &rpc.MSTGetRequest{Key: uint32Bytes(4)}))
+	assert.Equal(t, uint32Bytes(20), mustGet(t, s, &rpc.MSTGetRequest{Key: uint32Bytes(20)}))
+
+	// 0 through 9 without 3 and 4, and 20
+	summary, err := s.client.Aggregate(context.Background(), &rpc.MSTAggregateRequest{})
+	require.NoError(t, err)
+	assert.Equal(t, uint64(9), summary.GetCount())
+	assert.Equal(t, float64(45-3-4+20), summary.GetSum())
+	summary, err = s.client.Aggregate(
+		context.Background(),
+		&rpc.MSTAggregateRequest{Start: uint32Bytes(5), End: uint32Bytes(8)},
+	)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(3), summary.GetCount())
+	assert.Equal(t, float64(5), summary.GetMin())
+	assert.Equal(t, float64(7), summary.GetMax())
+}
+
+func TestMSTServerErrors(t *testing.T) {
+	s := newTestServer(t, "a", time.Hour)
+	ctx := context.Background()
+	_, err := s.client.Get(ctx, &rpc.MSTGetRequest{Key: uint32Bytes(1), Keyspace: "missing"})
+	assertCode(t, codes.NotFound, err)
+	_, err = s.client.Put(ctx, &rpc.MSTPutRequest{Key: []byte{1}, Value: uint32Bytes(1)})
+	assertCode(t, codes.InvalidArgument, err)
+	_, err = s.client.Get(ctx, &rpc.MSTGetRequest{Key: uint32Bytes(1), Snapshot: "missing"})
+	assertCode(t, codes.NotFound, err)
+	_, err = s.client.Get(
+		ctx,
+		&rpc.MSTGetRequest{Key: uint32Bytes(1), Snapshot: "missing", RootHash: []byte{1}},
+	)
+	assertCode(t, codes.InvalidArgument, err)
+	_, err = s.client.WriteBatch(ctx, &rpc.MSTWriteBatchRequest{
+		Writes: []*rpc.MSTWrite{{Key: uint32Bytes(1), Value: []byte{1}}},
+	})
+	assertCode(t, codes.InvalidArgument, err)
+	_, err = s.admin.ListKeyspaces(ctx, &empty.Empty{})
+	assert.NoError(t, err)
+}
+
+func TestMSTServerImport(t *testing.T) {
+	s := newTestServer(t, "a", time.Hour)
+	mustPut(t, s, "", uint32Bytes(5), uint32Bytes(50))
+	stream, err := s.client.Import(context.Background())
+	require.NoError(t, err)
+	for i := uint32(0); i < 100; i++ {
+		require.NoError(t, stream.Send(&rpc.MSTImportRequest{
+			Key:   uint32Bytes(i),
+			Value: uint32Bytes(i),
+		}))
+	}
+	resp, err := stream.CloseAndRecv()
+	require.NoError(t, err)
+	assert.Equal(t, uint64(100), resp.GetCount())
+
+	// The imported pairs are merged with the ones that were already there
+	assert.Equal(t, uint32Bytes(50), mustGet(t, s, &rpc.MSTGetRequest{Key: uint32Bytes(5)}))
+	assert.Equal(t, uint32Bytes(99), mustGet(t, s, &rpc.MSTGetRequest{Key: uint32Bytes(99)}))
+	ks, err := s.server.keyspace("")
+	require.NoError(t, err)
+	assert.Equal(t, resp.GetRootHash(), ks.getTree().RootHash())
+
+	stream, err = s.client.Import(context.Background())
+	require.NoError(t, err)
+	require.NoError(t, stream.Send(&rpc.MSTImportRequest{Key: uint32Bytes(2)}))
+	require.NoError(t, stream.Send(&rpc.MSTImportRequest{Key: uint32Bytes(1)}))
+	_, err = stream.CloseAndRecv()
+	assertCode(t, codes.InvalidArgument, err)
+}
+
+func storedHashes(t *testing.T, tree *mst.MerkleSearchTree) int {
+	hashes, err := tree.NodeStore().(mst.CollectableNodeStore).Hashes()
+	require.NoError(t, err)
+	return len(hashes)
+}
+
+func TestMSTServerCollectGarbage(t *testing.T) {
+	dir, err := ioutil.TempDir("", "vulture-server-test")
+	require.NoError(t, err)
+	t.Cleanup(func() { os.RemoveAll(dir) })
+	// Without a history only the current tree is kept
+	s := newTestServerWithStorage(t, "a", disk.NewStorage(dir), 0)
+	for i := uint32(0); i < 100; i++ {
+		mustPut(t, s, "", uint32Bytes(i), uint32Bytes(i))
+	}
+	collected, err := s.manager.collectGarbage()
+	require.NoError(t, err)
+	assert.NotZero(t, collected[mst.DefaultKeyspace])
+	ks, err := s.server.keyspace("")
+	require.NoError(t, err)
+	numNodes, err := ks.getTree().NumNodes()
+	require.NoError(t, err)
+	assert.Equal(t, int(numNodes), storedHashes(t, ks.getTree()))
+	for i := uint32(0); i < 100; i++ {
+		assert.Equal(t, uint32Bytes(i), mustGet(t, s, &rpc.MSTGetRequest{Key: uint32Bytes(i)}))
+	}
+
+	// A tree that a request is still reading keeps its nodes until it's unpinned
+	pinned, unpin := ks.pinTree()
+	mustPut(t, s, "", uint32Bytes(100), uint32Bytes(100))
+	collected, err = s.manager.collectGarbage()
+	require.NoError(t, err)
+	assert.Zero(t, collected[mst.DefaultKeyspace])
+	for i := uint32(0); i < 100; i++ {
+		val, err := pinned.Get(mst.UInt32(i))
+		require.NoError(t, err)
+		assert.Equal(t, mst.UInt32(i), val)
+	}
+	unpin()
+	collected, err = s.manager.collectGarbage()
+	require.NoError(t, err)
+	assert.NotZero(t, collected[mst.DefaultKeyspace])
+	numNodes, err = ks.getTree().NumNodes()
+	require.NoError(t, err)
+	assert.Equal(t, int(numNodes), storedHashes(t, ks.getTree()))
+}
@@ -0,0 +1,773 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/golang/protobuf/ptypes/empty"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/vulturedb/vulture/core"
+	"github.com/vulturedb/vulture/mst"
+	"github.com/vulturedb/vulture/query"
+	"github.com/vulturedb/vulture/service/rpc"
+	"github.com/vulturedb/vulture/table"
+)
+
+// Tables keep their rows and each of their indexes in keyspaces of an MSTServer, named
+// table-<table> and table-<table>-<index>, so that they're stored, snapshotted and reconciled with
+// other servers like any other keyspace. Rows are core.RowValue values under core.PrimaryKey keys,
+// and index entries core.IndexEntry values under core.IndexKey keys.
+const tableKeyspacePrefix = "table-"
+
+// Names of tables and indexes can't have a -, so that their keyspaces can't clash
+var tableNamePattern = regexp.MustCompile(`^[A-Za-z0-9_]+$`)
+
+func isTableKeyspace(name string) bool {
+	return strings.HasPrefix(name, tableKeyspacePrefix)
+}
+
+func rowsKeyspace(table string) string {
+	return tableKeyspacePrefix + table
+}
+
+func indexKeyspace(table string, index string) string {
+	return rowsKeyspace(table) + "-" + index
+}
+
+// TableStore persists the tables of a server, as the ID of the latest version of each one's schema
+// in its SchemaStore by name. SetTables replaces every table at once.
+type TableStore interface {
+	Tables() (map[string][]byte, error)
+	SetTables(map[string][]byte) error
+}
+
+// MemoryTableStore keeps the tables of a server in memory
+type MemoryTableStore struct {
+	tables map[string][]byte
+}
+
+func (s *MemoryTableStore) Tables() (map[string][]byte, error) {
+	return copyHeads(s.tables), nil
+}
+
+func (s *MemoryTableStore) SetTables(tables map[string][]byte) error {
+	s.tables = copyHeads(tables)
+	return nil
+}
+
+func copyHeads(heads map[string][]byte) map[string][]byte {
+	copied := make(map[string][]byte, len(heads))
+	for name, head := range heads {
+		copied[name] = head
+	}
+	return copied
+}
+
+// TableServer serves tables of rows that are validated against a schema
+type TableServer struct {
+	server     *MSTServer
+	clock      *core.Clock
+	schemas    SchemaStore
+	tableStore TableStore
+	tables     map[string]*servedTable // guarded by tableLock
+	heads      map[string][]byte       // guarded by tableLock
+	tableLock  sync.RWMutex
+}
+
+// servedTable is a table whose keyspaces are kept in sync with the latest version of its schema
+type servedTable struct {
+	name    string
+	schemas core.SchemaHistory // guarded by lock
+	// Held to read the trees of the table's keyspaces together, and exclusively to write them
+	lock sync.RWMutex
+}
+
+// SchemaStore keeps every version of the schemas of tables, each linked to the version it evolved
+// from
+type SchemaStore interface {
+	// PutSchema stores the first version of a schema and returns its ID
+	PutSchema(schema core.Schema) ([]byte, error)
+	// EvolveSchema stores next as the version that follows the one with ID parent, setting its
+	// version and parent, and returns its ID
+	EvolveSchema(parent []byte, next core.Schema) ([]byte, error)
+	// SchemaHistory returns the versions of a schema up to the one with ID head
+	SchemaHistory(head []byte) (core.SchemaHistory, error)
+}
+
+// NewTableServer creates a new table server that keeps its tables in keyspaces of server and their
+// schemas in schemas, and opens every table in tables. Keyspaces that are missing for a table are
+// created. Rows it writes are timestamped as writes of replica, which must be unique among the
+// servers whose tables are merged.
+func NewTableServer(
+	server *MSTServer,
+	replica string,
+	schemas SchemaStore,
+	tables TableStore,
+) (*TableServer, error) {
+	s := &TableServer{
+		server:     server,
+		clock:      core.NewClock(replica),
+		schemas:    schemas,
+		tableStore: tables,
+		tables:     make(map[string]*servedTable),
+	}
+	heads, err := tables.Tables()
+	if err != nil {
+		return nil, fmt.Errorf("Couldn't load tables: %w", err)
+	}
+	for name, head := range heads {
+		history, err := schemas.SchemaHistory(head)
+		if err != nil {
+			return nil, fmt.Errorf("Couldn't load schema of table %s: %w", name, err)
+		}
+		t := &servedTable{name: name, schemas: history}
+		err = server.catalog.setSchema(rowsKeyspace(name), head)
+		if err == nil {
+			err = s.syncKeyspaces(t)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("Couldn't open table %s: %w", name, err)
+		}
+		s.tables[name] = t
+	}
+	s.heads = heads
+	server.tables = s
+	log.Printf("Opened %d tables", len(s.tables))
+	return s, nil
+}
+
+// splitTableKeyspace returns the table and the index whose keyspace is named name, where the
+// index is empty for the keyspace of the table's rows
+func splitTableKeyspace(name string) (string, string) {
+	parts := strings.SplitN(strings.TrimPrefix(name, tableKeyspacePrefix), "-", 2)
+	if len(parts) == 1 {
+		return parts[0], ""
+	}
+	return parts[0], parts[1]
+}
+
+// setHead saves the ID of the latest version of a table's schema, or forgets the table if head is
+// nil. The table is in the catalog as the keyspace of its rows, with head as its schema. Must be
+// called with tableLock held.
+func (s *TableServer) setHead(name string, head []byte) error {
+	heads := copyHeads(s.heads)
+	if head == nil {
+		delete(heads, name)
+	} else {
+		heads[name] = head
+	}
+	err := s.tableStore.SetTables(heads)
+	if err != nil {
+		return err
+	}
+	s.heads = heads
+	return s.server.catalog.setSchema(rowsKeyspace(name), head)
+}
+
+// evolveHead stores schema as the version of a table's schema that follows its latest one, and
+// returns every version with the new one set as the latest
+func (s *TableServer) evolveHead(name string, schema core.Schema) (core.SchemaHistory, error) {
+	s.tableLock.Lock()
+	defer s.tableLock.Unlock()
+	head, err := s.schemas.EvolveSchema(s.heads[name], schema)
+	if err != nil {
+		return core.SchemaHistory{}, status.Errorf(codes.Internal, "Couldn't store schema: %s", err)
+	}
+	// The stored version is the one with its parent set
+	history, err := s.schemas.SchemaHistory(head)
+	if err != nil {
+		return core.SchemaHistory{}, status.Errorf(
+			codes.Internal,
+			"Couldn't get schema history: %s",
+			err,
+		)
+	}
+	err = s.setHead(name, head)
+	if err != nil {
+		return core.SchemaHistory{}, status.Errorf(codes.Internal, "Couldn't save table: %s", err)
+	}
+	return history, nil
+}
+
+func (s *TableServer) getTable(name string) (*servedTable, error) {
+	s.tableLock.RLock()
+	defer s.tableLock.RUnlock()
+	t, exists := s.tables[name]
+	if !exists {
+		return nil, status.Errorf(codes.NotFound, "Missing table %s", name)
+	}
+	return t, nil
+}
+
+// keyspaceSpecs returns the keyspaces a table with schema keeps its rows and indexes in, by name.
+// They use the base and hash function of the catalog.
+func (s *TableServer) keyspaceSpecs(
+	name string,
+	schema core.Schema,
+) (map[string]mst.Keyspace, error) {
+	base, hash := s.server.catalog.base, s.server.catalog.hash
+	specs := map[string]mst.Keyspace{
+		rowsKeyspace(name): {
+			Name:      rowsKeyspace(name),
+			Base:      base,
+			Hash:      hash,
+			KeyType:   schema.PrimaryKeyReader().Name(),
+			ValueType: core.RowValueType,
+		},
+	}
+	for index := range schema.Indexes {
+		if !tableNamePattern.MatchString(index) {
+			return nil, fmt.Errorf(
+				"Invalid index name %q, only letters, digits and _ are allowed",
+				index,
+			)
+		}
+		kr, err := schema.IndexKeyReader(index)
+		if err != nil {
+			return nil, err
+		}
+		specs[indexKeyspace(name, index)] = mst.Keyspace{
+			Name:      indexKeyspace(name, index),
+			Base:      base,
+			Hash:      hash,
+			KeyType:   kr.Name(),
+			ValueType: core.IndexEntryValueType,
+		}
+	}
+	return specs, nil
+}
+
+// keyspacesOf returns the keyspace of a table's rows and the keyspace of each of its indexes by
+// index name
+func (s *TableServer) keyspacesOf(t *servedTable) (*keyspace, map[string]*keyspace, error) {
+	rows, err := s.server.keyspace(rowsKeyspace(t.name))
+	if err != nil {
+		return nil, nil, err
+	}
+	indexes := make(map[string]*keyspace)
+	for index := range t.schemas.Latest().Indexes {
+		indexes[index], err = s.server.keyspace(indexKeyspace(t.name, index))
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+	return rows, indexes, nil
+}
+
+// syncKeyspaces creates the keyspaces of the latest version of a table's schema that are missing,
+// building the indexes among them from the rows, and drops the keyspaces of indexes the schema
+// doesn't have anymore. Must be called with the table's lock held, or before the table is served.
+func (s *TableServer) syncKeyspaces(t *servedTable) error {
+	specs, err := s.keyspaceSpecs(t.name, t.schemas.Latest())
+	if err != nil {
+		return err
+	}
+	missing := []mst.Keyspace{}
+	for name, spec := range specs {
+		ks, err := s.server.keyspace(name)
+		if err != nil {
+			missing = append(missing, spec)
+		} else if ks.spec != spec {
+			return fmt.Errorf("Keyspace %s doesn't match table %s", name, t.name)
+		}
+	}
+	sort.Slice(missing, func(i, j int) bool { return missing[i].Name < missing[j].Name })
+	stale := []string{}
+	for _, ks := range s.server.allKeyspaces() {
+		_, current := specs[ks.spec.Name]
+		if strings.HasPrefix(ks.spec.Name, rowsKeyspace(t.name)+"-") && !current {
+			stale = append(stale, ks.spec.Name)
+		}
+	}
+	if len(stale) > 0 {
+		err = s.server.dropKeyspaces(stale)
+		if err != nil {
+			return fmt.Errorf("Couldn't drop keyspaces of table %s: %w", t.name, err)
+		}
+		log.Printf("Dropped keyspaces %v of table %s", stale, t.name)
+	}
+	if len(missing) == 0 {
+		return nil
+	}
+	err = s.server.addKeyspaces(missing)
+	if err != nil {
+		return fmt.Errorf("Couldn't create keyspaces of table %s: %w", t.name, err)
+	}
+	created := make(map[string]bool, len(missing))
+	for _, spec := range missing {
+		created[spec.Name] = true
+	}
+	rows, indexes, err := s.keyspacesOf(t)
+	if err != nil {
+		return err
+	}
+	tree, unpin := rows.pinTree()
+	defer unpin()
+	trees := make(map[string]*mst.MerkleSearchTree, len(indexes))
+	for index, ks := range indexes {
+		if !created[ks.spec.Name] {
+			trees[index] = ks.getTree()
+		}
+	}
+	// The indexes that aren't in trees are built from the rows
+	opened, err := table.OpenTable(t.name, t.schemas, tree, trees)
+	if err != nil {
+		return err
+	}
+	for index, ks := range indexes {
+		if _, existed := trees[index]; existed {
+			continue
+		}
+		built, _ := opened.Index(index)
+		err = ks.mergeTree(built)
+		if err != nil {
+			return fmt.Errorf("Couldn't build index %s of table %s: %w", index, t.name, err)
+		}
+	}
+	log.Printf("Created %d keyspaces of table %s", len(missing), t.name)
+	return nil
+}
+
+// pinTable returns a table at the current trees of its keyspaces, which are kept from being
+// garbage collected until the returned function is called
+func (s *TableServer) pinTable(t *servedTable) (*table.Table, func(), error) {
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+	rows, indexes, err := s.keyspacesOf(t)
+	if err != nil {
+		return nil, nil, err
+	}
+	tree, unpin := rows.pinTree()
+	unpins := []func(){unpin}
+	unpinAll := func() {
+		for _, unpin := range unpins {
+			unpin()
+		}
+	}
+	trees := make(map[string]*mst.MerkleSearchTree, len(indexes))
+	for index, ks := range indexes {
+		trees[index], unpin = ks.pinTree()
+		unpins = append(unpins, unpin)
+	}
+	opened, err := table.OpenTable(t.name, t.schemas, tree, trees)
+	if err != nil {
+		unpinAll()
+		return nil, nil, err
+	}
+	return opened, unpinAll, nil
+}
+
+// openTable returns the named table pinned like pinTable does
+func (s *TableServer) openTable(name string) (*table.Table, func(), error) {
+	t, err := s.getTable(name)
+	if err != nil {
+		return nil, nil, err
+	}
+	opened, unpin, err := s.pinTable(t)
+	if err != nil {
+		log.Printf("Error opening table %s: %s", name, err)
+		return nil, nil, status.Errorf(codes.Internal, "Couldn't open table %s: %s", name, err)
+	}
+	return opened, unpin, nil
+}
+
+// writeTable applies write to a table at the current trees of its keyspaces, saves the trees it
+// changes and returns their keyspaces. Errors of write are returned as they are.
+func (s *TableServer) writeTable(
+	t *servedTable,
+	write func(*table.Table) (*table.Table, error),
+) ([]*keyspace, error) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	rows, indexes, err := s.keyspacesOf(t)
+	if err != nil {
+		return nil, err
+	}
+	// Anti-entropy can't merge into the keyspaces until every tree is saved, and other writes of
+	// the table wait for the table's lock
+	keyspaces := []*keyspace{rows}
+	trees := make(map[string]*mst.MerkleSearchTree, len(indexes))
+	for index, ks := range indexes {
+		keyspaces = append(keyspaces, ks)
+		ks.treeLock.Lock()
+		defer ks.treeLock.Unlock()
+		trees[index] = ks.tree
+	}
+	rows.treeLock.Lock()
+	defer rows.treeLock.Unlock()
+	opened, err := table.OpenTable(t.name, t.schemas, rows.tree, trees)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "Couldn't open table %s: %s", t.name, err)
+	}
+	written, err := write(opened)
+	if err != nil {
+		return nil, err
+	}
+	changed := []*keyspace{}
+	for index, ks := range indexes {
+		tree, _ := written.Index(index)
+		if !bytes.Equal(tree.RootHash(), ks.tree.RootHash()) {
+			changed = append(changed, ks)
+		}
+		err = ks.setTree(tree)
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "Couldn't write index %s: %s", index, err)
+		}
+	}
+	if !bytes.Equal(written.Tree().RootHash(), rows.tree.RootHash()) {
+		changed = append(changed, rows)
+	}
+	err = rows.setTree(written.Tree())
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "Couldn't write rows: %s", err)
+	}
+	return changed, nil
+}
+
+// observeTree moves the clock past the timestamps of the rows or index entries that tree has and
+// local doesn't, so that later writes are ordered after the writes of other replicas
+func (s *TableServer) observeTree(local *mst.MerkleSearchTree, tree *mst.MerkleSearchTree) error {
+	changes, err := local.Diff(tree)
+	if err != nil {
+		return err
+	}
+	for _, change := range changes {
+		switch v := change.To.(type) {
+		case core.RowValue:
+			s.clock.Observe(v.Timestamp())
+		case core.IndexEntry:
+			s.clock.Observe(v.Timestamp())
+		}
+	}
+	return nil
+}
+
+// mergeKeyspace merges a tree of the rows or of an index of a table into the table, with
+// table.Table.Merge, so that its rows and indexes are merged the way tables merge. Keyspaces whose
+// table or index isn't served anymore are merged like any other keyspace.
+func (s *TableServer) mergeKeyspace(ks *keyspace, tree *mst.MerkleSearchTree) error {
+	name, index := splitTableKeyspace(ks.spec.Name)
+	t, err := s.getTable(name)
+	if err != nil {
+		return ks.mergeTree(tree)
+	}
+	t.lock.RLock()
+	_, served := t.schemas.Latest().Indexes[index]
+	t.lock.RUnlock()
+	if index != "" && !served {
+		return ks.mergeTree(tree)
+	}
+	_, err = s.writeTable(t, func(local *table.Table) (*table.Table, error) {
+		rows := local.Tree()
+		indexes := make(map[string]*mst.MerkleSearchTree)
+		for other := range local.Schema().Indexes {
+			indexes[other], _ = local.Index(other)
+		}
+		var err error
+		if index == "" {
+			err = s.observeTree(rows, tree)
+			rows = tree
+		} else {
+			err = s.observeTree(indexes[index], tree)
+			indexes[index] = tree
+		}
+		if err != nil {
+			return nil, err
+		}
+		remote, err := table.OpenTable(name, local.Schemas(), rows, indexes)
+		if err != nil {
+			return nil, err
+		}
+		return local.Merge(remote)
+	})
+	if err != nil {
+		return err
+	}
+	log.Printf("Merged tree of %s into table %s (%s)", ks.spec.Name, name, describeTree(tree))
+	return nil
+}
+
+// CreateTable creates an empty table with the given schema
+func (s *TableServer) CreateTable(
+	ctx context.Context,
+	in *rpc.CreateTableRequest,
+) (*empty.Empty, error) {
+	name := in.GetName()
+	if !tableNamePattern.MatchString(name) {
+		return nil, status.Errorf(
+			codes.InvalidArgument,
+			"Invalid table name %q, only letters, digits and _ are allowed",
+			name,
+		)
+	}
+	schema, err := schemaFromRPC(in.GetSchema())
+	if err == nil {
+		err = schema.Validate()
+	}
+	if err == nil {
+		_, err = s.keyspaceSpecs(name, schema)
+	}
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "Invalid schema: %s", err)
+	}
+	s.tableLock.Lock()
+	defer s.tableLock.Unlock()
+	if _, exists := s.tables[name]; exists {
+		return nil, status.Errorf(codes.AlreadyExists, "Table %s already exists", name)
+	}
+	head, err := s.schemas.PutSchema(schema)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "Couldn't store schema: %s", err)
+	}
+	err = s.setHead(name, head)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "Couldn't save table: %s", err)
+	}
+	t := &servedTable{name: name, schemas: core.NewSchemaHistory(schema)}
+	err = s.syncKeyspaces(t)
+	if err != nil {
+		log.Printf("Error creating table %s: %s", name, err)
+		if forgetErr := s.setHead(name, nil); forgetErr != nil {
+			log.Printf("Error forgetting table %s: %s", name, forgetErr)
+		}
+		return nil, status.Errorf(codes.Internal, "Couldn't create table: %s", err)
+	}
+	s.tables[name] = t
+	log.Printf("Created table %s", name)
+	return &empty.Empty{}, nil
+}
+
+// AlterTable evolves the schema of a table. Rows written under earlier versions stay readable and
+// are returned as rows of the new version. Keyspaces of indexes the new version adds are built
+// from the rows, and the ones of indexes it drops are dropped.
+func (s *TableServer) AlterTable(
+	ctx context.Context,
+	in *rpc.AlterTableRequest,
+) (*rpc.AlterTableResponse, error) {
+	name := in.GetTable()
+	schema, err := schemaFromRPC(in.GetSchema())
+	if err == nil {
+		_, err = s.keyspaceSpecs(name, schema)
+	}
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "Invalid schema: %s", err)
+	}
+	t, err := s.getTable(name)
+	if err != nil {
+		return nil, err
+	}
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	if err = t.schemas.Latest().CheckEvolution(schema); err != nil {
+		return nil, status.Errorf(codes.FailedPrecondition, "Couldn't alter table: %s", err)
+	}
+	history, err := s.evolveHead(name, schema)
+	if err != nil {
+		return nil, err
+	}
+	t.schemas = history
+	err = s.syncKeyspaces(t)
+	if err != nil {
+		// The keyspaces are synced again when the server restarts
+		log.Printf("Error altering table %s: %s", name, err)
+		return nil, status.Errorf(codes.Internal, "Couldn't update keyspaces of table: %s", err)
+	}
+	version := history.Latest().Version
+	log.Printf("Altered table %s to schema version %d", name, version)
+	return &rpc.AlterTableResponse{Version: version}, nil
+}
+
+// ListTables returns every table with every version of its schema
+func (s *TableServer) ListTables(
+	ctx context.Context,
+	in *empty.Empty,
+) (*rpc.ListTablesResponse, error) {
+	s.tableLock.RLock()
+	tables := make([]*servedTable, 0, len(s.tables))
+	for _, t := range s.tables {
+		tables = append(tables, t)
+	}
+	s.tableLock.RUnlock()
+	sort.Slice(tables, func(i, j int) bool { return tables[i].name < tables[j].name })
+	resp := &rpc.ListTablesResponse{Tables: make([]*rpc.TableInfo, len(tables))}
+	for i, t := range tables {
+		// Altering a table changes both its schemas and its head with its lock held
+		t.lock.RLock()
+		history := t.schemas
+		s.tableLock.RLock()
+		head := s.heads[t.name]
+		s.tableLock.RUnlock()
+		t.lock.RUnlock()
+		versions := history.Versions()
+		info := &rpc.TableInfo{
+			Name:     t.name,
+			Versions: make([]*rpc.TableSchema, len(versions)),
+			SchemaId: head,
+			Keyspace: rowsKeyspace(t.name),
+		}
+		for j, version := range versions {
+			var err error
+			info.Versions[j], err = schemaToRPC(version)
+			if err != nil {
+				return nil, status.Errorf(
+					codes.Internal,
+					"Couldn't convert schema of table %s: %s",
+					t.name,
+					err,
+				)
+			}
+		}
+		resp.Tables[i] = info
+	}
+	return resp, nil
+}
+
+// InsertRow validates a row against its table's schema and writes it
+func (s *TableServer) InsertRow(
+	ctx context.Context,
+	in *rpc.InsertRowRequest,
+) (*empty.Empty, error) {
+	name := in.GetTable()
+	row, err := rowFromRPC(in.GetRow())
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "Invalid row: %s", err)
+	}
+	t, err := s.getTable(name)
+	if err != nil {
+		return nil, err
+	}
+	changed, err := s.writeTable(t, func(written *table.Table) (*table.Table, error) {
+		written, err := written.Insert(row, s.clock.Now())
+		if err != nil {
+			return nil, validationStatus("Couldn't insert row", err)
+		}
+		return written, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, ks := range changed {
+		go ks.runAntiEntropy()
+	}
+	log.Printf("Inserted row into %s", name)
+	return &empty.Empty{}, nil
+}
+
+// GetRow returns the row with the given primary key
+func (s *TableServer) GetRow(
+	ctx context.Context,
+	in *rpc.GetRowRequest,
+) (*rpc.GetRowResponse, error) {
+	t, unpin, err := s.openTable(in.GetTable())
+	if err != nil {
+		return nil, err
+	}
+	defer unpin()
+	key, err := rowFromRPC(in.GetKey())
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "Invalid key: %s", err)
+	}
+	row, found, err := t.Get(key)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "Couldn't get row: %s", err)
+	}
+	if !found {
+		return &rpc.GetRowResponse{}, nil
+	}
+	rpcRow, err := rowToRPC(row)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "Couldn't convert row: %s", err)
+	}
+	return &rpc.GetRowResponse{Row: rpcRow, Found: true}, nil
+}
+
+// ScanRows streams the rows of a table that match the prefix and predicates of the request, in
+// the order of the primary key or of the index they're found through
+func (s *TableServer) ScanRows(
+	in *rpc.ScanRowsRequest,
+	stream rpc.TableService_ScanRowsServer,
+) error {
+	t, unpin, err := s.openTable(in.GetTable())
+	if err != nil {
+		return err
+	}
+	defer unpin()
+	prefix, err := rowFromRPC(in.GetPrefix())
+	if err != nil {
+		return status.Errorf(codes.InvalidArgument, "Invalid prefix: %s", err)
+	}
+	if _, err := t.Schema().PrimaryKeyPrefix(prefix); err != nil {
+		return status.Errorf(codes.InvalidArgument, "Invalid prefix: %s", err)
+	}
+	preds, err := predicatesFromRPC(in.GetWhere())
+	if err != nil {
+		return status.Errorf(codes.InvalidArgument, "Invalid predicate: %s", err)
+	}
+	for fieldName, v := range prefix.Data {
+		preds = append(preds, core.Predicate{Field: fieldName, Op: core.OpEq, Value: v})
+	}
+	it, err := t.Select(preds)
+	if err != nil {
+		return status.Errorf(codes.InvalidArgument, "Invalid predicate: %s", err)
+	}
+	sent := uint32(0)
+	for (in.GetLimit() == 0 || sent < in.GetLimit()) && it.Next() {
+		rpcRow, err := rowToRPC(it.Row())
+		if err != nil {
+			return status.Errorf(codes.Internal, "Couldn't convert row: %s", err)
+		}
+		err = stream.Send(&rpc.ScanRowsResponse{Row: rpcRow})
+		if err != nil {
+			return err
+		}
+		sent++
+	}
+	if err := it.Err(); err != nil {
+		log.Printf("Error scanning table %s: %s", in.GetTable(), err)
+		return status.Errorf(codes.Internal, "Couldn't scan: %s", err)
+	}
+	return nil
+}
+
+// Query runs a query against a table and streams its columns followed by its rows
+func (s *TableServer) Query(in *rpc.QueryRequest, stream rpc.TableService_QueryServer) error {
+	q, err := query.Parse(in.GetQuery())
+	if err != nil {
+		return status.Errorf(codes.InvalidArgument, "Invalid query: %s", err)
+	}
+	t, unpin, err := s.openTable(q.Table)
+	if err != nil {
+		return err
+	}
+	defer unpin()
+	rows, err := query.Run(t, q)
+	if err != nil {
+		return status.Errorf(codes.InvalidArgument, "Invalid query: %s", err)
+	}
+	log.Printf("Querying %s using %s", q.Table, rows.Plan())
+	if err := stream.Send(&rpc.QueryResponse{Columns: rows.Columns()}); err != nil {
+		return err
+	}
+	for rows.Next() {
+		rpcRow, err := rowToRPC(rows.Row())
+		if err != nil {
+			return status.Errorf(codes.Internal, "Couldn't convert row: %s", err)
+		}
+		if err := stream.Send(&rpc.QueryResponse{Row: rpcRow}); err != nil {
+			return err
+		}
+	}
+	if err := rows.Err(); err != nil {
+		log.Printf("Error querying table %s: %s", q.Table, err)
+		return status.Errorf(codes.Internal, "Couldn't query: %s", err)
+	}
+	return nil
+}
@@ -0,0 +1,261 @@
+package server
+
+import (
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes/empty"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/vulturedb/vulture/service/rpc"
+)
+
+func peopleSchema() *rpc.TableSchema {
+	return &rpc.TableSchema{
+		Fields: map[string]*rpc.TableFieldSpec{
+			"id":   {Type: "long"},
+			"city": {Type: "string", Nullable: true},
+			"age":  {Type: "int"},
+		},
+		PrimaryKey: []string{"id"},
+		Indexes:    map[string]*rpc.TableIndex{"by_city": {Fields: []string{"city"}}},
+	}
+}
+
+func personKey(id int64) *rpc.TableRow {
+	return &rpc.TableRow{Fields: map[string]*rpc.TableValue{
+		"id": {Kind: &rpc.TableValue_LongValue{LongValue: id}},
+	}}
+}
+
+func person(id int64, city string, age int32) *rpc.TableRow {
+	row := personKey(id)
+	row.Fields["city"] = &rpc.TableValue{Kind: &rpc.TableValue_StringValue{StringValue: city}}
+	row.Fields["age"] = &rpc.TableValue{Kind: &rpc.TableValue_IntValue{IntValue: age}}
+	return row
+}
+
+func newPeopleServer(t *testing.T, replica string) *testServer {
+	s := newTestServer(t, replica, time.Hour)
+	_, err := s.tableClient.CreateTable(
+		context.Background(),
+		&rpc.CreateTableRequest{Name: "people", Schema: peopleSchema()},
+	)
+	require.NoError(t, err)
+	return s
+}
+
+func mustInsert(t *testing.T, s *testServer, row *rpc.TableRow) {
+	_, err := s.tableClient.InsertRow(
+		context.Background(),
+		&rpc.InsertRowRequest{Table: "people", Row: row},
+	)
+	require.NoError(t, err)
+}
+
+// mustGetPerson returns the person with an ID, or nil if there isn't one
+func mustGetPerson(t *testing.T, s *testServer, id int64) *rpc.TableRow {
+	resp, err := s.tableClient.GetRow(
+		context.Background(),
+		&rpc.GetRowRequest{Table: "people", Key: personKey(id)},
+	)
+	require.NoError(t, err)
+	if !resp.GetFound() {
+		return nil
+	}
+	return resp.GetRow()
+}
+
+// queryIDs returns the IDs of the rows a query returns in order
+func queryIDs(t *testing.T, s *testServer, q string) []int64 {
+	stream, err := s.tableClient.Query(context.Background(), &rpc.QueryRequest{Query: q})
+	require.NoError(t, err)
+	ids := []int64{}
+	for {
+		resp, err := stream.Recv()
+		if err == io.EOF {
+			return ids
+		}
+		require.NoError(t, err)
+		if resp.GetRow() != nil {
+			ids = append(ids, resp.GetRow().GetFields()["id"].GetLongValue())
+		}
+	}
+}
+
+func TestTableRows(t *testing.T) {
+	s := newPeopleServer(t, "a")
+	mustInsert(t, s, person(1, "Paris", 30))
+	mustInsert(t, s, person(2, "Oslo", 40))
+	mustInsert(t, s, person(3, "Paris", 50))
+
+	row := mustGetPerson(t, s, 2)
+	require.NotNil(t, row)
+	assert.Equal(t, "Oslo", row.GetFields()["city"].GetStringValue())
+	assert.Equal(t, int32(40), row.GetFields()["age"].GetIntValue())
+	assert.Nil(t, mustGetPerson(t, s, 4))
+	assert.Equal(
+		t,
+		[]int64{1, 3},
+		queryIDs(t, s, "SELECT * FROM people WHERE city = 'Paris'"),
+	)
+
+	stream, err := s.tableClient.ScanRows(
+		context.Background(),
+		&rpc.ScanRowsRequest{Table: "people", Limit: 2},
+	)
+	require.NoError(t, err)
+	ids := []int64{}
+	for {
+		resp, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		ids = append(ids, resp.GetRow().GetFields()["id"].GetLongValue())
+	}
+	assert.Equal(t, []int64{1, 2}, ids)
+}
+
+func TestTableErrors(t *testing.T) {
+	s := newPeopleServer(t, "a")
+	ctx := context.Background()
+	_, err := s.tableClient.CreateTable(
+		ctx,
+		&rpc.CreateTableRequest{Name: "people", Schema: peopleSchema()},
+	)
+	assertCode(t, codes.AlreadyExists, err)
+	_, err = s.tableClient.CreateTable(
+		ctx,
+		&rpc.CreateTableRequest{Name: "the-people", Schema: peopleSchema()},
+	)
+	assertCode(t, codes.InvalidArgument, err)
+	invalid := peopleSchema()
+	invalid.PrimaryKey = []string{"missing"}
+	_, err = s.tableClient.CreateTable(
+		ctx,
+		&rpc.CreateTableRequest{Name: "invalid", Schema: invalid},
+	)
+	assertCode(t, codes.InvalidArgument, err)
+	_, err = s.tableClient.InsertRow(
+		ctx,
+		&rpc.InsertRowRequest{Table: "missing", Row: person(1, "Paris", 30)},
+	)
+	assertCode(t, codes.NotFound, err)
+	_, err = s.tableClient.GetRow(ctx, &rpc.GetRowRequest{Table: "missing", Key: personKey(1)})
+	assertCode(t, codes.NotFound, err)
+	stream, err := s.tableClient.Query(ctx, &rpc.QueryRequest{Query: "SELECT FROM people"})
+	require.NoError(t, err)
+	_, err = stream.Recv()
+	assertCode(t, codes.InvalidArgument, err)
+
+	// Rows that don't match the schema come with a violation for each field
+	_, err = s.tableClient.InsertRow(
+		ctx,
+		&rpc.InsertRowRequest{Table: "people", Row: personKey(1)},
+	)
+	assertCode(t, codes.InvalidArgument, err)
+	details := status.Convert(err).Details()
+	require.Len(t, details, 1)
+	badRequest, isBadRequest := details[0].(*errdetails.BadRequest)
+	require.True(t, isBadRequest)
+	require.Len(t, badRequest.GetFieldViolations(), 1)
+	assert.Equal(t, "age", badRequest.GetFieldViolations()[0].GetField())
+}
+
+func TestAlterTable(t *testing.T) {
+	s := newPeopleServer(t, "a")
+	ctx := context.Background()
+	mustInsert(t, s, person(1, "Paris", 30))
+	listed, err := s.tableClient.ListTables(ctx, &empty.Empty{})
+	require.NoError(t, err)
+	require.Len(t, listed.GetTables(), 1)
+	created := listed.GetTables()[0]
+	assert.Equal(t, "people", created.GetName())
+	assert.Equal(t, rowsKeyspace("people"), created.GetKeyspace())
+	assert.Len(t, created.GetVersions(), 1)
+
+	next := peopleSchema()
+	next.Fields["email"] = &rpc.TableFieldSpec{Type: "string", Nullable: true}
+	next.Indexes["by_age"] = &rpc.TableIndex{Fields: []string{"age"}}
+	resp, err := s.tableClient.AlterTable(
+		ctx,
+		&rpc.AlterTableRequest{Table: "people", Schema: next},
+	)
+	require.NoError(t, err)
+	assert.Equal(t, uint32(1), resp.GetVersion())
+	listed, err = s.tableClient.ListTables(ctx, &empty.Empty{})
+	require.NoError(t, err)
+	require.Len(t, listed.GetTables(), 1)
+	altered := listed.GetTables()[0]
+	assert.Len(t, altered.GetVersions(), 2)
+	assert.NotEqual(t, created.GetSchemaId(), altered.GetSchemaId())
+	assert.Contains(t, catalogRoots(mustCatalog(t, s)), indexKeyspace("people", "by_age"))
+
+	// Rows of earlier versions are read as rows of the latest one, and new indexes cover them
+	mustInsert(t, s, person(2, "Oslo", 30))
+	assert.Equal(t, []int64{1, 2}, queryIDs(t, s, "SELECT * FROM people WHERE age = 30"))
+	row := mustGetPerson(t, s, 1)
+	require.NotNil(t, row)
+	assert.Equal(t, "Paris", row.GetFields()["city"].GetStringValue())
+
+	incompatible := peopleSchema()
+	incompatible.Fields["age"] = &rpc.TableFieldSpec{Type: "string"}
+	_, err = s.tableClient.AlterTable(
+		ctx,
+		&rpc.AlterTableRequest{Table: "people", Schema: incompatible},
+	)
+	assertCode(t, codes.FailedPrecondition, err)
+	_, err = s.tableClient.AlterTable(ctx, &rpc.AlterTableRequest{Table: "missing", Schema: next})
+	assertCode(t, codes.NotFound, err)
+}
+
+func TestTableAntiEntropy(t *testing.T) {
+	a := newPeopleServer(t, "a")
+	b := newPeopleServer(t, "b")
+	mustInsert(t, a, person(1, "Paris", 30))
+	mustInsert(t, a, person(2, "Oslo", 40))
+	mustInsert(t, b, person(3, "Paris", 50))
+	mustInsert(t, b, person(2, "Rome", 41))
+
+	keyspaces := []string{rowsKeyspace("people"), indexKeyspace("people", "by_city")}
+	for _, keyspace := range keyspaces {
+		syncKeyspace(t, a, b, keyspace)
+	}
+	// Merging keeps the indexes in line with the rows
+	for _, id := range []int64{1, 2, 3} {
+		assert.NotNil(t, mustGetPerson(t, b, id))
+	}
+	assert.Equal(
+		t,
+		[]int64{1, 3},
+		queryIDs(t, b, "SELECT * FROM people WHERE city = 'Paris'"),
+	)
+
+	for _, keyspace := range keyspaces {
+		syncKeyspace(t, b, a, keyspace)
+	}
+	for _, id := range []int64{1, 2, 3} {
+		assert.True(t, proto.Equal(mustGetPerson(t, b, id), mustGetPerson(t, a, id)))
+	}
+	city := mustGetPerson(t, a, 2).GetFields()["city"].GetStringValue()
+	assert.Equal(
+		t,
+		[]int64{2},
+		queryIDs(t, a, "SELECT * FROM people WHERE city = '"+city+"'"),
+	)
+	assert.Equal(
+		t,
+		queryIDs(t, a, "SELECT * FROM people WHERE city = 'Paris'"),
+		queryIDs(t, b, "SELECT * FROM people WHERE city = 'Paris'"),
+	)
+	// Both servers end up with the same catalog
+	assert.Equal(t, mustCatalog(t, a).GetRootHash(), mustCatalog(t, b).GetRootHash())
+}
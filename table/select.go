@@ -0,0 +1,99 @@
+package table
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/vulturedb/vulture/core"
+)
+
+// PrimaryKeyPlan is the plan of selects that scan the rows themselves, see Plan
+const PrimaryKeyPlan = "primary key"
+
+func (t *Table) checkPredicates(preds []core.Predicate) error {
+	for _, p := range preds {
+		if err := t.Schema().CheckPredicate(p); err != nil {
+			return fmt.Errorf("Invalid predicate %s: %w", p, err)
+		}
+	}
+	return nil
+}
+
+// plan picks the index whose range for preds is the narrowest, or the primary key if no index
+// beats it. An empty index name means the primary key.
+func (t *Table) plan(preds []core.Predicate) (string, core.KeyRange, error) {
+	if err := t.checkPredicates(preds); err != nil {
+		return "", core.KeyRange{}, err
+	}
+	schema := t.Schema()
+	best, err := schema.PrimaryKeyRange(preds)
+	if err != nil {
+		return "", core.KeyRange{}, err
+	}
+	bestIndex := ""
+	names := make([]string, 0, len(schema.Indexes))
+	for name := range schema.Indexes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		kr, err := schema.IndexKeyRange(name, preds)
+		if err != nil {
+			return "", core.KeyRange{}, err
+		}
+		if kr.Fields > best.Fields {
+			best, bestIndex = kr, name
+		}
+	}
+	return bestIndex, best, nil
+}
+
+// Plan returns the name of the index Select would find the rows matching preds with, or
+// PrimaryKeyPlan if it would scan the rows in primary key order
+func (t *Table) Plan(preds []core.Predicate) (string, error) {
+	index, _, err := t.plan(preds)
+	if err != nil {
+		return "", err
+	}
+	if index == "" {
+		return PrimaryKeyPlan, nil
+	}
+	return index, nil
+}
+
+// Select returns an iterator over the rows matching every predicate in preds. Equality predicates
+// on leading fields of the primary key or of an index, and range predicates on the field after
+// them, limit the rows that are read. The rows are in primary key order, unless they're found
+// through an index, in which case they're in the order of the index.
+func (t *Table) Select(preds []core.Predicate) (*RowIterator, error) {
+	index, kr, err := t.plan(preds)
+	if err != nil {
+		return nil, err
+	}
+	return t.selectRange(preds, index, kr), nil
+}
+
+// SelectByPrimaryKey is like Select, except that it never uses an index, so that the rows are in
+// primary key order, or in reverse if reverse is set
+func (t *Table) SelectByPrimaryKey(preds []core.Predicate, reverse bool) (*RowIterator, error) {
+	if err := t.checkPredicates(preds); err != nil {
+		return nil, err
+	}
+	kr, err := t.Schema().PrimaryKeyRange(preds)
+	if err != nil {
+		return nil, err
+	}
+	kr.Options.Reverse = reverse
+	return t.selectRange(preds, "", kr), nil
+}
+
+func (t *Table) selectRange(preds []core.Predicate, index string, kr core.KeyRange) *RowIterator {
+	it := &RowIterator{schemas: t.schemas, preds: preds}
+	if index == "" {
+		it.it = t.tree.Scan(kr.Options)
+	} else {
+		it.it = t.indexes[index].Scan(kr.Options)
+		it.rows, it.index = t.tree, index
+	}
+	return it
+}
@@ -0,0 +1,211 @@
+package table
+
+import (
+	"crypto"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/vulturedb/vulture/core"
+	"github.com/vulturedb/vulture/mst"
+)
+
+func peopleSchema() core.Schema {
+	return core.Schema{
+		Fields: map[string]core.FieldSpec{
+			"id":   {Type: "long"},
+			"city": {Type: "string", Nullable: true},
+			"age":  {Type: "int"},
+		},
+		PrimaryKey: []string{"id"},
+		Indexes:    map[string][]string{"by_city": {"city", "age"}, "by_age": {"age"}},
+	}
+}
+
+func newPeople(t *testing.T) *Table {
+	people, err := NewTable("people", peopleSchema(), mst.NewLocalMST(mst.Base4, crypto.SHA256))
+	require.NoError(t, err)
+	return people
+}
+
+// person returns a row whose city is null if it's nil
+func person(id int64, city interface{}, age int32) core.Row {
+	row := core.Row{Data: map[string]interface{}{"id": id, "age": age}}
+	if city != nil {
+		row.Data["city"] = city
+	}
+	return row
+}
+
+func mustInsert(t *testing.T, table *Table, row core.Row, timestamp core.HLC) *Table {
+	table, err := table.Insert(row, timestamp)
+	require.NoError(t, err)
+	return table
+}
+
+func pred(field string, op core.Op, value interface{}) core.Predicate {
+	return core.Predicate{Field: field, Op: op, Value: value}
+}
+
+// selectIDs returns the ids of the rows matching preds, in the order Select returns them
+func selectIDs(t *testing.T, table *Table, preds ...core.Predicate) []int64 {
+	it, err := table.Select(preds)
+	require.NoError(t, err)
+	ids := []int64{}
+	for it.Next() {
+		ids = append(ids, it.Row().Data["id"].(int64))
+	}
+	require.NoError(t, it.Err())
+	return ids
+}
+
+func indexSize(t *testing.T, table *Table, name string) int {
+	index, ok := table.Index(name)
+	require.True(t, ok)
+	size := 0
+	it := index.Scan(mst.ScanOptions{})
+	for it.Next() {
+		size++
+	}
+	require.NoError(t, it.Err())
+	return size
+}
+
+func TestTableSelect(t *testing.T) {
+	people := newPeople(t)
+	rows := []core.Row{
+		person(1, "paris", 30),
+		person(2, "oslo", 25),
+		person(3, "paris", 20),
+		person(4, nil, 40),
+		person(5, "paris", 35),
+		person(6, "parisville", 30),
+	}
+	for _, row := range rows {
+		people = mustInsert(t, people, row, at(1))
+	}
+
+	tests := []struct {
+		preds []core.Predicate
+		plan  string
+		ids   []int64
+	}{
+		{nil, "primary key", []int64{1, 2, 3, 4, 5, 6}},
+		{[]core.Predicate{pred("id", core.OpGe, int64(5))}, "primary key", []int64{5, 6}},
+		// Rows found through an index come in index order
+		{[]core.Predicate{pred("city", core.OpEq, "paris")}, "by_city", []int64{3, 1, 5}},
+		{
+			[]core.Predicate{pred("city", core.OpEq, "paris"), pred("age", core.OpLt, int32(35))},
+			"by_city",
+			[]int64{3, 1},
+		},
+		{[]core.Predicate{pred("age", core.OpGt, int32(30))}, "by_age", []int64{5, 4}},
+		{
+			[]core.Predicate{pred("age", core.OpEq, int32(30)), pred("city", core.OpGe, "p")},
+			"by_age",
+			[]int64{1, 6},
+		},
+		// An equality on the primary key beats an index
+		{
+			[]core.Predicate{pred("id", core.OpEq, int64(1)), pred("age", core.OpEq, int32(30))},
+			"primary key",
+			[]int64{1},
+		},
+		{[]core.Predicate{pred("city", core.OpLt, "z")}, "by_city", []int64{2, 3, 1, 5, 6}},
+	}
+	for _, test := range tests {
+		plan, err := people.Plan(test.preds)
+		require.NoError(t, err)
+		assert.Equal(t, test.plan, plan, "%v", test.preds)
+		assert.Equal(t, test.ids, selectIDs(t, people, test.preds...), "%v", test.preds)
+	}
+
+	_, err := people.Select([]core.Predicate{pred("name", core.OpEq, "x")})
+	assert.Error(t, err)
+	_, err = people.Select([]core.Predicate{pred("age", core.OpEq, "x")})
+	assert.Error(t, err)
+}
+
+func TestTableIndexUpdate(t *testing.T) {
+	people := newPeople(t)
+	people = mustInsert(t, people, person(1, "paris", 30), at(1))
+	people = mustInsert(t, people, person(1, "oslo", 30), at(2))
+	people = mustInsert(t, people, person(1, "oslo", 30), at(3))
+
+	assert.Equal(t, []int64{}, selectIDs(t, people, pred("city", core.OpEq, "paris")))
+	assert.Equal(t, []int64{1}, selectIDs(t, people, pred("city", core.OpEq, "oslo")))
+	assert.Equal(t, 1, indexSize(t, people, "by_city"))
+	assert.Equal(t, 1, indexSize(t, people, "by_age"))
+}
+
+func TestTableEvolveIndexes(t *testing.T) {
+	people := newPeople(t)
+	people = mustInsert(t, people, person(1, "paris", 30), at(1))
+	people = mustInsert(t, people, person(2, "oslo", 25), at(1))
+
+	next := peopleSchema()
+	next.Indexes = map[string][]string{"by_age": {"age"}, "by_age_city": {"age", "city"}}
+	evolved, err := people.Evolve(next)
+	require.NoError(t, err)
+	_, ok := evolved.Index("by_city")
+	assert.False(t, ok)
+	assert.Equal(t, 2, indexSize(t, evolved, "by_age_city"))
+	preds := []core.Predicate{pred("age", core.OpEq, int32(25)), pred("city", core.OpEq, "oslo")}
+	plan, err := evolved.Plan(preds)
+	require.NoError(t, err)
+	assert.Equal(t, "by_age_city", plan)
+	assert.Equal(t, []int64{2}, selectIDs(t, evolved, preds...))
+}
+
+func TestTableMergeIndexes(t *testing.T) {
+	base := mustInsert(t, newPeople(t), person(1, "paris", 30), at(1))
+	// One replica moves the person while the other only changes their age, so after merging the
+	// entry for paris is stale and the one for oslo is current
+	left := mustInsert(t, base, person(1, "oslo", 30), core.HLC{Wall: 3, Replica: "a"})
+	right := mustInsert(t, base, person(1, "paris", 31), core.HLC{Wall: 2, Replica: "b"})
+	right = mustInsert(t, right, person(2, "paris", 20), core.HLC{Wall: 2, Replica: "b"})
+
+	for _, tables := range [][2]*Table{{left, right}, {right, left}} {
+		merged, err := tables[0].Merge(tables[1])
+		require.NoError(t, err)
+		row, _, err := merged.Get(person(1, nil, 0))
+		require.NoError(t, err)
+		assert.Equal(t, person(1, "oslo", 30), row)
+
+		paris := pred("city", core.OpEq, "paris")
+		assert.Equal(t, []int64{2}, selectIDs(t, merged, paris))
+		oslo := pred("city", core.OpEq, "oslo")
+		assert.Equal(t, []int64{1}, selectIDs(t, merged, oslo))
+		young := pred("age", core.OpLe, int32(31))
+		assert.Equal(t, []int64{2, 1}, selectIDs(t, merged, young))
+	}
+
+	evolved, err := left.Evolve(peopleSchema())
+	require.NoError(t, err)
+	_, err = evolved.Merge(right)
+	assert.Error(t, err)
+}
+
+func TestOpenTable(t *testing.T) {
+	people := newPeople(t)
+	people = mustInsert(t, people, person(1, "paris", 30), at(1))
+	people = mustInsert(t, people, person(2, "oslo", 25), at(2))
+	byCity, _ := people.Index("by_city")
+
+	// The index that's given is kept as it is, and the missing one is built from the rows
+	opened, err := OpenTable(
+		"people",
+		people.Schemas(),
+		people.Tree(),
+		map[string]*mst.MerkleSearchTree{"by_city": byCity},
+	)
+	require.NoError(t, err)
+	index, _ := opened.Index("by_city")
+	assert.Equal(t, byCity.RootHash(), index.RootHash())
+	assert.Equal(t, 2, indexSize(t, opened, "by_age"))
+	assert.Equal(t, []int64{2}, selectIDs(t, opened, pred("age", core.OpLt, int32(30))))
+
+	opened = mustInsert(t, opened, person(3, "oslo", 20), at(3))
+	assert.Equal(t, []int64{3, 2}, selectIDs(t, opened, pred("city", core.OpEq, "oslo")))
+}
@@ -0,0 +1,425 @@
+package table
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+
+	"github.com/vulturedb/vulture/core"
+	"github.com/vulturedb/vulture/mst"
+)
+
+// Table is a set of rows that share a schema. Rows are kept in a MerkleSearchTree keyed by their
+// primary key, and each row is a core.RowValue whose fields merge according to the merge policies
+// of the schema, so that replicas writing the same row converge. Like the tree, a Table is
+// immutable and every write returns a new one.
+//
+// A table keeps every version of its schema. Rows written under an older version, possibly by a
+// peer that hasn't seen the latest one yet, are upgraded to the latest version when they're read.
+//
+// Each secondary index of the schema is kept in a tree of its own, keyed by core.IndexKey with
+// core.IndexEntry values. Entries are written along with their rows and merge like rows do, so
+// merged indexes may hold stale entries of rows that changed on another replica. Those are skipped
+// when the index is read.
+type Table struct {
+	name    string
+	schemas core.SchemaHistory
+	tree    *mst.MerkleSearchTree
+	indexes map[string]*mst.MerkleSearchTree
+}
+
+// NewTable creates a table whose rows are stored in tree. The tree's keys must be the schema's
+// core.PrimaryKey and its values core.RowValue.
+func NewTable(name string, schema core.Schema, tree *mst.MerkleSearchTree) (*Table, error) {
+	return NewVersionedTable(name, core.NewSchemaHistory(schema), tree)
+}
+
+// NewVersionedTable creates a table whose rows may have been written under any version of schemas.
+// The indexes of the latest version are built from the rows in tree.
+func NewVersionedTable(
+	name string,
+	schemas core.SchemaHistory,
+	tree *mst.MerkleSearchTree,
+) (*Table, error) {
+	return OpenTable(name, schemas, tree, nil)
+}
+
+// OpenTable creates a table from the trees of its rows and of the indexes of the latest version of
+// schemas by name, like the ones Tree and Index return. Indexes that aren't in indexes are built
+// from the rows in tree.
+func OpenTable(
+	name string,
+	schemas core.SchemaHistory,
+	tree *mst.MerkleSearchTree,
+	indexes map[string]*mst.MerkleSearchTree,
+) (*Table, error) {
+	if name == "" {
+		return nil, fmt.Errorf("Table name is empty")
+	}
+	if err := schemas.Latest().Validate(); err != nil {
+		return nil, fmt.Errorf("Invalid schema for table %s: %w", name, err)
+	}
+	t := &Table{name, schemas, tree, indexes}
+	return t.withIndexes(schemas.Latest())
+}
+
+func (t *Table) Name() string {
+	return t.name
+}
+
+// Schema returns the latest version of the table's schema
+func (t *Table) Schema() core.Schema {
+	return t.schemas.Latest()
+}
+
+func (t *Table) Schemas() core.SchemaHistory {
+	return t.schemas
+}
+
+func (t *Table) Tree() *mst.MerkleSearchTree {
+	return t.tree
+}
+
+func (t *Table) withTree(tree *mst.MerkleSearchTree) *Table {
+	return &Table{t.name, t.schemas, tree, t.indexes}
+}
+
+// Index returns the tree of the index named name
+func (t *Table) Index(name string) (*mst.MerkleSearchTree, bool) {
+	index, ok := t.indexes[name]
+	return index, ok
+}
+
+// withIndexes returns the table with the indexes of schema, building the ones it doesn't have yet
+// and dropping the ones schema doesn't have anymore
+func (t *Table) withIndexes(schema core.Schema) (*Table, error) {
+	indexes := make(map[string]*mst.MerkleSearchTree, len(schema.Indexes))
+	for name := range schema.Indexes {
+		index, ok := t.indexes[name]
+		if !ok {
+			var err error
+			index, err = t.buildIndex(name)
+			if err != nil {
+				return nil, fmt.Errorf("Couldn't build index %s of table %s: %w", name, t.name, err)
+			}
+		}
+		indexes[name] = index
+	}
+	return &Table{t.name, t.schemas, t.tree, indexes}, nil
+}
+
+type indexItem struct {
+	key   core.IndexKey
+	entry core.IndexEntry
+}
+
+// buildIndex creates the named index of the latest schema from the rows of the table, with its
+// nodes kept in memory. Its entries have a zero timestamp, so that replicas that build the same
+// index end up with the same entries.
+func (t *Table) buildIndex(name string) (*mst.MerkleSearchTree, error) {
+	schema := t.Schema()
+	items := []indexItem{}
+	it := t.Scan()
+	for it.Next() {
+		key, err := schema.PrimaryKeyOf(it.Row())
+		if err != nil {
+			return nil, err
+		}
+		indexKey, err := schema.IndexKeyOf(name, it.Row())
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, indexItem{indexKey, core.NewIndexEntry(key, core.HLC{})})
+	}
+	if err := it.Err(); err != nil {
+		return nil, err
+	}
+	sort.Slice(items, func(i, j int) bool { return items[i].key.Less(items[j].key) })
+	loader := mst.NewBulkLoader(t.tree.Base(), t.tree.Hash(), mst.NewLocalNodeStore(t.tree.Hash()))
+	for _, item := range items {
+		if err := loader.Add(item.key, item.entry); err != nil {
+			return nil, err
+		}
+	}
+	return loader.Tree()
+}
+
+// Evolve returns the table with next as the latest version of its schema. next must be an allowed
+// evolution of the current schema, see core.Schema.CheckEvolution. Indexes that next adds are
+// built from the rows of the table.
+func (t *Table) Evolve(next core.Schema) (*Table, error) {
+	schemas, err := t.schemas.Evolve(next)
+	if err != nil {
+		return nil, fmt.Errorf("Couldn't evolve schema of table %s: %w", t.name, err)
+	}
+	evolved := &Table{t.name, schemas, t.tree, t.indexes}
+	return evolved.withIndexes(schemas.Latest())
+}
+
+// Merge returns the table with the rows and index entries of other merged in. Both tables have to
+// be at the same version of the schema, since rows written under a version without one of the
+// indexes have no entries in it.
+func (t *Table) Merge(other *Table) (*Table, error) {
+	if t.name != other.name {
+		return nil, fmt.Errorf("Can't merge table %s with table %s", t.name, other.name)
+	}
+	version, otherVersion := t.Schema().Version, other.Schema().Version
+	if version != otherVersion {
+		return nil, fmt.Errorf(
+			"Can't merge table %s at schema version %d with version %d",
+			t.name,
+			version,
+			otherVersion,
+		)
+	}
+	tree, err := t.tree.Merge(other.tree)
+	if err != nil {
+		return nil, err
+	}
+	indexes := make(map[string]*mst.MerkleSearchTree, len(t.indexes))
+	for name, index := range t.indexes {
+		indexes[name], err = index.Merge(other.indexes[name])
+		if err != nil {
+			return nil, fmt.Errorf("Couldn't merge index %s: %w", name, err)
+		}
+	}
+	return &Table{t.name, t.schemas, tree, indexes}, nil
+}
+
+// Insert validates row against the table's schema and writes it, replacing any row with the same
+// primary key. Rows valid under an older version of the schema are upgraded to the latest one, and
+// fields the row leaves out are set to their defaults, except for counters, max and min fields of
+// an existing row, which keep their state.
+//
+// The write is made by the replica of timestamp, and each field is updated according to its merge
+// policy, see core.RowValue.Update. A row that was deleted starts over from an empty state, which
+// is then merged with the state it had when it was deleted, so counters keep counting from there.
+func (t *Table) Insert(row core.Row, timestamp core.HLC) (*Table, error) {
+	validated, err := t.schemas.ValidateRow(row)
+	if err != nil {
+		return nil, err
+	}
+	key, err := t.Schema().PrimaryKeyOf(validated)
+	if err != nil {
+		return nil, err
+	}
+	current, exists, err := t.getValue(key)
+	if err != nil {
+		return nil, err
+	}
+	for fieldName, spec := range t.Schema().Fields {
+		if _, written := row.Data[fieldName]; !written && current.KeepsState(fieldName, spec) {
+			delete(validated.Data, fieldName)
+		}
+	}
+	updated, err := current.Update(t.Schema(), validated, timestamp)
+	if err != nil {
+		return nil, err
+	}
+	tree, err := t.tree.Put(key, updated)
+	if err != nil {
+		return nil, err
+	}
+	var previous *core.RowValue
+	if exists {
+		previous = &current
+	}
+	indexes, err := t.updateIndexes(key, previous, updated, timestamp)
+	if err != nil {
+		return nil, err
+	}
+	return &Table{t.name, t.schemas, tree, indexes}, nil
+}
+
+// updateIndexes writes the index entries of a row that changed from previous, which is nil for a
+// new row, to updated. The entry of the updated row is written even if its key didn't change, so
+// that it's visible after merging with a replica where the row changed and the entry was deleted.
+func (t *Table) updateIndexes(
+	key core.PrimaryKey,
+	previous *core.RowValue,
+	updated core.RowValue,
+	timestamp core.HLC,
+) (map[string]*mst.MerkleSearchTree, error) {
+	if len(t.indexes) == 0 {
+		return t.indexes, nil
+	}
+	schema := t.Schema()
+	updatedRow, err := t.schemas.Upgrade(updated.Row())
+	if err != nil {
+		return nil, err
+	}
+	var previousRow *core.Row
+	if previous != nil {
+		row, err := t.schemas.Upgrade(previous.Row())
+		if err != nil {
+			return nil, err
+		}
+		previousRow = &row
+	}
+	indexes := make(map[string]*mst.MerkleSearchTree, len(t.indexes))
+	for name, index := range t.indexes {
+		batch := mst.NewBatch()
+		updatedKey, err := schema.IndexKeyOf(name, updatedRow)
+		if err != nil {
+			return nil, err
+		}
+		if previousRow != nil {
+			previousKey, err := schema.IndexKeyOf(name, *previousRow)
+			if err != nil {
+				return nil, err
+			}
+			if !bytes.Equal(previousKey.Bytes(), updatedKey.Bytes()) {
+				batch.Delete(previousKey)
+			}
+		}
+		batch.Put(updatedKey, core.NewIndexEntry(key, timestamp))
+		indexes[name], err = index.Apply(batch)
+		if err != nil {
+			return nil, fmt.Errorf("Couldn't update index %s: %w", name, err)
+		}
+	}
+	return indexes, nil
+}
+
+func (t *Table) getValue(key core.PrimaryKey) (core.RowValue, bool, error) {
+	val, err := t.tree.Get(key)
+	if err != nil || val == nil {
+		return core.NewRowValue(), false, err
+	}
+	rowValue, ok := val.(core.RowValue)
+	if !ok {
+		return core.RowValue{}, false, fmt.Errorf("Unexpected row value of type %T", val)
+	}
+	return rowValue, true, nil
+}
+
+// GetValue returns the replicated state of the row whose primary key fields match those in key,
+// which holds every value of fields that were written concurrently
+func (t *Table) GetValue(key core.Row) (core.RowValue, bool, error) {
+	primaryKey, err := t.Schema().PrimaryKeyOf(key)
+	if err != nil {
+		return core.RowValue{}, false, err
+	}
+	return t.getValue(primaryKey)
+}
+
+// Get returns the row whose primary key fields match those in key. Fields in key that aren't part
+// of the primary key are ignored.
+func (t *Table) Get(key core.Row) (core.Row, bool, error) {
+	primaryKey, err := t.Schema().PrimaryKeyOf(key)
+	if err != nil {
+		return core.Row{}, false, err
+	}
+	val, err := t.tree.Get(primaryKey)
+	if err != nil || val == nil {
+		return core.Row{}, false, err
+	}
+	row, err := decodeRow(t.schemas, val)
+	if err != nil {
+		return core.Row{}, false, err
+	}
+	return row, true, nil
+}
+
+func decodeRow(schemas core.SchemaHistory, val mst.Value) (core.Row, error) {
+	rowValue, ok := val.(core.RowValue)
+	if !ok {
+		return core.Row{}, fmt.Errorf("Unexpected row value of type %T", val)
+	}
+	return schemas.Upgrade(rowValue.Row())
+}
+
+// RowIterator walks over the rows of a table in primary key order, or in the order of an index
+// when the rows are found through one
+type RowIterator struct {
+	schemas core.SchemaHistory
+	it      *mst.Iterator
+	// Set when it walks over an index, whose entries point to rows in rows
+	rows  *mst.MerkleSearchTree
+	index string
+	// Only rows matching every predicate are returned
+	preds []core.Predicate
+	row   core.Row
+	err   error
+}
+
+// Scan returns an iterator over every row in the table
+func (t *Table) Scan() *RowIterator {
+	return &RowIterator{schemas: t.schemas, it: t.tree.Scan(mst.ScanOptions{})}
+}
+
+// ScanPrefix returns an iterator over the rows whose leading primary key fields match those set in
+// prefix. The fields set in prefix must be a leading run of the primary key.
+func (t *Table) ScanPrefix(prefix core.Row) (*RowIterator, error) {
+	key, err := t.Schema().PrimaryKeyPrefix(prefix)
+	if err != nil {
+		return nil, err
+	}
+	return &RowIterator{schemas: t.schemas, it: t.tree.Scan(mst.ScanOptions{Prefix: key})}, nil
+}
+
+// Next advances the iterator and returns whether there is a row to read
+func (it *RowIterator) Next() bool {
+	for it.err == nil && it.it.Next() {
+		var matches bool
+		it.row, matches, it.err = it.read()
+		if matches {
+			return true
+		}
+	}
+	return false
+}
+
+// read returns the row at the current position of the underlying iterator and whether it should be
+// returned
+func (it *RowIterator) read() (core.Row, bool, error) {
+	val := it.it.Value()
+	if it.rows != nil {
+		entry, ok := val.(core.IndexEntry)
+		if !ok {
+			return core.Row{}, false, fmt.Errorf("Unexpected index entry of type %T", val)
+		}
+		key, err := it.schemas.Latest().PrimaryKeyReader().FromBytes(entry.RowKey())
+		if err != nil {
+			return core.Row{}, false, err
+		}
+		val, err = it.rows.Get(key)
+		if err != nil || val == nil {
+			return core.Row{}, false, err
+		}
+	}
+	row, err := decodeRow(it.schemas, val)
+	if err != nil {
+		return core.Row{}, false, err
+	}
+	schema := it.schemas.Latest()
+	if it.rows != nil {
+		// Skip stale entries, whose row has since been written with other values
+		key, err := schema.IndexKeyOf(it.index, row)
+		if err != nil {
+			return core.Row{}, false, err
+		}
+		if !bytes.Equal(key.Bytes(), it.it.Key().(core.IndexKey).Bytes()) {
+			return core.Row{}, false, nil
+		}
+	}
+	for _, p := range it.preds {
+		matches, err := schema.Matches(row, p)
+		if err != nil || !matches {
+			return core.Row{}, false, err
+		}
+	}
+	return row, true, nil
+}
+
+func (it *RowIterator) Row() core.Row {
+	return it.row
+}
+
+// Err returns the error that stopped the iterator, if any
+func (it *RowIterator) Err() error {
+	if it.err != nil {
+		return it.err
+	}
+	return it.it.Err()
+}
@@ -0,0 +1,302 @@
+package table
+
+import (
+	"crypto"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/vulturedb/vulture/core"
+	"github.com/vulturedb/vulture/mst"
+)
+
+func usersSchema() core.Schema {
+	return core.Schema{
+		Fields: map[string]core.FieldSpec{
+			"id":    {Type: "long"},
+			"name":  {Type: "string"},
+			"email": {Type: "string", Nullable: true},
+		},
+		PrimaryKey: []string{"id"},
+	}
+}
+
+func newUsers(t *testing.T) *Table {
+	users, err := NewTable("users", usersSchema(), mst.NewLocalMST(mst.Base4, crypto.SHA256))
+	require.NoError(t, err)
+	return users
+}
+
+// at returns a timestamp of replica "a"
+func at(wall uint64) core.HLC {
+	return core.HLC{Wall: wall, Replica: "a"}
+}
+
+func user(id int64, name string) core.Row {
+	return core.Row{Data: map[string]interface{}{"id": id, "name": name}}
+}
+
+func TestNewTableInvalidSchema(t *testing.T) {
+	tree := mst.NewLocalMST(mst.Base4, crypto.SHA256)
+	schema := usersSchema()
+	schema.PrimaryKey = nil
+	_, err := NewTable("users", schema, tree)
+	assert.Error(t, err)
+
+	schema.PrimaryKey = []string{"email"}
+	_, err = NewTable("users", schema, tree)
+	assert.Error(t, err)
+
+	schema.PrimaryKey = []string{"missing"}
+	_, err = NewTable("users", schema, tree)
+	assert.Error(t, err)
+}
+
+func TestTableInsertGet(t *testing.T) {
+	users := newUsers(t)
+	users, err := users.Insert(user(1, "ada"), at(1))
+	require.NoError(t, err)
+	users, err = users.Insert(user(2, "grace"), at(1))
+	require.NoError(t, err)
+
+	row, found, err := users.Get(core.Row{Data: map[string]interface{}{"id": int64(2)}})
+	require.NoError(t, err)
+	assert.True(t, found)
+	assert.Equal(t, user(2, "grace"), row)
+
+	_, found, err = users.Get(core.Row{Data: map[string]interface{}{"id": int64(3)}})
+	require.NoError(t, err)
+	assert.False(t, found)
+
+	// The key has to have the right type
+	_, _, err = users.Get(core.Row{Data: map[string]interface{}{"id": int32(2)}})
+	assert.Error(t, err)
+}
+
+func TestTableInsertInvalidRow(t *testing.T) {
+	users := newUsers(t)
+	_, err := users.Insert(core.Row{Data: map[string]interface{}{"id": int64(1)}}, at(1))
+	assert.Error(t, err)
+	_, err = users.Insert(core.Row{Data: map[string]interface{}{"id": "1", "name": "ada"}}, at(1))
+	assert.Error(t, err)
+}
+
+func TestTableLastWriterWins(t *testing.T) {
+	left, err := newUsers(t).Insert(user(1, "old"), at(1))
+	require.NoError(t, err)
+	right, err := newUsers(t).Insert(user(1, "new"), at(2))
+	require.NoError(t, err)
+
+	merged, err := left.Tree().Merge(right.Tree())
+	require.NoError(t, err)
+	row, found, err := left.withTree(merged).Get(user(1, ""))
+	require.NoError(t, err)
+	assert.True(t, found)
+	assert.Equal(t, user(1, "new"), row)
+}
+
+func TestTableMergePolicies(t *testing.T) {
+	schema := core.Schema{
+		Fields: map[string]core.FieldSpec{
+			"page":   {Type: "string"},
+			"views":  {Type: "long", Merge: core.MergePNCounter},
+			"tags":   {Type: "array", Items: &core.FieldSpec{Type: "string"}, Merge: core.MergeSet},
+			"title":  {Type: "string", Merge: core.MergeMVR},
+			"latest": {Type: "long", Merge: core.MergeMax},
+		},
+		PrimaryKey: []string{"page"},
+	}
+	page := func(views int64, tags []interface{}, title string, latest int64) core.Row {
+		return core.Row{Data: map[string]interface{}{
+			"page":   "home",
+			"views":  views,
+			"tags":   tags,
+			"title":  title,
+			"latest": latest,
+		}}
+	}
+	base, err := NewTable("pages", schema, mst.NewLocalMST(mst.Base4, crypto.SHA256))
+	require.NoError(t, err)
+	base, err = base.Insert(page(1, []interface{}{"a"}, "Home", 1), core.HLC{Wall: 1, Replica: "a"})
+	require.NoError(t, err)
+
+	left, err := base.Insert(
+		page(3, []interface{}{"a", "b"}, "Left", 5),
+		core.HLC{Wall: 2, Replica: "a"},
+	)
+	require.NoError(t, err)
+	right, err := base.Insert(
+		page(2, []interface{}{"c"}, "Right", 3),
+		core.HLC{Wall: 3, Replica: "b"},
+	)
+	require.NoError(t, err)
+
+	for _, trees := range [][2]*Table{{left, right}, {right, left}} {
+		merged, err := trees[0].Tree().Merge(trees[1].Tree())
+		require.NoError(t, err)
+		table := trees[0].withTree(merged)
+		row, found, err := table.Get(core.Row{Data: map[string]interface{}{"page": "home"}})
+		require.NoError(t, err)
+		assert.True(t, found)
+		// Both increments count, both added tags stay while "a" is removed, the largest latest is
+		// kept and the title with the latest timestamp is shown
+		assert.Equal(t, page(4, []interface{}{"b", "c"}, "Right", 5), row)
+
+		value, _, err := table.GetValue(core.Row{Data: map[string]interface{}{"page": "home"}})
+		require.NoError(t, err)
+		assert.Equal(t, []interface{}{"Left", "Right"}, value.Values("title"))
+	}
+}
+
+func TestTableInsertKeepsCounters(t *testing.T) {
+	schema := core.Schema{
+		Fields: map[string]core.FieldSpec{
+			"page":   {Type: "string"},
+			"title":  {Type: "string", Default: "Untitled"},
+			"likes":  {Type: "long", Merge: core.MergeGCounter, Default: int64(0)},
+			"views":  {Type: "long", Merge: core.MergePNCounter, Default: int64(0)},
+			"oldest": {Type: "long", Merge: core.MergeMin, Default: int64(100)},
+		},
+		PrimaryKey: []string{"page"},
+	}
+	pages, err := NewTable("pages", schema, mst.NewLocalMST(mst.Base4, crypto.SHA256))
+	require.NoError(t, err)
+	home := core.Row{Data: map[string]interface{}{"page": "home"}}
+
+	// Fields that have no state yet take their defaults
+	pages, err = pages.Insert(home, at(1))
+	require.NoError(t, err)
+	row, _, err := pages.Get(home)
+	require.NoError(t, err)
+	assert.Equal(t, core.Row{Data: map[string]interface{}{
+		"page":   "home",
+		"title":  "Untitled",
+		"likes":  int64(0),
+		"views":  int64(0),
+		"oldest": int64(100),
+	}}, row)
+
+	pages, err = pages.Insert(core.Row{Data: map[string]interface{}{
+		"page":   "home",
+		"title":  "Home",
+		"likes":  int64(5),
+		"views":  int64(7),
+		"oldest": int64(3),
+	}}, at(2))
+	require.NoError(t, err)
+
+	// Leaving the counters out keeps counting from where they are instead of going back to their
+	// defaults, while the title is replaced by its default
+	pages, err = pages.Insert(home, at(3))
+	require.NoError(t, err)
+	row, _, err = pages.Get(home)
+	require.NoError(t, err)
+	assert.Equal(t, core.Row{Data: map[string]interface{}{
+		"page":   "home",
+		"title":  "Untitled",
+		"likes":  int64(5),
+		"views":  int64(7),
+		"oldest": int64(3),
+	}}, row)
+}
+
+func TestTableScan(t *testing.T) {
+	users := newUsers(t)
+	var err error
+	for i := int64(0); i < 20; i++ {
+		users, err = users.Insert(user(i, "user"), at(1))
+		require.NoError(t, err)
+	}
+	it := users.Scan()
+	count := 0
+	for it.Next() {
+		assert.Equal(t, "user", it.Row().Data["name"])
+		count++
+	}
+	require.NoError(t, it.Err())
+	assert.Equal(t, 20, count)
+}
+
+func TestTableScanPrefix(t *testing.T) {
+	schema := core.Schema{
+		Fields: map[string]core.FieldSpec{
+			"tenant": {Type: "string"},
+			"seq":    {Type: "int"},
+		},
+		PrimaryKey: []string{"tenant", "seq"},
+	}
+	events, err := NewTable("events", schema, mst.NewLocalMST(mst.Base4, crypto.SHA256))
+	require.NoError(t, err)
+	for _, tenant := range []string{"a", "ab", "b"} {
+		for seq := int32(-5); seq < 5; seq++ {
+			row := core.Row{Data: map[string]interface{}{"tenant": tenant, "seq": seq}}
+			events, err = events.Insert(row, at(1))
+			require.NoError(t, err)
+		}
+	}
+
+	it, err := events.ScanPrefix(core.Row{Data: map[string]interface{}{"tenant": "a"}})
+	require.NoError(t, err)
+	expected := int32(-5)
+	for it.Next() {
+		assert.Equal(t, "a", it.Row().Data["tenant"])
+		assert.Equal(t, expected, it.Row().Data["seq"])
+		expected++
+	}
+	require.NoError(t, it.Err())
+	assert.Equal(t, int32(5), expected)
+
+	_, err = events.ScanPrefix(core.Row{Data: map[string]interface{}{"seq": int32(1)}})
+	assert.Error(t, err)
+}
+
+func TestTableEvolve(t *testing.T) {
+	users := newUsers(t)
+	users, err := users.Insert(user(1, "old"), at(1))
+	require.NoError(t, err)
+
+	next := usersSchema()
+	next.Fields["age"] = core.FieldSpec{Type: "int", Nullable: true}
+	evolved, err := users.Evolve(next)
+	require.NoError(t, err)
+	assert.Equal(t, uint32(1), evolved.Schema().Version)
+
+	// Rows from before the change are still readable
+	row, found, err := evolved.Get(user(1, ""))
+	require.NoError(t, err)
+	assert.True(t, found)
+	assert.Equal(t, user(1, "old"), row)
+
+	newUser := user(2, "new")
+	newUser.Data["age"] = int32(30)
+	_, err = users.Insert(newUser, at(1))
+	assert.Error(t, err)
+	evolved, err = evolved.Insert(newUser, at(1))
+	require.NoError(t, err)
+
+	widened := next
+	widened.Fields = map[string]core.FieldSpec{}
+	for name, spec := range next.Fields {
+		widened.Fields[name] = spec
+	}
+	widened.Fields["age"] = core.FieldSpec{Type: "long", Nullable: true}
+	evolved, err = evolved.Evolve(widened)
+	require.NoError(t, err)
+	row, found, err = evolved.Get(user(2, ""))
+	require.NoError(t, err)
+	assert.True(t, found)
+	assert.Equal(t, int64(30), row.Data["age"])
+
+	// Rows written under an older version are upgraded on insert too
+	evolved, err = evolved.Insert(newUser, at(2))
+	require.NoError(t, err)
+	row, _, err = evolved.Get(user(2, ""))
+	require.NoError(t, err)
+	assert.Equal(t, int64(30), row.Data["age"])
+
+	removed := usersSchema()
+	delete(removed.Fields, "email")
+	_, err = evolved.Evolve(removed)
+	assert.Error(t, err)
+}